@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/porganisciak/agent-tmux/tmux"
+	"github.com/spf13/cobra"
+)
+
+var attachTemplate string
+
+// sessionRunning and attachNamedSession look up and attach to a session by
+// name; tests replace them.
+var (
+	sessionRunning     = func(name string) bool { return (&tmux.Session{Name: name}).Exists() }
+	attachNamedSession = attachToSession
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach [session-name|dir]",
+	Short: "Attach to a session by name, or to a directory's session, creating it if needed",
+	Long: `Attach to a running session by name, as 'atmux sessions NAME' does.
+
+Otherwise the argument is a directory (default: the current one): attach to
+its atmux session, creating it from the project's config first if it isn't
+running. The session is named the same way the landing page names it and is
+recorded in history.
+
+Unlike plain 'atmux', this never shows the landing page, so it suits a tmux
+keybinding. Inside tmux it switches the client to the session.
+
+Examples:
+  atmux attach
+  atmux attach my-app
+  atmux attach ~/code/my-app
+  atmux attach --template=frontend
+  bind-key A run-shell "atmux attach '#{pane_current_path}'"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAttach,
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+	attachCmd.Flags().StringVarP(&attachTemplate, "template", "t", "",
+		"Session template to use if the session has to be created")
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	if err := tmux.CheckInstalled(); err != nil {
+		return err
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		// A running session's name wins over a directory of the same name
+		if sessionRunning(atmuxSessionName(args[0])) {
+			return attachNamedSession(args[0])
+		}
+		dir = args[0]
+	}
+	// Name the session from the full path, as the landing page does from
+	// the working directory
+	workingDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	if info, err := os.Stat(workingDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", workingDir)
+	}
+
+	return runDirectAttach(workingDir, attachTemplate)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestAttachByRunningSessionName(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	origRunning, origAttach := sessionRunning, attachNamedSession
+	t.Cleanup(func() {
+		sessionRunning, attachNamedSession = origRunning, origAttach
+		rootCmd.SetArgs(nil)
+	})
+	sessionRunning = func(name string) bool { return name == "agent-api" }
+	var attached []string
+	attachNamedSession = func(name string) error {
+		attached = append(attached, name)
+		return nil
+	}
+
+	rootCmd.SetArgs([]string{"attach", "api"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if len(attached) != 1 || attached[0] != "api" {
+		t.Fatalf("expected atmux attach api to attach by name, got %q", attached)
+	}
+
+	// A name that isn't running is taken as a directory
+	rootCmd.SetArgs([]string{"attach", "no-such-session-or-dir"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected a missing directory error")
+	}
+	if len(attached) != 1 {
+		t.Fatalf("expected no further attach by name, got %q", attached)
+	}
+}
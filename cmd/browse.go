@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
 	"github.com/porganisciak/agent-tmux/tui"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +20,13 @@ var (
 	debugMode       bool
 	mobileMode      bool
 	browseRemote    string
+	onceTarget      string
+	onceEscapes     bool
+	onceLines       int
+
+	// --refresh was given, so it wins over the refresh_interval setting,
+	// also when browse is reopened from another screen
+	browseRefreshSet bool
 )
 
 var browseCmd = &cobra.Command{
@@ -28,16 +39,30 @@ Features:
   - Live preview of selected pane content
   - Send commands to any pane with a click
   - Mouse and keyboard navigation
+  - Tree stacked above the preview in tall, narrow windows
+    ("browse_layout" in settings: auto, side, or stacked)
 
 Controls:
   Tab/Shift+Tab  Cycle focus between tree, input, preview
   Up/Down or j/k Navigate tree
+  PgUp/PgDn      Move selection by a page (Home/End: first/last item)
+  1-9            Jump to a numbered node (type digits quickly for 10+)
   Enter/Space    Expand/collapse session or window
-  a (att)        Attach to session for selected window/pane
+  a (att)        Attach, landing on the selected window/pane
   s              Send command to selected pane
+  S              Send command to all panes in the selected window
+  v / V          Mark item / inactive sibling windows or panes for bulk kill
+  x or d         Kill selected item, or every marked item (Esc clears marks)
+  n              New session for current directory (or attach if it exists)
+  f              Search pane contents across hosts
+  y / Y          Copy target / pane content to clipboard
+  i              Show/hide pane sizes (e.g. 80x24)
+  < / >          Shrink/grow the tree (also drag the divider; the width is remembered)
+  Ctrl+P         Command palette (fuzzy-find any action)
+  z              Zoom preview to full screen (when preview focused)
   M              Toggle mouse capture (for text selection)
   r              Refresh tree
-  /              Focus command input
+  /              Filter tree by name or target (Enter keeps it, Esc clears)
   q/Esc          Quit
 
 Mouse:
@@ -46,6 +71,13 @@ Mouse:
   Click [SEND] button to send command to that pane
   Click [ESC] button to send Escape to that pane
   Click input/preview area to focus
+  Drag across the preview to select text; releasing copies it
+
+Print and exit (--once):
+  atmux browse --once agent-foo:0.1             Print the pane's screen, no TUI
+  atmux browse --once agent-foo:0.1 -e          Keep colors (escape sequences)
+  atmux browse --once agent-foo:0.1 --lines 500 Include 500 lines of scrollback
+  atmux browse --once agent-foo:0.1 --remote devbox
 
 Debug Mode (--debug):
   m              Cycle through send methods (Enter separate, C-m separate, etc.)
@@ -68,12 +100,24 @@ func init() {
 	browseCmd.Flags().BoolVarP(&debugMode, "debug", "d", false, "Enable debug mode to test different send methods")
 	browseCmd.Flags().BoolVarP(&mobileMode, "mobile", "m", false, "Mobile-optimized view for narrow terminals (auto-detected if width < 60)")
 	browseCmd.Flags().StringVar(&browseRemote, "remote", "", "Remote host(s) or aliases to include (comma-separated)")
+	browseCmd.Flags().StringVar(&onceTarget, "once", "", "Print a pane's content and exit instead of opening the TUI")
+	browseCmd.Flags().BoolVarP(&onceEscapes, "escapes", "e", false, "With --once, keep color escape sequences")
+	browseCmd.Flags().IntVar(&onceLines, "lines", 0, "With --once, scrollback lines to include above the visible screen")
 }
 
 func runBrowse(cmd *cobra.Command, args []string) error {
+	if onceTarget != "" {
+		return runBrowseOnce()
+	}
+
 	// Check if tmux server is running (only required when no remote hosts)
-	if browseRemote == "" && !tmuxServerRunning() {
-		return fmt.Errorf("tmux server not running - start a tmux session first")
+	if browseRemote == "" {
+		if err := tmux.CheckInstalled(); err != nil {
+			return err
+		}
+		if !tmuxServerRunning() {
+			return fmt.Errorf("tmux server not running - start a tmux session first")
+		}
 	}
 
 	// Default to popup when inside tmux, unless --no-popup is set
@@ -82,27 +126,105 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 		return launchAsPopup("browse")
 	}
 
+	browseRefreshSet = cmd.Flags().Changed("refresh")
+	sw, err := browseScreen("", "")
+	if err != nil {
+		return err
+	}
+	return runScreens(sw)
+}
+
+// browseScreen runs browse, selecting session on host once it loads when
+// one is given, and returns the screen the user switched to, if any.
+func browseScreen(session, host string) (*tui.ScreenSwitch, error) {
 	// Build executors when --remote is specified
 	opts := tui.Options{
 		RefreshInterval: time.Duration(refreshInterval) * time.Second,
 		PopupMode:       false,
 		DebugMode:       debugMode,
 		MobileMode:      mobileMode,
+		SelectSession:   session,
+		SelectHost:      host,
+		NoHistory:       noHistory,
+	}
+
+	// Derive the "new session here" name the same way the landing page does
+	if workingDir, err := os.Getwd(); err == nil {
+		opts.WorkingDir = workingDir
+		opts.SessionName = tmux.NewSession(workingDir).Name
+		if cfg, err := config.LoadConfig(filepath.Join(workingDir, config.DefaultConfigName)); err == nil {
+			opts.Templates = cfg.TemplateNames()
+			opts.Agents = cfg.CoreAgents
+		}
+	}
+
+	settings, _ := config.LoadSettings()
+	opts.SkipKillConfirm = settings.SkipKillConfirm
+	opts.SkipShellConfirm = settings.SkipShellConfirm
+	opts.RestoreFocus = settings.RestoreFocus
+	opts.AfterSend = settings.AfterSend
+	opts.StatusClock = settings.StatusClock
+	opts.StatusCounts = settings.StatusCounts
+	opts.WatchIdleAfter = settings.ParsedWatchIdleAfter()
+	opts.WatchNotify = settings.WatchNotify
+	opts.NewInPaneDir = settings.NewWindowDir == config.NewWindowDirPane
+	opts.MobileWidth = settings.EffectiveMobileWidth()
+	opts.TreeWidthPercent = settings.TreeWidthPercent
+	opts.Layout = settings.BrowseLayout
+	if !browseRefreshSet {
+		opts.RefreshInterval = settings.ParsedRefreshInterval()
 	}
+	opts.PreviewRefreshInterval = settings.ParsedPreviewRefreshInterval()
 
 	if browseRemote != "" {
 		executors, err := buildExecutors(browseRemote)
 		if err != nil {
-			return fmt.Errorf("failed to build executors: %w", err)
+			return nil, fmt.Errorf("failed to build executors: %w", err)
 		}
 		defer closeExecutors(executors)
 		registerCleanupSignals(executors)
 		opts.Executors = executors
+
+		opts.TreeCacheTTL = settings.TreeCache.ParsedTTL()
+		opts.DisableTreeCache = settings.TreeCache != nil && settings.TreeCache.Disabled
 	}
 
 	return tui.Run(opts)
 }
 
+// runBrowseOnce prints the --once pane's content to stdout, from the local
+// tmux server or the single --remote host, without starting the TUI.
+func runBrowseOnce() error {
+	if onceLines < 0 {
+		return fmt.Errorf("--lines must not be negative")
+	}
+	var executor tmux.TmuxExecutor = tmux.NewLocalExecutor()
+	if browseRemote == "" {
+		if err := tmux.CheckInstalled(); err != nil {
+			return err
+		}
+	} else {
+		executors, err := buildExecutors(browseRemote)
+		if err != nil {
+			return fmt.Errorf("failed to build executors: %w", err)
+		}
+		defer closeExecutors(executors)
+		// executors[0] is the local server
+		if len(executors) != 2 {
+			return fmt.Errorf("--once captures from one host, but --remote named %d", len(executors)-1)
+		}
+		executor = executors[1]
+	}
+
+	content, err := tmux.CaptureScrollbackWithExecutor(onceTarget, onceLines, onceEscapes, executor)
+	if err != nil {
+		return fmt.Errorf("failed to capture %s: %w", onceTarget, err)
+	}
+	// Drop the blank rows below the last output on a mostly empty screen
+	fmt.Println(strings.TrimRight(content, "\n"))
+	return nil
+}
+
 func tmuxServerRunning() bool {
 	cmd := exec.Command("tmux", "list-sessions")
 	return cmd.Run() == nil
@@ -3,6 +3,8 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"time"
 
@@ -36,6 +38,27 @@ var historyRemoveCmd = &cobra.Command{
 	RunE:  runHistoryRemove,
 }
 
+var historyExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export session history as JSON",
+	Long:  "Write all session history entries as JSON to a file, or to stdout when no file is given.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHistoryExport,
+}
+
+var historyImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import session history from a JSON export",
+	Long: `Merge entries from a file written by 'atmux history export' into the local
+history. Use - to read from stdin.
+
+Entries are matched on session name, working directory, and host; when an
+entry exists on both sides, the most recently used one is kept. Malformed
+rows are skipped and reported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryImport,
+}
+
 var historyJSON bool
 var historyHidePaths bool
 
@@ -44,6 +67,8 @@ func init() {
 	historyCmd.AddCommand(historyListCmd)
 	historyCmd.AddCommand(historyClearCmd)
 	historyCmd.AddCommand(historyRemoveCmd)
+	historyCmd.AddCommand(historyExportCmd)
+	historyCmd.AddCommand(historyImportCmd)
 
 	historyListCmd.Flags().BoolVar(&historyJSON, "json", false, "Output as JSON")
 	historyListCmd.Flags().BoolVar(&historyHidePaths, "hide-paths", false, hidePathsHelpText)
@@ -138,6 +163,66 @@ func runHistoryRemove(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	store, err := history.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer store.Close()
+
+	if len(args) == 0 {
+		if err := store.ExportJSON(cmd.OutOrStdout()); err != nil {
+			return fmt.Errorf("failed to export history: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := store.ExportJSON(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to export history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported history to %s.\n", args[0])
+	return nil
+}
+
+func runHistoryImport(cmd *cobra.Command, args []string) error {
+	var in io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	store, err := history.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer store.Close()
+
+	result, err := store.ImportJSON(in)
+	if err != nil {
+		return fmt.Errorf("failed to import history: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported history: %d added, %d updated, %d kept (local was newer)",
+		result.Added, result.Updated, result.Kept)
+	if result.Skipped > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), ", %d malformed skipped", result.Skipped)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), ".")
+	return nil
+}
+
 func timeAgo(t time.Time) string {
 	d := time.Since(t)
 
@@ -4,10 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
-	"regexp"
 	"strings"
 
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
 	"github.com/spf13/cobra"
 )
 
@@ -19,22 +19,31 @@ var (
 
 var keybindCmd = &cobra.Command{
 	Use:   "keybind",
-	Short: "Add a tmux keybinding for the session browser popup",
-	Long: `Adds a keybinding to ~/.tmux.conf that opens the atmux session browser.
+	Short: "Add a tmux keybinding that opens atmux",
+	Long: `Adds a keybinding to ~/.tmux.conf that opens an atmux screen.
 
-By default, binds prefix + S to open the sessions popup.
-The binding can be customized with --key.
+By default, binds prefix + S to open the session browser. Choose the key with
+--key and the screen with --command (browse, sessions, or landing); without
+flags the choice saved in settings ("atmux settings") is used, and each
+install is saved as the new default.
+
+Bindings are written between "# >>> atmux keybindings" and
+"# <<< atmux keybindings" comment markers. Re-binding a key replaces the
+managed line for it, and "atmux keybind remove" deletes the whole block.
+Keys already bound elsewhere in ~/.tmux.conf are reported before adding.
 
 Examples:
-  atmux keybind              # Adds: bind-key S run-shell "atmux browse"
-  atmux keybind --key T      # Adds: bind-key T run-shell "atmux browse"
-  atmux keybind --key C-s    # Adds: bind-key C-s run-shell "atmux browse"
-  atmux keybind -y           # Skip confirmation
+  atmux keybind                       # Adds: bind-key S run-shell "atmux browse"
+  atmux keybind --key T               # Adds: bind-key T run-shell "atmux browse"
+  atmux keybind --command sessions    # Adds: bind-key S run-shell "atmux sessions -p"
+  atmux keybind --command landing     # Adds: bind-key S display-popup -E "atmux landing"
+  atmux keybind -y                    # Skip confirmation
 
 Subcommands:
   atmux keybind show         # Print the keybinding snippet (ready to copy-paste)
+  atmux keybind remove       # Remove the atmux-managed keybindings
 
-After adding the keybinding, reload your tmux config:
+After changing keybindings, reload your tmux config:
   tmux source-file ~/.tmux.conf`,
 	RunE: runKeybind,
 }
@@ -51,73 +60,95 @@ Examples:
   atmux keybind show                  # Show default binding (prefix + S)
   atmux keybind show --key T          # Show binding for prefix + T
   atmux keybind show --command sessions  # Show binding for sessions command`,
-	Run: runKeybindShow,
+	RunE: runKeybindShow,
+}
+
+var keybindRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the atmux-managed keybindings from ~/.tmux.conf",
+	Long: `Removes the block of keybindings atmux manages in ~/.tmux.conf, leaving
+the rest of the file untouched. Bindings added by hand or by older atmux
+versions (outside the markers) are not removed.`,
+	RunE: runKeybindRemove,
 }
 
 func init() {
 	rootCmd.AddCommand(keybindCmd)
 	keybindCmd.Flags().StringVarP(&keybindKey, "key", "k", "S", "Key to bind (e.g., S, C-s, M-s)")
 	keybindCmd.Flags().BoolVarP(&keybindYes, "yes", "y", false, "Skip confirmation prompt")
-	keybindCmd.Flags().StringVar(&keybindCommand, "command", "browse", "Command to run (browse or sessions)")
+	keybindCmd.Flags().StringVar(&keybindCommand, "command", "browse", "Screen to open (browse, sessions, or landing)")
 
 	// Add show subcommand
 	keybindCmd.AddCommand(keybindShowCmd)
 	keybindShowCmd.Flags().StringVarP(&keybindKey, "key", "k", "S", "Key to bind (e.g., S, C-s, M-s)")
-	keybindShowCmd.Flags().StringVar(&keybindCommand, "command", "browse", "Command to run (browse or sessions)")
+	keybindShowCmd.Flags().StringVar(&keybindCommand, "command", "browse", "Screen to open (browse, sessions, or landing)")
+
+	keybindCmd.AddCommand(keybindRemoveCmd)
+	keybindRemoveCmd.Flags().BoolVarP(&keybindYes, "yes", "y", false, "Skip confirmation prompt")
 }
 
-func runKeybindShow(cmd *cobra.Command, args []string) {
-	// Validate command
-	if keybindCommand != "browse" && keybindCommand != "sessions" {
-		fmt.Fprintf(os.Stderr, "Error: --command must be 'browse' or 'sessions'\n")
-		os.Exit(1)
+// resolveKeybind returns the key and target to bind: flags when given,
+// otherwise the saved settings.
+func resolveKeybind(cmd *cobra.Command, settings *config.Settings) (key, target string) {
+	key, target = settings.KeybindKey(), settings.KeybindTarget()
+	if cmd.Flags().Changed("key") {
+		key = keybindKey
 	}
+	if cmd.Flags().Changed("command") {
+		target = keybindCommand
+	}
+	return key, target
+}
 
-	// Build the binding line
-	bindingLine := fmt.Sprintf("bind-key %s run-shell \"atmux %s\"", keybindKey, keybindCommand)
-	commentLine := "# atmux: open session browser popup"
+func runKeybindShow(cmd *cobra.Command, args []string) error {
+	settings, _ := config.LoadSettings()
+	key, target := resolveKeybind(cmd, settings)
+	bindingLine, err := tmux.KeybindLine(key, target)
+	if err != nil {
+		return err
+	}
 
 	fmt.Println("# Add this to ~/.tmux.conf:")
-	fmt.Println(commentLine)
+	fmt.Println(tmux.KeybindBlockStart)
 	fmt.Println(bindingLine)
+	fmt.Println(tmux.KeybindBlockEnd)
 	fmt.Println()
 	fmt.Println("# Then reload your config:")
 	fmt.Println("# tmux source-file ~/.tmux.conf")
-	fmt.Printf("#\n# Press prefix + %s to open the session browser.\n", keybindKey)
+	fmt.Printf("#\n# Press prefix + %s to open atmux %s.\n", key, target)
+	return nil
 }
 
 func runKeybind(cmd *cobra.Command, args []string) error {
-	// Get tmux config path
-	home, err := os.UserHomeDir()
+	tmuxConfPath, err := tmux.TmuxConfPath()
 	if err != nil {
-		return fmt.Errorf("could not determine home directory: %w", err)
+		return err
 	}
-	tmuxConfPath := filepath.Join(home, ".tmux.conf")
-
-	// Validate command
-	if keybindCommand != "browse" && keybindCommand != "sessions" {
-		return fmt.Errorf("--command must be 'browse' or 'sessions'")
+	settings, _ := config.LoadSettings()
+	key, target := resolveKeybind(cmd, settings)
+	bindingLine, err := tmux.KeybindLine(key, target)
+	if err != nil {
+		return err
 	}
 
-	// Build the binding line
-	bindingLine := fmt.Sprintf("bind-key %s run-shell \"atmux %s\"", keybindKey, keybindCommand)
-	commentLine := "# atmux: open session browser popup"
-	fullBinding := fmt.Sprintf("\n%s\n%s\n", commentLine, bindingLine)
-
 	// Read existing config (if any)
 	existingContent := ""
-	if _, err := os.Stat(tmuxConfPath); err == nil {
-		content, err := os.ReadFile(tmuxConfPath)
-		if err != nil {
-			return fmt.Errorf("could not read %s: %w", tmuxConfPath, err)
-		}
+	if content, err := os.ReadFile(tmuxConfPath); err == nil {
 		existingContent = string(content)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("could not read %s: %w", tmuxConfPath, err)
 	}
 
-	// Check for duplicate bindings
-	duplicateKey, duplicateLine := findDuplicateBinding(existingContent, keybindKey)
-	if duplicateKey {
-		fmt.Printf("Warning: Key '%s' is already bound in %s:\n", keybindKey, tmuxConfPath)
+	// Check if exact binding already exists
+	if strings.Contains(existingContent, bindingLine) {
+		fmt.Printf("Binding already exists in %s:\n", tmuxConfPath)
+		fmt.Printf("  %s\n", bindingLine)
+		return saveKeybindSettings(settings, key, target)
+	}
+
+	// Check for bindings atmux doesn't manage (managed ones are replaced)
+	if duplicate, duplicateLine := tmux.FindUnmanagedKeybinding(existingContent, key); duplicate {
+		fmt.Printf("Warning: Key '%s' is already bound in %s:\n", key, tmuxConfPath)
 		fmt.Printf("  %s\n\n", duplicateLine)
 		if !keybindYes {
 			fmt.Print("Do you want to add this binding anyway? [y/N] ")
@@ -128,16 +159,8 @@ func runKeybind(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Check if exact binding already exists
-	if strings.Contains(existingContent, bindingLine) {
-		fmt.Printf("Binding already exists in %s:\n", tmuxConfPath)
-		fmt.Printf("  %s\n", bindingLine)
-		return nil
-	}
-
 	// Show what we'll add and confirm
-	fmt.Printf("Will add to %s:\n", tmuxConfPath)
-	fmt.Printf("  %s\n", commentLine)
+	fmt.Printf("Will add to the atmux keybindings in %s:\n", tmuxConfPath)
 	fmt.Printf("  %s\n\n", bindingLine)
 
 	if !keybindYes {
@@ -148,34 +171,68 @@ func runKeybind(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Append to file
-	f, err := os.OpenFile(tmuxConfPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	err = tmux.UpdateTmuxConf(tmuxConfPath, func(content string) string {
+		return tmux.AddManagedKeybinding(content, bindingLine)
+	})
 	if err != nil {
-		return fmt.Errorf("could not open %s for writing: %w", tmuxConfPath, err)
-	}
-	defer f.Close()
-
-	if _, err := f.WriteString(fullBinding); err != nil {
-		return fmt.Errorf("could not write to %s: %w", tmuxConfPath, err)
+		return err
 	}
 
 	fmt.Printf("\n✓ Keybinding added to %s\n", tmuxConfPath)
 	fmt.Println("\nTo activate, run:")
 	fmt.Println("  tmux source-file ~/.tmux.conf")
-	fmt.Printf("\nThen press prefix + %s to open the session browser.\n", keybindKey)
+	fmt.Printf("\nThen press prefix + %s to open atmux %s.\n", key, target)
 
+	return saveKeybindSettings(settings, key, target)
+}
+
+func runKeybindRemove(cmd *cobra.Command, args []string) error {
+	tmuxConfPath, err := tmux.TmuxConfPath()
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(tmuxConfPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read %s: %w", tmuxConfPath, err)
+	}
+	if _, found := tmux.RemoveManagedKeybindings(string(content)); !found {
+		fmt.Printf("No atmux-managed keybindings in %s.\n", tmuxConfPath)
+		return nil
+	}
+
+	fmt.Printf("Will remove from %s:\n", tmuxConfPath)
+	for _, line := range tmux.ManagedKeybindings(string(content)) {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+	if !keybindYes {
+		fmt.Print("Proceed? [Y/n] ")
+		if !confirmPromptDefault(true) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	err = tmux.UpdateTmuxConf(tmuxConfPath, func(content string) string {
+		updated, _ := tmux.RemoveManagedKeybindings(content)
+		return updated
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n✓ Keybindings removed from %s\n", tmuxConfPath)
+	fmt.Println("\nReload your tmux config (or restart tmux) for the keys to be unbound:")
+	fmt.Println("  tmux source-file ~/.tmux.conf")
 	return nil
 }
 
-// findDuplicateBinding checks if the key is already bound in the config
-func findDuplicateBinding(content, key string) (bool, string) {
-	// Match bind-key or bind followed by the key
-	pattern := regexp.MustCompile(`(?m)^\s*bind(?:-key)?\s+` + regexp.QuoteMeta(key) + `\s+.*$`)
-	match := pattern.FindString(content)
-	if match != "" {
-		return true, strings.TrimSpace(match)
+// saveKeybindSettings records key and target as the default keybinding.
+func saveKeybindSettings(settings *config.Settings, key, target string) error {
+	settings.Keybind = &config.KeybindConfig{Key: key, Target: target}
+	if err := settings.Save(); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
 	}
-	return false, ""
+	return nil
 }
 
 // confirmPrompt asks for y/n with default no
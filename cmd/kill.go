@@ -26,6 +26,10 @@ func init() {
 }
 
 func runKill(cmd *cobra.Command, args []string) error {
+	if err := tmux.CheckInstalled(); err != nil {
+		return err
+	}
+
 	if killAll {
 		return killAllSessions()
 	}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/porganisciak/agent-tmux/tmux"
+	"github.com/porganisciak/agent-tmux/tui"
 	"github.com/spf13/cobra"
 )
 
@@ -20,11 +21,18 @@ func init() {
 }
 
 func runLandingCmd(cmd *cobra.Command, args []string) error {
+	if err := tmux.CheckInstalled(); err != nil {
+		return err
+	}
+	return runScreens(&tui.ScreenSwitch{To: tui.ScreenLanding})
+}
+
+// landingScreen shows the landing page for the current directory, selecting
+// the local session named selected if there is one.
+func landingScreen(selected string) (*tui.ScreenSwitch, error) {
 	workingDir, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
 	}
-
-	session := tmux.NewSession(workingDir)
-	return runLandingPage(session, workingDir)
+	return runLandingPage(tmux.NewSession(workingDir), workingDir, selected)
 }
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/spf13/cobra"
+)
+
+var lintGlobal bool
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [config-file]",
+	Short: "Check a config file for mistakes",
+	Long: `Check an atmux config file for common mistakes without starting tmux.
+
+Lints ./.agent-tmux.conf by default, the global config with --global, or the
+given file. Findings are printed as path:line: severity: message. Warnings
+(e.g. a pane before any window, an unknown directive) don't fail the lint;
+errors (e.g. an agent with no command, an invalid directive value) exit
+non-zero.`,
+	Args:         cobra.MaximumNArgs(1),
+	RunE:         runLint,
+	SilenceUsage: true, // Lint failures are findings, not usage mistakes
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolVarP(&lintGlobal, "global", "g", false, "Lint the global config (~/.config/atmux/config)")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	globalPath, err := config.GlobalConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get global config path: %w", err)
+	}
+
+	path := filepath.Join(".", config.DefaultConfigName)
+	switch {
+	case len(args) > 0:
+		path = args[0]
+	case lintGlobal:
+		path = globalPath
+	}
+	if !config.Exists(path) {
+		return fmt.Errorf("config file %s not found", path)
+	}
+
+	out := cmd.OutOrStdout()
+	cfg, err := config.Parse(path)
+	if err != nil {
+		// Parse stops at the first invalid directive
+		fmt.Fprintf(out, "%v\n", err)
+		return fmt.Errorf("%s has errors", path)
+	}
+
+	// Remote projects may refer to hosts defined in the global config
+	if path != globalPath && config.Exists(globalPath) {
+		if global, err := config.Parse(globalPath); err == nil {
+			cfg.RemoteHosts = append(global.RemoteHosts, cfg.RemoteHosts...)
+		}
+	}
+
+	findings := cfg.Validate()
+	for _, f := range findings {
+		fmt.Fprintln(out, f)
+	}
+	if config.HasErrors(findings) {
+		return fmt.Errorf("%s has errors", path)
+	}
+	if len(findings) == 0 {
+		fmt.Fprintf(out, "%s: no problems found\n", path)
+	}
+	return nil
+}
@@ -27,6 +27,10 @@ func init() {
 }
 
 func runOpen(cmd *cobra.Command, args []string) error {
+	if err := tmux.CheckInstalled(); err != nil {
+		return err
+	}
+
 	// Default to popup when inside tmux
 	insideTmux := os.Getenv("TMUX") != ""
 	if insideTmux && !openNoPopup {
@@ -44,8 +48,7 @@ func runOpen(cmd *cobra.Command, args []string) error {
 
 	if result.IsFromHistory {
 		// Revival from history
-		session := tmux.NewSession(result.WorkingDir)
-		return runDirectAttach(session, result.WorkingDir)
+		return runDirectAttach(result.WorkingDir, "")
 	}
 
 	// Attach to existing session
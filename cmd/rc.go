@@ -65,13 +65,14 @@ func runRC(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		for _, rh := range remoteHosts {
-			executors = append(executors, tmux.NewRemoteExecutor(
-				rh.Host, rh.Port, rh.AttachMethod, rh.Alias,
-			))
+			executors = append(executors, newRemoteExecutor(rh))
 		}
 		// Also include local
 		executors = append([]tmux.TmuxExecutor{tmux.NewLocalExecutor()}, executors...)
 	} else {
+		if err := tmux.CheckInstalled(); err != nil {
+			return err
+		}
 		executors = []tmux.TmuxExecutor{tmux.NewLocalExecutor()}
 	}
 	defer closeExecutors(executors)
@@ -128,9 +129,7 @@ func runRC(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("  Sending to %s ... ", cp.Label())
-		err := tmux.SendCommandWithMethodAndExecutor(
-			cp.Pane.Target, name, tmux.SendMethodEnterDelayed, cp.Executor,
-		)
+		err := tmux.SendAgentCommandWithExecutor(cp.Pane.Target, name, cp.Executor)
 		if err != nil {
 			fmt.Printf("error: %v\n", err)
 		} else {
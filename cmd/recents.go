@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/config"
 	"github.com/porganisciak/agent-tmux/history"
 	"github.com/porganisciak/agent-tmux/tmux"
 	"github.com/porganisciak/agent-tmux/tui"
@@ -71,14 +72,15 @@ func runRecents(cmd *cobra.Command, args []string) error {
 
 	// Remote session revival - reattach via the appropriate executor
 	if result.Host != "" {
-		executor := tmux.NewRemoteExecutor(result.Host, 0, result.AttachMethod, result.Host)
+		executor := newRemoteExecutor(config.RemoteHostConfig{
+			Host: result.Host, AttachMethod: result.AttachMethod, Alias: result.Host,
+		})
 		defer executor.Close()
 		return tmux.AttachToSessionWithExecutor(result.SessionName, executor)
 	}
 
 	// Local session revival - create new session in that directory
-	session := tmux.NewSession(result.WorkingDir)
-	return runDirectAttach(session, result.WorkingDir)
+	return runDirectAttach(result.WorkingDir, "")
 }
 
 func runRecentsList(cmd *cobra.Command) error {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -13,25 +14,42 @@ import (
 // buildExecutors builds a list of TmuxExecutors from config and --remote flag.
 // The local executor is always first. Remote executors follow.
 func buildExecutors(remoteFlag string) ([]tmux.TmuxExecutor, error) {
-	executors := []tmux.TmuxExecutor{tmux.NewLocalExecutor()}
-
 	cfg, err := loadRemoteConfig()
 	if err != nil {
 		return nil, err
 	}
+	return executorsForConfig(cfg, remoteFlag)
+}
+
+// executorsForConfig is buildExecutors for an already loaded config.
+func executorsForConfig(cfg *config.Config, remoteFlag string) ([]tmux.TmuxExecutor, error) {
+	executors := []tmux.TmuxExecutor{tmux.NewLocalExecutor()}
 	remoteHosts, err := config.ResolveRemoteHosts(cfg, remoteFlag, true)
 	if err != nil {
 		return nil, err
 	}
 	for _, rh := range remoteHosts {
-		executors = append(executors, tmux.NewRemoteExecutor(
-			rh.Host, rh.Port, rh.AttachMethod, rh.Alias,
-		))
+		executors = append(executors, newRemoteExecutor(rh))
 	}
 
 	return executors, nil
 }
 
+// newRemoteExecutor creates the executor for a resolved remote host, with
+// the per-command timeout, connection sharing, and fetch concurrency from
+// settings.
+func newRemoteExecutor(rh config.RemoteHostConfig) *tmux.RemoteExecutor {
+	executor := tmux.NewRemoteExecutor(rh.Host, rh.Port, rh.AttachMethod, rh.Alias)
+	settings, _ := config.LoadSettings()
+	executor.Timeout = settings.ParsedRemoteTimeout()
+	if settings != nil {
+		executor.NoMultiplex = !settings.SSHMultiplex.IsEnabled()
+		executor.ControlPersist = settings.SSHMultiplex.ParsedPersist()
+	}
+	tmux.SetRemoteFetchLimit(settings.EffectiveRemoteConcurrency())
+	return executor
+}
+
 // loadRemoteConfig loads remote host config from global and local configs.
 func loadRemoteConfig() (*config.Config, error) {
 	localPath := filepath.Join(".", config.DefaultConfigName)
@@ -42,6 +60,9 @@ func loadRemoteConfig() (*config.Config, error) {
 		}
 		return &config.Config{}, nil
 	}
+	for _, warning := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
 	return cfg, nil
 }
 
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porganisciak/agent-tmux/tmux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remoteCheckRemote  string
+	remoteCheckTimeout time.Duration
+	remoteCheckJobs    int
+)
+
+var remoteCheckCmd = &cobra.Command{
+	Use:     "remote-check",
+	Aliases: []string{"ping"},
+	Short:   "Check that remote hosts are reachable and running tmux",
+	Long: `Check each configured remote host (plus any given with --remote) by running
+'tmux -V' over SSH, and report whether it answered, its tmux version and how
+long it took. Hosts that attach with mosh are also checked for mosh-server.
+
+Exits non-zero if any host is unreachable, so it can gate scripts.
+
+Examples:
+  atmux remote-check
+  atmux remote-check --remote=devbox,user@build --timeout=3s`,
+	Args:         cobra.NoArgs,
+	RunE:         runRemoteCheck,
+	SilenceUsage: true, // Unreachable hosts are findings, not usage mistakes
+}
+
+func init() {
+	rootCmd.AddCommand(remoteCheckCmd)
+	remoteCheckCmd.Flags().StringVar(&remoteCheckRemote, "remote", "",
+		"Remote host(s) or aliases to check (comma-separated; default: all configured)")
+	remoteCheckCmd.Flags().DurationVar(&remoteCheckTimeout, "timeout", tmux.DefaultProbeTimeout,
+		"How long to wait for each host")
+	remoteCheckCmd.Flags().IntVarP(&remoteCheckJobs, "jobs", "j", tmux.DefaultProbeConcurrency,
+		"How many hosts to check at once")
+}
+
+func runRemoteCheck(cmd *cobra.Command, args []string) error {
+	executors, err := buildExecutors(remoteCheckRemote)
+	if err != nil {
+		return fmt.Errorf("failed to build executors: %w", err)
+	}
+	defer closeExecutors(executors)
+	registerCleanupSignals(executors)
+
+	// executors[0] is the local server
+	remotes := executors[1:]
+	if len(remotes) == 0 {
+		return fmt.Errorf("no remote hosts configured; add remote_host entries or pass --remote")
+	}
+
+	results := tmux.ProbeHosts(remotes, remoteCheckJobs, remoteCheckTimeout)
+	fmt.Fprint(cmd.OutOrStdout(), formatHostHealth(results))
+
+	down := 0
+	for _, h := range results {
+		if !h.Reachable() {
+			down++
+		}
+	}
+	if down > 0 {
+		return fmt.Errorf("%d of %d hosts unreachable", down, len(results))
+	}
+	return nil
+}
+
+// formatHostHealth renders probe results as an aligned table, one host per
+// line.
+func formatHostHealth(results []tmux.HostHealth) string {
+	hostWidth := len("HOST")
+	for _, h := range results {
+		hostWidth = max(hostWidth, len(h.Host))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s  %-6s  %-7s  %s\n", hostWidth, "HOST", "STATUS", "LATENCY", "DETAILS")
+	for _, h := range results {
+		status, latency, details := "ok", tmux.FormatLatency(h.Latency), h.Version
+		if !h.Reachable() {
+			status, latency, details = "down", "-", tmux.FetchErrorReason(h.Err)
+		}
+		if h.Note != "" {
+			details += " (" + h.Note + ")"
+		}
+		fmt.Fprintf(&b, "%-*s  %-6s  %-7s  %s\n", hostWidth, h.Host, status, latency, details)
+	}
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestFormatHostHealth(t *testing.T) {
+	got := formatHostHealth([]tmux.HostHealth{
+		{Host: "devbox", Version: "tmux 3.4", Latency: 42 * time.Millisecond},
+		{Host: "build-server", Version: "tmux 3.2a", Latency: 1200 * time.Millisecond, Note: "mosh-server not found"},
+		{Host: "old", Err: context.DeadlineExceeded},
+	})
+	want := strings.Join([]string{
+		"HOST          STATUS  LATENCY  DETAILS",
+		"devbox        ok      42ms     tmux 3.4",
+		"build-server  ok      1.2s     tmux 3.2a (mosh-server not found)",
+		"old           down    -        timed out",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
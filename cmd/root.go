@@ -13,6 +13,7 @@ import (
 )
 
 var resetDefaults bool
+var noHistory bool
 
 var rootCmd = &cobra.Command{
 	Use:   "atmux",
@@ -28,6 +29,8 @@ It creates a session with an 'agents' window configured via:
 func init() {
 	rootCmd.Flags().BoolVar(&resetDefaults, "reset-defaults", false,
 		"Reset default startup behavior to show landing page")
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false,
+		"Don't record sessions opened by this command in history")
 }
 
 func Execute() {
@@ -46,6 +49,10 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if err := tmux.CheckInstalled(); err != nil {
+		return err
+	}
+
 	// Get working directory
 	workingDir, err := os.Getwd()
 	if err != nil {
@@ -59,36 +66,48 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	settings, _ := config.LoadSettings()
 	switch settings.DefaultAction {
 	case "resume":
-		return runDirectAttach(session, workingDir)
+		return runDirectAttach(workingDir, "")
 	case "sessions":
 		result, err := tui.RunSessionsList(tui.SessionsOptions{AltScreen: false})
 		if err != nil {
 			return err
 		}
+		if result.Switch != nil {
+			return runScreens(result.Switch)
+		}
 		if result.SessionName == "" {
 			return nil
 		}
 		if result.IsFromHistory {
 			// Revival from history
-			histSession := tmux.NewSession(result.WorkingDir)
-			return runDirectAttach(histSession, result.WorkingDir)
+			return runDirectAttach(result.WorkingDir, "")
 		}
 		if sessionPath := tmux.GetSessionPath(result.SessionName); sessionPath != "" {
 			saveHistory(filepath.Base(sessionPath), sessionPath, result.SessionName, "", "")
 		}
+		restoreFocus(result.SessionName)
+		if result.ReadOnly {
+			return tmux.AttachReadOnly(result.SessionName)
+		}
 		return tmux.AttachToSession(result.SessionName)
 	default: // "landing" or empty
-		return runLandingPage(session, workingDir)
+		sw, err := runLandingPage(session, workingDir, "")
+		if err != nil {
+			return err
+		}
+		return runScreens(sw)
 	}
 }
 
-// runDirectAttach performs the original behavior: create/attach directly
-func runDirectAttach(session *tmux.Session, workingDir string) error {
-	// Check if session already exists
-	if session.Exists() {
+// runDirectAttach performs the original behavior: create/attach directly.
+// A non-empty template selects a named session template for a new session.
+func runDirectAttach(workingDir, template string) error {
+	// An existing session is attached as is, whatever its config says now
+	if session := tmux.NewSession(workingDir); session.Exists() {
 		fmt.Printf("Attaching to existing session: %s\n", session.Name)
+		restoreFocus(session.Name)
 		saveHistory(filepath.Base(workingDir), workingDir, session.Name, "", "")
-		return session.Attach()
+		return tmux.AttachToSession(session.Name)
 	}
 
 	// Load merged config (global + local)
@@ -98,29 +117,35 @@ func runDirectAttach(session *tmux.Session, workingDir string) error {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
 		cfg = nil
 	}
+	if template != "" {
+		if cfg, err = cfg.WithTemplate(template); err != nil {
+			return err
+		}
+	}
 
-	// Create new session with agent config
-	fmt.Printf("Creating new session: %s\n", session.Name)
-	if err := session.Create(cfg); err != nil {
+	session, err := tmux.EnsureSession(workingDir, cfg)
+	if err != nil {
 		return err
 	}
+	saveHistory(filepath.Base(workingDir), workingDir, session.Name, "", "")
+	return tmux.AttachToSession(session.Name)
+}
 
-	// Apply additional windows/panes from config
-	if cfg != nil {
-		if err := session.ApplyConfig(cfg); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to apply config: %v\n", err)
-		}
+// restoreFocus returns an existing session to the window or pane last
+// focused in it, when the restore_focus setting is on.
+func restoreFocus(session string) {
+	if settings, _ := config.LoadSettings(); settings != nil && settings.RestoreFocus {
+		tui.RestoreFocus(session)
 	}
-
-	// Save to history and attach
-	saveHistory(filepath.Base(workingDir), workingDir, session.Name, "", "")
-	session.SelectDefault()
-	return session.Attach()
 }
 
 // saveHistory saves a session to history, logging any errors.
 // host and attachMethod should be empty for local sessions.
 func saveHistory(name, workingDir, sessionName, host, attachMethod string) {
+	if noHistory || projectDisablesHistory(workingDir) {
+		return
+	}
+
 	store, err := history.Open()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to open history: %v\n", err)
@@ -133,29 +158,55 @@ func saveHistory(name, workingDir, sessionName, host, attachMethod string) {
 	}
 }
 
-// runLandingPage shows the interactive landing page
-func runLandingPage(session *tmux.Session, workingDir string) error {
+// projectDisablesHistory reports whether the config for workingDir sets
+// no_history:true.
+func projectDisablesHistory(workingDir string) bool {
+	if workingDir == "" {
+		return false
+	}
+	cfg, err := config.LoadConfig(filepath.Join(workingDir, config.DefaultConfigName))
+	return err == nil && cfg.NoHistory
+}
+
+// runLandingPage shows the interactive landing page, selecting the session
+// named selected if there is one, and acts on the choice. It returns the
+// screen the user switched to instead, if any.
+func runLandingPage(session *tmux.Session, workingDir, selected string) (*tui.ScreenSwitch, error) {
+	var templates []string
+	if cfg, err := config.LoadConfig(filepath.Join(workingDir, config.DefaultConfigName)); err == nil {
+		templates = cfg.TemplateNames()
+	}
 	result, err := tui.RunLanding(tui.LandingOptions{
 		SessionName: session.Name,
 		AltScreen:   false,
+		Templates:   templates,
+		Select:      selected,
 	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if result.Switch != nil {
+		return result.Switch, nil
 	}
+	return nil, attachLandingResult(result, workingDir)
+}
 
+// attachLandingResult resumes, attaches to, or revives what was chosen on
+// the landing page.
+func attachLandingResult(result *tui.LandingResult, workingDir string) error {
 	switch result.Action {
 	case "resume":
-		return runDirectAttach(session, workingDir)
+		return runDirectAttach(workingDir, result.Template)
 	case "attach":
 		// Save to history before attaching to another session
 		if sessionPath := tmux.GetSessionPath(result.Target); sessionPath != "" {
 			saveHistory(filepath.Base(sessionPath), sessionPath, result.Target, "", "")
 		}
+		restoreFocus(result.Target)
 		return tmux.AttachToSession(result.Target)
 	case "revive":
 		// Revival from history - create session in the saved working directory
-		histSession := tmux.NewSession(result.WorkingDir)
-		return runDirectAttach(histSession, result.WorkingDir)
+		return runDirectAttach(result.WorkingDir, "")
 	default:
 		// User quit without action
 		return nil
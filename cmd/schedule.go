@@ -1,6 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
 	"github.com/porganisciak/agent-tmux/tui"
 	"github.com/spf13/cobra"
 )
@@ -19,15 +30,114 @@ Controls:
   a               Add new job
   e               Toggle enabled/disabled
   d/x             Delete selected job
+  h               Show/hide recent runs of the selected job
   q/Esc           Quit
 
-Note: The scheduler daemon must be running for jobs to execute.
-Use 'atmux schedule daemon' to start the background scheduler.`,
+A job can target one pane or "All agent panes", which sends to every pane
+running a configured agent command (agent: directives, or the defaults),
+found each time the job runs. Jobs whose pane has since been killed are
+flagged in the list and skipped when due, unless they use the new-session
+pre-action, which recreates the session first.
+
+Commands may include time tokens, expanded when the job is sent using Go
+time layouts: {{now:15:04}} and {{date:2006-01-02}}. Unknown tokens are sent
+as written.
+
+Schedules run on local time unless a job sets a timezone (an IANA name such
+as UTC or Europe/Berlin). Across DST changes, a time of day skipped when
+clocks spring forward runs right after the jump, and one repeated when
+clocks fall back runs once.
+
+Runs missed while the machine was asleep or off are skipped by default. Set
+a job's missed-runs option to "Run once" to send it once as soon as possible
+instead, however many runs were missed.
+
+To manage jobs from scripts, use the add, list, remove, and toggle
+subcommands instead.
+
+Note: Jobs only run while the scheduler is running. Start it with
+'atmux schedule daemon', e.g. in its own tmux window.`,
 	RunE: runSchedule,
 }
 
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a scheduled job without the TUI",
+	Long: `Add a scheduled job from the command line. The job is validated the
+same way as in the TUI before it is saved, and its ID is printed.
+
+Examples:
+  atmux schedule add --cron "0 9 * * 1-5" --target api:0.1 --command "git pull"
+  atmux schedule add --cron "*/30 * * * *" --all-agents --command "/compact" --name compact`,
+	Args: cobra.NoArgs,
+	RunE: runScheduleAdd,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled jobs as JSON",
+	Args:  cobra.NoArgs,
+	RunE:  runScheduleList,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a scheduled job",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScheduleRemove,
+}
+
+var scheduleToggleCmd = &cobra.Command{
+	Use:   "toggle <id>",
+	Short: "Enable or disable a scheduled job",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScheduleToggle,
+}
+
+var scheduleDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run scheduled jobs as they come due",
+	Long: `Runs in the foreground, sending each enabled job when it comes due,
+until interrupted. The schedule is reread every minute, so jobs added or
+changed in the TUI or with the subcommands are picked up. Run one scheduler
+at a time, e.g. in its own tmux window or as a login service.`,
+	Args: cobra.NoArgs,
+	RunE: runScheduleDaemon,
+}
+
+var (
+	scheduleAddCron      string
+	scheduleAddTarget    string
+	scheduleAddAllAgents bool
+	scheduleAddCommand   string
+	scheduleAddName      string
+	scheduleAddPreAction string
+	scheduleAddCatchUp   string
+	scheduleAddSendAs    string
+	scheduleAddTimezone  string
+	scheduleAddDisabled  bool
+)
+
 func init() {
 	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleToggleCmd)
+	scheduleCmd.AddCommand(scheduleDaemonCmd)
+
+	scheduleAddCmd.Flags().StringVar(&scheduleAddCron, "cron", "", "5-field cron expression (required)")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddTarget, "target", "", "Tmux target to send to, e.g. session:window.pane")
+	scheduleAddCmd.Flags().BoolVar(&scheduleAddAllAgents, "all-agents", false, "Send to every pane running a configured agent instead of one target")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddCommand, "command", "", "Command to send (required)")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddName, "name", "", "Optional name for the job")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddPreAction, "pre-action", string(config.PreActionNone), "What to do before sending: none, compact, or new_session")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddCatchUp, "catch-up", string(config.CatchUpSkip), "What to do about missed runs: skip or run_once")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddSendAs, "send-method", string(config.JobSendKeys), "How to put the command in the pane: keys, or bracketed_paste for multi-line text")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddTimezone, "timezone", "", "IANA timezone the cron expression is read in (default local time)")
+	scheduleAddCmd.Flags().BoolVar(&scheduleAddDisabled, "disabled", false, "Save the job disabled")
+	scheduleAddCmd.MarkFlagRequired("cron")    //nolint:errcheck
+	scheduleAddCmd.MarkFlagRequired("command") //nolint:errcheck
 }
 
 func runSchedule(cmd *cobra.Command, args []string) error {
@@ -35,3 +145,156 @@ func runSchedule(cmd *cobra.Command, args []string) error {
 		AltScreen: true,
 	})
 }
+
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	if scheduleAddAllAgents && scheduleAddTarget != "" {
+		return fmt.Errorf("use either --target or --all-agents, not both")
+	}
+	job := config.ScheduledJob{
+		Name:       scheduleAddName,
+		CronExpr:   scheduleAddCron,
+		Target:     scheduleAddTarget,
+		TargetMode: config.TargetModePane,
+		Command:    scheduleAddCommand,
+		PreAction:  config.PreAction(scheduleAddPreAction),
+		CatchUp:    config.CatchUpPolicy(scheduleAddCatchUp),
+		SendMethod: config.JobSendMethod(scheduleAddSendAs),
+		Timezone:   scheduleAddTimezone,
+		Enabled:    !scheduleAddDisabled,
+	}
+	if scheduleAddAllAgents {
+		job.TargetMode = config.TargetModeAllAgents
+	}
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	schedule, err := config.LoadSchedule()
+	if err != nil {
+		return fmt.Errorf("failed to load schedule: %w", err)
+	}
+	if err := schedule.AddJob(job); err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+	added := schedule.Jobs[len(schedule.Jobs)-1]
+	fmt.Fprintf(cmd.OutOrStdout(), "Added job %s: %s -> %s (%s)\n",
+		added.ID, config.CronToEnglish(added.CronExpr), added.TargetLabel(), added.FormatNextRun())
+	return nil
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	schedule, err := config.LoadSchedule()
+	if err != nil {
+		return fmt.Errorf("failed to load schedule: %w", err)
+	}
+	jobs := schedule.SortedJobs()
+	if jobs == nil {
+		jobs = []config.ScheduledJob{}
+	}
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return nil
+}
+
+func runScheduleRemove(cmd *cobra.Command, args []string) error {
+	schedule, err := config.LoadSchedule()
+	if err != nil {
+		return fmt.Errorf("failed to load schedule: %w", err)
+	}
+	if err := schedule.DeleteJob(args[0]); err != nil {
+		return fmt.Errorf("failed to remove job: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed job %s.\n", args[0])
+	return nil
+}
+
+func runScheduleToggle(cmd *cobra.Command, args []string) error {
+	schedule, err := config.LoadSchedule()
+	if err != nil {
+		return fmt.Errorf("failed to load schedule: %w", err)
+	}
+	if err := schedule.ToggleJob(args[0]); err != nil {
+		return fmt.Errorf("failed to toggle job: %w", err)
+	}
+	job, _ := schedule.GetJob(args[0])
+	state := "disabled"
+	if job.Enabled {
+		state = "enabled"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Job %s is now %s.\n", args[0], state)
+	return nil
+}
+
+func runScheduleDaemon(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	exec := tmux.NewLocalExecutor()
+	fmt.Fprintln(out, "Scheduler running. Press Ctrl+C to stop.")
+	for {
+		runDueJobs(out, time.Now(), exec)
+		nextMinute := time.Now().Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(nextMinute)):
+		}
+	}
+}
+
+// runDueJobs sends every job due at now through exec and marks it run,
+// reloading the schedule so edits made elsewhere are picked up.
+func runDueJobs(out io.Writer, now time.Time, exec tmux.TmuxExecutor) {
+	stamp := now.Format("15:04")
+	schedule, err := config.LoadSchedule()
+	if err != nil {
+		fmt.Fprintf(out, "%s failed to load schedule: %v\n", stamp, err)
+		return
+	}
+	var agents []config.AgentConfig
+	if cfg, err := config.LoadConfig(""); err == nil {
+		agents = cfg.CoreAgents
+	}
+	for _, job := range schedule.DueJobs(now) {
+		run := config.ScheduleRun{JobID: job.ID, At: now, Target: job.TargetLabel()}
+		targets, err := tmux.SendJob(job, now, agents, exec)
+		if len(targets) > 0 {
+			run.Target = strings.Join(targets, ", ")
+		}
+		if err != nil {
+			run.Error = err.Error()
+			fmt.Fprintf(out, "%s %s: %v\n", stamp, jobLabel(job), err)
+		} else {
+			fmt.Fprintf(out, "%s %s: sent to %s\n", stamp, jobLabel(job), run.Target)
+		}
+		if err := recordJobRun(run); err != nil {
+			fmt.Fprintf(out, "%s %s: %v\n", stamp, jobLabel(job), err)
+		}
+	}
+}
+
+// recordJobRun logs a run and updates its job's LastRunAt in a freshly
+// loaded schedule, so a send that took a while doesn't overwrite edits
+// saved meanwhile.
+func recordJobRun(run config.ScheduleRun) error {
+	schedule, err := config.LoadSchedule()
+	if err != nil {
+		return fmt.Errorf("failed to load schedule: %w", err)
+	}
+	if err := schedule.RecordRun(run); err != nil {
+		return fmt.Errorf("failed to record run: %w", err)
+	}
+	return nil
+}
+
+// jobLabel names a job in the scheduler's output.
+func jobLabel(job config.ScheduledJob) string {
+	if job.Name != "" {
+		return job.Name
+	}
+	return job.ID
+}
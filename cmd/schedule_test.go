@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
+	"github.com/spf13/cobra"
+)
+
+func TestScheduleSubcommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+
+	scheduleAddCron = "bogus"
+	scheduleAddTarget = "api:0.1"
+	scheduleAddCommand = "git pull"
+	scheduleAddPreAction = string(config.PreActionNone)
+	scheduleAddCatchUp = string(config.CatchUpSkip)
+	if err := runScheduleAdd(cmd, nil); err == nil {
+		t.Fatal("expected an invalid cron expression to be rejected")
+	}
+
+	scheduleAddCron = "0 9 * * 1-5"
+	if err := runScheduleAdd(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "Added job job_") {
+		t.Fatalf("unexpected add output %q", out.String())
+	}
+
+	out.Reset()
+	if err := runScheduleList(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	var jobs []config.ScheduledJob
+	if err := json.Unmarshal(out.Bytes(), &jobs); err != nil {
+		t.Fatalf("list output isn't JSON: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Target != "api:0.1" || jobs[0].Command != "git pull" || !jobs[0].Enabled {
+		t.Fatalf("unexpected jobs %+v", jobs)
+	}
+	id := jobs[0].ID
+
+	if err := runScheduleToggle(cmd, []string{id}); err != nil {
+		t.Fatal(err)
+	}
+	schedule, _ := config.LoadSchedule()
+	if job, _ := schedule.GetJob(id); job.Enabled {
+		t.Fatal("expected toggle to disable the job")
+	}
+
+	if err := runScheduleRemove(cmd, []string{id}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runScheduleRemove(cmd, []string{id}); err == nil {
+		t.Fatal("expected removing an unknown job to fail")
+	}
+	schedule, _ = config.LoadSchedule()
+	if len(schedule.Jobs) != 0 {
+		t.Fatalf("expected no jobs left, got %+v", schedule.Jobs)
+	}
+}
+
+// recordingExecutor records the tmux commands run through it, and answers
+// queries from outputs, keyed by tmux command (e.g. "list-panes").
+type recordingExecutor struct {
+	tmux.LocalExecutor
+	outputs map[string]string
+	runs    []string
+}
+
+func (r *recordingExecutor) Run(args ...string) error {
+	r.runs = append(r.runs, strings.Join(args, " "))
+	return nil
+}
+
+func (r *recordingExecutor) Output(args ...string) ([]byte, error) {
+	return []byte(r.outputs[args[0]]), nil
+}
+
+func TestRunDueJobsSendsAndRecordsRun(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	schedule := &config.Schedule{}
+	for _, job := range []config.ScheduledJob{
+		{ID: "a", Name: "pull", CronExpr: "* * * * *", Target: "api:0.1", Command: "git pull", Enabled: true},
+		{ID: "b", CronExpr: "* * * * *", Target: "api:0.2", Command: "ls"},
+	} {
+		if err := schedule.AddJob(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	exec := &recordingExecutor{outputs: map[string]string{
+		"list-sessions": "api:0:0\n",
+		"list-windows":  "@1:0:agents:1:0:0\n",
+		"list-panes":    "%1:0::claude:1:80:24\n%2:1::zsh:0:80:24\n",
+	}}
+	now := time.Date(2026, 3, 9, 9, 0, 10, 0, time.Local)
+	runDueJobs(&out, now, exec)
+	if want := []string{"send-keys -t api:0.1 git pull", "send-keys -t api:0.1 Enter"}; strings.Join(exec.runs, "|") != strings.Join(want, "|") {
+		t.Fatalf("runs = %q, want %q", exec.runs, want)
+	}
+	if !strings.Contains(out.String(), "pull: sent to api:0.1") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	schedule, _ = config.LoadSchedule()
+	if job, _ := schedule.GetJob("a"); !job.LastRunAt.Equal(now) {
+		t.Fatalf("expected the run to be marked, got %v", job.LastRunAt)
+	}
+	runs, _ := config.LoadScheduleRuns()
+	if len(runs) != 1 || runs[0].JobID != "a" || runs[0].Target != "api:0.1" || !runs[0].Succeeded() {
+		t.Fatalf("expected the run in the run log, got %+v", runs)
+	}
+	exec.runs = nil
+	runDueJobs(&out, now.Add(time.Second), exec)
+	if len(exec.runs) != 0 {
+		t.Fatalf("expected no second send in the same minute, got %q", exec.runs)
+	}
+}
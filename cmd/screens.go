@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/porganisciak/agent-tmux/tui"
+)
+
+// runScreens opens the screen sw asks for, and then whichever screen that
+// one switches to, until one of them attaches or quits. This lets the
+// landing page, sessions list, and browse hand over to each other (g then
+// l, s, or b) without going back to the shell. A nil sw does nothing.
+func runScreens(sw *tui.ScreenSwitch) error {
+	for sw != nil {
+		var err error
+		switch sw.To {
+		case tui.ScreenLanding:
+			// The landing page only lists local sessions
+			selected := sw.Session
+			if sw.Host != "" {
+				selected = ""
+			}
+			sw, err = landingScreen(selected)
+		case tui.ScreenSessions:
+			sw, err = sessionsScreen(sw.Session, sw.Host)
+		case tui.ScreenBrowse:
+			sw, err = browseScreen(sw.Session, sw.Host)
+		default:
+			return fmt.Errorf("unknown screen %q", sw.To)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
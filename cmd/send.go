@@ -29,6 +29,9 @@ Methods:
   - enter-delayed Send text, wait 500ms, then "Enter" (default)
   - enter-literal Send text with -l flag, then "Enter"
   - cm            Send text, then "C-m" separately
+  - bracketed-paste Paste text in one piece through a tmux buffer
+                  (bracketed when the program supports it), then "Enter",
+                  so multi-line text isn't submitted line by line
 
 Examples:
   atmux send agent-project:agents.0 "Take a beads task"
@@ -41,7 +44,7 @@ Examples:
 
 func init() {
 	sendCmd.Flags().StringVarP(&sendMethod, "method", "m", "enter-delayed",
-		"Send method: enter, enter-delayed, enter-literal, cm")
+		"Send method: enter, enter-delayed, enter-literal, cm, bracketed-paste")
 	sendCmd.Flags().StringVarP(&sendRemote, "remote", "r", "",
 		"Remote host(s) or aliases to send to (comma-separated)")
 	sendCmd.Flags().BoolVarP(&sendNoEnter, "no-enter", "n", false,
@@ -70,11 +73,12 @@ func runSend(cmd *cobra.Command, args []string) error {
 		}
 		// Use only remote executors specified by --remote flag.
 		for _, host := range remoteHosts {
-			executors = append(executors, tmux.NewRemoteExecutor(
-				host.Host, host.Port, host.AttachMethod, host.Alias,
-			))
+			executors = append(executors, newRemoteExecutor(host))
 		}
 	} else {
+		if err := tmux.CheckInstalled(); err != nil {
+			return err
+		}
 		// Use local executor
 		executors = []tmux.TmuxExecutor{tmux.NewLocalExecutor()}
 	}
@@ -88,7 +92,7 @@ func runSend(cmd *cobra.Command, args []string) error {
 		var err error
 		if sendNoEnter {
 			// Send text without Enter
-			err = exec.Run("send-keys", "-t", target, text)
+			err = exec.Run("send-keys", "-t", target, tmux.WrapCommand(text))
 		} else {
 			// Use the standard send method
 			err = tmux.SendCommandWithMethodAndExecutor(target, text, method, exec)
@@ -123,6 +127,8 @@ func parseMethod(s string) tmux.SendMethod {
 		return tmux.SendMethodCmAppended
 	case "enter-delayed-long":
 		return tmux.SendMethodEnterDelayedLong
+	case "bracketed-paste":
+		return tmux.SendMethodBracketedPaste
 	default:
 		return tmux.SendMethodEnterDelayed
 	}
@@ -15,10 +15,21 @@ import (
 
 var sessionsCmd = &cobra.Command{
 	Use:     "sessions [session-name]",
-	Aliases: []string{"lsessions", "list-sessions", "list", "ls", "attach"},
+	Aliases: []string{"lsessions", "list-sessions", "list", "ls"},
 	Short:   "List sessions or attach directly by name",
-	Args:    cobra.MaximumNArgs(1),
-	RunE:    runSessions,
+	Long: `List tmux sessions (local and remote) and attach to one.
+
+Controls:
+  Up/Down or j/k Select session
+  digits         Jump to session by number
+  Enter          Attach (revive a recent session, or open a remote project)
+  r              Attach read-only (no typing; scrolling and copy mode still work)
+  y              Copy the attach command (ssh/mosh for remote sessions)
+  x              Kill session / remove recent entry
+  S              Kill stale sessions
+  q/Esc          Quit`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSessions,
 }
 
 var (
@@ -49,14 +60,26 @@ func runSessions(cmd *cobra.Command, args []string) error {
 		return attachToSession(args[0])
 	}
 
+	cfg, err := loadRemoteConfig()
+	if err != nil {
+		return err
+	}
+
 	// Build executors (local + configured remotes + --remote flag)
-	executors, err := buildExecutors(sessionsRemote)
+	executors, err := executorsForConfig(cfg, sessionsRemote)
 	if err != nil {
 		return fmt.Errorf("failed to build executors: %w", err)
 	}
 	defer closeExecutors(executors)
 	registerCleanupSignals(executors)
 
+	// Without remotes there's nothing to list if tmux is missing
+	if len(executors) == 1 {
+		if err := tmux.CheckInstalled(); err != nil {
+			return err
+		}
+	}
+
 	// Non-interactive mode: print all sessions and exit
 	if sessionsNonInteractive {
 		return runSessionsNonInteractive(cmd, executors)
@@ -72,30 +95,78 @@ func runSessions(cmd *cobra.Command, args []string) error {
 		return switchToPopupTarget()
 	}
 
+	sw, err := runSessionsList(cfg, executors, "", "")
+	if err != nil {
+		return err
+	}
+	return runScreens(sw)
+}
+
+// sessionsScreen opens the sessions list from another screen, selecting
+// session on host if it is listed.
+func sessionsScreen(session, host string) (*tui.ScreenSwitch, error) {
+	cfg, err := loadRemoteConfig()
+	if err != nil {
+		return nil, err
+	}
+	executors, err := executorsForConfig(cfg, sessionsRemote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build executors: %w", err)
+	}
+	defer closeExecutors(executors)
+	return runSessionsList(cfg, executors, session, host)
+}
+
+// runSessionsList shows the sessions list and acts on the choice, or
+// returns the screen the user switched to instead.
+func runSessionsList(cfg *config.Config, executors []tmux.TmuxExecutor, selectSession, selectHost string) (*tui.ScreenSwitch, error) {
+	remoteProjects, err := config.ResolveRemoteProjects(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, _ := config.LoadSettings()
 	result, err := tui.RunSessionsList(tui.SessionsOptions{
 		AltScreen:        !sessionsInline,
 		Executors:        executors,
-		ShowBeads:        !sessionsNoBeads,
+		ShowBeads:        !sessionsNoBeads && !settings.HideBeads,
 		DisableStaleness: sessionsNoStaleness,
+		RemoteProjects:   remoteProjects,
+		SelectSession:    selectSession,
+		SelectHost:       selectHost,
 	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if result.Switch != nil {
+		return result.Switch, nil
 	}
+	return nil, attachSessionsResult(result)
+}
+
+// attachSessionsResult attaches to, revives, or opens what was chosen in
+// the sessions list.
+func attachSessionsResult(result *tui.SessionsResult) error {
 	if result.SessionName == "" {
 		return nil
 	}
 
+	// Remote projects live on another host, so even from a popup they are
+	// opened here rather than handed back to the parent as a local target.
+	if result.RemoteProject != nil {
+		return openRemoteProject(*result.RemoteProject)
+	}
+
 	// If running inside a popup, communicate the target session back to the
 	// parent process via a tmux global option instead of switching directly.
 	// The parent reads this after the popup closes and performs the real switch.
-	if tmuxClientIsPopup() {
+	if tmuxClientIsPopup() && !result.ReadOnly {
 		return handlePopupSelection(result)
 	}
 
 	if result.IsFromHistory {
 		// Revival from history - create new session in that directory
-		session := tmux.NewSession(result.WorkingDir)
-		return runDirectAttach(session, result.WorkingDir)
+		return runDirectAttach(result.WorkingDir, "")
 	}
 
 	// Attach to existing session via the appropriate executor
@@ -118,9 +189,29 @@ func runSessions(cmd *cobra.Command, args []string) error {
 		}
 	}
 	strategy := resolveAttachStrategy(executor)
+	if result.ReadOnly {
+		return tmux.AttachReadOnlyWithStrategy(result.SessionName, executor, strategy)
+	}
 	return tmux.AttachToSessionWithStrategy(result.SessionName, executor, strategy)
 }
 
+// openRemoteProject connects to a remote project's host, creates its session
+// in the project directory if it isn't running yet, and attaches to it.
+func openRemoteProject(project config.ResolvedRemoteProject) error {
+	rh := project.Remote
+	executor := newRemoteExecutor(rh)
+	defer executor.Close()
+
+	if err := executor.Run("has-session", "-t", project.SessionName); err != nil {
+		if err := executor.Run("new-session", "-d", "-s", project.SessionName, "-c", project.WorkingDir); err != nil {
+			return fmt.Errorf("failed to create session %s on %s: %w", project.SessionName, executor.HostLabel(), err)
+		}
+	}
+
+	saveHistory(project.Name, "", project.SessionName, executor.HostLabel(), executor.AttachMethod)
+	return tmux.AttachToSessionWithStrategy(project.SessionName, executor, resolveAttachStrategy(executor))
+}
+
 // resolveAttachStrategy determines the attach strategy from (in order):
 // 1. --strategy flag, 2. per-host override, 3. global setting, 4. "auto".
 func resolveAttachStrategy(executor tmux.TmuxExecutor) config.AttachStrategy {
@@ -227,12 +318,17 @@ func handlePopupSelection(result *tui.SessionsResult) error {
 	return exec.Command("tmux", "set-option", "-g", "@atmux-popup-target", target).Run()
 }
 
-func attachToSession(name string) error {
-	sessionName := name
-	if !strings.HasPrefix(sessionName, "agent-") && !strings.HasPrefix(sessionName, "atmux-") {
-		sessionName = "agent-" + sessionName
+// atmuxSessionName returns name with the agent- prefix added unless it
+// already has an atmux prefix.
+func atmuxSessionName(name string) string {
+	if !strings.HasPrefix(name, "agent-") && !strings.HasPrefix(name, "atmux-") {
+		return "agent-" + name
 	}
+	return name
+}
 
+func attachToSession(name string) error {
+	sessionName := atmuxSessionName(name)
 	session := &tmux.Session{Name: sessionName}
 	if !session.Exists() {
 		return fmt.Errorf("session %s does not exist\nUse 'atmux sessions' to see active sessions", sessionName)
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/porganisciak/agent-tmux/tui"
+	"github.com/spf13/cobra"
+)
+
+var settingsInline bool
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Edit atmux settings",
+	Long: `Edit the preferences stored in settings.json: the default action, sessions
+list and staleness options, browse refresh and mobile layout, remote attach
+strategy, history pruning, and theme.
+
+Changes are validated and saved as you make them.
+
+Controls:
+  Up/Down or j/k   Select setting
+  Space/Enter      Toggle, cycle a choice, or edit a value
+  Left/Right       Cycle a choice backwards/forwards
+  Backspace        Reset a value to its default
+  q/Esc            Quit`,
+	RunE: runSettings,
+}
+
+func init() {
+	rootCmd.AddCommand(settingsCmd)
+	settingsCmd.Flags().BoolVar(&settingsInline, "inline", false, "Render without alt screen (non-fullscreen)")
+}
+
+func runSettings(cmd *cobra.Command, args []string) error {
+	return tui.RunSettings(tui.SettingsOptions{AltScreen: !settingsInline})
+}
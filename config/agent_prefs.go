@@ -0,0 +1,63 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// AgentPreference is a known agent's default command line, kept in
+// settings.json so flags can change without re-running onboard.
+type AgentPreference struct {
+	Name    string `json:"name"`            // Display name, e.g. "Claude"; identifies the agent
+	Command string `json:"command"`         // Base command, e.g. "claude"
+	Enabled bool   `json:"enabled"`         // Start a pane for it in new sessions
+	Yolo    bool   `json:"yolo,omitempty"`  // Add the agent's auto-approve flag
+	Flags   string `json:"flags,omitempty"` // Extra flags appended to the command
+}
+
+// CommandLine returns the command to run for the agent, with its
+// auto-approve flag from agents and its extra flags.
+func (p AgentPreference) CommandLine(agents []AgentSpec) string {
+	cmd := p.Command
+	if flag := YoloFlag(agents, p.Command); p.Yolo && flag != "" {
+		cmd += " " + flag
+	}
+	if flags := strings.TrimSpace(p.Flags); flags != "" {
+		cmd += " " + flags
+	}
+	return cmd
+}
+
+// EffectiveAgents returns the saved agent preferences followed by any known
+// agent not saved yet. Before any are saved, every known agent starts from
+// its defaults; agents registered later start disabled.
+func (s *Settings) EffectiveAgents() []AgentPreference {
+	prefs := append([]AgentPreference(nil), s.Agents...)
+	for _, a := range s.KnownAgents() {
+		if slices.ContainsFunc(prefs, func(p AgentPreference) bool { return p.Name == a.Name }) {
+			continue
+		}
+		pref := a.DefaultPreference()
+		if len(s.Agents) > 0 {
+			pref.Enabled, pref.Yolo = false, false
+		}
+		prefs = append(prefs, pref)
+	}
+	return prefs
+}
+
+// AgentCommands returns the enabled agents' command lines, or nil when no
+// agent preferences are saved, so callers keep their own defaults.
+func (s *Settings) AgentCommands() []AgentConfig {
+	if len(s.Agents) == 0 {
+		return nil
+	}
+	known := s.KnownAgents()
+	var agents []AgentConfig
+	for _, p := range s.Agents {
+		if p.Enabled && strings.TrimSpace(p.Command) != "" {
+			agents = append(agents, AgentConfig{Command: p.CommandLine(known)})
+		}
+	}
+	return agents
+}
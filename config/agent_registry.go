@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// AgentSpec describes an agent CLI atmux knows how to start. Adding an
+// agent is one entry in builtinAgents, or in custom_agents in settings.json.
+type AgentSpec struct {
+	Name         string `json:"name"`                   // Display name, e.g. "Claude"
+	Command      string `json:"command"`                // Base command, e.g. "claude"
+	YoloFlag     string `json:"yolo_flag,omitempty"`    // Flag that skips permission prompts ("" = none)
+	Default      bool   `json:"default,omitempty"`      // Enabled, with auto-approve, before any agents are saved
+	Experimental bool   `json:"experimental,omitempty"` // Warn that support is untested
+}
+
+// builtinAgents are the agents offered out of the box.
+var builtinAgents = []AgentSpec{
+	{Name: "Claude", Command: "claude", YoloFlag: "--dangerously-skip-permissions", Default: true},
+	{Name: "Codex", Command: "codex", YoloFlag: "--yolo", Default: true},
+	{Name: "Gemini CLI", Command: "gemini", YoloFlag: "--yolo", Experimental: true},
+}
+
+// BuiltinAgents returns the agents atmux offers without any custom_agents.
+func BuiltinAgents() []AgentSpec {
+	return append([]AgentSpec(nil), builtinAgents...)
+}
+
+// program returns the first word of command, the executable it runs.
+func program(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// Installed reports whether the agent's program is on PATH.
+func (a AgentSpec) Installed() bool {
+	name := program(a.Command)
+	if name == "" {
+		return false
+	}
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// DefaultPreference returns the agent's preferences before any are saved.
+func (a AgentSpec) DefaultPreference() AgentPreference {
+	return AgentPreference{Name: a.Name, Command: a.Command, Enabled: a.Default, Yolo: a.Default && a.YoloFlag != ""}
+}
+
+// KnownAgents returns the built-in agents followed by the custom ones. A
+// custom agent with a built-in agent's name replaces it.
+func (s *Settings) KnownAgents() []AgentSpec {
+	agents := BuiltinAgents()
+	for _, custom := range s.CustomAgents {
+		if strings.TrimSpace(custom.Name) == "" || strings.TrimSpace(custom.Command) == "" {
+			continue
+		}
+		replaced := false
+		for i := range agents {
+			if agents[i].Name == custom.Name {
+				agents[i] = custom
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			agents = append(agents, custom)
+		}
+	}
+	return agents
+}
+
+// LookupAgent returns the agent in agents that runs command's program.
+func LookupAgent(agents []AgentSpec, command string) (AgentSpec, bool) {
+	name := program(command)
+	if name == "" {
+		return AgentSpec{}, false
+	}
+	for _, a := range agents {
+		if program(a.Command) == name {
+			return a, true
+		}
+	}
+	return AgentSpec{}, false
+}
+
+// YoloFlag returns the auto-approve flag agents list for command's
+// program, or "" if it has none.
+func YoloFlag(agents []AgentSpec, command string) string {
+	a, _ := LookupAgent(agents, command)
+	return a.YoloFlag
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity classifies a config lint finding.
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning" // Works, but probably not as intended
+	SeverityError   Severity = "error"   // Produces a broken session
+)
+
+// Finding is a problem found while parsing or validating a config file.
+type Finding struct {
+	Path     string
+	Line     int // 1-based line number, 0 when the finding isn't tied to a line
+	Severity Severity
+	Message  string
+}
+
+// String formats the finding as "path:line: severity: message".
+func (f Finding) String() string {
+	location := f.Path
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, f.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, f.Severity, f.Message)
+}
+
+// knownDirectives lists the directives Parse understands.
+var knownDirectives = map[string]bool{
+	"template": true, "window": true, "pane": true, "vpane": true,
+	"agents": true, "vagents": true, "agent": true,
+	"remote_host": true, "remote_alias": true, "remote_port": true, "remote_attach": true,
+	"remote_project": true, "remote_project_host": true, "remote_project_dir": true, "remote_project_session": true,
+	"no_history": true,
+}
+
+// addFinding records a non-fatal problem found by Parse.
+func (c *Config) addFinding(path string, line int, severity Severity, format string, args ...any) {
+	c.findings = append(c.findings, Finding{
+		Path:     path,
+		Line:     line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks a parsed config for common mistakes that Parse accepts
+// silently: panes outside a window, empty agent commands, unknown directives,
+// ambiguous remote hosts, and remote projects whose host isn't a configured
+// remote_host. Line numbers are only available for configs returned by Parse.
+func (c *Config) Validate() []Finding {
+	findings := append([]Finding(nil), c.findings...)
+
+	for i, rp := range c.RemoteProjects {
+		if remoteHostDefined(c.RemoteHosts, rp.Host) || strings.Contains(rp.Host, "@") {
+			continue
+		}
+		f := Finding{
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("remote_project %s uses host %q, which no remote_host defines; it will be used as an SSH destination as-is", rp.Name, rp.Host),
+		}
+		if i < len(c.remoteProjectLines) {
+			f.Path = c.path
+			f.Line = c.remoteProjectLines[i]
+		}
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// HasErrors reports whether any finding is an error.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteHostDefined reports whether name is the host or alias of a configured
+// remote host.
+func remoteHostDefined(hosts []RemoteHostConfig, name string) bool {
+	for _, rh := range hosts {
+		if rh.Host == name || rh.Alias == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsFindingsWithLineNumbers(t *testing.T) {
+	path := writeTempConfig(t, `# comment
+pane:npm run dev
+agent:
+window:server
+pane:npm start
+colour:red
+just some text
+remote_host:user@devbox.example.com
+remote_alias:devbox
+remote_project:api
+remote_project_host:devbox
+remote_project_dir:/srv/api
+remote_project:web
+remote_project_host:buildbox
+remote_project_dir:/srv/web
+`)
+
+	cfg, err := Parse(path)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	findings := cfg.Validate()
+
+	want := []struct {
+		line     int
+		severity Severity
+		fragment string
+	}{
+		{2, SeverityWarning, "pane before any window"},
+		{3, SeverityError, "agent requires a command"},
+		{6, SeverityWarning, `unknown directive "colour"`},
+		{7, SeverityWarning, "without a directive:value pair"},
+		{13, SeverityWarning, `remote_project web uses host "buildbox"`},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("expected %d findings, got %d: %v", len(want), len(findings), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Path != path || f.Line != w.line || f.Severity != w.severity || !strings.Contains(f.Message, w.fragment) {
+			t.Errorf("finding %d = %v, want line %d %s containing %q", i, f, w.line, w.severity, w.fragment)
+		}
+	}
+	if !HasErrors(findings) {
+		t.Fatal("expected the empty agent to count as an error")
+	}
+	if got := findings[1].String(); got != path+":3: error: agent requires a command" {
+		t.Fatalf("unexpected formatting %q", got)
+	}
+}
+
+func TestValidateTemplatesLintClean(t *testing.T) {
+	for name, content := range map[string]string{"default": DefaultTemplate(), "global": GlobalTemplate()} {
+		path := writeTempConfig(t, content)
+		cfg, err := Parse(path)
+		if err != nil {
+			t.Fatalf("Parse(%s template) returned error: %v", name, err)
+		}
+		if findings := cfg.Validate(); len(findings) != 0 {
+			t.Fatalf("expected the %s template to lint clean, got %v", name, findings)
+		}
+	}
+}
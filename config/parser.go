@@ -41,12 +41,72 @@ type RemoteProjectConfig struct {
 	SessionName string // Preferred tmux session name on the remote host
 }
 
+// TemplateConfig is a named session layout (from a template: block) that can
+// be chosen in place of the default layout when creating a session.
+type TemplateConfig struct {
+	Name       string
+	Windows    []WindowConfig
+	AgentPanes []PaneConfig
+	CoreAgents []AgentConfig
+}
+
 type Config struct {
 	Windows        []WindowConfig        // New windows to create
 	AgentPanes     []PaneConfig          // Extra panes to add to agents window
 	CoreAgents     []AgentConfig         // Core agent panes (from agent: directive)
 	RemoteHosts    []RemoteHostConfig    // Remote hosts for sessions list
 	RemoteProjects []RemoteProjectConfig // Reusable remote projects
+	Templates      []TemplateConfig      // Named session layouts
+	NoHistory      bool                  // Don't record sessions in the recent-sessions history
+	Warnings       []string              // Non-fatal problems found while parsing (e.g. ambiguous remote hosts)
+
+	// Set by Parse for Validate
+	path               string
+	findings           []Finding
+	remoteProjectLines []int // Line of each remote_project directive
+}
+
+// TemplateNames returns the names of the configured session templates.
+func (c *Config) TemplateNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.Templates))
+	for _, t := range c.Templates {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// WithTemplate returns a copy of the config whose layout comes from the named
+// template: its windows and agents-window panes replace the default ones, and
+// its agent: lines replace the core agents when it defines any.
+func (c *Config) WithTemplate(name string) (*Config, error) {
+	if c == nil {
+		c = &Config{}
+	}
+	idx := findTemplate(c.Templates, name)
+	if idx == -1 {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	t := c.Templates[idx]
+
+	result := *c
+	result.Windows = t.Windows
+	result.AgentPanes = t.AgentPanes
+	if len(t.CoreAgents) > 0 {
+		result.CoreAgents = t.CoreAgents
+	}
+	return &result, nil
+}
+
+func findTemplate(templates []TemplateConfig, name string) int {
+	for i, t := range templates {
+		if strings.EqualFold(t.Name, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
 }
 
 const (
@@ -121,21 +181,29 @@ func defaultRemoteProjectSessionName(name string) string {
 // ResolveRemoteHosts resolves a comma-separated remote host flag against config
 // entries. When includeConfigured is true, configured hosts are included even if
 // they are not explicitly listed in remoteFlag.
+//
+// Configured entries that share a host or alias are collapsed first: the later
+// entry wins and takes the earlier entry's place in the list, so a name always
+// resolves to the same host (Parse reports these collisions in Warnings).
 func ResolveRemoteHosts(cfg *Config, remoteFlag string, includeConfigured bool) ([]RemoteHostConfig, error) {
 	if cfg == nil {
 		cfg = &Config{}
 	}
 
 	configured := make([]RemoteHostConfig, 0, len(cfg.RemoteHosts))
-	lookup := make(map[string]RemoteHostConfig, len(cfg.RemoteHosts)*2)
 	for _, rh := range cfg.RemoteHosts {
 		normalized, err := NormalizeRemoteHost(rh)
 		if err != nil {
 			return nil, fmt.Errorf("invalid configured remote host %q: %w", rh.Host, err)
 		}
 		configured = append(configured, normalized)
-		lookup[normalized.Host] = normalized
-		lookup[normalized.Alias] = normalized
+	}
+	configured = dedupeRemoteHosts(configured)
+
+	lookup := make(map[string]RemoteHostConfig, len(configured)*2)
+	for _, rh := range configured {
+		lookup[rh.Host] = rh
+		lookup[rh.Alias] = rh
 	}
 
 	remoteFlag = strings.TrimSpace(remoteFlag)
@@ -143,7 +211,7 @@ func ResolveRemoteHosts(cfg *Config, remoteFlag string, includeConfigured bool)
 		if !includeConfigured {
 			return []RemoteHostConfig{}, nil
 		}
-		return dedupeRemoteHosts(configured), nil
+		return configured, nil
 	}
 
 	var resolved []RemoteHostConfig
@@ -186,6 +254,38 @@ func ResolveRemoteHosts(cfg *Config, remoteFlag string, includeConfigured bool)
 	return resolved, nil
 }
 
+// ResolvedRemoteProject is a remote project paired with the connection
+// details of its host.
+type ResolvedRemoteProject struct {
+	RemoteProjectConfig
+	Remote RemoteHostConfig
+}
+
+// ResolveRemoteProjects maps each configured remote project's host alias to
+// connection details via ResolveRemoteHosts. Hosts without a remote_host entry
+// are used as plain SSH destinations.
+func ResolveRemoteProjects(cfg *Config) ([]ResolvedRemoteProject, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	var projects []ResolvedRemoteProject
+	for _, rp := range cfg.RemoteProjects {
+		normalized, err := NormalizeRemoteProject(rp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote project %q: %w", rp.Name, err)
+		}
+		hosts, err := ResolveRemoteHosts(cfg, normalized.Host, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve host for remote project %q: %w", normalized.Name, err)
+		}
+		if len(hosts) == 0 {
+			return nil, fmt.Errorf("failed to resolve host for remote project %q", normalized.Name)
+		}
+		projects = append(projects, ResolvedRemoteProject{RemoteProjectConfig: normalized, Remote: hosts[0]})
+	}
+	return projects, nil
+}
+
 // DefaultConfigName is the name of the config file to look for
 const DefaultConfigName = ".agent-tmux.conf"
 
@@ -245,6 +345,9 @@ func mergeConfigs(global, local *Config) *Config {
 		result.Windows = append(result.Windows, global.Windows...)
 		result.RemoteHosts = append(result.RemoteHosts, global.RemoteHosts...)
 		result.RemoteProjects = append(result.RemoteProjects, global.RemoteProjects...)
+		result.Templates = append(result.Templates, global.Templates...)
+		result.NoHistory = global.NoHistory
+		result.Warnings = append(result.Warnings, global.Warnings...)
 	}
 
 	// Override/add from local
@@ -258,6 +361,9 @@ func mergeConfigs(global, local *Config) *Config {
 		result.Windows = append(result.Windows, local.Windows...)
 		result.RemoteHosts = mergeRemoteHosts(result.RemoteHosts, local.RemoteHosts)
 		result.RemoteProjects = mergeRemoteProjects(result.RemoteProjects, local.RemoteProjects)
+		result.Templates = mergeTemplates(result.Templates, local.Templates)
+		result.NoHistory = result.NoHistory || local.NoHistory
+		result.Warnings = append(result.Warnings, local.Warnings...)
 	}
 
 	return result
@@ -271,11 +377,14 @@ func Parse(path string) (*Config, error) {
 	}
 	defer file.Close()
 
-	config := &Config{}
+	config := &Config{path: path}
 	var currentWindow *WindowConfig
 	var currentRemote *RemoteHostConfig
 	var currentRemoteProject *RemoteProjectConfig
 
+	// Layout directives go to the top level until a template: block starts
+	windows, agentPanes, coreAgents := &config.Windows, &config.AgentPanes, &config.CoreAgents
+
 	scanner := bufio.NewScanner(file)
 	lineNumber := 0
 	for scanner.Scan() {
@@ -290,20 +399,39 @@ func Parse(path string) (*Config, error) {
 		// Parse directive:value
 		parts := strings.SplitN(line, ":", 2)
 		if len(parts) != 2 {
+			config.addFinding(path, lineNumber, SeverityWarning, "ignoring line without a directive:value pair")
 			continue
 		}
 
 		directive := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+		value := strings.TrimSpace(stripTrailingComment(parts[1]))
+		if !knownDirectives[directive] {
+			config.addFinding(path, lineNumber, SeverityWarning, "unknown directive %q ignored", directive)
+		}
 
 		switch directive {
+		case "template":
+			// Start (or continue) a named template; layout directives that
+			// follow belong to it until the next template: line
+			if value == "" {
+				return nil, fmt.Errorf("%s:%d: template requires a name", path, lineNumber)
+			}
+			idx := findTemplate(config.Templates, value)
+			if idx == -1 {
+				config.Templates = append(config.Templates, TemplateConfig{Name: value})
+				idx = len(config.Templates) - 1
+			}
+			t := &config.Templates[idx]
+			windows, agentPanes, coreAgents = &t.Windows, &t.AgentPanes, &t.CoreAgents
+			currentWindow = nil
+
 		case "window":
 			// Start a new window
-			config.Windows = append(config.Windows, WindowConfig{
+			*windows = append(*windows, WindowConfig{
 				Name:  value,
 				Panes: []PaneConfig{},
 			})
-			currentWindow = &config.Windows[len(config.Windows)-1]
+			currentWindow = &(*windows)[len(*windows)-1]
 
 		case "pane":
 			// Add horizontal pane to current window
@@ -312,6 +440,8 @@ func Parse(path string) (*Config, error) {
 					Command:  value,
 					Vertical: false,
 				})
+			} else {
+				config.addFinding(path, lineNumber, SeverityWarning, "pane before any window is ignored")
 			}
 
 		case "vpane":
@@ -321,25 +451,36 @@ func Parse(path string) (*Config, error) {
 					Command:  value,
 					Vertical: true,
 				})
+			} else {
+				config.addFinding(path, lineNumber, SeverityWarning, "vpane before any window is ignored")
 			}
 
 		case "agents":
 			// Add horizontal pane to agents window
-			config.AgentPanes = append(config.AgentPanes, PaneConfig{
+			if value == "" {
+				config.addFinding(path, lineNumber, SeverityError, "agents requires a command")
+			}
+			*agentPanes = append(*agentPanes, PaneConfig{
 				Command:  value,
 				Vertical: false,
 			})
 
 		case "vagents":
 			// Add vertical pane to agents window
-			config.AgentPanes = append(config.AgentPanes, PaneConfig{
+			if value == "" {
+				config.addFinding(path, lineNumber, SeverityError, "vagents requires a command")
+			}
+			*agentPanes = append(*agentPanes, PaneConfig{
 				Command:  value,
 				Vertical: true,
 			})
 
 		case "agent":
 			// Core agent pane
-			config.CoreAgents = append(config.CoreAgents, AgentConfig{
+			if value == "" {
+				config.addFinding(path, lineNumber, SeverityError, "agent requires a command")
+			}
+			*coreAgents = append(*coreAgents, AgentConfig{
 				Command: value,
 			})
 
@@ -388,6 +529,7 @@ func Parse(path string) (*Config, error) {
 			config.RemoteProjects = append(config.RemoteProjects, RemoteProjectConfig{
 				Name: value,
 			})
+			config.remoteProjectLines = append(config.remoteProjectLines, lineNumber)
 			currentRemoteProject = &config.RemoteProjects[len(config.RemoteProjects)-1]
 
 		case "remote_project_host":
@@ -416,6 +558,13 @@ func Parse(path string) (*Config, error) {
 				return nil, fmt.Errorf("%s:%d: remote_project_session requires a value", path, lineNumber)
 			}
 			currentRemoteProject.SessionName = value
+
+		case "no_history":
+			noHistory, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: no_history must be true or false", path, lineNumber)
+			}
+			config.NoHistory = noHistory
 		}
 	}
 
@@ -439,9 +588,36 @@ func Parse(path string) (*Config, error) {
 		config.RemoteProjects[i] = normalized
 	}
 
+	for _, warning := range remoteHostConflicts(config.RemoteHosts) {
+		config.Warnings = append(config.Warnings, fmt.Sprintf("%s: %s", path, warning))
+		config.addFinding(path, 0, SeverityWarning, "%s", warning)
+	}
+
 	return config, nil
 }
 
+// stripTrailingComment removes a trailing "# ..." comment from a directive
+// value. A # only starts a comment when it follows whitespace and sits outside
+// quotes, so values like "echo '#1'" or "https://host/#fragment" are kept.
+func stripTrailingComment(value string) string {
+	var quote rune
+	prevSpace := false
+	for i, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '#' && prevSpace:
+			return value[:i]
+		}
+		prevSpace = r == ' ' || r == '\t'
+	}
+	return value
+}
+
 func mergeRemoteHosts(base, overrides []RemoteHostConfig) []RemoteHostConfig {
 	merged := append([]RemoteHostConfig{}, base...)
 	for _, override := range overrides {
@@ -478,6 +654,31 @@ func mergeRemoteProjects(base, overrides []RemoteProjectConfig) []RemoteProjectC
 	return dedupeRemoteProjects(merged)
 }
 
+// mergeTemplates merges local templates into global ones. A local template
+// with the same name as a global one is merged into it with the same rules
+// as the top-level config.
+func mergeTemplates(base, overrides []TemplateConfig) []TemplateConfig {
+	merged := append([]TemplateConfig{}, base...)
+	for _, override := range overrides {
+		idx := findTemplate(merged, override.Name)
+		if idx == -1 {
+			merged = append(merged, override)
+			continue
+		}
+		layout := mergeConfigs(
+			&Config{Windows: merged[idx].Windows, AgentPanes: merged[idx].AgentPanes, CoreAgents: merged[idx].CoreAgents},
+			&Config{Windows: override.Windows, AgentPanes: override.AgentPanes, CoreAgents: override.CoreAgents},
+		)
+		merged[idx] = TemplateConfig{
+			Name:       override.Name,
+			Windows:    layout.Windows,
+			AgentPanes: layout.AgentPanes,
+			CoreAgents: layout.CoreAgents,
+		}
+	}
+	return merged
+}
+
 func dedupeRemoteHosts(hosts []RemoteHostConfig) []RemoteHostConfig {
 	var deduped []RemoteHostConfig
 	for _, host := range hosts {
@@ -530,6 +731,49 @@ func sameRemoteIdentity(a, b RemoteHostConfig) bool {
 	return false
 }
 
+// remoteHostConflicts describes remote_host entries that share a host or
+// alias with an earlier entry. Exact duplicates are harmless and skipped.
+func remoteHostConflicts(hosts []RemoteHostConfig) []string {
+	var warnings []string
+	for j := range hosts {
+		for i := 0; i < j; i++ {
+			a, b := hosts[i], hosts[j]
+			if !sameRemoteIdentity(a, b) || sameRemoteHost(a, b) {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf(
+				"remote_host %s and %s both use %q; using %s (later entries win)",
+				a.Host, b.Host, sharedRemoteName(a, b), b.Host))
+		}
+	}
+	return warnings
+}
+
+// sameRemoteHost reports whether two entries describe the same connection
+// once defaults are filled in.
+func sameRemoteHost(a, b RemoteHostConfig) bool {
+	na, errA := NormalizeRemoteHost(a)
+	nb, errB := NormalizeRemoteHost(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return na == nb
+}
+
+// sharedRemoteName returns the host or alias that makes a and b collide.
+func sharedRemoteName(a, b RemoteHostConfig) string {
+	switch {
+	case a.Host != "" && a.Host == b.Host:
+		return a.Host
+	case a.Alias != "" && a.Alias == b.Alias:
+		return a.Alias
+	case a.Host != "" && a.Host == b.Alias:
+		return a.Host
+	default:
+		return a.Alias
+	}
+}
+
 func sameRemoteProjectIdentity(a, b RemoteProjectConfig) bool {
 	return strings.EqualFold(strings.TrimSpace(a.Name), strings.TrimSpace(b.Name))
 }
@@ -565,6 +809,8 @@ func DefaultTemplate() string {
 #   remote_project_host:... - Host/alias for the last remote_project
 #   remote_project_dir:.... - Remote working dir for the last remote_project
 #   remote_project_session: - Optional tmux session name for the last remote_project
+#   template:name    - Start a named layout; following agent/window/pane lines belong to it
+#   no_history:true  - Don't add sessions for this project to the recent history
 
 # ── Custom Agent Setup ───────────────────────────────────────────────
 # Override the default agent panes. When any agent: line is present,
@@ -629,6 +875,28 @@ func DefaultTemplate() string {
 # remote_project_host:devbox
 # remote_project_dir:/home/user/projects/atmux
 # remote_project_session:agent-atmux
+
+# ── History ──────────────────────────────────────────────────────────
+# Keep throwaway or experiment projects out of the Recent list
+# (same as passing --no-history every time).
+#
+# no_history:true
+
+# ── Session Templates ────────────────────────────────────────────────
+# Named layouts you can pick when starting a session (landing page or
+# "new session" in browse). A template: block collects the agent:, agents:,
+# window:, and pane: lines after it, up to the next template: line, so keep
+# templates at the end of the file. A template with the same name in the
+# global and local config is merged like the rest of the config.
+#
+# template:frontend
+# window:dev
+# pane:npm run dev
+#
+# template:backend
+# agent:claude --dangerously-skip-permissions
+# window:server
+# pane:go run ./cmd/server
 `
 }
 
@@ -658,6 +926,7 @@ agent:codex --full-auto
 #   remote_project_host:... - Host/alias for the last remote_project
 #   remote_project_dir:.... - Remote working dir for the last remote_project
 #   remote_project_session: - Optional tmux session name for the last remote_project
+#   template:name   - Start a named layout (keep templates at the end of the file)
 
 # Example remote host
 # remote_host:user@devbox.example.com
@@ -235,3 +235,220 @@ func TestResolveRemoteHosts(t *testing.T) {
 		}
 	})
 }
+
+func TestParseWarnsOnConflictingRemoteHosts(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		want    string // Expected warning fragment, "" for none
+	}{
+		{
+			name:    "shared alias",
+			content: "remote_host:user@a\nremote_alias:dev\nremote_host:user@b\nremote_alias:dev\n",
+			want:    `remote_host user@a and user@b both use "dev"; using user@b`,
+		},
+		{
+			name:    "alias matches another host",
+			content: "remote_host:devbox\nremote_host:user@b\nremote_alias:devbox\n",
+			want:    `remote_host devbox and user@b both use "devbox"; using user@b`,
+		},
+		{
+			name:    "same host with different port",
+			content: "remote_host:user@a\nremote_alias:a\nremote_host:user@a\nremote_alias:a\nremote_port:2202\n",
+			want:    `remote_host user@a and user@a both use "user@a"`,
+		},
+		{
+			name:    "exact duplicate",
+			content: "remote_host:user@a\nremote_alias:a\nremote_host:user@a\nremote_alias:a\n",
+		},
+		{
+			name:    "distinct hosts",
+			content: "remote_host:user@a\nremote_alias:a\nremote_host:user@b\nremote_alias:b\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTempConfig(t, tc.content)
+			cfg, err := Parse(path)
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if tc.want == "" {
+				if len(cfg.Warnings) != 0 {
+					t.Fatalf("expected no warnings, got %v", cfg.Warnings)
+				}
+				return
+			}
+			if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], tc.want) {
+				t.Fatalf("expected warning containing %q, got %v", tc.want, cfg.Warnings)
+			}
+			if !strings.HasPrefix(cfg.Warnings[0], path+": ") {
+				t.Fatalf("expected warning to name the config file, got %q", cfg.Warnings[0])
+			}
+		})
+	}
+}
+
+func TestResolveRemoteHostsLaterConflictingEntryWins(t *testing.T) {
+	cfg := &Config{
+		RemoteHosts: []RemoteHostConfig{
+			{Host: "user@a", Alias: "dev", Port: 22, AttachMethod: "ssh"},
+			{Host: "user@db", Alias: "db", Port: 22, AttachMethod: "ssh"},
+			{Host: "user@b", Alias: "dev", Port: 2202, AttachMethod: "mosh"},
+		},
+	}
+
+	hosts, err := ResolveRemoteHosts(cfg, "", true)
+	if err != nil {
+		t.Fatalf("ResolveRemoteHosts returned error: %v", err)
+	}
+	if got, want := len(hosts), 2; got != want {
+		t.Fatalf("expected %d hosts, got %d: %+v", want, got, hosts)
+	}
+	if hosts[0].Host != "user@b" || hosts[1].Host != "user@db" {
+		t.Fatalf("expected the later entry in the earlier entry's place, got %+v", hosts)
+	}
+
+	// The alias resolves to the same entry whether or not configured hosts are included
+	for _, include := range []bool{false, true} {
+		hosts, err := ResolveRemoteHosts(cfg, "dev", include)
+		if err != nil {
+			t.Fatalf("ResolveRemoteHosts returned error: %v", err)
+		}
+		if hosts[0].Host != "user@b" || hosts[0].Port != 2202 {
+			t.Fatalf("expected dev to resolve to user@b, got %+v", hosts[0])
+		}
+		if include && len(hosts) != 2 {
+			t.Fatalf("expected the replaced entry to stay out of the list, got %+v", hosts)
+		}
+	}
+}
+
+func TestParseTemplateBlocks(t *testing.T) {
+	path := writeTempConfig(t, `
+agent:claude
+window:dev
+pane:npm run dev
+
+template:backend
+window:server
+pane:go run ./cmd/server
+
+template:review
+agent:codex
+agents:git log --oneline
+`)
+
+	cfg, err := Parse(path)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(cfg.Windows) != 1 || cfg.Windows[0].Name != "dev" {
+		t.Fatalf("expected top-level dev window only, got %+v", cfg.Windows)
+	}
+	if got := strings.Join(cfg.TemplateNames(), ","); got != "backend,review" {
+		t.Fatalf("unexpected template names %q", got)
+	}
+
+	backend, err := cfg.WithTemplate("backend")
+	if err != nil {
+		t.Fatalf("WithTemplate returned error: %v", err)
+	}
+	if len(backend.Windows) != 1 || backend.Windows[0].Name != "server" || len(backend.Windows[0].Panes) != 1 {
+		t.Fatalf("expected backend server window, got %+v", backend.Windows)
+	}
+	if len(backend.CoreAgents) != 1 || backend.CoreAgents[0].Command != "claude" {
+		t.Fatalf("expected default core agents to be kept, got %+v", backend.CoreAgents)
+	}
+
+	review, err := cfg.WithTemplate("review")
+	if err != nil {
+		t.Fatalf("WithTemplate returned error: %v", err)
+	}
+	if len(review.Windows) != 0 || len(review.AgentPanes) != 1 {
+		t.Fatalf("expected review layout only, got windows=%+v panes=%+v", review.Windows, review.AgentPanes)
+	}
+	if len(review.CoreAgents) != 1 || review.CoreAgents[0].Command != "codex" {
+		t.Fatalf("expected template core agents, got %+v", review.CoreAgents)
+	}
+
+	if _, err := cfg.WithTemplate("missing"); err == nil {
+		t.Fatal("expected error for unknown template")
+	}
+}
+
+func TestMergeConfigsMergesTemplatesByName(t *testing.T) {
+	global := &Config{Templates: []TemplateConfig{
+		{Name: "backend", CoreAgents: []AgentConfig{{Command: "claude"}}, Windows: []WindowConfig{{Name: "server"}}},
+		{Name: "docs", Windows: []WindowConfig{{Name: "preview"}}},
+	}}
+	local := &Config{Templates: []TemplateConfig{
+		{Name: "Backend", Windows: []WindowConfig{{Name: "db"}}},
+		{Name: "frontend"},
+	}}
+
+	merged := mergeConfigs(global, local)
+	if got := strings.Join(merged.TemplateNames(), ","); got != "Backend,docs,frontend" {
+		t.Fatalf("unexpected template names %q", got)
+	}
+	backend := merged.Templates[0]
+	if len(backend.Windows) != 2 || backend.Windows[0].Name != "server" || backend.Windows[1].Name != "db" {
+		t.Fatalf("expected global then local windows, got %+v", backend.Windows)
+	}
+	if len(backend.CoreAgents) != 1 || backend.CoreAgents[0].Command != "claude" {
+		t.Fatalf("expected global core agents to be kept, got %+v", backend.CoreAgents)
+	}
+}
+
+func TestParseNoHistory(t *testing.T) {
+	cfg, err := Parse(writeTempConfig(t, "no_history:true\n"))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if !cfg.NoHistory {
+		t.Fatal("expected no_history:true to be parsed")
+	}
+
+	if _, err := Parse(writeTempConfig(t, "no_history:sometimes\n")); err == nil {
+		t.Fatal("expected error for invalid no_history value")
+	}
+
+	merged := mergeConfigs(&Config{}, &Config{NoHistory: true})
+	if !merged.NoHistory {
+		t.Fatal("expected local no_history to carry into the merged config")
+	}
+}
+
+func TestParseStripsTrailingComments(t *testing.T) {
+	path := writeTempConfig(t, `
+window:dev  # dev servers
+pane:npm run dev  # the bundler
+pane:echo "build # 1" && make	# quoted # stays
+vpane:open https://example.com/docs#setup
+remote_host:user@h # note
+remote_alias:h
+`)
+
+	cfg, err := Parse(path)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if got := cfg.Windows[0].Name; got != "dev" {
+		t.Fatalf("window name = %q, want %q", got, "dev")
+	}
+	want := []string{
+		"npm run dev",
+		`echo "build # 1" && make`,
+		"open https://example.com/docs#setup",
+	}
+	for i, w := range want {
+		if got := cfg.Windows[0].Panes[i].Command; got != w {
+			t.Errorf("pane %d command = %q, want %q", i, got, w)
+		}
+	}
+	if got := cfg.RemoteHosts[0].Host; got != "user@h" {
+		t.Fatalf("remote host = %q, want %q", got, "user@h")
+	}
+}
@@ -192,3 +192,36 @@ remote_alias:devbox
 		t.Fatalf("unexpected appended remote project: %+v", got)
 	}
 }
+
+func TestResolveRemoteProjectsUsesRemoteHostAliases(t *testing.T) {
+	cfg := &Config{
+		RemoteHosts: []RemoteHostConfig{
+			{Host: "user@devbox.example.com", Port: 2222, AttachMethod: "mosh", Alias: "devbox"},
+		},
+		RemoteProjects: []RemoteProjectConfig{
+			{Name: "atmux", Host: "devbox", WorkingDir: "/home/user/atmux"},
+			{Name: "dotfiles", Host: "user@shell.example.com", WorkingDir: "/home/user/dotfiles"},
+		},
+	}
+
+	projects, err := ResolveRemoteProjects(cfg)
+	if err != nil {
+		t.Fatalf("ResolveRemoteProjects returned error: %v", err)
+	}
+	if got, want := len(projects), 2; got != want {
+		t.Fatalf("expected %d projects, got %d", want, got)
+	}
+
+	first := projects[0]
+	if first.Remote.Host != "user@devbox.example.com" || first.Remote.Port != 2222 || first.Remote.AttachMethod != "mosh" {
+		t.Fatalf("expected alias to resolve to configured host, got %+v", first.Remote)
+	}
+	if first.SessionName != "agent-atmux" {
+		t.Fatalf("expected default session agent-atmux, got %q", first.SessionName)
+	}
+
+	second := projects[1]
+	if second.Remote.Host != "user@shell.example.com" || second.Remote.AttachMethod != "ssh" {
+		t.Fatalf("expected unknown host to be used directly, got %+v", second.Remote)
+	}
+}
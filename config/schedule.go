@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,18 +21,181 @@ const (
 	PreActionNewSession PreAction = "new_session"
 )
 
+// TargetMode defines which panes a scheduled command is sent to
+type TargetMode string
+
+const (
+	TargetModePane      TargetMode = "pane"       // The single pane in Target
+	TargetModeAllAgents TargetMode = "all_agents" // Every pane running a configured agent, found at run time
+)
+
+// CatchUpPolicy defines what happens to runs missed while nothing could
+// send them, e.g. because the machine was asleep
+type CatchUpPolicy string
+
+const (
+	CatchUpSkip    CatchUpPolicy = "skip"     // Drop missed runs and wait for the next scheduled time
+	CatchUpRunOnce CatchUpPolicy = "run_once" // Send once at the next chance, however many runs were missed
+)
+
+// JobSendMethod defines how a scheduled command is put into its pane
+type JobSendMethod string
+
+const (
+	JobSendKeys           JobSendMethod = "keys"            // Type the command, then press Enter
+	JobSendBracketedPaste JobSendMethod = "bracketed_paste" // Paste the command in one piece, then press Enter
+)
+
 // ScheduledJob represents a scheduled command
 type ScheduledJob struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`      // Optional friendly name
-	CronExpr  string    `json:"cron_expr"` // 5-field cron expression
-	Target    string    `json:"target"`    // Tmux target (session:window.pane)
-	Command   string    `json:"command"`   // Command to send
-	PreAction PreAction `json:"pre_action"`
-	Enabled   bool      `json:"enabled"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	LastRunAt time.Time `json:"last_run_at,omitempty"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`      // Optional friendly name
+	CronExpr   string     `json:"cron_expr"` // 5-field cron expression
+	Target     string     `json:"target"`    // Tmux target (session:window.pane)
+	TargetMode TargetMode `json:"target_mode,omitempty"`
+	Command    string     `json:"command"` // Command to send
+	PreAction  PreAction  `json:"pre_action"`
+	Enabled    bool       `json:"enabled"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastRunAt  time.Time  `json:"last_run_at,omitempty"`
+
+	CatchUp  CatchUpPolicy `json:"catch_up,omitempty"` // Empty means CatchUpSkip
+	Timezone string        `json:"timezone,omitempty"` // IANA name the cron expression is read in; empty means local time
+
+	SendMethod JobSendMethod `json:"send_method,omitempty"` // Empty means JobSendKeys
+}
+
+// PastesCommand reports whether the job sends its command as a bracketed
+// paste, for multi-line commands.
+func (j ScheduledJob) PastesCommand() bool {
+	return j.SendMethod == JobSendBracketedPaste
+}
+
+// LoadTimezone resolves an IANA timezone name such as "UTC" or
+// "Europe/Berlin". An empty name means local time.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// Location returns the timezone the job's schedule is read in, falling back
+// to local time when Timezone is unset or unknown.
+func (j ScheduledJob) Location() *time.Location {
+	loc, err := LoadTimezone(j.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// NextRun calculates the job's next run time from now in its timezone.
+func (j ScheduledJob) NextRun() (time.Time, error) {
+	return NextRunFrom(j.CronExpr, time.Now().In(j.Location()))
+}
+
+// FormatNextRun formats the job's next run time relative to now, adding the
+// wall-clock time in its timezone when it has one.
+func (j ScheduledJob) FormatNextRun() string {
+	if strings.TrimSpace(j.Timezone) == "" {
+		return FormatNextRun(j.CronExpr)
+	}
+	return FormatNextRunIn(j.CronExpr, j.Location())
+}
+
+// TargetsAllAgents reports whether the job sends to every agent pane rather
+// than a single target. Jobs saved before target modes existed use Target.
+func (j ScheduledJob) TargetsAllAgents() bool {
+	return j.TargetMode == TargetModeAllAgents
+}
+
+// TargetLabel describes the job's target for display.
+func (j ScheduledJob) TargetLabel() string {
+	if j.TargetsAllAgents() {
+		return "all agent panes"
+	}
+	return j.Target
+}
+
+// Validate checks that the job can be scheduled: a valid cron expression,
+// a target (unless it sends to all agents), a command, and known pre-action,
+// catch-up policy, send method, and timezone values.
+func (j ScheduledJob) Validate() error {
+	if err := ParseCron(j.CronExpr); err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
+	if !j.TargetsAllAgents() && strings.TrimSpace(j.Target) == "" {
+		return fmt.Errorf("job needs a target")
+	}
+	if strings.TrimSpace(j.Command) == "" {
+		return fmt.Errorf("job needs a command")
+	}
+	switch j.PreAction {
+	case "", PreActionNone, PreActionCompact, PreActionNewSession:
+	default:
+		return fmt.Errorf("unknown pre-action %q (use none, compact, or new_session)", j.PreAction)
+	}
+	switch j.CatchUp {
+	case "", CatchUpSkip, CatchUpRunOnce:
+	default:
+		return fmt.Errorf("unknown catch-up policy %q (use skip or run_once)", j.CatchUp)
+	}
+	switch j.SendMethod {
+	case "", JobSendKeys, JobSendBracketedPaste:
+	default:
+		return fmt.Errorf("unknown send method %q (use keys or bracketed_paste)", j.SendMethod)
+	}
+	if _, err := LoadTimezone(j.Timezone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CatchesUp reports whether the job runs once after missing scheduled runs.
+func (j ScheduledJob) CatchesUp() bool {
+	return j.CatchUp == CatchUpRunOnce
+}
+
+// ShouldRun reports whether the scheduler should send the job at now.
+//
+// A job is due when now falls in a minute its cron expression matches and it
+// hasn't already run in that minute. A run is missed when a matching minute
+// has passed since the job last ran (or was created, if it never ran) without
+// it being sent. Missed runs are dropped unless the job catches up, in which
+// case it is due once: after it runs, LastRunAt moves past every missed time,
+// so several missed runs still send only once.
+func (j ScheduledJob) ShouldRun(now time.Time) bool {
+	fields := strings.Fields(j.CronExpr)
+	if len(fields) != 5 {
+		return false
+	}
+	minute := now.In(j.Location()).Truncate(time.Minute)
+	if !j.LastRunAt.Before(minute) {
+		return false
+	}
+	if cronDue(minute, fields) {
+		return true
+	}
+	if !j.CatchesUp() {
+		return false
+	}
+
+	since := j.LastRunAt
+	if since.IsZero() {
+		since = j.CreatedAt
+	}
+	if since.IsZero() {
+		return false
+	}
+	missed, err := NextRunFrom(j.CronExpr, since.In(j.Location()))
+	return err == nil && missed.Before(minute)
 }
 
 // Schedule represents the schedule configuration
@@ -117,6 +281,10 @@ func (s *Schedule) UpdateJob(job ScheduledJob) error {
 		if j.ID == job.ID {
 			job.UpdatedAt = time.Now()
 			job.CreatedAt = j.CreatedAt
+			if job.LastRunAt.IsZero() {
+				// Edits from the wizard don't carry run history
+				job.LastRunAt = j.LastRunAt
+			}
 			s.Jobs[i] = job
 			return s.Save()
 		}
@@ -168,6 +336,17 @@ func (s *Schedule) EnabledJobs() []ScheduledJob {
 	return enabled
 }
 
+// DueJobs returns the enabled jobs the scheduler should send at now.
+func (s *Schedule) DueJobs(now time.Time) []ScheduledJob {
+	var due []ScheduledJob
+	for _, j := range s.Jobs {
+		if j.Enabled && j.ShouldRun(now) {
+			due = append(due, j)
+		}
+	}
+	return due
+}
+
 // SortedJobs returns jobs sorted by next run time
 func (s *Schedule) SortedJobs() []ScheduledJob {
 	jobs := make([]ScheduledJob, len(s.Jobs))
@@ -178,13 +357,49 @@ func (s *Schedule) SortedJobs() []ScheduledJob {
 			return jobs[i].Enabled
 		}
 		// Then by next run time
-		nextI, _ := NextRun(jobs[i].CronExpr)
-		nextJ, _ := NextRun(jobs[j].CronExpr)
+		nextI, _ := jobs[i].NextRun()
+		nextJ, _ := jobs[j].NextRun()
 		return nextI.Before(nextJ)
 	})
 	return jobs
 }
 
+// commandTokenPattern matches template tokens such as {{now:15:04}} in job commands
+var commandTokenPattern = regexp.MustCompile(`\{\{\s*(\w+)(?::([^}]*))?\}\}`)
+
+// Default layouts for template tokens written without one (e.g. {{date}})
+var commandTokenLayouts = map[string]string{
+	"now":  "15:04",
+	"date": "2006-01-02",
+}
+
+// ExpandCommand expands the template tokens in a scheduled command using Go
+// time formatting at time t: {{now:15:04}} and {{date:2006-01-02}}. Unknown
+// tokens are left as written and reported in the returned warnings.
+func ExpandCommand(command string, t time.Time) (string, []string) {
+	var warnings []string
+	expanded := commandTokenPattern.ReplaceAllStringFunc(command, func(token string) string {
+		parts := commandTokenPattern.FindStringSubmatch(token)
+		name, layout := parts[1], parts[2]
+		defaultLayout, ok := commandTokenLayouts[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown template token %s left as-is", token))
+			return token
+		}
+		if layout == "" {
+			layout = defaultLayout
+		}
+		return t.Format(layout)
+	})
+	return expanded, warnings
+}
+
+// ExpandedCommand returns the job's command with template tokens expanded
+// for a send at time t. See ExpandCommand.
+func (j ScheduledJob) ExpandedCommand(t time.Time) (string, []string) {
+	return ExpandCommand(j.Command, t)
+}
+
 // generateJobID creates a unique job ID
 func generateJobID() string {
 	return fmt.Sprintf("job_%d", time.Now().UnixNano())
@@ -469,7 +684,8 @@ func NextRun(expr string) (time.Time, error) {
 	return NextRunFrom(expr, time.Now())
 }
 
-// NextRunFrom calculates the next run time from a given time
+// NextRunFrom calculates the next run time from a given time, reading the
+// cron expression on the wall clock of from's location.
 func NextRunFrom(expr string, from time.Time) (time.Time, error) {
 	fields := strings.Fields(expr)
 	if len(fields) != 5 {
@@ -483,7 +699,7 @@ func NextRunFrom(expr string, from time.Time) (time.Time, error) {
 	endSearch := next.AddDate(4, 0, 0)
 
 	for next.Before(endSearch) {
-		if matchesCron(next, fields) {
+		if cronDue(next, fields) {
 			return next, nil
 		}
 		next = next.Add(time.Minute)
@@ -492,6 +708,56 @@ func NextRunFrom(expr string, from time.Time) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("no matching time found within 4 years")
 }
 
+// cronDue reports whether a cron expression fires at minute t, read on the
+// wall clock of t's location. Across DST changes, jobs pinned to a time of
+// day behave like classic cron: a time skipped when clocks spring forward
+// runs at the first minute after the jump, and a time repeated when clocks
+// fall back runs only the first time. Jobs with a wildcard minute or hour
+// keep their interval and aren't adjusted.
+func cronDue(t time.Time, fields []string) bool {
+	pinned := !strings.HasPrefix(fields[0], "*") && !strings.HasPrefix(fields[1], "*")
+	if matchesCron(t, fields) {
+		return !pinned || !repeatedWallTime(t)
+	}
+	return pinned && skippedMatch(t, fields)
+}
+
+// wallClock returns t's wall-clock time as a UTC time, for comparing clock
+// readings across zone offsets.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// repeatedWallTime reports whether t's clock reading already happened
+// earlier, as it does in the hour repeated when clocks fall back.
+func repeatedWallTime(t time.Time) bool {
+	wall := wallClock(t)
+	for _, shift := range []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour} {
+		if wallClock(t.Add(-shift)).Equal(wall) {
+			return true
+		}
+	}
+	return false
+}
+
+// skippedMatch reports whether t is the first minute after clocks sprang
+// forward and the expression matched a clock reading that was skipped.
+func skippedMatch(t time.Time, fields []string) bool {
+	prev := t.Add(-time.Minute)
+	_, prevOffset := prev.Zone()
+	_, offset := t.Zone()
+	if offset <= prevOffset {
+		return false
+	}
+	end := wallClock(t)
+	for w := wallClock(prev).Add(time.Minute); w.Before(end); w = w.Add(time.Minute) {
+		if matchesCron(w, fields) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchesCron checks if a time matches a cron expression
 func matchesCron(t time.Time, fields []string) bool {
 	minute, hour, day, month, weekday := fields[0], fields[1], fields[2], fields[3], fields[4]
@@ -558,8 +824,23 @@ func FormatNextRun(expr string) string {
 	if err != nil {
 		return "invalid"
 	}
+	return formatUntil(next, time.Now())
+}
+
+// FormatNextRunIn formats the next run time of an expression read in loc,
+// followed by its wall-clock time and zone abbreviation, e.g.
+// "in 3h 12m (09:00 UTC)".
+func FormatNextRunIn(expr string, loc *time.Location) string {
+	now := time.Now().In(loc)
+	next, err := NextRunFrom(expr, now)
+	if err != nil {
+		return "invalid"
+	}
+	return formatUntil(next, now) + " (" + next.Format("15:04 MST") + ")"
+}
 
-	now := time.Now()
+// formatUntil describes how long from now until next.
+func formatUntil(next, now time.Time) string {
 	diff := next.Sub(now)
 
 	switch {
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ScheduleRun records one attempt by the scheduler to send a job
+type ScheduleRun struct {
+	JobID  string    `json:"job_id"`
+	At     time.Time `json:"at"`
+	Target string    `json:"target"`          // Pane(s) the command was sent to, resolved at run time
+	Error  string    `json:"error,omitempty"` // Empty when the run succeeded
+}
+
+// Succeeded reports whether the run sent its command without error.
+func (r ScheduleRun) Succeeded() bool {
+	return r.Error == ""
+}
+
+const scheduleLogFileName = "schedule_runs.jsonl"
+
+// scheduleLogMaxBytes caps the run log. When the log reaches it, the log is
+// moved to a single ".1" backup and a fresh one started, so history covers
+// between one and two logs' worth of runs.
+const scheduleLogMaxBytes = 256 * 1024
+
+// ScheduleLogPath returns the path to the schedule run log
+func ScheduleLogPath() (string, error) {
+	dir, err := SettingsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, scheduleLogFileName), nil
+}
+
+// AppendScheduleRun adds a run to the schedule run log, rotating the log
+// when it has grown past its size cap.
+func AppendScheduleRun(run ScheduleRun) error {
+	path, err := ScheduleLogPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= scheduleLogMaxBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate schedule log: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open schedule log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write schedule log: %w", err)
+	}
+	return nil
+}
+
+// RecordRun logs a run of the job with the given ID and updates its
+// LastRunAt. The scheduler calls it after each attempt, successful or not.
+func (s *Schedule) RecordRun(run ScheduleRun) error {
+	logErr := AppendScheduleRun(run)
+	for i := range s.Jobs {
+		if s.Jobs[i].ID == run.JobID {
+			s.Jobs[i].LastRunAt = run.At
+			if err := s.Save(); err != nil {
+				return err
+			}
+			return logErr
+		}
+	}
+	if logErr != nil {
+		return logErr
+	}
+	return fmt.Errorf("job not found: %s", run.JobID)
+}
+
+// LoadScheduleRuns reads the schedule run log, oldest first, including the
+// rotated backup. Malformed lines are skipped.
+func LoadScheduleRuns() ([]ScheduleRun, error) {
+	path, err := ScheduleLogPath()
+	if err != nil {
+		return nil, err
+	}
+	var runs []ScheduleRun
+	for _, p := range []string{path + ".1", path} {
+		f, err := os.Open(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return runs, fmt.Errorf("failed to read schedule log: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 2*scheduleLogMaxBytes)
+		for scanner.Scan() {
+			var run ScheduleRun
+			if json.Unmarshal(scanner.Bytes(), &run) == nil && run.JobID != "" {
+				runs = append(runs, run)
+			}
+		}
+		f.Close()
+	}
+	return runs, nil
+}
+
+// RecentRuns returns up to n of a job's runs from runs (oldest first, as
+// LoadScheduleRuns returns them), newest first.
+func RecentRuns(runs []ScheduleRun, jobID string, n int) []ScheduleRun {
+	var recent []ScheduleRun
+	for i := len(runs) - 1; i >= 0 && len(recent) < n; i-- {
+		if runs[i].JobID == jobID {
+			recent = append(recent, runs[i])
+		}
+	}
+	return recent
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleRunLog(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	schedule := &Schedule{Jobs: []ScheduledJob{{ID: "a"}, {ID: "b"}}}
+	start := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		run := ScheduleRun{JobID: "a", At: start.Add(time.Duration(i) * time.Hour), Target: "dev:0.1"}
+		if i == 3 {
+			run.Error = "pane not found"
+		}
+		if err := schedule.RecordRun(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := schedule.RecordRun(ScheduleRun{JobID: "b", At: start, Target: "dev:1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := schedule.RecordRun(ScheduleRun{JobID: "gone", At: start}); err == nil {
+		t.Fatal("expected an error recording a run for an unknown job")
+	}
+
+	loaded, err := LoadSchedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Jobs[0].LastRunAt; !got.Equal(start.Add(3 * time.Hour)) {
+		t.Fatalf("LastRunAt = %v, want the latest run", got)
+	}
+
+	runs, err := LoadScheduleRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent := RecentRuns(runs, "a", 2)
+	if len(recent) != 2 || recent[0].Succeeded() || recent[0].Error != "pane not found" || !recent[1].Succeeded() {
+		t.Fatalf("unexpected recent runs: %+v", recent)
+	}
+	if !recent[0].At.After(recent[1].At) {
+		t.Fatal("expected newest runs first")
+	}
+}
+
+func TestScheduleRunLogRotates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	path, err := ScheduleLogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendScheduleRun(ScheduleRun{JobID: "old", At: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	// Pad the log past its cap
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(strings.Repeat("x", scheduleLogMaxBytes) + "\n")
+	f.Close()
+
+	if err := AppendScheduleRun(ScheduleRun{JobID: "new", At: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() >= scheduleLogMaxBytes {
+		t.Fatalf("expected a fresh log after rotation, got %v, %v", info, err)
+	}
+
+	// The backup is still read, and the padding is skipped as malformed
+	runs, err := LoadScheduleRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].JobID != "old" || runs[1].JobID != "new" {
+		t.Fatalf("unexpected runs: %+v", runs)
+	}
+}
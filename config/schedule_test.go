@@ -0,0 +1,160 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestExpandCommand(t *testing.T) {
+	at := time.Date(2026, 3, 9, 14, 5, 0, 0, time.UTC)
+	tests := []struct {
+		command  string
+		want     string
+		warnings int
+	}{
+		{"echo plain", "echo plain", 0},
+		{"log {{now:15:04}}", "log 14:05", 0},
+		{"backup-{{date:2006-01-02}}.tar", "backup-2026-03-09.tar", 0},
+		{"{{date}} {{now}}", "2026-03-09 14:05", 0},
+		{"echo {{counter}} at {{now:15:04}}", "echo {{counter}} at 14:05", 1},
+	}
+	for _, tt := range tests {
+		got, warnings := ExpandCommand(tt.command, at)
+		if got != tt.want || len(warnings) != tt.warnings {
+			t.Errorf("ExpandCommand(%q) = %q, %v; want %q with %d warnings", tt.command, got, warnings, tt.want, tt.warnings)
+		}
+	}
+
+	job := ScheduledJob{Command: "note {{bogus:x}}"}
+	_, warnings := job.ExpandedCommand(at)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "{{bogus:x}}") {
+		t.Fatalf("expected warning naming the unknown token, got %v", warnings)
+	}
+}
+
+func TestScheduledJobShouldRun(t *testing.T) {
+	// Daily at 09:00; the machine slept from before 09:00 until 11:30
+	created := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	lastRun := time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)
+	onTime := time.Date(2026, 3, 9, 9, 0, 30, 0, time.UTC)
+	woke := time.Date(2026, 3, 9, 11, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		job  ScheduledJob
+		now  time.Time
+		want bool
+	}{
+		{"on time", ScheduledJob{CronExpr: "0 9 * * *", LastRunAt: lastRun}, onTime, true},
+		{"already ran this minute", ScheduledJob{CronExpr: "0 9 * * *", LastRunAt: onTime.Add(-10 * time.Second)}, onTime, false},
+		{"not due", ScheduledJob{CronExpr: "0 9 * * *", LastRunAt: onTime}, onTime.Add(time.Hour), false},
+		{"missed with skip", ScheduledJob{CronExpr: "0 9 * * *", LastRunAt: lastRun}, woke, false},
+		{"missed with default policy", ScheduledJob{CronExpr: "0 9 * * *", LastRunAt: lastRun, CatchUp: ""}, woke, false},
+		{"missed with run once", ScheduledJob{CronExpr: "0 9 * * *", LastRunAt: lastRun, CatchUp: CatchUpRunOnce}, woke, true},
+		{"never ran, missed since creation", ScheduledJob{CronExpr: "0 9 * * *", CreatedAt: created, CatchUp: CatchUpRunOnce}, woke, true},
+		{"created after the missed time", ScheduledJob{CronExpr: "0 9 * * *", CreatedAt: woke.Add(-time.Hour), CatchUp: CatchUpRunOnce}, woke, false},
+		{"bad expression", ScheduledJob{CronExpr: "nope", CatchUp: CatchUpRunOnce}, woke, false},
+	}
+	for _, tt := range tests {
+		if got := tt.job.ShouldRun(tt.now); got != tt.want {
+			t.Errorf("%s: ShouldRun = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Catching up after several missed runs sends once, then waits for the
+	// next scheduled time
+	job := ScheduledJob{CronExpr: "*/15 * * * *", LastRunAt: time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC), CatchUp: CatchUpRunOnce}
+	if !job.ShouldRun(woke.Add(time.Minute)) {
+		t.Fatal("expected a catch-up run after waking")
+	}
+	job.LastRunAt = woke.Add(time.Minute)
+	if job.ShouldRun(woke.Add(2 * time.Minute)) {
+		t.Fatal("expected one catch-up run, not one per missed time")
+	}
+	if !job.ShouldRun(time.Date(2026, 3, 9, 11, 45, 0, 0, time.UTC)) {
+		t.Fatal("expected the next scheduled run to fire")
+	}
+}
+
+func TestNextRunFromTimezone(t *testing.T) {
+	tokyo, err := LoadTimezone("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC) // 21:00 in Tokyo
+	next, err := NextRunFrom("0 9 * * *", from.In(tokyo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("next = %v, want %v", next, want)
+	}
+
+	job := ScheduledJob{CronExpr: "0 9 * * *", Timezone: "Asia/Tokyo"}
+	if !job.ShouldRun(time.Date(2026, 3, 10, 0, 0, 20, 0, time.UTC)) {
+		t.Fatal("expected the job to run at 09:00 Tokyo time")
+	}
+	if job.ShouldRun(time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected the job not to run at 09:00 UTC")
+	}
+
+	if loc, err := LoadTimezone(""); err != nil || loc != time.Local {
+		t.Fatalf("empty timezone should be local, got %v, %v", loc, err)
+	}
+	if _, err := LoadTimezone("Nowhere/Special"); err == nil {
+		t.Fatal("expected an error for an unknown timezone")
+	}
+	if got := FormatNextRunIn("0 9 * * *", time.UTC); !strings.HasSuffix(got, "(09:00 UTC)") {
+		t.Fatalf("FormatNextRunIn = %q, want the time with its zone", got)
+	}
+}
+
+func TestNextRunFromAcrossDST(t *testing.T) {
+	ny, err := LoadTimezone("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, ny)
+	}
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// Clocks jump from 02:00 to 03:00 on March 8
+		{"skipped time runs after the jump", "30 2 * * *", at(3, 7, 12, 0), at(3, 8, 3, 0)},
+		{"skipped time is back the next day", "30 2 * * *", at(3, 8, 3, 0), at(3, 9, 2, 30)},
+		{"interval jobs skip the missing hour", "0 * * * *", at(3, 8, 1, 30), at(3, 8, 3, 0)},
+		// Clocks fall back from 02:00 to 01:00 on November 1
+		{"repeated time runs the first time", "30 1 * * *", at(10, 31, 12, 0), time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)},
+		{"repeated time runs once", "30 1 * * *", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(ny), at(11, 2, 1, 30)},
+		{"interval jobs run in both copies", "*/30 * * * *", time.Date(2026, 11, 1, 5, 45, 0, 0, time.UTC).In(ny), time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := NextRunFrom(tt.expr, tt.from)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: NextRunFrom(%q, %v) = %v, want %v", tt.name, tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleDueJobs(t *testing.T) {
+	now := time.Date(2026, 3, 9, 9, 0, 30, 0, time.UTC)
+	s := &Schedule{Jobs: []ScheduledJob{
+		{ID: "due", CronExpr: "0 9 * * *", Timezone: "UTC", Enabled: true},
+		{ID: "disabled", CronExpr: "0 9 * * *", Timezone: "UTC"},
+		{ID: "ran", CronExpr: "0 9 * * *", Timezone: "UTC", Enabled: true, LastRunAt: now.Add(-10 * time.Second)},
+		{ID: "later", CronExpr: "0 10 * * *", Timezone: "UTC", Enabled: true},
+	}}
+	due := s.DueJobs(now)
+	if len(due) != 1 || due[0].ID != "due" {
+		t.Fatalf("expected only the enabled job not yet run this minute, got %+v", due)
+	}
+}
@@ -2,8 +2,14 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -75,6 +81,165 @@ func (c *StalenessConfig) EffectiveSuggestionThreshold() int {
 	return c.SuggestionThreshold
 }
 
+// TreeCacheConfig controls the on-disk cache of remote browse trees.
+type TreeCacheConfig struct {
+	TTL      string `json:"ttl,omitempty"` // default "5m"; older entries are marked stale
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+const defaultTreeCacheTTL = 5 * time.Minute
+
+// ParsedTTL returns the cache TTL, falling back to the default.
+func (c *TreeCacheConfig) ParsedTTL() time.Duration {
+	if c == nil || c.TTL == "" {
+		return defaultTreeCacheTTL
+	}
+	if d, err := time.ParseDuration(c.TTL); err == nil && d > 0 {
+		return d
+	}
+	return defaultTreeCacheTTL
+}
+
+// SSHMultiplexConfig controls the shared SSH connection (ControlMaster)
+// atmux keeps open to each remote host.
+type SSHMultiplexConfig struct {
+	// Persist is how long an idle shared connection stays open, e.g. "10m"
+	// (default "5m").
+	Persist string `json:"persist,omitempty"`
+	// Disabled leaves connection sharing to the user's own ssh config.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+const defaultSSHControlPersist = 5 * time.Minute
+
+// ParsedPersist returns how long an idle shared connection is kept,
+// falling back to the default.
+func (c *SSHMultiplexConfig) ParsedPersist() time.Duration {
+	if c == nil || c.Persist == "" {
+		return defaultSSHControlPersist
+	}
+	if d, err := time.ParseDuration(c.Persist); err == nil && d > 0 {
+		return d
+	}
+	return defaultSSHControlPersist
+}
+
+// IsEnabled reports whether atmux should share one SSH connection per host.
+func (c *SSHMultiplexConfig) IsEnabled() bool {
+	return c == nil || !c.Disabled
+}
+
+// HistoryRetentionConfig controls automatic pruning of the recent-sessions history.
+type HistoryRetentionConfig struct {
+	// MaxAge removes entries not used within this long, e.g. "90d" or "720h".
+	// Empty keeps entries regardless of age.
+	MaxAge string `json:"max_age,omitempty"`
+	// MaxEntries keeps only the most recently used entries (0 = no extra limit).
+	MaxEntries int  `json:"max_entries,omitempty"`
+	Disabled   bool `json:"disabled,omitempty"`
+}
+
+// ParsedMaxAge returns the maximum entry age, or 0 when entries never expire.
+// Besides Go durations, a whole number of days such as "90d" is accepted.
+func (c *HistoryRetentionConfig) ParsedMaxAge() time.Duration {
+	if c == nil || c.Disabled || c.MaxAge == "" {
+		return 0
+	}
+	if days, ok := strings.CutSuffix(c.MaxAge, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour
+		}
+		return 0
+	}
+	if d, err := time.ParseDuration(c.MaxAge); err == nil && d > 0 {
+		return d
+	}
+	return 0
+}
+
+// EffectiveMaxEntries returns the entry limit, or 0 when there is none.
+func (c *HistoryRetentionConfig) EffectiveMaxEntries() int {
+	if c == nil || c.Disabled || c.MaxEntries < 0 {
+		return 0
+	}
+	return c.MaxEntries
+}
+
+// ThemeConfig selects the TUI color theme.
+type ThemeConfig struct {
+	// Name is a built-in theme: "dark" (default), "light", or "high-contrast".
+	Name string `json:"name,omitempty"`
+	// Colors overrides individual color roles (e.g. "primary", "dim", "stale")
+	// with lipgloss color values: ANSI indexes ("39") or hex ("#00afff").
+	Colors map[string]string `json:"colors,omitempty"`
+}
+
+// NewWindowDir controls where windows and panes created from browse start.
+type NewWindowDir string
+
+const (
+	// NewWindowDirSession starts new windows in the session directory (tmux default).
+	NewWindowDirSession NewWindowDir = "session"
+	// NewWindowDirPane starts new windows in the current pane's directory.
+	NewWindowDirPane NewWindowDir = "pane"
+)
+
+// AfterSend controls what happens to the browse command input once a
+// command has been sent.
+type AfterSend string
+
+const (
+	// AfterSendKeep leaves the command in the input to send again (default).
+	AfterSendKeep AfterSend = "keep"
+	// AfterSendClear clears the input as soon as the command is sent.
+	AfterSendClear AfterSend = "clear"
+	// AfterSendClearOnSuccess clears the input once tmux has accepted the
+	// command, keeping it to retry when sending fails.
+	AfterSendClearOnSuccess AfterSend = "clear-on-success"
+)
+
+// BrowseLayout arranges the browse tree and preview.
+type BrowseLayout string
+
+const (
+	// BrowseLayoutAuto stacks the panels in tall, narrow windows (default).
+	BrowseLayoutAuto BrowseLayout = "auto"
+	// BrowseLayoutSide always puts the tree beside the preview.
+	BrowseLayoutSide BrowseLayout = "side"
+	// BrowseLayoutStacked always puts the tree above the preview.
+	BrowseLayoutStacked BrowseLayout = "stacked"
+)
+
+// SessionSort orders sessions within each host group in the sessions list.
+type SessionSort string
+
+const (
+	// SessionSortActivity lists the most recently active sessions first (default).
+	SessionSortActivity SessionSort = "activity"
+	// SessionSortName lists sessions alphabetically.
+	SessionSortName SessionSort = "name"
+	// SessionSortCreated lists the newest sessions first.
+	SessionSortCreated SessionSort = "created"
+	// SessionSortWindows lists sessions with the most windows first.
+	SessionSortWindows SessionSort = "windows"
+	// SessionSortAttached lists the most recently attached sessions first,
+	// with never-attached sessions last.
+	SessionSortAttached SessionSort = "attached"
+)
+
+// SessionSorts lists the sort orders in the order the sessions list cycles them.
+var SessionSorts = []SessionSort{SessionSortActivity, SessionSortName, SessionSortCreated, SessionSortWindows, SessionSortAttached}
+
+// ValidSessionSort reports whether s is a recognized session sort order.
+func ValidSessionSort(s SessionSort) bool {
+	for _, sort := range SessionSorts {
+		if s == sort {
+			return true
+		}
+	}
+	return false
+}
+
 // Settings stores user preferences for atmux (agent-tmux)
 type Settings struct {
 	// DefaultAction controls what happens when running `atmux` with no subcommand
@@ -85,8 +250,308 @@ type Settings struct {
 	// Values: "auto" (default), "replace", "new-window"
 	RemoteAttachStrategy AttachStrategy `json:"remote_attach_strategy,omitempty"`
 
+	// RemoteTimeout bounds each tmux command sent to a remote host, e.g.
+	// "5s", so a stalled host shows as timed out instead of holding up the
+	// others (default 10s).
+	RemoteTimeout string `json:"remote_timeout,omitempty"`
+
+	// RemoteConcurrency caps how many remote hosts are fetched at once, so a
+	// long host list doesn't open every SSH connection together (default 4).
+	RemoteConcurrency int `json:"remote_concurrency,omitempty"`
+
+	// SSHMultiplex controls the shared SSH connection kept to each host.
+	SSHMultiplex *SSHMultiplexConfig `json:"ssh_multiplex,omitempty"`
+
 	// Staleness controls session staleness indicators in the sessions TUI.
 	Staleness *StalenessConfig `json:"staleness,omitempty"`
+
+	// TreeCache controls the cached remote tree shown while browse connects.
+	TreeCache *TreeCacheConfig `json:"tree_cache,omitempty"`
+
+	// HistoryRetention prunes old recent-sessions history entries.
+	HistoryRetention *HistoryRetentionConfig `json:"history_retention,omitempty"`
+
+	// HistoryDB relocates the history database, e.g. off a synced drive
+	// where sqlite misbehaves ("" = the default data directory). A directory
+	// gets history.sqlite3 inside it; ATMUX_HISTORY_DB overrides it.
+	HistoryDB string `json:"history_db,omitempty"`
+
+	// Agents holds the known agents and their flags, used for new sessions
+	// unless a config file lists agent: lines. Written by onboard.
+	Agents []AgentPreference `json:"agents,omitempty"`
+
+	// CustomAgents adds agent CLIs to the built-in ones onboard and the
+	// settings screen offer, or replaces a built-in agent of the same name.
+	CustomAgents []AgentSpec `json:"custom_agents,omitempty"`
+
+	// Theme selects the TUI color theme and per-role color overrides.
+	Theme *ThemeConfig `json:"theme,omitempty"`
+
+	// AccessibleMode shows staleness with symbols (! stale, ~ getting stale)
+	// instead of relying on color alone. Also enabled by the NO_COLOR env var.
+	AccessibleMode bool `json:"accessible_mode,omitempty"`
+
+	// RestoreFocus remembers the window or pane atmux last attached or
+	// switched to in each session and returns to it on resume, instead of
+	// tmux's own last-active window.
+	RestoreFocus bool `json:"restore_focus,omitempty"`
+
+	// SkipKillConfirm kills sessions, windows, and panes without a y/n prompt.
+	// Killing the currently attached session always asks for confirmation.
+	SkipKillConfirm bool `json:"skip_kill_confirm,omitempty"`
+
+	// SkipShellConfirm sends commands from browse to panes running a plain
+	// shell (bash, zsh, fish, ...) without asking first.
+	SkipShellConfirm bool `json:"skip_shell_confirm,omitempty"`
+
+	// CommandPrefix and CommandSuffix wrap every command sent to a pane
+	// (from browse, `atmux send`, and scheduled jobs), each separated from
+	// it by a space. atmux's own /compact and /remote-control are sent as
+	// they are. Empty by default.
+	CommandPrefix string `json:"command_prefix,omitempty"`
+	CommandSuffix string `json:"command_suffix,omitempty"`
+
+	// WatchIdleAfter is how long a pane watched in browse must stay quiet
+	// before atmux says it went idle, e.g. "30s" (the default).
+	WatchIdleAfter string `json:"watch_idle_after,omitempty"`
+
+	// WatchNotify also sends a desktop notification (terminal-notifier or
+	// notify-send) when a watched pane goes idle, not just the bell.
+	WatchNotify bool `json:"watch_notify,omitempty"`
+
+	// SessionNameTemplate names the session for a directory, e.g.
+	// "{parent}-{basename}" (default "{basename}"). Names get the "agent-"
+	// prefix unless the template starts with "agent-" or "atmux-".
+	SessionNameTemplate string `json:"session_name_template,omitempty"`
+
+	// StatusClock shows the time at the right of the browse status bar.
+	StatusClock bool `json:"status_clock,omitempty"`
+
+	// StatusCounts shows session, window and pane counts at the right of the
+	// browse status bar, per host when browsing several.
+	StatusCounts bool `json:"status_counts,omitempty"`
+
+	// NewWindowDir controls where windows and panes created from browse start.
+	// Values: "session" (default), "pane"
+	NewWindowDir NewWindowDir `json:"new_window_dir,omitempty"`
+
+	// AfterSend controls whether browse clears the command input after
+	// sending it. Values: "keep" (default), "clear", "clear-on-success"
+	AfterSend AfterSend `json:"after_send,omitempty"`
+
+	// SessionsSort is the sessions list order, cycled with o in the list.
+	// Values: "activity" (default), "name", "created", "windows"
+	SessionsSort SessionSort `json:"sessions_sort,omitempty"`
+
+	// AbsoluteTimes shows when recent sessions were last used as local
+	// timestamps instead of "3h ago", in the sessions list and landing page.
+	// Toggled with t on either screen.
+	AbsoluteTimes bool `json:"absolute_times,omitempty"`
+
+	// PinnedSessions are listed first in their host group in the sessions
+	// list, toggled with p. Remote sessions are stored as "host/name".
+	PinnedSessions []string `json:"pinned_sessions,omitempty"`
+
+	// RefreshInterval is how often browse refreshes the tree, e.g. "5s".
+	// "0s" turns auto-refresh off. The --refresh flag takes precedence.
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+
+	// PreviewRefreshInterval is how often browse re-captures the previewed
+	// pane, e.g. "1s", on a timer of its own. Unset or "0s" re-captures it
+	// with each tree refresh.
+	PreviewRefreshInterval string `json:"preview_refresh_interval,omitempty"`
+
+	// MobileWidth is the terminal width below which browse switches to the
+	// mobile layout (default 60).
+	MobileWidth int `json:"mobile_width,omitempty"`
+
+	// HideBeads hides beads issue counts in the sessions list, like --no-beads.
+	HideBeads bool `json:"hide_beads,omitempty"`
+
+	// BeadsCommand runs in a session's directory when its beads label is
+	// clicked in the sessions list (default "bd list").
+	BeadsCommand string `json:"beads_command,omitempty"`
+
+	// TreeWidthPercent is the browse tree's share of the window width, as
+	// last set by dragging the divider or pressing < / > (default 35).
+	TreeWidthPercent int `json:"tree_width_percent,omitempty"`
+
+	// BrowseLayout places the browse tree beside or above the preview.
+	// Values: "auto" (default), "side", "stacked"
+	BrowseLayout BrowseLayout `json:"browse_layout,omitempty"`
+
+	// Keybind is the tmux keybinding `atmux keybind` installs by default.
+	Keybind *KeybindConfig `json:"keybind,omitempty"`
+}
+
+// KeybindConfig is the preferred tmux keybinding for opening atmux.
+type KeybindConfig struct {
+	// Key is bound after the tmux prefix, e.g. "S" or "C-s" (default "S").
+	Key string `json:"key,omitempty"`
+
+	// Target is the screen the key opens.
+	// Values: "browse" (default), "sessions", "landing"
+	Target string `json:"target,omitempty"`
+}
+
+// KeybindKey returns the preferred keybinding key, defaulting to "S".
+func (s *Settings) KeybindKey() string {
+	if s == nil || s.Keybind == nil || s.Keybind.Key == "" {
+		return "S"
+	}
+	return s.Keybind.Key
+}
+
+// KeybindTarget returns the preferred keybinding target, defaulting to
+// "browse".
+func (s *Settings) KeybindTarget() string {
+	if s == nil || s.Keybind == nil || s.Keybind.Target == "" {
+		return "browse"
+	}
+	return s.Keybind.Target
+}
+
+const (
+	defaultRefreshInterval   = 2 * time.Second
+	defaultMobileWidth       = 60
+	defaultRemoteTimeout     = 10 * time.Second
+	defaultRemoteConcurrency = 4
+	defaultBeadsCommand      = "bd list"
+
+	// DefaultSessionNameTemplate names sessions after their directory.
+	DefaultSessionNameTemplate = "{basename}"
+)
+
+// SessionNameTokens are the placeholders a session name template can use:
+// the directory's name, its parent's name, the name of the git repository
+// it is in, and a short hash of its full path.
+var SessionNameTokens = []string{"{basename}", "{parent}", "{git}", "{hash}"}
+
+var sessionNameToken = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ValidateSessionNameTemplate checks that a session name template only uses
+// known tokens. Empty means the default.
+func ValidateSessionNameTemplate(template string) error {
+	for _, token := range sessionNameToken.FindAllString(template, -1) {
+		if !slices.Contains(SessionNameTokens, token) {
+			return fmt.Errorf("unknown token %s (use %s)", token, strings.Join(SessionNameTokens, ", "))
+		}
+	}
+	return nil
+}
+
+// EffectiveSessionNameTemplate returns the session name template, falling
+// back to the default when unset or invalid.
+func (s *Settings) EffectiveSessionNameTemplate() string {
+	if s == nil || strings.TrimSpace(s.SessionNameTemplate) == "" || ValidateSessionNameTemplate(s.SessionNameTemplate) != nil {
+		return DefaultSessionNameTemplate
+	}
+	return s.SessionNameTemplate
+}
+
+// ParsedRefreshInterval returns the browse refresh interval, falling back to
+// the default when unset or invalid.
+func (s *Settings) ParsedRefreshInterval() time.Duration {
+	if s == nil || s.RefreshInterval == "" {
+		return defaultRefreshInterval
+	}
+	if d, err := time.ParseDuration(s.RefreshInterval); err == nil && d >= 0 {
+		return d
+	}
+	return defaultRefreshInterval
+}
+
+// ParsedPreviewRefreshInterval returns the preview refresh interval, or 0
+// when the preview refreshes with the tree (unset or invalid).
+func (s *Settings) ParsedPreviewRefreshInterval() time.Duration {
+	if s == nil || s.PreviewRefreshInterval == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(s.PreviewRefreshInterval); err == nil && d > 0 {
+		return d
+	}
+	return 0
+}
+
+// ParsedRemoteTimeout returns the per-command timeout for remote hosts,
+// falling back to the default when unset or invalid.
+func (s *Settings) ParsedRemoteTimeout() time.Duration {
+	if s == nil || s.RemoteTimeout == "" {
+		return defaultRemoteTimeout
+	}
+	if d, err := time.ParseDuration(s.RemoteTimeout); err == nil && d > 0 {
+		return d
+	}
+	return defaultRemoteTimeout
+}
+
+// WrapCommand adds the configured prefix and suffix to a command about to
+// be sent to a pane.
+func (s *Settings) WrapCommand(command string) string {
+	if s == nil {
+		return command
+	}
+	return WrapCommand(s.CommandPrefix, s.CommandSuffix, command)
+}
+
+// WrapCommand joins prefix, command, and suffix with spaces, skipping the
+// empty ones.
+func WrapCommand(prefix, suffix, command string) string {
+	if prefix != "" {
+		command = prefix + " " + command
+	}
+	if suffix != "" {
+		command += " " + suffix
+	}
+	return command
+}
+
+// EffectiveBeadsCommand returns the command the sessions list runs for a
+// clicked beads label, defaulting to "bd list".
+func (s *Settings) EffectiveBeadsCommand() string {
+	if s == nil || strings.TrimSpace(s.BeadsCommand) == "" {
+		return defaultBeadsCommand
+	}
+	return s.BeadsCommand
+}
+
+// ParsedWatchIdleAfter returns how long a watched pane must stay quiet to
+// count as idle, or 0 for the default when unset or invalid.
+func (s *Settings) ParsedWatchIdleAfter() time.Duration {
+	if s == nil || s.WatchIdleAfter == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(s.WatchIdleAfter); err == nil && d > 0 {
+		return d
+	}
+	return 0
+}
+
+// EffectiveRemoteConcurrency returns how many remote hosts may be fetched
+// at once, falling back to the default.
+func (s *Settings) EffectiveRemoteConcurrency() int {
+	if s == nil || s.RemoteConcurrency <= 0 {
+		return defaultRemoteConcurrency
+	}
+	return s.RemoteConcurrency
+}
+
+// EffectiveMobileWidth returns the mobile layout width threshold, falling
+// back to the default.
+func (s *Settings) EffectiveMobileWidth() int {
+	if s == nil || s.MobileWidth <= 0 {
+		return defaultMobileWidth
+	}
+	return s.MobileWidth
+}
+
+// SymbolIndicatorsEnabled reports whether state should be shown with symbols
+// rather than color alone: AccessibleMode is set or NO_COLOR is non-empty.
+func (s *Settings) SymbolIndicatorsEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	return s != nil && s.AccessibleMode
 }
 
 // DefaultSettings returns settings with default values
@@ -96,9 +561,29 @@ func DefaultSettings() *Settings {
 	}
 }
 
-// SettingsDir returns the config directory path
+// UserConfigHome returns the base directory for user config:
+// $XDG_CONFIG_HOME when set to an absolute path (on any OS), else ~/.config
+// on Linux and the OS equivalent elsewhere (e.g. ~/Library/Application
+// Support on macOS). Relative XDG paths are ignored, as the spec requires.
+func UserConfigHome() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return os.UserConfigDir()
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+// SettingsDir returns the config directory path. The global config,
+// settings.json, and schedule.json all live here.
 func SettingsDir() (string, error) {
-	configDir, err := os.UserConfigDir()
+	configDir, err := UserConfigHome()
 	if err != nil {
 		return "", err
 	}
@@ -106,7 +591,7 @@ func SettingsDir() (string, error) {
 }
 
 func legacySettingsDir() (string, error) {
-	configDir, err := os.UserConfigDir()
+	configDir, err := UserConfigHome()
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPathsFollowXDGConfigHome(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", base)
+
+	want := filepath.Join(base, "atmux")
+	paths := map[string]func() (string, error){
+		"settings dir": SettingsDir,
+		"settings":     SettingsPath,
+		"global":       GlobalConfigPath,
+		"schedule":     SchedulePath,
+	}
+	for name, path := range paths {
+		got, err := path()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != want && filepath.Dir(got) != want {
+			t.Errorf("%s path = %q, want it in %q", name, got, want)
+		}
+	}
+}
+
+func TestUserConfigHomeIgnoresRelativeXDG(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the ~/.config fallback is Linux-specific")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, xdg := range []string{"", "relative/config"} {
+		t.Setenv("XDG_CONFIG_HOME", xdg)
+		got, err := UserConfigHome()
+		if err != nil {
+			t.Fatalf("XDG_CONFIG_HOME=%q: %v", xdg, err)
+		}
+		if want := filepath.Join(home, ".config"); got != want {
+			t.Errorf("XDG_CONFIG_HOME=%q: got %q, want %q", xdg, got, want)
+		}
+	}
+}
+
+func TestSettingsSaveUnderXDGConfigHome(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", base)
+
+	settings := &Settings{DefaultAction: "sessions"}
+	if err := settings.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "atmux", "settings.json")); err != nil {
+		t.Fatalf("expected settings.json under XDG_CONFIG_HOME: %v", err)
+	}
+	loaded, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if loaded.DefaultAction != "sessions" {
+		t.Fatalf("expected the saved settings back, got %q", loaded.DefaultAction)
+	}
+}
+
+func TestAgentCommandsFromPreferences(t *testing.T) {
+	if got := (&Settings{}).AgentCommands(); got != nil {
+		t.Fatalf("expected no agents without saved preferences, got %+v", got)
+	}
+
+	s := &Settings{Agents: []AgentPreference{
+		{Name: "Claude", Command: "claude", Enabled: true, Yolo: true, Flags: " --model opus "},
+		{Name: "Codex", Command: "codex", Yolo: true},
+		{Name: "Gemini CLI", Command: "gemini", Enabled: true, Yolo: true},
+	}}
+	var got []string
+	for _, agent := range s.AgentCommands() {
+		got = append(got, agent.Command)
+	}
+	want := []string{"claude --dangerously-skip-permissions --model opus", "gemini --yolo"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("AgentCommands() = %q, want %q", got, want)
+	}
+}
+
+func TestYoloFlag(t *testing.T) {
+	tests := map[string]string{
+		"claude":           "--dangerously-skip-permissions",
+		"codex --model o3": "--yolo",
+		"gemini":           "--yolo",
+		"aider --no-git":   "",
+		"":                 "",
+	}
+	for command, want := range tests {
+		if got := YoloFlag(BuiltinAgents(), command); got != want {
+			t.Errorf("YoloFlag(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestKnownAgentsAddsCustomAgents(t *testing.T) {
+	s := &Settings{CustomAgents: []AgentSpec{
+		{Name: "Aider", Command: "aider", YoloFlag: "--yes-always"},
+		{Name: "Codex", Command: "codex", YoloFlag: "--full-auto"},
+		{Name: "", Command: "broken"},
+	}}
+	known := s.KnownAgents()
+	var names []string
+	for _, a := range known {
+		names = append(names, a.Name)
+	}
+	if strings.Join(names, ",") != "Claude,Codex,Gemini CLI,Aider" {
+		t.Fatalf("KnownAgents() names = %q", names)
+	}
+	if got := YoloFlag(known, "codex"); got != "--full-auto" {
+		t.Fatalf("expected the custom Codex entry to replace the built-in, got %q", got)
+	}
+	if got := YoloFlag(known, "aider --no-git"); got != "--yes-always" {
+		t.Fatalf("expected aider's flag from the registry, got %q", got)
+	}
+}
+
+func TestEffectiveAgentsOffersNewAgentsDisabled(t *testing.T) {
+	fresh := (&Settings{CustomAgents: []AgentSpec{{Name: "Aider", Command: "aider", Default: true}}}).EffectiveAgents()
+	if len(fresh) != 4 || !fresh[0].Enabled || !fresh[0].Yolo || fresh[2].Enabled || !fresh[3].Enabled || fresh[3].Yolo {
+		t.Fatalf("expected registry defaults before anything is saved, got %+v", fresh)
+	}
+
+	s := &Settings{
+		Agents:       []AgentPreference{{Name: "Claude", Command: "claude", Enabled: true}},
+		CustomAgents: []AgentSpec{{Name: "Aider", Command: "aider", Default: true}},
+	}
+	got := s.EffectiveAgents()
+	if len(got) != 4 || got[0].Name != "Claude" {
+		t.Fatalf("expected saved agents first, then the rest of the registry, got %+v", got)
+	}
+	for _, p := range got[1:] {
+		if p.Enabled {
+			t.Fatalf("expected agents not saved yet to start disabled, got %+v", p)
+		}
+	}
+}
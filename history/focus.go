@@ -0,0 +1,31 @@
+package history
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SetLastFocus records target, a window or pane such as "work:1.2", as the
+// last one focused in key's session, replacing any earlier one.
+func (s *Store) SetLastFocus(key NoteKey, target string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO session_focus (session_name, host, target, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (session_name, host) DO UPDATE SET
+			target = excluded.target,
+			updated_at = excluded.updated_at
+	`, key.SessionName, key.Host, target, time.Now().Unix())
+	return err
+}
+
+// LastFocus returns the target last focused in key's session, or "" when
+// none has been recorded.
+func (s *Store) LastFocus(key NoteKey) (string, error) {
+	var target string
+	err := s.db.QueryRow("SELECT target FROM session_focus WHERE session_name = ? AND host = ?", key.SessionName, key.Host).Scan(&target)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return target, err
+}
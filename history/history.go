@@ -3,17 +3,25 @@ package history
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/porganisciak/agent-tmux/config"
 )
 
 const (
 	schemaVersion = 3
 	maxHistory    = 100 // Maximum entries before LRU eviction
+
+	// autoPruneInterval is how often Open applies the retention settings.
+	autoPruneInterval = 24 * time.Hour
 )
 
 // Entry represents a single agent history entry.
@@ -33,8 +41,19 @@ type Store struct {
 	db *sql.DB
 }
 
-// DataDir returns the user data directory for atmux.
+// DataDir returns the user data directory for atmux: $XDG_DATA_HOME/atmux
+// when XDG_DATA_HOME is set to an absolute path (on any OS), else
+// ~/.local/share/atmux on Linux and the OS equivalent elsewhere.
 func DataDir() (string, error) {
+	if base := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(base) {
+		return filepath.Join(base, "atmux"), nil
+	}
+	return platformDataDir()
+}
+
+// platformDataDir returns the OS's own data directory for atmux, ignoring
+// XDG_DATA_HOME.
+func platformDataDir() (string, error) {
 	var base string
 	switch runtime.GOOS {
 	case "darwin":
@@ -53,41 +72,124 @@ func DataDir() (string, error) {
 			base = filepath.Join(home, "AppData", "Roaming")
 		}
 	default: // Linux and others
-		base = os.Getenv("XDG_DATA_HOME")
-		if base == "" {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return "", err
-			}
-			base = filepath.Join(home, ".local", "share")
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
 		}
+		base = filepath.Join(home, ".local", "share")
 	}
 	return filepath.Join(base, "atmux"), nil
 }
 
-// DBPath returns the full path to the history database.
+// DBPathEnv names the environment variable that relocates the history
+// database, overriding history_db in settings.json.
+const DBPathEnv = "ATMUX_HISTORY_DB"
+
+const dbFileName = "history.sqlite3"
+
+// DBPath returns the full path to the history database: $ATMUX_HISTORY_DB,
+// else history_db from settings.json, else history.sqlite3 in DataDir.
 func DBPath() (string, error) {
+	settings, _ := config.LoadSettings()
+	return dbPathFor(settings)
+}
+
+func dbPathFor(settings *config.Settings) (string, error) {
+	path := os.Getenv(DBPathEnv)
+	if path == "" {
+		path = settings.HistoryDB
+	}
+	if path != "" {
+		return resolveDBPath(path)
+	}
 	dir, err := DataDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, "history.sqlite3"), nil
+	path = filepath.Join(dir, dbFileName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// XDG_DATA_HOME used to be ignored outside Linux, so history from
+		// then is still in the platform directory: keep using it there
+		if legacyDir, err := platformDataDir(); err == nil {
+			legacy := filepath.Join(legacyDir, dbFileName)
+			if _, err := os.Stat(legacy); err == nil {
+				return legacy, nil
+			}
+		}
+	}
+	return path, nil
+}
+
+// resolveDBPath expands a leading ~ in a configured database path and makes
+// it absolute. A path naming an existing directory gets the default file
+// name inside it.
+func resolveDBPath(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || rest[0] == '/') {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, dbFileName)
+	}
+	return path, nil
+}
+
+// checkWritable reports an error unless a file can be created in dir, so a
+// read-only or unmounted location fails clearly instead of deep in sqlite.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".atmux-write-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
 }
 
 // Open opens the history store, creating the database if needed.
 func Open() (*Store, error) {
-	dbPath, err := DBPath()
+	settings, _ := config.LoadSettings()
+	dbPath, err := dbPathFor(settings)
 	if err != nil {
 		return nil, err
 	}
 
-	// Ensure directory exists
+	// Ensure directory exists and can take the database and its journal
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory %s: %w", dir, err)
+	}
+	if err := checkWritable(dir); err != nil {
+		return nil, fmt.Errorf("history directory %s is not writable (move it with history_db in settings.json or %s): %w", dir, DBPathEnv, err)
+	}
+
+	store, err := openPath(dbPath)
+	if err != nil {
 		return nil, err
 	}
 
-	db, err := sql.Open("sqlite3", dbPath+"?_busy_timeout=5000")
+	// Retention is best effort: a failed prune shouldn't block using history.
+	store.autoPrune(settings.HistoryRetention, time.Now())
+
+	return store, nil
+}
+
+// dbOptions lets the scheduler and interactive atmux processes share the
+// database: WAL keeps readers and a writer from blocking each other,
+// synchronous=NORMAL is durable enough under WAL, and immediate
+// transactions take the write lock up front so busy_timeout covers them
+// instead of failing with "database is locked" when a read upgrades.
+const dbOptions = "?_busy_timeout=5000&_journal_mode=WAL&_synchronous=NORMAL&_txlock=immediate"
+
+// openPath opens the database at dbPath and brings its schema up to date.
+func openPath(dbPath string) (*Store, error) {
+	db, err := sql.Open("sqlite3", dbPath+dbOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +199,6 @@ func Open() (*Store, error) {
 		db.Close()
 		return nil, err
 	}
-
 	return store, nil
 }
 
@@ -155,6 +256,27 @@ func (s *Store) migrate() error {
 		CREATE INDEX IF NOT EXISTS agent_history_name
 			ON agent_history (name);
 
+		CREATE TABLE IF NOT EXISTS history_meta (
+			key TEXT PRIMARY KEY,
+			value INTEGER NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS session_notes (
+			session_name TEXT NOT NULL,
+			host TEXT NOT NULL DEFAULT '',
+			note TEXT NOT NULL,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (session_name, host)
+		);
+
+		CREATE TABLE IF NOT EXISTS session_focus (
+			session_name TEXT NOT NULL,
+			host TEXT NOT NULL DEFAULT '',
+			target TEXT NOT NULL,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (session_name, host)
+		);
+
 		PRAGMA user_version = 3;
 	`)
 	if err != nil {
@@ -174,46 +296,92 @@ func (s *Store) SaveEntry(name, workingDir, sessionName, host, attachMethod stri
 		attachMethod = "ssh"
 	}
 
-	// Try to update existing entry first
-	result, err := s.db.Exec(`
-		UPDATE agent_history
-		SET name = ?, last_used_at = ?, attach_method = ?
-		WHERE session_name = ? AND working_directory = ? AND host = ?
-	`, name, now, attachMethod, sessionName, workingDir, host)
-	if err != nil {
-		return err
-	}
-
-	affected, err := result.RowsAffected()
+	// One upsert, so another process saving the same entry between an
+	// update and an insert can't trip the unique index
+	_, err := s.db.Exec(`
+		INSERT INTO agent_history (name, working_directory, session_name, host, attach_method, created_at, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (session_name, working_directory, host) DO UPDATE
+		SET name = excluded.name, last_used_at = excluded.last_used_at, attach_method = excluded.attach_method
+	`, name, workingDir, sessionName, host, attachMethod, now, now)
 	if err != nil {
 		return err
 	}
 
-	if affected == 0 {
-		// Insert new entry
-		_, err = s.db.Exec(`
-			INSERT INTO agent_history (name, working_directory, session_name, host, attach_method, created_at, last_used_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?)
-		`, name, workingDir, sessionName, host, attachMethod, now, now)
-		if err != nil {
-			return err
-		}
-	}
-
 	// Enforce max history limit (LRU eviction)
 	return s.enforceLimitLRU()
 }
 
 // enforceLimitLRU removes oldest entries if over the limit.
 func (s *Store) enforceLimitLRU() error {
-	_, err := s.db.Exec(`
+	_, err := s.PruneToMaxEntries(maxHistory)
+	return err
+}
+
+// PruneOlderThan removes entries not used within d and returns how many were
+// removed.
+func (s *Store) PruneOlderThan(d time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-d).Unix()
+	result, err := s.db.Exec("DELETE FROM agent_history WHERE last_used_at < ?", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PruneToMaxEntries keeps the n most recently used entries, removes the rest,
+// and returns how many were removed.
+func (s *Store) PruneToMaxEntries(n int) (int64, error) {
+	if n < 0 {
+		n = 0
+	}
+	result, err := s.db.Exec(`
 		DELETE FROM agent_history
 		WHERE id NOT IN (
 			SELECT id FROM agent_history
-			ORDER BY last_used_at DESC
+			ORDER BY last_used_at DESC, id DESC
 			LIMIT ?
 		)
-	`, maxHistory)
+	`, n)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// autoPrune applies the retention settings at most once per
+// autoPruneInterval, recording when it last ran in history_meta.
+func (s *Store) autoPrune(retention *config.HistoryRetentionConfig, now time.Time) error {
+	maxAge := retention.ParsedMaxAge()
+	maxEntries := retention.EffectiveMaxEntries()
+	if maxAge == 0 && maxEntries == 0 {
+		return nil
+	}
+
+	var lastPruned int64
+	err := s.db.QueryRow("SELECT value FROM history_meta WHERE key = 'last_pruned_at'").Scan(&lastPruned)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if now.Sub(time.Unix(lastPruned, 0)) < autoPruneInterval {
+		return nil
+	}
+
+	if maxAge > 0 {
+		if _, err := s.PruneOlderThan(maxAge); err != nil {
+			return err
+		}
+	}
+	if maxEntries > 0 {
+		if _, err := s.PruneToMaxEntries(maxEntries); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO history_meta (key, value) VALUES ('last_pruned_at', ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`, now.Unix())
 	return err
 }
 
@@ -255,6 +423,17 @@ func (s *Store) DeleteBySessionName(sessionName string) error {
 	return err
 }
 
+// RenameSession points the local entry for sessionName in workingDir at
+// newName, replacing any entry newName already had there.
+func (s *Store) RenameSession(sessionName, workingDir, newName string) error {
+	_, err := s.db.Exec(`
+		UPDATE OR REPLACE agent_history
+		SET session_name = ?
+		WHERE session_name = ? AND working_directory = ? AND host = ''
+	`, newName, sessionName, workingDir)
+	return err
+}
+
 // ClearHistory removes all entries.
 func (s *Store) ClearHistory() error {
 	_, err := s.db.Exec("DELETE FROM agent_history")
@@ -289,3 +468,151 @@ func (s *Store) Count() (int, error) {
 	err := s.db.QueryRow("SELECT COUNT(*) FROM agent_history").Scan(&count)
 	return count, err
 }
+
+// exportVersion is the format version written by ExportJSON.
+const exportVersion = 1
+
+// ExportedEntry is one history entry in the JSON export format.
+type ExportedEntry struct {
+	Name             string    `json:"name"`
+	WorkingDirectory string    `json:"working_directory"`
+	SessionName      string    `json:"session_name"`
+	Host             string    `json:"host,omitempty"`
+	AttachMethod     string    `json:"attach_method,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastUsedAt       time.Time `json:"last_used_at"`
+}
+
+// exportFile is the top-level JSON document written by ExportJSON.
+type exportFile struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Entries    []json.RawMessage `json:"entries"`
+}
+
+// ImportResult summarizes what ImportJSON did with each row.
+type ImportResult struct {
+	Added   int // New entries inserted
+	Updated int // Existing entries replaced by a newer imported row
+	Kept    int // Imported rows ignored because the local entry is as new or newer
+	Skipped int // Malformed rows that were not imported
+}
+
+// ExportJSON writes every history entry to w as indented JSON.
+func (s *Store) ExportJSON(w io.Writer) error {
+	entries, err := s.LoadHistory()
+	if err != nil {
+		return err
+	}
+
+	doc := exportFile{Version: exportVersion, ExportedAt: time.Now().UTC(), Entries: []json.RawMessage{}}
+	for _, e := range entries {
+		raw, err := json.Marshal(ExportedEntry{
+			Name:             e.Name,
+			WorkingDirectory: e.WorkingDirectory,
+			SessionName:      e.SessionName,
+			Host:             e.Host,
+			AttachMethod:     e.AttachMethod,
+			CreatedAt:        e.CreatedAt.UTC(),
+			LastUsedAt:       e.LastUsedAt.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		doc.Entries = append(doc.Entries, raw)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// ImportJSON merges entries written by ExportJSON into the store.
+// Rows are matched on (session_name, working_directory, host); when both
+// sides have an entry, the one with the newest LastUsedAt wins. Malformed
+// rows are skipped and counted rather than aborting the import.
+func (s *Store) ImportJSON(r io.Reader) (ImportResult, error) {
+	var result ImportResult
+	var doc exportFile
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return result, fmt.Errorf("invalid history export: %w", err)
+	}
+	if doc.Version > exportVersion {
+		return result, fmt.Errorf("unsupported history export version %d", doc.Version)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	for _, raw := range doc.Entries {
+		var e ExportedEntry
+		if err := json.Unmarshal(raw, &e); err != nil || !validImport(&e) {
+			result.Skipped++
+			continue
+		}
+		if e.CreatedAt.IsZero() || e.CreatedAt.After(e.LastUsedAt) {
+			e.CreatedAt = e.LastUsedAt
+		}
+		if e.AttachMethod == "" {
+			e.AttachMethod = "ssh"
+		}
+		if e.Name == "" {
+			e.Name = e.SessionName
+		}
+
+		var localLastUsed int64
+		err := tx.QueryRow(`
+			SELECT last_used_at FROM agent_history
+			WHERE session_name = ? AND working_directory = ? AND host = ?
+		`, e.SessionName, e.WorkingDirectory, e.Host).Scan(&localLastUsed)
+		switch {
+		case err == sql.ErrNoRows:
+			_, err = tx.Exec(`
+				INSERT INTO agent_history (name, working_directory, session_name, host, attach_method, created_at, last_used_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`, e.Name, e.WorkingDirectory, e.SessionName, e.Host, e.AttachMethod, e.CreatedAt.Unix(), e.LastUsedAt.Unix())
+			if err != nil {
+				return result, err
+			}
+			result.Added++
+		case err != nil:
+			return result, err
+		case e.LastUsedAt.Unix() > localLastUsed:
+			_, err = tx.Exec(`
+				UPDATE agent_history
+				SET name = ?, attach_method = ?, last_used_at = ?, created_at = MIN(created_at, ?)
+				WHERE session_name = ? AND working_directory = ? AND host = ?
+			`, e.Name, e.AttachMethod, e.LastUsedAt.Unix(), e.CreatedAt.Unix(), e.SessionName, e.WorkingDirectory, e.Host)
+			if err != nil {
+				return result, err
+			}
+			result.Updated++
+		default:
+			result.Kept++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+	return result, s.enforceLimitLRU()
+}
+
+// validImport reports whether an imported row has the fields needed to
+// identify and order it.
+func validImport(e *ExportedEntry) bool {
+	if strings.TrimSpace(e.SessionName) == "" || strings.TrimSpace(e.WorkingDirectory) == "" {
+		return false
+	}
+	if e.LastUsedAt.IsZero() {
+		return false
+	}
+	switch e.AttachMethod {
+	case "", "ssh", "mosh":
+		return true
+	}
+	return false
+}
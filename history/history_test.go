@@ -1,12 +1,18 @@
 package history
 
 import (
+	"bytes"
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/porganisciak/agent-tmux/config"
 )
 
 func setupTestDB(t *testing.T) (*Store, func()) {
@@ -34,27 +40,6 @@ func setupTestDB(t *testing.T) (*Store, func()) {
 	return db, cleanup
 }
 
-// openPath is like Open but with a specific path (for testing).
-func openPath(dbPath string) (*Store, error) {
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, err
-	}
-
-	db, err := sql.Open("sqlite3", dbPath+"?_busy_timeout=5000")
-	if err != nil {
-		return nil, err
-	}
-
-	store := &Store{db: db}
-	if err := store.migrate(); err != nil {
-		db.Close()
-		return nil, err
-	}
-
-	return store, nil
-}
-
 func TestSaveAndLoadHistory(t *testing.T) {
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -247,6 +232,34 @@ func TestGetBySessionName(t *testing.T) {
 	}
 }
 
+func TestRenameSession(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	store.SaveEntry("api", "/work/a/api", "agent-api", "", "")
+	store.SaveEntry("api", "/work/a/api", "agent-api-2", "", "")
+	store.SaveEntry("api", "/work/b/api", "agent-api", "", "")
+
+	if err := store.RenameSession("agent-api", "/work/a/api", "agent-api-2"); err != nil {
+		t.Fatalf("RenameSession failed: %v", err)
+	}
+
+	entries, err := store.LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the renamed entry to replace its duplicate, got %d entries", len(entries))
+	}
+	names := map[string]string{}
+	for _, e := range entries {
+		names[e.WorkingDirectory] = e.SessionName
+	}
+	if names["/work/a/api"] != "agent-api-2" || names["/work/b/api"] != "agent-api" {
+		t.Errorf("unexpected session names %v", names)
+	}
+}
+
 func TestSaveEntryWithHost(t *testing.T) {
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -545,3 +558,469 @@ func TestUniqueIndexWithHost(t *testing.T) {
 		t.Errorf("expected 3 entries after update, got %d", count)
 	}
 }
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src, cleanupSrc := setupTestDB(t)
+	defer cleanupSrc()
+	dst, cleanupDst := setupTestDB(t)
+	defer cleanupDst()
+
+	if err := src.SaveEntry("project", "/home/user/project", "atmux-project", "", ""); err != nil {
+		t.Fatalf("SaveEntry failed: %v", err)
+	}
+	if err := src.SaveEntry("remote", "/srv/app", "atmux-app", "devbox", "mosh"); err != nil {
+		t.Fatalf("SaveEntry failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.ExportJSON(&buf); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	result, err := dst.ImportJSON(&buf)
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	if result.Added != 2 || result.Updated != 0 || result.Skipped != 0 {
+		t.Fatalf("unexpected import result %+v", result)
+	}
+
+	entry, err := dst.GetBySessionName("atmux-app")
+	if err != nil || entry == nil {
+		t.Fatalf("expected imported entry, got %v (err %v)", entry, err)
+	}
+	if entry.Host != "devbox" || entry.AttachMethod != "mosh" || entry.WorkingDirectory != "/srv/app" {
+		t.Errorf("unexpected imported entry %+v", entry)
+	}
+}
+
+func TestImportKeepsNewestAndSkipsMalformedRows(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Local entries last used "now"
+	if err := store.SaveEntry("local-name", "/work/a", "atmux-a", "", ""); err != nil {
+		t.Fatalf("SaveEntry failed: %v", err)
+	}
+	if err := store.SaveEntry("local-name", "/work/b", "atmux-b", "", ""); err != nil {
+		t.Fatalf("SaveEntry failed: %v", err)
+	}
+
+	older := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	newer := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	doc := `{"version": 1, "entries": [
+		{"name": "stale", "working_directory": "/work/a", "session_name": "atmux-a", "last_used_at": "` + older + `"},
+		{"name": "fresh", "working_directory": "/work/b", "session_name": "atmux-b", "last_used_at": "` + newer + `"},
+		{"name": "new", "working_directory": "/work/c", "session_name": "atmux-c", "last_used_at": "` + older + `"},
+		{"name": "no-dir", "session_name": "atmux-d", "last_used_at": "` + older + `"},
+		{"name": "bad-time", "working_directory": "/work/e", "session_name": "atmux-e", "last_used_at": "yesterday"},
+		{"name": "bad-method", "working_directory": "/work/f", "session_name": "atmux-f", "attach_method": "telnet", "last_used_at": "` + older + `"},
+		"not an object"
+	]}`
+
+	result, err := store.ImportJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	want := ImportResult{Added: 1, Updated: 1, Kept: 1, Skipped: 4}
+	if result != want {
+		t.Fatalf("expected %+v, got %+v", want, result)
+	}
+
+	a, _ := store.GetBySessionName("atmux-a")
+	if a == nil || a.Name != "local-name" {
+		t.Errorf("expected newer local entry to be kept, got %+v", a)
+	}
+	b, _ := store.GetBySessionName("atmux-b")
+	if b == nil || b.Name != "fresh" {
+		t.Errorf("expected newer imported entry to win, got %+v", b)
+	}
+	c, _ := store.GetBySessionName("atmux-c")
+	if c == nil || c.AttachMethod != "ssh" || !c.CreatedAt.Equal(c.LastUsedAt) {
+		t.Errorf("expected defaults filled in for new entry, got %+v", c)
+	}
+}
+
+func TestImportRejectsInvalidDocument(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := store.ImportJSON(strings.NewReader("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, err := store.ImportJSON(strings.NewReader(`{"version": 99, "entries": []}`)); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+// seedAges saves one entry per age and backdates its last_used_at.
+func seedAges(t *testing.T, store *Store, ages map[string]time.Duration) {
+	t.Helper()
+	for session, age := range ages {
+		if err := store.SaveEntry(session, "/work/"+session, session, "", ""); err != nil {
+			t.Fatalf("SaveEntry failed: %v", err)
+		}
+		_, err := store.db.Exec("UPDATE agent_history SET last_used_at = ? WHERE session_name = ?",
+			time.Now().Add(-age).Unix(), session)
+		if err != nil {
+			t.Fatalf("failed to backdate %s: %v", session, err)
+		}
+	}
+}
+
+func survivors(t *testing.T, store *Store) []string {
+	t.Helper()
+	entries, err := store.LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.SessionName)
+	}
+	return names
+}
+
+func TestPruneOlderThan(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	seedAges(t, store, map[string]time.Duration{
+		"today":      time.Hour,
+		"last-week":  7 * 24 * time.Hour,
+		"last-month": 40 * 24 * time.Hour,
+		"last-year":  300 * 24 * time.Hour,
+	})
+
+	removed, err := store.PruneOlderThan(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("PruneOlderThan failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d", removed)
+	}
+	got := strings.Join(survivors(t, store), ",")
+	if got != "today,last-week" {
+		t.Errorf("expected survivors today,last-week, got %s", got)
+	}
+}
+
+func TestPruneToMaxEntries(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	seedAges(t, store, map[string]time.Duration{
+		"a": 1 * time.Hour,
+		"b": 2 * time.Hour,
+		"c": 3 * time.Hour,
+		"d": 4 * time.Hour,
+	})
+
+	removed, err := store.PruneToMaxEntries(2)
+	if err != nil {
+		t.Fatalf("PruneToMaxEntries failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d", removed)
+	}
+	got := strings.Join(survivors(t, store), ",")
+	if got != "a,b" {
+		t.Errorf("expected survivors a,b, got %s", got)
+	}
+}
+
+func TestAutoPruneRunsOncePerInterval(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	retention := &config.HistoryRetentionConfig{MaxAge: "30d", MaxEntries: 2}
+	seedAges(t, store, map[string]time.Duration{
+		"new":   time.Hour,
+		"newer": time.Minute,
+		"mid":   2 * time.Hour,
+		"old":   60 * 24 * time.Hour,
+	})
+
+	now := time.Now()
+	if err := store.autoPrune(retention, now); err != nil {
+		t.Fatalf("autoPrune failed: %v", err)
+	}
+	got := strings.Join(survivors(t, store), ",")
+	if got != "newer,new" {
+		t.Fatalf("expected survivors newer,new, got %s", got)
+	}
+
+	// Within the interval nothing else is pruned
+	seedAges(t, store, map[string]time.Duration{"stale": 90 * 24 * time.Hour})
+	if err := store.autoPrune(retention, now.Add(time.Hour)); err != nil {
+		t.Fatalf("autoPrune failed: %v", err)
+	}
+	if count, _ := store.Count(); count != 3 {
+		t.Fatalf("expected prune to be skipped within the interval, got %d entries", count)
+	}
+
+	if err := store.autoPrune(retention, now.Add(autoPruneInterval+time.Minute)); err != nil {
+		t.Fatalf("autoPrune failed: %v", err)
+	}
+	got = strings.Join(survivors(t, store), ",")
+	if got != "newer,new" {
+		t.Errorf("expected survivors newer,new after the interval, got %s", got)
+	}
+}
+
+func TestAutoPruneWithoutRetentionKeepsEverything(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	seedAges(t, store, map[string]time.Duration{"ancient": 1000 * 24 * time.Hour})
+	if err := store.autoPrune(nil, time.Now()); err != nil {
+		t.Fatalf("autoPrune failed: %v", err)
+	}
+	if err := store.autoPrune(&config.HistoryRetentionConfig{MaxAge: "1d", Disabled: true}, time.Now()); err != nil {
+		t.Fatalf("autoPrune failed: %v", err)
+	}
+	if count, _ := store.Count(); count != 1 {
+		t.Errorf("expected entry to be kept, got %d entries", count)
+	}
+}
+
+func TestSessionNotes(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	local := NoteKey{SessionName: "agent-api"}
+	remote := NoteKey{SessionName: "agent-api", Host: "devbox"}
+	if err := store.SetNote(local, "waiting on review"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetNote(remote, "flaky test repro"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetNote(local, "  ready to merge  "); err != nil {
+		t.Fatal(err)
+	}
+
+	notes, err := store.LoadNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 || notes[local] != "ready to merge" || notes[remote] != "flaky test repro" {
+		t.Fatalf("unexpected notes %v", notes)
+	}
+
+	// Removing the history entry keeps the note; clearing the text removes it
+	if err := store.SaveEntry("api", "/tmp/api", "agent-api", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteBySessionName("agent-api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetNote(remote, ""); err != nil {
+		t.Fatal(err)
+	}
+	notes, _ = store.LoadNotes()
+	if len(notes) != 1 || notes[local] != "ready to merge" {
+		t.Fatalf("unexpected notes after removal %v", notes)
+	}
+}
+
+func TestSessionLastFocus(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	key := NoteKey{SessionName: "agent-api"}
+	if target, err := store.LastFocus(key); err != nil || target != "" {
+		t.Fatalf("expected no focus recorded, got %q, %v", target, err)
+	}
+	if err := store.SetLastFocus(key, "agent-api:1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetLastFocus(key, "agent-api:2.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetLastFocus(NoteKey{SessionName: "agent-api", Host: "devbox"}, "agent-api:0"); err != nil {
+		t.Fatal(err)
+	}
+	if target, err := store.LastFocus(key); err != nil || target != "agent-api:2.1" {
+		t.Fatalf("expected the latest local focus, got %q, %v", target, err)
+	}
+}
+
+func TestDBPathFollowsXDGDataHome(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", base)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(DBPathEnv, "")
+
+	got, err := DBPath()
+	if err != nil {
+		t.Fatalf("DBPath: %v", err)
+	}
+	if want := filepath.Join(base, "atmux", "history.sqlite3"); got != want {
+		t.Fatalf("DBPath() = %q, want %q", got, want)
+	}
+
+	// A relative path is ignored, as the XDG spec requires
+	t.Setenv("XDG_DATA_HOME", "relative/data")
+	if dir, err := DataDir(); err != nil || !filepath.IsAbs(dir) {
+		t.Fatalf("expected the default data dir for a relative XDG_DATA_HOME, got %q (%v)", dir, err)
+	}
+}
+
+func TestDBPathKeepsHistoryFromPlatformDataDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("APPDATA", "")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(DBPathEnv, "")
+
+	// History recorded before XDG_DATA_HOME was honored stays in use
+	legacyDir, err := platformDataDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(legacyDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(legacyDir, "history.sqlite3")
+	if err := os.WriteFile(legacy, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := DBPath(); err != nil || got != legacy {
+		t.Fatalf("DBPath() = %q (%v), want the existing %q", got, err, legacy)
+	}
+
+	// A database under XDG_DATA_HOME wins once there is one
+	dir, _ := DataDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	current := filepath.Join(dir, "history.sqlite3")
+	if err := os.WriteFile(current, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := DBPath(); err != nil || got != current {
+		t.Fatalf("DBPath() = %q (%v), want %q", got, err, current)
+	}
+}
+
+func TestDBPathRelocation(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	synced := t.TempDir()
+
+	tests := []struct {
+		name    string
+		env     string
+		setting string
+		want    string
+	}{
+		{"setting", "", "/data/atmux.db", "/data/atmux.db"},
+		{"env wins over setting", "/env/history.db", "/data/atmux.db", "/env/history.db"},
+		{"home expanded", "", "~/dbs/history.db", filepath.Join(home, "dbs", "history.db")},
+		{"directory gets the default file", synced, "", filepath.Join(synced, "history.sqlite3")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DBPathEnv, tt.env)
+			got, err := dbPathFor(&config.Settings{HistoryDB: tt.setting})
+			if err != nil {
+				t.Fatalf("dbPathFor: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenUsesRelocatedDB(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "nested", "atmux.db")
+	t.Setenv(DBPathEnv, dbPath)
+
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	store.Close()
+	if _, err := os.Stat(dbPath); err != nil {
+		t.Fatalf("expected the database at %s: %v", dbPath, err)
+	}
+}
+
+func TestOpenRejectsUnusableDirectory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(DBPathEnv, filepath.Join(blocker, "history.db"))
+	if _, err := Open(); err == nil || !strings.Contains(err.Error(), "failed to create history directory") {
+		t.Fatalf("expected a clear error for a path under a file, got %v", err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	readOnly := t.TempDir()
+	if err := os.Chmod(readOnly, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(readOnly, 0755)
+	t.Setenv(DBPathEnv, filepath.Join(readOnly, "history.db"))
+	if _, err := Open(); err == nil || !strings.Contains(err.Error(), "is not writable") {
+		t.Fatalf("expected a not-writable error, got %v", err)
+	}
+}
+
+func TestConcurrentStoresSaveWithoutLocking(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "history.sqlite3")
+	stores := make([]*Store, 2)
+	for i := range stores {
+		store, err := openPath(dbPath)
+		if err != nil {
+			t.Fatalf("open store %d: %v", i, err)
+		}
+		defer store.Close()
+		stores[i] = store
+	}
+
+	var mode string
+	if err := stores[0].db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Fatalf("expected WAL journal mode, got %q (%v)", mode, err)
+	}
+
+	// Both handles save the same entries at once, as the scheduler and an
+	// interactive atmux would
+	const saves = 50
+	errs := make(chan error, len(stores)*saves)
+	var wg sync.WaitGroup
+	for _, store := range stores {
+		wg.Add(1)
+		go func(store *Store) {
+			defer wg.Done()
+			for i := range saves {
+				name := fmt.Sprintf("project-%d", i%10)
+				errs <- store.SaveEntry(name, "/work/"+name, "agent-"+name, "", "")
+			}
+		}(store)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent SaveEntry failed: %v", err)
+		}
+	}
+
+	count, err := stores[1].Count()
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 10 {
+		t.Fatalf("expected 10 distinct entries, got %d", count)
+	}
+}
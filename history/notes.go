@@ -0,0 +1,52 @@
+package history
+
+import (
+	"strings"
+	"time"
+)
+
+// NoteKey identifies the session a note belongs to. Notes are keyed by
+// session name and host rather than history entry, so they stay attached
+// when a session is killed and later revived under the same name.
+type NoteKey struct {
+	SessionName string
+	Host        string // Empty for local sessions
+}
+
+// SetNote saves the note for a session, replacing any existing one. An empty
+// note removes it.
+func (s *Store) SetNote(key NoteKey, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		_, err := s.db.Exec("DELETE FROM session_notes WHERE session_name = ? AND host = ?", key.SessionName, key.Host)
+		return err
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO session_notes (session_name, host, note, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (session_name, host) DO UPDATE SET
+			note = excluded.note,
+			updated_at = excluded.updated_at
+	`, key.SessionName, key.Host, note, time.Now().Unix())
+	return err
+}
+
+// LoadNotes returns every saved session note.
+func (s *Store) LoadNotes() (map[NoteKey]string, error) {
+	rows, err := s.db.Query("SELECT session_name, host, note FROM session_notes")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := make(map[NoteKey]string)
+	for rows.Next() {
+		var key NoteKey
+		var note string
+		if err := rows.Scan(&key.SessionName, &key.Host, &note); err != nil {
+			return nil, err
+		}
+		notes[key] = note
+	}
+	return notes, rows.Err()
+}
@@ -0,0 +1,73 @@
+package tmux
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// agentProgram returns the program an agent command runs, as tmux reports it
+// in pane_current_command (e.g. "claude" for "claude --resume").
+func agentProgram(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(fields[0])
+}
+
+// AgentPrograms returns the lowercased programs the agents run, for matching
+// against a pane's current command.
+func AgentPrograms(agents []config.AgentConfig) map[string]bool {
+	programs := make(map[string]bool)
+	for _, agent := range agents {
+		if program := agentProgram(agent.Command); program != "" {
+			programs[strings.ToLower(program)] = true
+		}
+	}
+	return programs
+}
+
+// AgentPanes returns the targets of panes in tree whose current command is
+// one of the agents' programs.
+func AgentPanes(tree *Tree, agents []config.AgentConfig) []string {
+	if tree == nil {
+		return nil
+	}
+	programs := AgentPrograms(agents)
+
+	var targets []string
+	for _, sess := range tree.Sessions {
+		for _, win := range sess.Windows {
+			for _, pane := range win.Panes {
+				if programs[strings.ToLower(pane.Command)] {
+					targets = append(targets, pane.Target)
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// ResolveJobTargets returns the panes a scheduled job sends to. All-agent
+// jobs scan exec's live tree for panes running one of agents (the default
+// agents when none are configured); other jobs use their single target.
+func ResolveJobTargets(job config.ScheduledJob, agents []config.AgentConfig, exec TmuxExecutor) ([]string, error) {
+	if !job.TargetsAllAgents() {
+		if job.Target == "" {
+			return nil, fmt.Errorf("job %s has no target", job.ID)
+		}
+		return []string{job.Target}, nil
+	}
+
+	if len(agents) == 0 {
+		agents = DefaultAgents()
+	}
+	tree, err := fetchTreeWithExecutor(exec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tmux tree: %w", err)
+	}
+	return AgentPanes(tree, agents), nil
+}
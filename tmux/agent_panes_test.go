@@ -0,0 +1,40 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+func TestAgentPanesMatchesAgentPrograms(t *testing.T) {
+	tree := &Tree{Sessions: []TmuxSession{
+		{Name: "api", Windows: []Window{{Index: 0, Panes: []Pane{
+			{Target: "api:0.0", Command: "claude"},
+			{Target: "api:0.1", Command: "zsh"},
+		}}}},
+		{Name: "web", Windows: []Window{{Index: 0, Panes: []Pane{
+			{Target: "web:0.0", Command: "codex"},
+			{Target: "web:0.1", Command: "vim"},
+		}}}},
+	}}
+	agents := []config.AgentConfig{
+		{Command: "claude --dangerously-skip-permissions"},
+		{Command: "/usr/local/bin/codex --full-auto"},
+	}
+
+	got := AgentPanes(tree, agents)
+	if strings.Join(got, ",") != "api:0.0,web:0.0" {
+		t.Fatalf("unexpected agent panes %v", got)
+	}
+}
+
+func TestResolveJobTargetsSingleTarget(t *testing.T) {
+	targets, err := ResolveJobTargets(config.ScheduledJob{Target: "api:0.1"}, nil, nil)
+	if err != nil || len(targets) != 1 || targets[0] != "api:0.1" {
+		t.Fatalf("expected the job's own target, got %v (%v)", targets, err)
+	}
+	if _, err := ResolveJobTargets(config.ScheduledJob{ID: "job_1"}, nil, nil); err == nil {
+		t.Fatal("expected an error for a job without a target")
+	}
+}
@@ -128,3 +128,20 @@ func TestSettingsRemoteAttachStrategy(t *testing.T) {
 		t.Fatalf("expected empty default RemoteAttachStrategy, got %q", s.RemoteAttachStrategy)
 	}
 }
+
+func TestAttachReadOnlyWithStrategy_PassesReadOnlyFlag(t *testing.T) {
+	mock := &mockExecutor{isRemote: true}
+	err := AttachReadOnlyWithStrategy("mysess", mock, config.AttachStrategyReplace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"attach-session", "-r", "-t", "mysess"}
+	if len(mock.interactiveArgs) != len(want) {
+		t.Fatalf("expected args %v, got %v", want, mock.interactiveArgs)
+	}
+	for i := range want {
+		if mock.interactiveArgs[i] != want[i] {
+			t.Fatalf("expected args %v, got %v", want, mock.interactiveArgs)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package tmux
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoClipboard is returned when no clipboard mechanism is available.
+var ErrNoClipboard = errors.New("no clipboard tool found (install pbcopy, wl-copy or xclip, or run inside tmux)")
+
+// clipboardCommands returns the candidate commands for writing stdin to the
+// clipboard, in order of preference. OS clipboard tools come first; tmux's
+// own buffer is the fallback when running inside tmux.
+func clipboardCommands(goos string, getenv func(string) string, lookPath func(string) (string, error)) [][]string {
+	var candidates [][]string
+	has := func(name string) bool {
+		_, err := lookPath(name)
+		return err == nil
+	}
+
+	if goos == "darwin" && has("pbcopy") {
+		candidates = append(candidates, []string{"pbcopy"})
+	}
+	if getenv("WAYLAND_DISPLAY") != "" && has("wl-copy") {
+		candidates = append(candidates, []string{"wl-copy"})
+	}
+	if getenv("DISPLAY") != "" && has("xclip") {
+		candidates = append(candidates, []string{"xclip", "-selection", "clipboard"})
+	}
+	if getenv("TMUX") != "" {
+		// -w also forwards to the terminal clipboard (OSC 52) where supported
+		candidates = append(candidates, []string{"tmux", "load-buffer", "-w", "-"})
+		candidates = append(candidates, []string{"tmux", "load-buffer", "-"})
+	}
+	return candidates
+}
+
+// CopyToClipboard writes text to the system clipboard, falling back to the
+// tmux paste buffer when inside tmux. It returns the name of the tool used.
+func CopyToClipboard(text string) (string, error) {
+	candidates := clipboardCommands(runtime.GOOS, os.Getenv, exec.LookPath)
+	if len(candidates) == 0 {
+		return "", ErrNoClipboard
+	}
+
+	var lastErr error
+	for _, args := range candidates {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			lastErr = err
+			continue
+		}
+		if args[0] == "tmux" {
+			return "tmux buffer", nil
+		}
+		return args[0], nil
+	}
+	return "", lastErr
+}
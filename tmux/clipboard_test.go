@@ -0,0 +1,70 @@
+package tmux
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClipboardCommands(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	tests := []struct {
+		name   string
+		goos   string
+		env    map[string]string
+		tools  []string
+		expect [][]string
+	}{
+		{
+			name:   "macOS uses pbcopy",
+			goos:   "darwin",
+			tools:  []string{"pbcopy"},
+			expect: [][]string{{"pbcopy"}},
+		},
+		{
+			name:   "wayland preferred over X11",
+			goos:   "linux",
+			env:    map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"},
+			tools:  []string{"wl-copy", "xclip"},
+			expect: [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}},
+		},
+		{
+			name:   "tool without display is skipped",
+			goos:   "linux",
+			tools:  []string{"xclip"},
+			expect: nil,
+		},
+		{
+			name:  "tmux buffer as fallback",
+			goos:  "linux",
+			env:   map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"},
+			tools: nil,
+			expect: [][]string{
+				{"tmux", "load-buffer", "-w", "-"},
+				{"tmux", "load-buffer", "-"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clipboardCommands(tt.goos, env(tt.env), installed(tt.tools...))
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Fatalf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
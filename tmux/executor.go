@@ -35,17 +35,18 @@ func NewLocalExecutor() *LocalExecutor {
 }
 
 func (e *LocalExecutor) Run(args ...string) error {
-	return exec.Command("tmux", args...).Run()
+	return localTmuxError(exec.Command("tmux", args...).Run())
 }
 
 func (e *LocalExecutor) Output(args ...string) ([]byte, error) {
-	return exec.Command("tmux", args...).Output()
+	out, err := exec.Command("tmux", args...).Output()
+	return out, localTmuxError(err)
 }
 
 func (e *LocalExecutor) RunWithDir(dir string, args ...string) error {
 	cmd := exec.Command("tmux", args...)
 	cmd.Dir = dir
-	return cmd.Run()
+	return localTmuxError(cmd.Run())
 }
 
 func (e *LocalExecutor) Interactive(args ...string) error {
@@ -53,7 +54,7 @@ func (e *LocalExecutor) Interactive(args ...string) error {
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return localTmuxError(cmd.Run())
 }
 
 func (e *LocalExecutor) RunGeneric(command string, args ...string) ([]byte, error) {
@@ -0,0 +1,40 @@
+package tmux
+
+import "sync"
+
+// DefaultRemoteFetchLimit is how many remote hosts are fetched at once
+// unless SetRemoteFetchLimit says otherwise.
+const DefaultRemoteFetchLimit = 4
+
+var (
+	remoteFetchMu    sync.Mutex
+	remoteFetchSlots = make(chan struct{}, DefaultRemoteFetchLimit)
+)
+
+// SetRemoteFetchLimit caps how many remote fetches (tree and session list)
+// run at the same time, so a long host list doesn't open every SSH
+// connection at once. n <= 0 restores the default. Fetches already holding
+// a slot finish under the old limit.
+func SetRemoteFetchLimit(n int) {
+	if n <= 0 {
+		n = DefaultRemoteFetchLimit
+	}
+	remoteFetchMu.Lock()
+	defer remoteFetchMu.Unlock()
+	if cap(remoteFetchSlots) != n {
+		remoteFetchSlots = make(chan struct{}, n)
+	}
+}
+
+// acquireFetchSlot blocks until exec may start a fetch and returns the
+// function that gives the slot back. Local fetches never wait.
+func acquireFetchSlot(exec TmuxExecutor) (release func()) {
+	if !exec.IsRemote() {
+		return func() {}
+	}
+	remoteFetchMu.Lock()
+	slots := remoteFetchSlots
+	remoteFetchMu.Unlock()
+	slots <- struct{}{}
+	return func() { <-slots }
+}
@@ -0,0 +1,121 @@
+package tmux
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrencyCounter tracks how many fetches are in flight across hosts.
+type concurrencyCounter struct {
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+// countingExecutor records concurrent Output calls on a shared counter,
+// holding each one briefly so overlapping fetches are visible.
+type countingExecutor struct {
+	fakeExecutor
+	counter *concurrencyCounter
+}
+
+func (c *countingExecutor) Output(args ...string) ([]byte, error) {
+	c.counter.mu.Lock()
+	c.counter.inFlight++
+	c.counter.max = max(c.counter.max, c.counter.inFlight)
+	c.counter.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.counter.mu.Lock()
+	c.counter.inFlight--
+	c.counter.mu.Unlock()
+	return c.fakeExecutor.Output(args...)
+}
+
+func TestFetchTreeWithExecutorsRespectsRemoteLimit(t *testing.T) {
+	SetRemoteFetchLimit(2)
+	defer SetRemoteFetchLimit(0)
+
+	counter := &concurrencyCounter{}
+	var executors []TmuxExecutor
+	for i := range 6 {
+		executors = append(executors, &countingExecutor{
+			fakeExecutor: fakeExecutor{
+				host:   fmt.Sprintf("host%d", i),
+				remote: true,
+				responses: map[string]fakeResponse{
+					"list-sessions": {output: []byte("work:0\n")},
+				},
+			},
+			counter: counter,
+		})
+	}
+
+	results := FetchTreeWithExecutors(executors)
+	if len(results) != 6 {
+		t.Fatalf("expected 6 host trees, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("%s: unexpected error %v", r.Host, r.Err)
+		}
+	}
+	if counter.max > 2 {
+		t.Errorf("max concurrent remote fetches = %d, want at most 2", counter.max)
+	}
+	if counter.max < 2 {
+		t.Errorf("max concurrent remote fetches = %d, want the limit of 2 to be used", counter.max)
+	}
+}
+
+func TestLocalFetchSkipsRemoteLimit(t *testing.T) {
+	SetRemoteFetchLimit(1)
+	defer SetRemoteFetchLimit(0)
+
+	// Hold the only remote slot, as a stalled host would.
+	release := acquireFetchSlot(&fakeExecutor{host: "slow", remote: true})
+	defer release()
+
+	local := &fakeExecutor{responses: map[string]fakeResponse{
+		"list-sessions": {output: []byte("work:0\n")},
+	}}
+	done := make(chan HostTree)
+	go func() { done <- FetchHostTree(local) }()
+
+	select {
+	case ht := <-done:
+		if ht.Err != nil {
+			t.Fatalf("unexpected error: %v", ht.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("local fetch waited on the remote limit")
+	}
+}
+
+func TestListSessionsWaitsForRemoteSlot(t *testing.T) {
+	SetRemoteFetchLimit(1)
+	defer SetRemoteFetchLimit(0)
+
+	release := acquireFetchSlot(&fakeExecutor{host: "slow", remote: true})
+	remote := &fakeExecutor{host: "devbox", remote: true, responses: map[string]fakeResponse{
+		"list-sessions": {output: []byte("work\t100\t\n")},
+	}}
+	done := make(chan error)
+	go func() {
+		_, err := ListSessionsRawWithExecutor(remote)
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("remote session list ran while the limit was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxFetchRetries is how many times a host failing with a transient error
+// is fetched again before it is treated as down.
+const MaxFetchRetries = 3
+
+// transientFetchErrors are ssh failures worth retrying: the network or
+// the host is briefly unavailable, as opposed to refusing our credentials.
+var transientFetchErrors = []string{
+	"connection refused",
+	"connection timed out",
+	"connection reset",
+	"connection closed",
+	"broken pipe",
+	"no route to host",
+	"network is unreachable",
+	"temporary failure in name resolution",
+}
+
+// IsTransientFetchError reports whether a failed fetch is likely to succeed
+// if tried again shortly, e.g. a timeout or refused connection. Permanent
+// failures such as an authentication error or missing tmux are not.
+func IsTransientFetchError(err error) bool {
+	if err == nil || errors.Is(err, ErrTmuxNotFound) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	reason := strings.ToLower(FetchErrorReason(err))
+	for _, s := range transientFetchErrors {
+		if strings.Contains(reason, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchRetryDelay returns how long to wait before retry number attempt
+// (1-based), doubling from one second.
+func FetchRetryDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	return time.Second << (attempt - 1)
+}
@@ -0,0 +1,69 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsTransientFetchError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout", fmt.Errorf("devbox: %w", context.DeadlineExceeded), true},
+		{"refused", &exec.ExitError{Stderr: []byte("ssh: connect to host devbox port 22: Connection refused\n")}, true},
+		{"no route", errors.New("ssh: connect to host devbox port 22: No route to host"), true},
+		{"auth", &exec.ExitError{Stderr: []byte("user@devbox: Permission denied (publickey).\n")}, false},
+		{"no tmux", fmt.Errorf("devbox: %w", ErrTmuxNotFound), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransientFetchError(tt.err); got != tt.want {
+			t.Errorf("%s: IsTransientFetchError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFetchRetryDelayBacksOff(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	for i, d := range want {
+		if got := FetchRetryDelay(i + 1); got != d {
+			t.Errorf("FetchRetryDelay(%d) = %v, want %v", i+1, got, d)
+		}
+	}
+}
+
+func TestControlMasterRetriesAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+	// Write the refusal where -E points, as ssh does
+	script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do [ \"$1\" = -E ] && echo 'ssh: connect to host devbox port 22: Connection refused' >> \"$2\"; shift; done\nexit 255\n"
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+
+	e := NewRemoteExecutor("devbox", 0, "", "")
+	defer e.Close()
+	err := e.ensureControlMaster()
+	if !IsTransientFetchError(err) {
+		t.Fatalf("expected a transient refusal, got %v (%s)", err, FetchErrorReason(err))
+	}
+
+	// The network is back: the next command starts the master afresh
+	t.Setenv("PATH", path)
+	fakeSSH(t)
+	if err := e.ensureControlMaster(); err != nil {
+		t.Fatalf("expected the master to start on retry, got %v", err)
+	}
+	if e.controlPath == "" {
+		t.Fatal("expected a control path once the master is up")
+	}
+}
@@ -0,0 +1,116 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultProbeConcurrency is how many hosts ProbeHosts checks at once.
+	DefaultProbeConcurrency = 4
+	// DefaultProbeTimeout bounds each host's probe, including the SSH connect.
+	DefaultProbeTimeout = 5 * time.Second
+)
+
+// HostHealth is the result of probing one host with `tmux -V`.
+type HostHealth struct {
+	Host     string        // Host label ("" for local)
+	Version  string        // tmux version, e.g. "tmux 3.4"
+	Latency  time.Duration // How long the probe took
+	Err      error         // Why the host is unreachable (nil if reachable)
+	Note     string        // Non-fatal problem, e.g. mosh-server missing
+	Executor TmuxExecutor  // The executor that was probed
+}
+
+// Reachable reports whether tmux answered on the host.
+func (h HostHealth) Reachable() bool {
+	return h.Err == nil
+}
+
+// ProbeHosts runs `tmux -V` through each executor, at most concurrency at a
+// time, giving each host timeout to answer before it's reported as timed
+// out. Results are in executor order. Hosts that attach with mosh are also
+// checked for mosh-server.
+func ProbeHosts(executors []TmuxExecutor, concurrency int, timeout time.Duration) []HostHealth {
+	if concurrency <= 0 {
+		concurrency = DefaultProbeConcurrency
+	}
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+
+	results := make([]HostHealth, len(executors))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, exec := range executors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = probeHost(exec, timeout)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// probeHost probes a single executor. The executor API has no cancellation,
+// so a probe that outlives timeout is abandoned rather than killed; remote
+// executors still end it at their own SSH timeout.
+func probeHost(exec TmuxExecutor, timeout time.Duration) HostHealth {
+	health := HostHealth{Host: exec.HostLabel(), Executor: exec}
+
+	type probeResult struct {
+		version string
+		note    string
+		err     error
+	}
+	done := make(chan probeResult, 1)
+	start := time.Now()
+	go func() {
+		out, err := exec.Output("-V")
+		if err != nil {
+			done <- probeResult{err: err}
+			return
+		}
+		res := probeResult{version: strings.TrimSpace(string(out))}
+		if remote, ok := exec.(*RemoteExecutor); ok && remote.AttachMethod == "mosh" {
+			if _, err := exec.RunGeneric("sh", "-c", "command -v mosh-server"); err != nil {
+				res.note = "mosh-server not found"
+			}
+		}
+		done <- res
+	}()
+
+	select {
+	case res := <-done:
+		health.Latency = time.Since(start)
+		health.Version = res.version
+		health.Note = res.note
+		health.Err = res.err
+	case <-time.After(timeout):
+		health.Latency = timeout
+		health.Err = fmt.Errorf("probe of %s: %w", hostLabelOrLocal(exec), context.DeadlineExceeded)
+	}
+	return health
+}
+
+// FormatLatency formats a host round trip (e.g. "430ms", "1.2s").
+func FormatLatency(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// hostLabelOrLocal returns the executor's host label, or "local".
+func hostLabelOrLocal(exec TmuxExecutor) string {
+	if label := exec.HostLabel(); label != "" {
+		return label
+	}
+	return "local"
+}
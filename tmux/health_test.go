@@ -0,0 +1,88 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowExecutor answers `tmux -V` after delay, tracking how many probes run
+// at once.
+type slowExecutor struct {
+	fakeExecutor
+	delay   time.Duration
+	running *atomic.Int32
+	peak    *atomic.Int32
+}
+
+func (s *slowExecutor) Output(args ...string) ([]byte, error) {
+	n := s.running.Add(1)
+	defer s.running.Add(-1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(s.delay)
+	return s.fakeExecutor.Output(args...)
+}
+
+func TestProbeHostsReportsVersionAndErrors(t *testing.T) {
+	up := &fakeExecutor{host: "devbox", remote: true, responses: map[string]fakeResponse{
+		"-V": {output: []byte("tmux 3.4\n")},
+	}}
+	down := &fakeExecutor{host: "old", remote: true, responses: map[string]fakeResponse{
+		"-V": {err: errors.New("connection refused")},
+	}}
+
+	results := ProbeHosts([]TmuxExecutor{up, down}, 2, time.Second)
+	if len(results) != 2 || results[0].Host != "devbox" || results[1].Host != "old" {
+		t.Fatalf("expected results in executor order, got %+v", results)
+	}
+	if !results[0].Reachable() || results[0].Version != "tmux 3.4" {
+		t.Fatalf("expected devbox up on tmux 3.4, got %+v", results[0])
+	}
+	if results[1].Reachable() {
+		t.Fatalf("expected old to be down, got %+v", results[1])
+	}
+}
+
+func TestProbeHostsTimesOutSlowHosts(t *testing.T) {
+	var running, peak atomic.Int32
+	slow := &slowExecutor{
+		fakeExecutor: fakeExecutor{host: "slow", remote: true},
+		delay:        time.Second,
+		running:      &running,
+		peak:         &peak,
+	}
+
+	start := time.Now()
+	results := ProbeHosts([]TmuxExecutor{slow}, 1, 20*time.Millisecond)
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("expected the probe to give up at its timeout")
+	}
+	if !errors.Is(results[0].Err, context.DeadlineExceeded) || FetchErrorReason(results[0].Err) != "timed out" {
+		t.Fatalf("expected a timeout, got %v", results[0].Err)
+	}
+}
+
+func TestProbeHostsBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	var executors []TmuxExecutor
+	for range 6 {
+		executors = append(executors, &slowExecutor{
+			fakeExecutor: fakeExecutor{remote: true},
+			delay:        20 * time.Millisecond,
+			running:      &running,
+			peak:         &peak,
+		})
+	}
+
+	ProbeHosts(executors, 2, time.Second)
+	if got := peak.Load(); got > 2 {
+		t.Fatalf("expected at most 2 probes at once, got %d", got)
+	}
+}
@@ -0,0 +1,41 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// ErrTmuxNotFound is returned when the tmux binary can't be found, locally
+// or on a remote host.
+var ErrTmuxNotFound = errors.New("tmux not found; install tmux to use atmux")
+
+// lookPath is swapped out in tests.
+var lookPath = exec.LookPath
+
+// CheckInstalled returns ErrTmuxNotFound if tmux isn't on the local PATH.
+func CheckInstalled() error {
+	if _, err := lookPath("tmux"); err != nil {
+		return ErrTmuxNotFound
+	}
+	return nil
+}
+
+// localTmuxError maps a failure to start the local tmux binary to
+// ErrTmuxNotFound, leaving other errors as they are.
+func localTmuxError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return ErrTmuxNotFound
+	}
+	return err
+}
+
+// remoteTmuxError maps the shell's "command not found" exit status from a
+// tmux command run over SSH to ErrTmuxNotFound, naming the host.
+func remoteTmuxError(host string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 127 {
+		return fmt.Errorf("%s: %w", host, ErrTmuxNotFound)
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package tmux
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCheckInstalled(t *testing.T) {
+	orig := lookPath
+	defer func() { lookPath = orig }()
+
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+	if err := CheckInstalled(); !errors.Is(err, ErrTmuxNotFound) {
+		t.Fatalf("expected ErrTmuxNotFound, got %v", err)
+	}
+
+	lookPath = func(string) (string, error) { return "/usr/bin/tmux", nil }
+	if err := CheckInstalled(); err != nil {
+		t.Fatalf("expected tmux to be found, got %v", err)
+	}
+}
+
+func TestLocalTmuxError(t *testing.T) {
+	err := exec.Command("atmux-no-such-binary").Run()
+	if got := localTmuxError(err); !errors.Is(got, ErrTmuxNotFound) {
+		t.Fatalf("expected a missing binary to map to ErrTmuxNotFound, got %v", got)
+	}
+	if got := localTmuxError(nil); got != nil {
+		t.Fatalf("expected nil to stay nil, got %v", got)
+	}
+}
+
+func TestRemoteTmuxError(t *testing.T) {
+	notFound := exec.Command("sh", "-c", "exit 127").Run()
+	got := remoteTmuxError("devbox", notFound)
+	if !errors.Is(got, ErrTmuxNotFound) || !strings.HasPrefix(got.Error(), "devbox: ") {
+		t.Fatalf("expected devbox: ErrTmuxNotFound, got %v", got)
+	}
+	if reason := FetchErrorReason(got); reason != "tmux not installed" {
+		t.Fatalf("expected a short reason for the host row, got %q", reason)
+	}
+
+	// Other failures, like no server running, pass through untouched
+	other := exec.Command("sh", "-c", "exit 1").Run()
+	if got := remoteTmuxError("devbox", other); got != other {
+		t.Fatalf("expected exit 1 to pass through, got %v", got)
+	}
+}
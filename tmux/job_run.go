@@ -0,0 +1,51 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// jobCompactWait is how long the compact pre-action gives /compact to
+// finish before the job's command is sent; tests shorten it.
+var jobCompactWait = 30 * time.Second
+
+// SendJob sends a due scheduled job's command through exec to each pane it
+// targets, found at run time for all-agent jobs (see ResolveJobTargets),
+// and returns those panes. A single target is checked first, and recreated
+// for new-session jobs (see EnsureJobTarget). Time tokens are expanded for
+// now; unknown ones are sent as written. The compact pre-action runs in
+// every pane first, and the command goes in with the job's send method,
+// wrapped like any other (see WrapCommand).
+func SendJob(job config.ScheduledJob, now time.Time, agents []config.AgentConfig, exec TmuxExecutor) ([]string, error) {
+	if err := EnsureJobTarget(job, exec); err != nil {
+		return nil, err
+	}
+	targets, err := ResolveJobTargets(job, agents, exec)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no agent panes to send to")
+	}
+
+	var errs []error
+	if job.PreAction == config.PreActionCompact {
+		for _, target := range targets {
+			if err := SendAgentCommandWithExecutor(target, "/compact", exec); err != nil {
+				errs = append(errs, fmt.Errorf("failed to compact %s: %w", target, err))
+			}
+		}
+		time.Sleep(jobCompactWait)
+	}
+	command, _ := job.ExpandedCommand(now)
+	method := SendMethodForJob(job)
+	for _, target := range targets {
+		if err := SendCommandWithMethodAndExecutor(target, command, method, exec); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send to %s: %w", target, err))
+		}
+	}
+	return targets, errors.Join(errs...)
+}
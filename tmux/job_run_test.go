@@ -0,0 +1,127 @@
+package tmux
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// apiSessionExecutor records runs against a session "api" whose one window
+// has claude, a shell, and codex in panes 0 to 2.
+func apiSessionExecutor() *runRecorder {
+	return &runRecorder{fakeExecutor: fakeExecutor{responses: map[string]fakeResponse{
+		"list-sessions": {output: []byte("api:0:0\n")},
+		"list-windows":  {output: []byte("@1:0:agents:1:0:0\n")},
+		"list-panes":    {output: []byte("%1:0::claude:1:80:24\n%2:1::zsh:0:80:24\n%3:2::codex:0:80:24\n")},
+	}}}
+}
+
+func TestSendJobCompactsFirst(t *testing.T) {
+	defer func(wait time.Duration) { jobCompactWait = wait }(jobCompactWait)
+	jobCompactWait = 0
+
+	exec := apiSessionExecutor()
+	job := config.ScheduledJob{Target: "api:0.1", Command: "git pull", PreAction: config.PreActionCompact}
+	if _, err := SendJob(job, time.Now(), nil, exec); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"send-keys", "-t", "api:0.1", "/compact"},
+		{"send-keys", "-t", "api:0.1", "Enter"},
+		{"send-keys", "-t", "api:0.1", "git pull"},
+		{"send-keys", "-t", "api:0.1", "Enter"},
+	}
+	if !reflect.DeepEqual(exec.runs, want) {
+		t.Fatalf("runs = %v, want %v", exec.runs, want)
+	}
+}
+
+func TestSendJobWrapsCommandButNotCompact(t *testing.T) {
+	defer func(wait time.Duration) { jobCompactWait = wait }(jobCompactWait)
+	jobCompactWait = 0
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	settings := config.DefaultSettings()
+	settings.CommandPrefix = "[atmux]"
+	if err := settings.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := apiSessionExecutor()
+	job := config.ScheduledJob{Target: "api:0.1", Command: "git pull", PreAction: config.PreActionCompact}
+	if _, err := SendJob(job, time.Now(), nil, exec); err != nil {
+		t.Fatal(err)
+	}
+	if got := exec.runs[0][3]; got != "/compact" {
+		t.Fatalf("compacted with %q, want /compact as-is", got)
+	}
+	if got := exec.runs[2][3]; got != "[atmux] git pull" {
+		t.Fatalf("sent %q, want the job's command wrapped", got)
+	}
+}
+
+func TestSendJobExpandsTimeTokens(t *testing.T) {
+	exec := apiSessionExecutor()
+	job := config.ScheduledJob{Target: "api:0.1", Command: "echo {{date}} {{now:15:04}} {{bogus}}"}
+	at := time.Date(2026, 3, 9, 14, 5, 0, 0, time.UTC)
+	if _, err := SendJob(job, at, nil, exec); err != nil {
+		t.Fatal(err)
+	}
+	if got := exec.runs[0][3]; got != "echo 2026-03-09 14:05 {{bogus}}" {
+		t.Fatalf("sent %q, want the tokens expanded at send time", got)
+	}
+}
+
+func TestSendJobToAllAgentPanes(t *testing.T) {
+	exec := apiSessionExecutor()
+	job := config.ScheduledJob{TargetMode: config.TargetModeAllAgents, Command: "/status"}
+	agents := []config.AgentConfig{{Command: "claude"}, {Command: "codex --yolo"}}
+
+	targets, err := SendJob(job, time.Now(), agents, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(targets, []string{"api:0.0", "api:0.2"}) {
+		t.Fatalf("targets = %v, want the two agent panes", targets)
+	}
+	if len(exec.runs) != 4 || exec.runs[0][2] != "api:0.0" || exec.runs[2][2] != "api:0.2" {
+		t.Fatalf("expected the command sent to each agent pane, got %v", exec.runs)
+	}
+
+	exec.responses["list-panes"] = fakeResponse{output: []byte("%2:1::zsh:0:80:24\n")}
+	if _, err := SendJob(job, time.Now(), agents, exec); err == nil {
+		t.Fatal("expected an error when no agent panes are running")
+	}
+}
+
+func TestSendJobChecksTargetFirst(t *testing.T) {
+	exec := apiSessionExecutor()
+	job := config.ScheduledJob{Target: "web:0.0", Command: "ls"}
+	if _, err := SendJob(job, time.Now(), nil, exec); !errors.Is(err, ErrTargetMissing) {
+		t.Fatalf("expected ErrTargetMissing, got %v", err)
+	}
+	if len(exec.runs) != 0 {
+		t.Fatalf("expected nothing sent to a missing target, got %v", exec.runs)
+	}
+
+	job.PreAction = config.PreActionNewSession
+	if _, err := SendJob(job, time.Now(), nil, exec); err != nil {
+		t.Fatal(err)
+	}
+	if len(exec.runs) != 3 || exec.runs[0][0] != "new-session" || exec.runs[0][3] != "web" || exec.runs[1][2] != "web:0.0" {
+		t.Fatalf("expected the session recreated, then the send, got %v", exec.runs)
+	}
+}
+
+func TestSendJobPastesWhenAsked(t *testing.T) {
+	exec := apiSessionExecutor()
+	job := config.ScheduledJob{Target: "api:0.0", Command: "line one\nline two", SendMethod: config.JobSendBracketedPaste}
+	if _, err := SendJob(job, time.Now(), nil, exec); err != nil {
+		t.Fatal(err)
+	}
+	if len(exec.runs) != 3 || exec.runs[0][0] != "set-buffer" || exec.runs[1][0] != "paste-buffer" {
+		t.Fatalf("expected the command pasted, then Enter, got %v", exec.runs)
+	}
+}
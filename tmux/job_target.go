@@ -0,0 +1,108 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// ErrTargetMissing is returned when a scheduled job's target no longer
+// exists in tmux.
+var ErrTargetMissing = errors.New("target no longer exists")
+
+// HasTarget reports whether the tree contains target, which may name a
+// session ("api"), a window ("api:0" or "api:editor"), a pane ("api:0.1"),
+// or a pane ID ("%3").
+func (t *Tree) HasTarget(target string) bool {
+	if t == nil || target == "" {
+		return false
+	}
+	session, rest, _ := strings.Cut(target, ":")
+	window, pane, hasPane := strings.Cut(rest, ".")
+	for _, sess := range t.Sessions {
+		if strings.HasPrefix(target, "%") {
+			for _, win := range sess.Windows {
+				for _, p := range win.Panes {
+					if p.ID == target {
+						return true
+					}
+				}
+			}
+			continue
+		}
+		if sess.Name != session {
+			continue
+		}
+		if window == "" {
+			return true
+		}
+		for _, win := range sess.Windows {
+			if strconv.Itoa(win.Index) != window && win.Name != window {
+				continue
+			}
+			if !hasPane {
+				return true
+			}
+			for _, p := range win.Panes {
+				if strconv.Itoa(p.Index) == pane {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MissingJobTarget reports whether a single-target job's target is gone
+// from tree. All-agent jobs never are, since they find panes at run time.
+func MissingJobTarget(job config.ScheduledJob, tree *Tree) bool {
+	if job.TargetsAllAgents() || job.Target == "" {
+		return false
+	}
+	return !tree.HasTarget(job.Target)
+}
+
+// SendMethodForJob returns the method the scheduler sends a job's command
+// with: a bracketed paste when the job asks for one, otherwise the default.
+func SendMethodForJob(job config.ScheduledJob) SendMethod {
+	if job.PastesCommand() {
+		return SendMethodBracketedPaste
+	}
+	return SendMethodEnterDelayed
+}
+
+// EnsureJobTarget checks through exec that a job's target still exists
+// before the scheduler sends to it. When the target is gone, jobs with the
+// new-session pre-action recreate its session, detached in the home
+// directory; other jobs get an error wrapping ErrTargetMissing, and the run
+// is skipped.
+func EnsureJobTarget(job config.ScheduledJob, exec TmuxExecutor) error {
+	if job.TargetsAllAgents() || job.Target == "" {
+		return nil
+	}
+	tree, err := fetchTreeWithExecutor(exec)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tmux tree: %w", err)
+	}
+	if tree.HasTarget(job.Target) {
+		return nil
+	}
+	if job.PreAction != config.PreActionNewSession {
+		return fmt.Errorf("%s: %w", job.Target, ErrTargetMissing)
+	}
+
+	session, _, _ := strings.Cut(job.Target, ":")
+	if strings.HasPrefix(session, "%") || tree.HasTarget(session) {
+		// Only whole sessions can be recreated
+		return fmt.Errorf("%s: %w", job.Target, ErrTargetMissing)
+	}
+	home, _ := os.UserHomeDir()
+	if err := exec.Run("new-session", "-d", "-s", session, "-c", home); err != nil {
+		return fmt.Errorf("failed to recreate session %s: %w", session, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package tmux
+
+import (
+	"testing"
+
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+func TestTreeHasTarget(t *testing.T) {
+	tree := &Tree{Sessions: []TmuxSession{
+		{Name: "api", Windows: []Window{{Index: 0, Name: "editor", Panes: []Pane{
+			{ID: "%1", Index: 0, Target: "api:0.0"},
+			{ID: "%2", Index: 1, Target: "api:0.1"},
+		}}}},
+	}}
+	for target, want := range map[string]bool{
+		"api":        true,
+		"api:0":      true,
+		"api:editor": true,
+		"api:0.1":    true,
+		"%2":         true,
+		"api:0.2":    false,
+		"api:1":      false,
+		"web:0.0":    false,
+		"%9":         false,
+		"":           false,
+	} {
+		if got := tree.HasTarget(target); got != want {
+			t.Errorf("HasTarget(%q) = %v, want %v", target, got, want)
+		}
+	}
+
+	if !MissingJobTarget(config.ScheduledJob{Target: "web:0.0"}, tree) {
+		t.Fatal("expected a killed session's target to be missing")
+	}
+	if MissingJobTarget(config.ScheduledJob{Target: "api:0.1"}, tree) {
+		t.Fatal("expected a live target not to be missing")
+	}
+	if MissingJobTarget(config.ScheduledJob{TargetMode: config.TargetModeAllAgents}, tree) {
+		t.Fatal("all-agent jobs have no fixed target to miss")
+	}
+}
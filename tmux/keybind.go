@@ -0,0 +1,163 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Markers delimiting the atmux-managed keybindings in ~/.tmux.conf. Lines
+// between them are owned by atmux and rewritten or removed as a whole.
+const (
+	KeybindBlockStart = "# >>> atmux keybindings (managed by atmux keybind) >>>"
+	KeybindBlockEnd   = "# <<< atmux keybindings <<<"
+)
+
+// KeybindTargets lists the atmux screens a keybinding can open.
+var KeybindTargets = []string{"browse", "sessions", "landing"}
+
+// keybindCommands is the tmux command each target runs. browse opens its
+// own popup; sessions is asked to, and landing has no popup mode so tmux
+// provides one.
+var keybindCommands = map[string]string{
+	"browse":   `run-shell "atmux browse"`,
+	"sessions": `run-shell "atmux sessions -p"`,
+	"landing":  `display-popup -E "atmux landing"`,
+}
+
+// TmuxConfPath returns the path of the user's ~/.tmux.conf.
+func TmuxConfPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".tmux.conf"), nil
+}
+
+// KeybindLine returns the bind-key line that opens target on prefix + key.
+func KeybindLine(key, target string) (string, error) {
+	if key == "" || strings.ContainsAny(key, " \t\"'") {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	command, ok := keybindCommands[target]
+	if !ok {
+		return "", fmt.Errorf("unknown keybinding target %q (want %s)", target, strings.Join(KeybindTargets, ", "))
+	}
+	return fmt.Sprintf("bind-key %s %s", key, command), nil
+}
+
+// bindingKeyPattern extracts the key from a bind-key line, skipping -r/-n.
+var bindingKeyPattern = regexp.MustCompile(`^\s*bind(?:-key)?\s+(?:-[rn]\s+)?(\S+)\s`)
+
+// bindingKey returns the key bound by line, or "" if it isn't a bind-key.
+func bindingKey(line string) string {
+	if m := bindingKeyPattern.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// splitKeybindBlock splits content around the managed block. found is false
+// when content has no complete block, in which case before is content.
+func splitKeybindBlock(content string) (before string, block []string, after string, found bool) {
+	start := strings.Index(content, KeybindBlockStart+"\n")
+	if start < 0 {
+		return content, nil, "", false
+	}
+	bodyStart := start + len(KeybindBlockStart) + 1
+	end := strings.Index(content[bodyStart:], KeybindBlockEnd)
+	if end < 0 {
+		return content, nil, "", false
+	}
+	body := strings.TrimSuffix(content[bodyStart:bodyStart+end], "\n")
+	if body != "" {
+		block = strings.Split(body, "\n")
+	}
+	after = strings.TrimPrefix(content[bodyStart+end+len(KeybindBlockEnd):], "\n")
+	return content[:start], block, after, true
+}
+
+// ManagedKeybindings returns the lines in content's managed block.
+func ManagedKeybindings(content string) []string {
+	_, block, _, _ := splitKeybindBlock(content)
+	return block
+}
+
+// FindDuplicateKeybinding reports whether key is already bound in content,
+// returning the first binding line found.
+func FindDuplicateKeybinding(content, key string) (bool, string) {
+	// Match bind-key or bind followed by the key
+	pattern := regexp.MustCompile(`(?m)^\s*bind(?:-key)?\s+` + regexp.QuoteMeta(key) + `\s+.*$`)
+	match := pattern.FindString(content)
+	if match != "" {
+		return true, strings.TrimSpace(match)
+	}
+	return false, ""
+}
+
+// FindUnmanagedKeybinding is FindDuplicateKeybinding restricted to lines
+// outside the managed block, which atmux is free to replace.
+func FindUnmanagedKeybinding(content, key string) (bool, string) {
+	before, _, after, _ := splitKeybindBlock(content)
+	return FindDuplicateKeybinding(before+after, key)
+}
+
+// AddManagedKeybinding returns content with line in the managed block,
+// replacing any managed binding for the same key. The block is appended to
+// the end of content when missing.
+func AddManagedKeybinding(content, line string) string {
+	before, block, after, found := splitKeybindBlock(content)
+	key := bindingKey(line)
+	var kept []string
+	for _, existing := range block {
+		if bindingKey(existing) != key {
+			kept = append(kept, existing)
+		}
+	}
+	kept = append(kept, line)
+
+	section := KeybindBlockStart + "\n" + strings.Join(kept, "\n") + "\n" + KeybindBlockEnd + "\n"
+	if found {
+		return before + section + after
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + section
+}
+
+// RemoveManagedKeybindings returns content without the managed block and
+// whether there was one to remove.
+func RemoveManagedKeybindings(content string) (string, bool) {
+	before, _, after, found := splitKeybindBlock(content)
+	if !found {
+		return content, false
+	}
+	// Drop the blank line AddManagedKeybinding put before the block
+	if strings.HasSuffix(before, "\n\n") {
+		before = strings.TrimSuffix(before, "\n")
+	}
+	return before + after, true
+}
+
+// UpdateTmuxConf rewrites the tmux config at path with edit applied to its
+// contents. A missing file is treated as empty.
+func UpdateTmuxConf(path string, edit func(content string) string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read %s: %w", path, err)
+	}
+	updated := edit(string(data))
+	if updated == string(data) {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("could not write to %s: %w", path, err)
+	}
+	return nil
+}
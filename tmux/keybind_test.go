@@ -0,0 +1,63 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeybindLine(t *testing.T) {
+	got, err := KeybindLine("C-s", "landing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `bind-key C-s display-popup -E "atmux landing"`; got != want {
+		t.Fatalf("KeybindLine = %q, want %q", got, want)
+	}
+	if _, err := KeybindLine("S", "tree"); err == nil {
+		t.Fatal("expected an unknown target to be rejected")
+	}
+	if _, err := KeybindLine("a b", "browse"); err == nil {
+		t.Fatal("expected a key with spaces to be rejected")
+	}
+}
+
+func TestManagedKeybindingBlock(t *testing.T) {
+	original := "set -g mouse on\nbind-key S choose-tree\n"
+	browse, _ := KeybindLine("T", "browse")
+	sessions, _ := KeybindLine("s", "sessions")
+
+	content := AddManagedKeybinding(original, browse)
+	content = AddManagedKeybinding(content, sessions)
+	if got := ManagedKeybindings(content); len(got) != 2 || got[0] != browse || got[1] != sessions {
+		t.Fatalf("managed bindings = %q", got)
+	}
+
+	// Re-binding a key replaces its managed line
+	landing, _ := KeybindLine("T", "landing")
+	content = AddManagedKeybinding(content, landing)
+	if got := ManagedKeybindings(content); len(got) != 2 || got[1] != landing {
+		t.Fatalf("expected T to be rebound, got %q", got)
+	}
+
+	// Only bindings outside the block count as duplicates
+	if found, _ := FindUnmanagedKeybinding(content, "T"); found {
+		t.Fatal("managed binding reported as a duplicate")
+	}
+	if found, line := FindUnmanagedKeybinding(content, "S"); !found || line != "bind-key S choose-tree" {
+		t.Fatalf("expected the hand-written S binding, got %v %q", found, line)
+	}
+
+	removed, ok := RemoveManagedKeybindings(content + "set -g status on\n")
+	if !ok {
+		t.Fatal("expected the block to be removed")
+	}
+	if want := original + "set -g status on\n"; removed != want {
+		t.Fatalf("after removal:\n%q\nwant:\n%q", removed, want)
+	}
+	if strings.Contains(removed, KeybindBlockStart) {
+		t.Fatal("start marker left behind")
+	}
+	if _, ok := RemoveManagedKeybindings(original); ok {
+		t.Fatal("expected nothing to remove without a block")
+	}
+}
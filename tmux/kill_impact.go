@@ -0,0 +1,90 @@
+package tmux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KillImpact counts what killing a session, window or pane would destroy.
+type KillImpact struct {
+	Windows int
+	Panes   int
+	Agents  []string // Panes running a known agent, as "claude in api:0.1"
+}
+
+// String summarizes the impact, e.g. "2 windows, 5 panes" or "1 pane".
+// Panes are left out when only the windows are known.
+func (i KillImpact) String() string {
+	var parts []string
+	if i.Windows > 0 {
+		parts = append(parts, plural(i.Windows, "window"))
+	}
+	if i.Panes > 0 || i.Windows == 0 {
+		parts = append(parts, plural(i.Panes, "pane"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// addPane counts one pane, noting it when its command is one of programs
+// (see AgentPrograms).
+func (i *KillImpact) addPane(target, command string, programs map[string]bool) {
+	i.Panes++
+	if programs[strings.ToLower(command)] {
+		i.Agents = append(i.Agents, command+" in "+target)
+	}
+}
+
+// KillImpact returns what killing the session, window or pane at target
+// (nodeType "session", "window" or "pane") would destroy, with panes running
+// one of programs listed as agents.
+func (t *Tree) KillImpact(nodeType, target string, programs map[string]bool) KillImpact {
+	var impact KillImpact
+	if t == nil {
+		return impact
+	}
+	for _, sess := range t.Sessions {
+		for _, win := range sess.Windows {
+			whole := (nodeType == "session" && sess.Name == target) ||
+				(nodeType == "window" && sess.Name+":"+strconv.Itoa(win.Index) == target)
+			if whole && nodeType == "session" {
+				impact.Windows++ // A window kill is its panes; no need to count it
+			}
+			for _, pane := range win.Panes {
+				if whole || (nodeType == "pane" && pane.Target == target) {
+					impact.addPane(pane.Target, pane.Command, programs)
+				}
+			}
+		}
+	}
+	return impact
+}
+
+// SessionKillImpactWithExecutor lists a session's panes through exec to
+// count what killing it would destroy, for callers without a tree.
+func SessionKillImpactWithExecutor(session string, programs map[string]bool, exec TmuxExecutor) (KillImpact, error) {
+	output, err := exec.Output("list-panes", "-s", "-t", "="+session, "-F",
+		"#{window_index}\t#{session_name}:#{window_index}.#{pane_index}\t#{pane_current_command}")
+	if err != nil {
+		return KillImpact{}, fmt.Errorf("failed to list panes of %s: %w", session, err)
+	}
+	var impact KillImpact
+	windows := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		windows[fields[0]] = true
+		impact.addPane(fields[1], fields[2], programs)
+	}
+	impact.Windows = len(windows)
+	return impact, nil
+}
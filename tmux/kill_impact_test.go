@@ -0,0 +1,47 @@
+package tmux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTreeKillImpact(t *testing.T) {
+	tree := &Tree{Sessions: []TmuxSession{{
+		Name: "api",
+		Windows: []Window{
+			{Index: 0, Panes: []Pane{
+				{Target: "api:0.0", Command: "zsh"},
+				{Target: "api:0.1", Command: "claude"},
+			}},
+			{Index: 1, Panes: []Pane{{Target: "api:1.0", Command: "vim"}}},
+		},
+	}, {
+		Name:    "web",
+		Windows: []Window{{Index: 0, Panes: []Pane{{Target: "web:0.0", Command: "codex"}}}},
+	}}}
+	programs := map[string]bool{"claude": true, "codex": true}
+
+	session := tree.KillImpact("session", "api", programs)
+	if session.String() != "2 windows, 3 panes" || !reflect.DeepEqual(session.Agents, []string{"claude in api:0.1"}) {
+		t.Fatalf("unexpected session impact %+v", session)
+	}
+	if window := tree.KillImpact("window", "api:0", programs); window.String() != "2 panes" || len(window.Agents) != 1 {
+		t.Fatalf("unexpected window impact %+v", window)
+	}
+	if pane := tree.KillImpact("pane", "web:0.0", programs); pane.String() != "1 pane" || len(pane.Agents) != 1 {
+		t.Fatalf("unexpected pane impact %+v", pane)
+	}
+}
+
+func TestSessionKillImpactWithExecutor(t *testing.T) {
+	exec := &fakeExecutor{responses: map[string]fakeResponse{
+		"list-panes": {output: []byte("0\tapi:0.0\tzsh\n0\tapi:0.1\tClaude\n2\tapi:2.0\tvim\n")},
+	}}
+	impact, err := SessionKillImpactWithExecutor("api", map[string]bool{"claude": true}, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if impact.String() != "2 windows, 3 panes" || !reflect.DeepEqual(impact.Agents, []string{"Claude in api:0.1"}) {
+		t.Fatalf("unexpected impact %+v", impact)
+	}
+}
@@ -0,0 +1,67 @@
+package tmux
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// pasteChunkSize keeps each set-buffer call well under tmux's limit on the
+// size of a command sent to its server.
+const pasteChunkSize = 8 * 1024
+
+// PasteToTarget pastes content into a pane through a tmux paste buffer, the
+// way a terminal paste arrives, instead of typing it key by key with
+// send-keys. Programs in the pane then see one paste rather than reacting to
+// each character (autocomplete, key bindings). Bracketed paste is used when
+// the program asks for it. Trailing newlines are dropped so the paste isn't
+// submitted.
+func PasteToTarget(target, content string) error {
+	return PasteToTargetWithExecutor(target, content, NewLocalExecutor())
+}
+
+// PasteToTargetWithExecutor pastes content into a pane using the given executor.
+func PasteToTargetWithExecutor(target, content string, exec TmuxExecutor) error {
+	content = strings.TrimRight(content, "\r\n")
+	if content == "" {
+		return fmt.Errorf("nothing to paste")
+	}
+
+	// A buffer of our own, so the user's paste buffers are left alone
+	buffer := fmt.Sprintf("atmux-paste-%d", time.Now().UnixNano())
+	for i, chunk := range pasteChunks(content, pasteChunkSize) {
+		args := []string{"set-buffer", "-b", buffer}
+		if i > 0 {
+			args = append(args, "-a")
+		}
+		args = append(args, "--", chunk)
+		if err := exec.Run(args...); err != nil {
+			exec.Run("delete-buffer", "-b", buffer) //nolint:errcheck
+			return fmt.Errorf("failed to load paste buffer: %w", err)
+		}
+	}
+	if err := exec.Run("paste-buffer", "-p", "-d", "-b", buffer, "-t", target); err != nil {
+		exec.Run("delete-buffer", "-b", buffer) //nolint:errcheck
+		return fmt.Errorf("failed to paste into %s: %w", target, err)
+	}
+	return nil
+}
+
+// pasteChunks splits s into pieces of at most size bytes without splitting
+// a UTF-8 character.
+func pasteChunks(s string, size int) []string {
+	var chunks []string
+	for len(s) > size {
+		end := size
+		for end > 0 && !utf8.RuneStart(s[end]) {
+			end--
+		}
+		if end == 0 {
+			end = size
+		}
+		chunks = append(chunks, s[:end])
+		s = s[end:]
+	}
+	return append(chunks, s)
+}
@@ -0,0 +1,82 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// runRecorder records the tmux commands run through it.
+type runRecorder struct {
+	fakeExecutor
+	runs [][]string
+}
+
+func (r *runRecorder) Run(args ...string) error {
+	r.runs = append(r.runs, args)
+	return nil
+}
+
+func TestPasteToTargetLoadsBufferThenPastes(t *testing.T) {
+	exec := &runRecorder{}
+	content := strings.Repeat("é", pasteChunkSize) + "\n\n" // Two chunks' worth, multi-byte
+	if err := PasteToTargetWithExecutor("api:0.1", content, exec); err != nil {
+		t.Fatal(err)
+	}
+	if len(exec.runs) != 3 {
+		t.Fatalf("expected two set-buffer calls and a paste, got %v", len(exec.runs))
+	}
+
+	var loaded strings.Builder
+	for i, args := range exec.runs[:2] {
+		if args[0] != "set-buffer" || (i > 0) != (args[3] == "-a") {
+			t.Fatalf("unexpected buffer call %v", args[:4])
+		}
+		chunk := args[len(args)-1]
+		if !utf8.ValidString(chunk) || len(chunk) > pasteChunkSize {
+			t.Fatalf("chunk %d is %d bytes or splits a character", i, len(chunk))
+		}
+		loaded.WriteString(chunk)
+	}
+	if loaded.String() != strings.TrimRight(content, "\n") {
+		t.Fatal("expected the buffer to hold the content without trailing newlines")
+	}
+
+	paste := strings.Join(exec.runs[2], " ")
+	if !strings.HasPrefix(paste, "paste-buffer -p -d -b atmux-paste-") || !strings.HasSuffix(paste, "-t api:0.1") {
+		t.Fatalf("unexpected paste command %q", paste)
+	}
+
+	if err := PasteToTargetWithExecutor("api:0.1", "\n", &runRecorder{}); err == nil {
+		t.Fatal("expected an error pasting nothing")
+	}
+}
+
+func TestSendCommandBracketedPasteUsesPasteBuffer(t *testing.T) {
+	exec := &runRecorder{}
+	text := "first line\nsecond line"
+	if err := SendCommandWithMethodAndExecutor("api:0.1", text, SendMethodBracketedPaste, exec); err != nil {
+		t.Fatal(err)
+	}
+	if len(exec.runs) != 3 {
+		t.Fatalf("expected the buffer, the paste, and then Enter, got %v", exec.runs)
+	}
+	if load := exec.runs[0]; load[0] != "set-buffer" || load[len(load)-1] != text {
+		t.Fatalf("expected the text loaded into a buffer as-is, got %q", load)
+	}
+	if paste := exec.runs[1]; paste[0] != "paste-buffer" || paste[1] != "-p" {
+		t.Fatalf("expected paste-buffer -p, so the program decides on bracketing, got %v", paste)
+	}
+	if strings.Join(exec.runs[2], " ") != "send-keys -t api:0.1 Enter" {
+		t.Fatalf("expected Enter after the paste, got %v", exec.runs[2])
+	}
+
+	if SendMethodForJob(config.ScheduledJob{SendMethod: config.JobSendBracketedPaste}) != SendMethodBracketedPaste {
+		t.Fatal("expected pasting jobs to use bracketed paste")
+	}
+	if SendMethodForJob(config.ScheduledJob{}) != SendMethodEnterDelayed {
+		t.Fatal("expected other jobs to use the default method")
+	}
+}
@@ -2,6 +2,7 @@ package tmux
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,8 +14,9 @@ import (
 )
 
 const (
-	defaultSSHPort    = 22
-	defaultSSHTimeout = 10 * time.Second
+	defaultSSHPort        = 22
+	defaultSSHTimeout     = 10 * time.Second
+	defaultControlPersist = 5 * time.Minute
 )
 
 // RemoteExecutor runs tmux commands on a remote host via SSH.
@@ -26,9 +28,19 @@ type RemoteExecutor struct {
 	Alias          string // Display alias (e.g., "devbox")
 	AttachStrategy string // Per-host override: "auto", "replace", or "new-window" (empty = use global)
 
-	controlPath string    // ControlMaster socket path
-	controlOnce sync.Once // Ensures ControlMaster is started at most once
-	controlErr  error     // Error from ControlMaster setup
+	// Timeout bounds each command run over SSH, including the initial
+	// connect (0 = defaultSSHTimeout).
+	Timeout time.Duration
+
+	// NoMultiplex leaves connection sharing to the user's ssh config
+	// instead of starting atmux's own ControlMaster.
+	NoMultiplex bool
+	// ControlPersist is how long the shared connection stays open once
+	// idle (0 = defaultControlPersist).
+	ControlPersist time.Duration
+
+	controlPath string     // ControlMaster socket path, set once the master is up
+	controlMu   sync.Mutex // Serializes starting the ControlMaster
 }
 
 // NewRemoteExecutor creates a new RemoteExecutor for the given host.
@@ -50,76 +62,143 @@ func NewRemoteExecutor(host string, port int, attachMethod, alias string) *Remot
 	}
 }
 
-// ensureControlMaster lazily starts an SSH ControlMaster connection.
+// ensureControlMaster lazily starts an SSH ControlMaster connection. The
+// socket lives in a fresh directory per executor, so each host gets its
+// own. A master that fails to come up is cleaned away and tried again on
+// the next command, so a network blip doesn't break the host for good.
 func (e *RemoteExecutor) ensureControlMaster() error {
-	e.controlOnce.Do(func() {
-		// Create a temp directory for the socket under /tmp to keep paths short.
-		// macOS limits Unix socket paths to 104 bytes; the default os.TempDir()
-		// (/var/folders/...) is too long when combined with the %C hash expansion.
-		dir, err := os.MkdirTemp("/tmp", "atmux-*")
+	if e.NoMultiplex {
+		return nil
+	}
+	e.controlMu.Lock()
+	defer e.controlMu.Unlock()
+	if e.controlPath != "" {
+		return nil
+	}
+	return e.startControlMaster()
+}
+
+// startControlMaster starts the master and sets controlPath once it is up.
+func (e *RemoteExecutor) startControlMaster() (err error) {
+	// Create a temp directory for the socket under /tmp to keep paths short.
+	// macOS limits Unix socket paths to 104 bytes; the default os.TempDir()
+	// (/var/folders/...) is too long when combined with the %C hash expansion.
+	dir, err := os.MkdirTemp("/tmp", "atmux-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir for SSH socket: %w", err)
+	}
+	path := filepath.Join(dir, "s")
+	// ssh's own messages go to a log file rather than a pipe, which the
+	// backgrounded master would hold open
+	logPath := filepath.Join(dir, "log")
+	defer func() {
 		if err != nil {
-			e.controlErr = fmt.Errorf("failed to create temp dir for SSH socket: %w", err)
+			os.RemoveAll(dir) //nolint:errcheck
 			return
 		}
-		e.controlPath = filepath.Join(dir, "s")
-
-		ctx, cancel := context.WithTimeout(context.Background(), defaultSSHTimeout)
-		defer cancel()
-
-		args := []string{
-			"-o", "ControlMaster=yes",
-			"-o", "ControlPath=" + e.controlPath,
-			"-o", "ControlPersist=300", // Keep alive for 5 minutes
-			"-o", "StrictHostKeyChecking=accept-new",
-			"-p", strconv.Itoa(e.Port),
-			"-N", // No remote command
-			e.Host,
-		}
+		e.controlPath = path
+	}()
 
-		cmd := exec.CommandContext(ctx, "ssh", args...)
-		if err := cmd.Start(); err != nil {
-			e.controlErr = fmt.Errorf("failed to start SSH ControlMaster to %s: %w", e.Host, err)
-			return
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), e.commandTimeout())
+	defer cancel()
 
-		// Wait for the control socket to appear or the process to exit.
-		done := make(chan error, 1)
-		go func() { done <- cmd.Wait() }()
-
-		// Poll for the socket file to appear (handles slow connections).
-		ticker := time.NewTicker(100 * time.Millisecond)
-		defer ticker.Stop()
-		deadline := time.After(defaultSSHTimeout)
-
-		for {
-			select {
-			case err := <-done:
-				// Process exited — expected with -N and ControlPersist once forked.
-				if err != nil {
-					e.controlErr = fmt.Errorf("SSH ControlMaster to %s failed: %w", e.Host, err)
-				}
-				return
-			case <-deadline:
-				e.controlErr = fmt.Errorf("SSH ControlMaster to %s timed out waiting for socket", e.Host)
-				return
-			case <-ticker.C:
-				if socketExists(e.controlPath) {
-					return
+	args := []string{
+		"-o", "ControlMaster=yes",
+		"-o", "ControlPath=" + path,
+		"-o", "ControlPersist=" + e.controlPersist(),
+		"-o", "StrictHostKeyChecking=accept-new",
+		"-E", logPath,
+		"-p", strconv.Itoa(e.Port),
+		"-N", // No remote command
+		e.Host,
+	}
+
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start SSH ControlMaster to %s: %w", e.Host, err)
+	}
+
+	// Wait for the control socket to appear or the process to exit.
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	// Poll for the socket file to appear (handles slow connections).
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(e.commandTimeout())
+
+	for {
+		select {
+		case err := <-done:
+			// Process exited — expected with -N and ControlPersist once forked.
+			if err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					exitErr.Stderr, _ = os.ReadFile(logPath)
 				}
+				return fmt.Errorf("SSH ControlMaster to %s failed: %w", e.Host, err)
+			}
+			return nil
+		case <-deadline:
+			return fmt.Errorf("SSH ControlMaster to %s timed out waiting for socket: %w", e.Host, context.DeadlineExceeded)
+		case <-ticker.C:
+			if socketExists(path) {
+				return nil
 			}
 		}
-	})
-	return e.controlErr
+	}
 }
 
-// sshArgs returns the common SSH arguments including ControlPath.
+// commandTimeout returns how long each SSH command may take.
+func (e *RemoteExecutor) commandTimeout() time.Duration {
+	if e.Timeout > 0 {
+		return e.Timeout
+	}
+	return defaultSSHTimeout
+}
+
+// controlPersist returns the ssh ControlPersist value in whole seconds.
+func (e *RemoteExecutor) controlPersist() string {
+	d := e.ControlPersist
+	if d <= 0 {
+		d = defaultControlPersist
+	}
+	return strconv.Itoa(max(1, int(d.Seconds())))
+}
+
+// sshCommand builds an ssh command that is killed when ctx expires.
+// WaitDelay keeps a killed ssh from blocking on output pipes that a
+// lingering child still holds open.
+func sshCommand(ctx context.Context, args []string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// timeoutError reports a command killed at its deadline as
+// context.DeadlineExceeded, so callers can tell a stalled host ("timed
+// out") from one that answered with an error.
+func (e *RemoteExecutor) timeoutError(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s: %w", e.Alias, context.DeadlineExceeded)
+	}
+	return err
+}
+
+// sshArgs returns the common SSH arguments including ControlPath. With
+// NoMultiplex the control options are left out so ~/.ssh/config decides.
 func (e *RemoteExecutor) sshArgs() []string {
 	args := []string{
-		"-o", "ControlMaster=auto",
-		"-o", "ControlPersist=300",
 		"-o", "StrictHostKeyChecking=accept-new",
 		"-p", strconv.Itoa(e.Port),
 	}
+	if e.NoMultiplex {
+		return args
+	}
+	args = append(args,
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPersist="+e.controlPersist(),
+	)
 	if e.controlPath != "" {
 		args = append(args, "-o", "ControlPath="+e.controlPath)
 	}
@@ -149,13 +228,14 @@ func (e *RemoteExecutor) Run(args ...string) error {
 		return err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultSSHTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), e.commandTimeout())
 	defer cancel()
 
 	sshArgs := e.sshArgs()
 	sshArgs = append(sshArgs, e.Host, remoteCommand("tmux", args))
 
-	return exec.CommandContext(ctx, "ssh", sshArgs...).Run()
+	err := sshCommand(ctx, sshArgs).Run()
+	return remoteTmuxError(e.Alias, e.timeoutError(ctx, err))
 }
 
 func (e *RemoteExecutor) Output(args ...string) ([]byte, error) {
@@ -163,13 +243,14 @@ func (e *RemoteExecutor) Output(args ...string) ([]byte, error) {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultSSHTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), e.commandTimeout())
 	defer cancel()
 
 	sshArgs := e.sshArgs()
 	sshArgs = append(sshArgs, e.Host, remoteCommand("tmux", args))
 
-	return exec.CommandContext(ctx, "ssh", sshArgs...).Output()
+	out, err := sshCommand(ctx, sshArgs).Output()
+	return out, remoteTmuxError(e.Alias, e.timeoutError(ctx, err))
 }
 
 func (e *RemoteExecutor) RunWithDir(dir string, args ...string) error {
@@ -231,6 +312,37 @@ func (e *RemoteExecutor) buildMoshArgs(args ...string) []string {
 	return moshArgs
 }
 
+// InteractiveCommand returns the command line Interactive runs for args,
+// quoted for a POSIX shell so it can be pasted into another terminal.
+func (e *RemoteExecutor) InteractiveCommand(args ...string) string {
+	if e.AttachMethod == "mosh" {
+		return ShellJoin(append([]string{"mosh"}, e.buildMoshArgs(args...)...))
+	}
+	return ShellJoin(append([]string{"ssh"}, e.buildSSHInteractiveArgs(args...)...))
+}
+
+// ShellJoin joins args into a single command line, single-quoting any
+// argument that a shell would otherwise split or expand.
+func ShellJoin(args []string) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.IndexFunc(a, needsShellQuote) >= 0 {
+			a = shellQuote(a)
+		}
+		parts[i] = a
+	}
+	return strings.Join(parts, " ")
+}
+
+// needsShellQuote reports whether r is special to a POSIX shell.
+func needsShellQuote(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("@%+=:,./_-", r)
+}
+
 func (e *RemoteExecutor) interactiveMosh(args ...string) error {
 	moshArgs := e.buildMoshArgs(args...)
 
@@ -249,13 +361,14 @@ func (e *RemoteExecutor) RunGeneric(command string, args ...string) ([]byte, err
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultSSHTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), e.commandTimeout())
 	defer cancel()
 
 	sshArgs := e.sshArgs()
 	sshArgs = append(sshArgs, e.Host, remoteCommand(command, args))
 
-	return exec.CommandContext(ctx, "ssh", sshArgs...).Output()
+	out, err := sshCommand(ctx, sshArgs).Output()
+	return out, e.timeoutError(ctx, err)
 }
 
 // socketExists checks whether a Unix socket file exists at the given path.
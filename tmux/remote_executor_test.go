@@ -1,8 +1,13 @@
 package tmux
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestBuildSSHInteractiveArgs_DefaultPort(t *testing.T) {
@@ -156,3 +161,126 @@ func TestIsRemote(t *testing.T) {
 		t.Fatal("expected IsRemote() to be true")
 	}
 }
+
+func TestInteractiveCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		exec *RemoteExecutor
+		want string
+	}{
+		{"ssh", NewRemoteExecutor("user@devbox", 22, "ssh", "devbox"), "ssh -t -p 22 user@devbox tmux attach-session -t 'my work'"},
+		{"mosh custom port", NewRemoteExecutor("user@devbox", 2222, "mosh", "devbox"), "mosh '--ssh=ssh -p 2222' user@devbox -- tmux attach-session -t 'my work'"},
+	}
+	for _, tt := range tests {
+		if got := tt.exec.InteractiveCommand("attach-session", "-t", "my work"); got != tt.want {
+			t.Errorf("%s: InteractiveCommand = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// fakeSSH puts an ssh on PATH that lets the ControlMaster start and stop
+// but stalls every command.
+func fakeSSH(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\nfor a in \"$@\"; do case \"$a\" in -N|-O) exit 0;; esac; done\nexec sleep 5\n"
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRemoteExecutorTimesOut(t *testing.T) {
+	fakeSSH(t)
+	e := NewRemoteExecutor("devbox", 0, "", "")
+	e.Timeout = 100 * time.Millisecond
+	defer e.Close()
+
+	start := time.Now()
+	_, err := e.Output("list-sessions")
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("expected the command to be killed at its timeout, took %v", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || FetchErrorReason(err) != "timed out" {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func TestFetchTreeWithExecutorsKeepsFastHosts(t *testing.T) {
+	fakeSSH(t)
+	slow := NewRemoteExecutor("devbox", 0, "", "")
+	slow.Timeout = 200 * time.Millisecond
+	defer slow.Close()
+	fast := &fakeExecutor{host: "fast", remote: true, responses: map[string]fakeResponse{
+		"list-sessions": {output: []byte("work:0:0\n")},
+	}}
+
+	results := FetchTreeWithExecutors([]TmuxExecutor{slow, fast})
+	if FetchErrorReason(results[0].Err) != "timed out" {
+		t.Fatalf("expected devbox to time out, got %v", results[0].Err)
+	}
+	if results[1].Err != nil || results[1].Tree == nil || len(results[1].Tree.Sessions) != 1 {
+		t.Fatalf("expected the fast host's sessions, got %+v", results[1])
+	}
+}
+
+func TestSSHArgsMultiplexing(t *testing.T) {
+	e := NewRemoteExecutor("devbox", 0, "", "")
+	e.ControlPersist = 10 * time.Minute
+	e.controlPath = "/tmp/atmux-test/s"
+	want := []string{
+		"-o", "StrictHostKeyChecking=accept-new",
+		"-p", "22",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPersist=600",
+		"-o", "ControlPath=/tmp/atmux-test/s",
+	}
+	if got := e.sshArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("sshArgs = %q, want %q", got, want)
+	}
+
+	e.NoMultiplex = true
+	want = []string{"-o", "StrictHostKeyChecking=accept-new", "-p", "22"}
+	if got := e.sshArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("sshArgs without multiplexing = %q, want %q", got, want)
+	}
+}
+
+func TestControlPersistDefault(t *testing.T) {
+	e := NewRemoteExecutor("devbox", 0, "", "")
+	if got := e.controlPersist(); got != "300" {
+		t.Errorf("controlPersist = %q, want 300", got)
+	}
+}
+
+func TestNoMultiplexSkipsControlMaster(t *testing.T) {
+	fakeSSH(t)
+	e := NewRemoteExecutor("devbox", 0, "", "")
+	e.NoMultiplex = true
+	if err := e.ensureControlMaster(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.controlPath != "" {
+		t.Errorf("expected no control socket, got %q", e.controlPath)
+	}
+}
+
+func TestFailedControlMasterRemovesSocketDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte("#!/bin/sh\nexit 255\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	before, _ := filepath.Glob("/tmp/atmux-*")
+	e := NewRemoteExecutor("devbox", 0, "", "")
+	if err := e.ensureControlMaster(); err == nil {
+		t.Fatal("expected the ControlMaster to fail")
+	}
+	if e.controlPath != "" {
+		t.Errorf("expected the control path to be cleared, got %q", e.controlPath)
+	}
+	if after, _ := filepath.Glob("/tmp/atmux-*"); len(after) > len(before) {
+		t.Errorf("socket directory left behind: %v", after)
+	}
+}
@@ -0,0 +1,95 @@
+package tmux
+
+import (
+	"strings"
+	"sync"
+)
+
+// searchConcurrency bounds how many capture-pane calls run at once across
+// all hosts, so searching several remotes doesn't open a flood of SSH calls.
+const searchConcurrency = 4
+
+// maxSnippetLen is the maximum length (in runes) of a search result snippet.
+const maxSnippetLen = 80
+
+// PaneMatch is a pane whose content matched a search query.
+type PaneMatch struct {
+	Host    string // Host label ("" for local)
+	Target  string // Pane target (session:window.pane)
+	Snippet string // First matching line, trimmed
+}
+
+// SearchPanes captures every pane of every reachable host tree and returns
+// the panes whose content contains query (case-insensitive). Results keep
+// the host/session/window/pane order of hostTrees.
+func SearchPanes(hostTrees []HostTree, query string) []PaneMatch {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	needle := strings.ToLower(query)
+
+	type job struct {
+		host   string
+		target string
+		exec   TmuxExecutor
+	}
+	var jobs []job
+	for _, ht := range hostTrees {
+		if ht.Err != nil || ht.Tree == nil || ht.Executor == nil {
+			continue
+		}
+		for _, sess := range ht.Tree.Sessions {
+			for _, win := range sess.Windows {
+				for _, pane := range win.Panes {
+					jobs = append(jobs, job{host: ht.Host, target: pane.Target, exec: ht.Executor})
+				}
+			}
+		}
+	}
+
+	results := make([]*PaneMatch, len(jobs))
+	sem := make(chan struct{}, searchConcurrency)
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		go func(i int, j job) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			output, err := j.exec.Output("capture-pane", "-t", j.target, "-p")
+			if err != nil {
+				return
+			}
+			if snippet, ok := matchSnippet(string(output), needle); ok {
+				results[i] = &PaneMatch{Host: j.host, Target: j.target, Snippet: snippet}
+			}
+		}(i, j)
+	}
+	wg.Wait()
+
+	var matches []PaneMatch
+	for _, r := range results {
+		if r != nil {
+			matches = append(matches, *r)
+		}
+	}
+	return matches
+}
+
+// matchSnippet returns the first line of content containing needle
+// (already lowercased), trimmed and truncated for display.
+func matchSnippet(content, needle string) (string, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.Contains(strings.ToLower(line), needle) {
+			continue
+		}
+		snippet := strings.TrimSpace(line)
+		if runes := []rune(snippet); len(runes) > maxSnippetLen {
+			snippet = string(runes[:maxSnippetLen-3]) + "..."
+		}
+		return snippet, true
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+package tmux
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSearchPanes_MatchesAcrossHosts(t *testing.T) {
+	local := &fakeExecutor{
+		responses: map[string]fakeResponse{
+			"capture-pane": {output: []byte("$ ls\n  Build FAILED: missing dep  \n")},
+		},
+	}
+	remote := &fakeExecutor{
+		host:   "devbox",
+		remote: true,
+		responses: map[string]fakeResponse{
+			"capture-pane": {output: []byte("all good\n")},
+		},
+	}
+	hostTrees := []HostTree{
+		{Host: "", Executor: local, Tree: &Tree{Sessions: []TmuxSession{{
+			Name: "a",
+			Windows: []Window{{Index: 0, Panes: []Pane{
+				{Index: 0, Target: "a:0.0"},
+				{Index: 1, Target: "a:0.1"},
+			}}},
+		}}}},
+		{Host: "devbox", Executor: remote, Tree: &Tree{Sessions: []TmuxSession{{
+			Name:    "b",
+			Windows: []Window{{Index: 0, Panes: []Pane{{Index: 0, Target: "b:0.0"}}}},
+		}}}},
+		{Host: "down", Err: errors.New("unreachable")},
+	}
+
+	matches := SearchPanes(hostTrees, "build failed")
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].Target != "a:0.0" || matches[1].Target != "a:0.1" {
+		t.Fatalf("expected matches in pane order, got %q and %q", matches[0].Target, matches[1].Target)
+	}
+	if matches[0].Host != "" {
+		t.Fatalf("expected local host, got %q", matches[0].Host)
+	}
+	if matches[0].Snippet != "Build FAILED: missing dep" {
+		t.Fatalf("unexpected snippet %q", matches[0].Snippet)
+	}
+}
+
+func TestSearchPanes_EmptyQuery(t *testing.T) {
+	local := &fakeExecutor{
+		responses: map[string]fakeResponse{
+			"capture-pane": {output: []byte("anything\n")},
+		},
+	}
+	hostTrees := []HostTree{{Executor: local, Tree: &Tree{Sessions: []TmuxSession{{
+		Name:    "a",
+		Windows: []Window{{Panes: []Pane{{Target: "a:0.0"}}}},
+	}}}}}
+
+	if matches := SearchPanes(hostTrees, "   "); matches != nil {
+		t.Fatalf("expected no matches for blank query, got %v", matches)
+	}
+}
+
+func TestMatchSnippet_Truncates(t *testing.T) {
+	long := "needle " + strings.Repeat("x", 200)
+	snippet, ok := matchSnippet(long, "needle")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	if len([]rune(snippet)) != maxSnippetLen {
+		t.Fatalf("expected snippet of %d runes, got %d", maxSnippetLen, len([]rune(snippet)))
+	}
+}
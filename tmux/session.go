@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"crypto/sha1"
 	"fmt"
 	"os"
 	"os/exec"
@@ -21,41 +22,133 @@ type Session struct {
 
 // SessionLine mirrors a single line from `tmux list-sessions`.
 type SessionLine struct {
-	Name     string
-	Line     string
-	Host     string // Remote host label (empty for local)
-	Activity int64  // Unix timestamp of last activity (for sorting)
+	Name         string
+	Line         string
+	Host         string // Remote host label (empty for local)
+	Activity     int64  // Unix timestamp of last activity (for sorting)
+	Created      int64  // Unix timestamp of session creation (0 if unknown)
+	Windows      int    // Number of windows (0 if unknown)
+	LastAttached int64  // Unix timestamp the session was last attached (0 if never)
 }
 
-// NewSession creates a new session configuration based on the current directory
+// NewSession creates a new session configuration based on the current
+// directory, named by the session_name_template setting
 func NewSession(workingDir string) *Session {
-	basename := filepath.Base(workingDir)
-	// Sanitize: replace non-alphanumeric (except _ and -) with _
-	reg := regexp.MustCompile(`[^a-zA-Z0-9_-]`)
-	slug := reg.ReplaceAllString(basename, "_")
-
+	settings, _ := config.LoadSettings()
 	return &Session{
-		Name:       "agent-" + slug,
+		Name:       SessionNameFor(workingDir, settings.EffectiveSessionNameTemplate()),
 		WorkingDir: workingDir,
 	}
 }
 
+// sessionNameSlug matches characters tmux session names shouldn't contain.
+var sessionNameSlug = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// SessionNameFor expands a session name template (see
+// config.SessionNameTokens) for dir into a valid tmux session name. The
+// name gets the "agent-" prefix unless the template already starts with
+// "agent-" or "atmux-", so atmux still recognizes its sessions.
+func SessionNameFor(dir, template string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	replacements := []string{
+		"{basename}", filepath.Base(dir),
+		"{parent}", filepath.Base(filepath.Dir(dir)),
+		"{hash}", fmt.Sprintf("%x", sha1.Sum([]byte(dir)))[:6],
+	}
+	if strings.Contains(template, "{git}") {
+		replacements = append(replacements, "{git}", gitRepoName(dir))
+	}
+	name := strings.NewReplacer(replacements...).Replace(template)
+
+	// Sanitize: replace non-alphanumeric (except _ and -) with _
+	slug := sessionNameSlug.ReplaceAllString(name, "_")
+	if slug == "" {
+		slug = "project"
+	}
+	if strings.HasPrefix(template, "agent-") || strings.HasPrefix(template, "atmux-") {
+		return slug
+	}
+	return "agent-" + slug
+}
+
+// gitRepoName returns the name of the git repository dir is in, or dir's
+// own name outside a repository.
+func gitRepoName(dir string) string {
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if root := strings.TrimSpace(string(output)); err == nil && root != "" {
+		return filepath.Base(root)
+	}
+	return filepath.Base(dir)
+}
+
+// ReviveSession returns the session to revive for a history entry recorded
+// as name in dir. That name is kept while it is free or already belongs to a
+// session in dir; when another directory's session has taken it, the first
+// free "<name>-2", "<name>-3", ... is used instead, so reviving never
+// attaches to the wrong session. An empty name falls back to NewSession's.
+func ReviveSession(name, dir string) *Session {
+	if name == "" {
+		name = NewSession(dir).Name
+	}
+	name = freeSessionName(name, dir, func(candidate string) (string, bool) {
+		s := &Session{Name: candidate}
+		if !s.Exists() {
+			return "", false
+		}
+		return GetSessionPath(candidate), true
+	})
+	return &Session{Name: name, WorkingDir: dir}
+}
+
+// freeSessionName returns base, or base with the first numeric suffix, that
+// either isn't running or is running in dir. pathOf reports a session's
+// directory and whether it exists; an unknown directory counts as dir.
+func freeSessionName(base, dir string, pathOf func(name string) (string, bool)) string {
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+		path, exists := pathOf(candidate)
+		if !exists || path == "" || filepath.Clean(path) == filepath.Clean(dir) {
+			return candidate
+		}
+	}
+	return base
+}
+
 // Exists checks if the tmux session already exists
 func (s *Session) Exists() bool {
 	cmd := exec.Command("tmux", "has-session", "-t", s.Name)
 	return cmd.Run() == nil
 }
 
-// DefaultAgents returns the default agent commands when no config is provided
+// DefaultAgents returns the agent commands used when no config lists any:
+// the enabled agents in settings.json, else the registry's default agents
+// as settings and onboarding show them.
 func DefaultAgents() []config.AgentConfig {
-	return []config.AgentConfig{
-		{Command: "claude --dangerously-skip-permissions"},
-		{Command: "codex --full-auto"},
+	settings, _ := config.LoadSettings()
+	if agents := settings.AgentCommands(); len(agents) > 0 {
+		return agents
 	}
+	known := settings.KnownAgents()
+	var agents []config.AgentConfig
+	for _, a := range known {
+		if a.Default {
+			agents = append(agents, config.AgentConfig{Command: a.DefaultPreference().CommandLine(known)})
+		}
+	}
+	return agents
 }
 
 // Create creates a new tmux session with the agents window
 func (s *Session) Create(cfg *config.Config) error {
+	if err := checkWorkingDir(s.WorkingDir); err != nil {
+		return err
+	}
+
 	// Determine which agents to use
 	agents := DefaultAgents()
 	if cfg != nil && len(cfg.CoreAgents) > 0 {
@@ -135,6 +228,28 @@ func (s *Session) Attach() error {
 	return cmd.Run()
 }
 
+// EnsureSession returns the atmux session for dir, named as the landing
+// page names it, first creating it with cfg's agents, panes and windows if
+// it isn't running.
+func EnsureSession(dir string, cfg *config.Config) (*Session, error) {
+	session := NewSession(dir)
+	if session.Exists() {
+		return session, nil
+	}
+
+	fmt.Printf("Creating new session: %s\n", session.Name)
+	if err := session.Create(cfg); err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		if err := session.ApplyConfig(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to apply config: %v\n", err)
+		}
+	}
+	session.SelectDefault()
+	return session, nil
+}
+
 // AttachToSession attaches or switches to the given tmux session.
 func AttachToSession(name string) error {
 	if name == "" {
@@ -150,11 +265,46 @@ func AttachToSession(name string) error {
 	return cmd.Run()
 }
 
+// AttachReadOnly attaches to the given tmux session as a read-only client
+// (attach-session -r). Keys are not sent to panes, but copy mode still works
+// for scrolling and copying. Inside tmux, the read-only client runs in a new
+// window so the current client is left untouched.
+func AttachReadOnly(name string) error {
+	if name == "" {
+		return nil
+	}
+	if os.Getenv("TMUX") != "" {
+		shellCmd := shellQuoteJoin([]string{"env", "-u", "TMUX", "tmux", "attach-session", "-r", "-t", name})
+		return exec.Command("tmux", "new-window", "-n", "ro:"+name, shellCmd).Run()
+	}
+	cmd := exec.Command("tmux", "attach-session", "-r", "-t", name)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // Kill kills the tmux session
 func (s *Session) Kill() error {
 	return s.run("kill-session", "-t", s.Name)
 }
 
+// checkWorkingDir returns a clear error when dir can't host a new session,
+// e.g. a project revived from history that has since been deleted.
+func checkWorkingDir(dir string) error {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("cannot create session: directory %s no longer exists", dir)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot create session in %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("cannot create session: %s is not a directory", dir)
+	}
+	return nil
+}
+
 // run executes a tmux command
 func (s *Session) run(args ...string) error {
 	cmd := exec.Command("tmux", args...)
@@ -181,9 +331,10 @@ func ListSessions() ([]string, error) {
 }
 
 // sessionListFormat is the tmux format string used for list-sessions.
-// It prepends the activity timestamp (tab-separated) to a display line
-// that closely matches the default tmux output.
-const sessionListFormat = `#{session_activity}	#{session_name}: #{session_windows} windows (created #{t:session_created})#{?session_attached, (attached),}`
+// It prepends the activity, creation and last-attached timestamps
+// (tab-separated) to a display line that closely matches the default tmux
+// output. The last-attached field is empty for sessions never attached.
+const sessionListFormat = `#{session_activity}	#{session_created}	#{session_last_attached}	#{session_name}: #{session_windows} windows (created #{t:session_created})#{?session_attached, (attached),}`
 
 // ListSessionsRaw returns tmux list-sessions output with parsed names,
 // sorted by most recently active first.
@@ -194,7 +345,7 @@ func ListSessionsRaw() ([]SessionLine, error) {
 		if isNoServerError(err) {
 			return []SessionLine{}, nil
 		}
-		return nil, err
+		return nil, localTmuxError(err)
 	}
 
 	sessions := parseSessionLines(string(output))
@@ -218,22 +369,37 @@ func parseSessionLines(output string) []SessionLine {
 func parseSessionLine(line string) SessionLine {
 	trimmed := strings.TrimSpace(line)
 
-	var activity int64
+	var timestamps [3]int64
 	displayLine := trimmed
 
-	// Parse "activity\tdisplay_line" format
-	if idx := strings.IndexByte(trimmed, '\t'); idx != -1 {
-		if ts, err := strconv.ParseInt(trimmed[:idx], 10, 64); err == nil {
-			activity = ts
-			displayLine = trimmed[idx+1:]
+	// Parse "activity\tcreated\tlast_attached\tdisplay_line"; older formats
+	// stop after activity or created, and last_attached may be empty
+	for i := range timestamps {
+		idx := strings.IndexByte(displayLine, '\t')
+		if idx == -1 {
+			break
 		}
+		field := displayLine[:idx]
+		if i == 2 && field == "" {
+			displayLine = displayLine[idx+1:]
+			break
+		}
+		ts, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			break
+		}
+		timestamps[i] = ts
+		displayLine = displayLine[idx+1:]
 	}
+	activity, created, lastAttached := timestamps[0], timestamps[1], timestamps[2]
 
 	name := displayLine
+	var windows int
 	if idx := strings.Index(displayLine, ":"); idx != -1 {
 		name = displayLine[:idx]
+		fmt.Sscanf(strings.TrimSpace(displayLine[idx+1:]), "%d windows", &windows)
 	}
-	return SessionLine{Name: name, Line: displayLine, Activity: activity}
+	return SessionLine{Name: name, Line: displayLine, Activity: activity, Created: created, Windows: windows, LastAttached: lastAttached}
 }
 
 // sortSessionsByActivity sorts sessions by activity timestamp, most recent first.
@@ -252,7 +418,9 @@ func KillSession(name string) error {
 // ListSessionsRawWithExecutor returns tmux list-sessions output using the given executor,
 // sorted by most recently active first.
 func ListSessionsRawWithExecutor(exec TmuxExecutor) ([]SessionLine, error) {
+	release := acquireFetchSlot(exec)
 	output, err := exec.Output("list-sessions", "-F", sessionListFormat)
+	release()
 	if err != nil {
 		if isNoServerError(err) {
 			return []SessionLine{}, nil
@@ -289,43 +457,73 @@ func AttachToSessionWithStrategy(name string, executor TmuxExecutor, strategy co
 	if !executor.IsRemote() {
 		return AttachToSession(name)
 	}
+	return attachRemote(name, executor, strategy, false)
+}
 
+// AttachReadOnlyWithStrategy is like AttachToSessionWithStrategy but attaches
+// as a read-only client. For remote sessions -r is passed through the SSH or
+// mosh attach command.
+func AttachReadOnlyWithStrategy(name string, executor TmuxExecutor, strategy config.AttachStrategy) error {
+	if name == "" {
+		return nil
+	}
+	if !executor.IsRemote() {
+		return AttachReadOnly(name)
+	}
+	return attachRemote(name, executor, strategy, true)
+}
+
+// attachSessionArgs returns the attach-session arguments for the given session.
+func attachSessionArgs(name string, readOnly bool) []string {
+	if readOnly {
+		return []string{"attach-session", "-r", "-t", name}
+	}
+	return []string{"attach-session", "-t", name}
+}
+
+// attachRemote attaches to a remote session according to the strategy.
+func attachRemote(name string, executor TmuxExecutor, strategy config.AttachStrategy, readOnly bool) error {
 	insideTmux := os.Getenv("TMUX") != ""
+	args := attachSessionArgs(name, readOnly)
 
 	switch strategy {
 	case config.AttachStrategyReplace:
-		return executor.Interactive("attach-session", "-t", name)
+		return executor.Interactive(args...)
 	case config.AttachStrategyNewWindow:
 		if !insideTmux {
 			// Can't create a tmux window if we're not inside tmux
-			return executor.Interactive("attach-session", "-t", name)
+			return executor.Interactive(args...)
 		}
-		return attachRemoteInNewWindow(name, executor)
+		return attachRemoteInNewWindow(name, executor, readOnly)
 	default: // auto
 		if insideTmux {
-			return attachRemoteInNewWindow(name, executor)
+			return attachRemoteInNewWindow(name, executor, readOnly)
 		}
-		return executor.Interactive("attach-session", "-t", name)
+		return executor.Interactive(args...)
 	}
 }
 
 // attachRemoteInNewWindow opens a new local tmux window that runs the remote
 // attach command (SSH or mosh) for the given session.
-func attachRemoteInNewWindow(name string, executor TmuxExecutor) error {
+func attachRemoteInNewWindow(name string, executor TmuxExecutor, readOnly bool) error {
+	args := attachSessionArgs(name, readOnly)
 	re, ok := executor.(*RemoteExecutor)
 	if !ok {
 		// Fallback: not a RemoteExecutor, attach directly
-		return executor.Interactive("attach-session", "-t", name)
+		return executor.Interactive(args...)
 	}
 
 	windowName := "remote:" + name
+	if readOnly {
+		windowName = "remote-ro:" + name
+	}
 	var shellCmd []string
 
 	if re.AttachMethod == "mosh" && moshAvailable() {
-		shellCmd = re.buildMoshArgs("attach-session", "-t", name)
+		shellCmd = re.buildMoshArgs(args...)
 		shellCmd = append([]string{"mosh"}, shellCmd...)
 	} else {
-		shellCmd = re.buildSSHInteractiveArgs("attach-session", "-t", name)
+		shellCmd = re.buildSSHInteractiveArgs(args...)
 		shellCmd = append([]string{"ssh"}, shellCmd...)
 	}
 
@@ -349,6 +547,18 @@ func shellQuoteJoin(args []string) string {
 	return strings.Join(quoted, " ")
 }
 
+// RunInNewWindow opens a window named name in session, in dir, running
+// command. The window stays open after command exits until Enter is pressed
+// so its output can be read.
+func RunInNewWindow(session, name, dir, command string) error {
+	shellCmd := command + `; printf '\n[Enter to close]'; read _`
+	cmd := exec.Command("tmux", "new-window", "-t", session+":", "-n", name, "-c", dir, shellCmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to open %s window: %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // GetSessionPath returns the working directory of a tmux session.
 func GetSessionPath(name string) string {
 	cmd := exec.Command("tmux", "display-message", "-t", name, "-p", "#{session_path}")
@@ -359,6 +569,17 @@ func GetSessionPath(name string) string {
 	return strings.TrimSpace(string(output))
 }
 
+// GetPaneCurrentPath returns the current directory of the pane at target
+// (for a session or window target, its active pane).
+func GetPaneCurrentPath(target string) string {
+	cmd := exec.Command("tmux", "display-message", "-t", target, "-p", "#{pane_current_path}")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 func isNoServerError(err error) bool {
 	if err == nil {
 		return false
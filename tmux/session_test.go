@@ -2,8 +2,13 @@ package tmux
 
 import (
 	"errors"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/porganisciak/agent-tmux/config"
 )
 
 func TestParseSessionLine(t *testing.T) {
@@ -17,6 +22,30 @@ func TestParseSessionLine(t *testing.T) {
 	}
 }
 
+func TestParseSessionLineWithTimestamps(t *testing.T) {
+	parsed := parseSessionLine("1767229200\t1767225600\tagent-foo: 2 windows (created Thu Jan  1 00:00:00 2026)")
+	if parsed.Name != "agent-foo" || parsed.Activity != 1767229200 || parsed.Created != 1767225600 || parsed.Windows != 2 {
+		t.Fatalf("unexpected parse %+v", parsed)
+	}
+
+	parsed = parseSessionLine("1767229200\t1767225600\t1767228000\tagent-foo: 2 windows")
+	if parsed.Name != "agent-foo" || parsed.Created != 1767225600 || parsed.LastAttached != 1767228000 {
+		t.Fatalf("unexpected parse %+v", parsed)
+	}
+
+	// Never-attached sessions have an empty last-attached field
+	parsed = parseSessionLine("1767229200\t1767225600\t\tagent-foo: 2 windows")
+	if parsed.Name != "agent-foo" || parsed.Created != 1767225600 || parsed.LastAttached != 0 {
+		t.Fatalf("unexpected parse %+v", parsed)
+	}
+
+	// Activity-only lines (older format) leave Created unset
+	parsed = parseSessionLine("1767229200\tagent-foo: 2 windows")
+	if parsed.Name != "agent-foo" || parsed.Activity != 1767229200 || parsed.Created != 0 {
+		t.Fatalf("unexpected parse %+v", parsed)
+	}
+}
+
 func TestListSessionsRawWithExecutorNoServerRunning(t *testing.T) {
 	executor := stubExecutor{
 		outputErr: &exec.ExitError{
@@ -85,3 +114,79 @@ func (s stubExecutor) IsRemote() bool {
 func (s stubExecutor) Close() error {
 	return nil
 }
+
+func TestCreateRefusesMissingDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "deleted-project")
+	err := NewSession(dir).Create(nil)
+	if err == nil || !strings.Contains(err.Error(), "no longer exists") {
+		t.Fatalf("expected missing directory error, got %v", err)
+	}
+}
+
+func TestDefaultAgentsFollowRegistryDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var got []string
+	for _, a := range DefaultAgents() {
+		got = append(got, a.Command)
+	}
+	want := []string{"claude --dangerously-skip-permissions", "codex --yolo"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFreeSessionNameSkipsOtherDirectories(t *testing.T) {
+	running := map[string]string{
+		"agent-api":   "/work/b/api",
+		"agent-api-2": "/work/c/api",
+		"agent-web":   "/work/a/web/",
+	}
+	pathOf := func(name string) (string, bool) {
+		path, ok := running[name]
+		return path, ok
+	}
+
+	if got := freeSessionName("agent-api", "/work/a/api", pathOf); got != "agent-api-3" {
+		t.Errorf("expected the first free suffix, got %q", got)
+	}
+	if got := freeSessionName("agent-api", "/work/c/api", pathOf); got != "agent-api-2" {
+		t.Errorf("expected the session already in the directory, got %q", got)
+	}
+	if got := freeSessionName("agent-web", "/work/a/web", pathOf); got != "agent-web" {
+		t.Errorf("expected a matching session to keep its name, got %q", got)
+	}
+	if got := freeSessionName("agent-cli", "/work/a/cli", pathOf); got != "agent-cli" {
+		t.Errorf("expected a free name to be kept, got %q", got)
+	}
+}
+
+func TestSessionNameForTemplates(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "work", "my.project")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		config.DefaultSessionNameTemplate: "agent-my_project",
+		"{parent}-{basename}":             "agent-work-my_project",
+		"{git}":                           "agent-my_project", // Not in a repository
+		"atmux-{basename}":                "atmux-my_project",
+		"{basename}:{parent}":             "agent-my_project_work",
+	}
+	for template, want := range cases {
+		if got := SessionNameFor(dir, template); got != want {
+			t.Errorf("SessionNameFor(%q) = %q, want %q", template, got, want)
+		}
+	}
+
+	hashed := SessionNameFor(dir, "{basename}-{hash}")
+	other := SessionNameFor(filepath.Join(filepath.Dir(dir), "..", "other", "my.project"), "{basename}-{hash}")
+	if !strings.HasPrefix(hashed, "agent-my_project-") || len(hashed) != len("agent-my_project-")+6 || hashed == other {
+		t.Errorf("expected a short hash that tells directories apart, got %q and %q", hashed, other)
+	}
+
+	if err := config.ValidateSessionNameTemplate("{basename}-{branch}"); err == nil {
+		t.Error("expected an unknown token to be rejected")
+	}
+}
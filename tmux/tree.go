@@ -1,10 +1,16 @@
 package tmux
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/porganisciak/agent-tmux/config"
 )
 
 // Pane represents a tmux pane
@@ -21,17 +27,20 @@ type Pane struct {
 
 // Window represents a tmux window
 type Window struct {
-	ID     string
-	Index  int
-	Name   string
-	Active bool
-	Panes  []Pane
+	ID           string
+	Index        int
+	Name         string
+	Active       bool
+	Synchronized bool // synchronize-panes is on
+	Zoomed       bool // The active pane is zoomed to fill the window
+	Panes        []Pane
 }
 
 // TmuxSession represents a tmux session (distinct from Session config type)
 type TmuxSession struct {
 	Name     string
 	Attached bool
+	Created  int64 // Unix timestamp of session creation (0 if unknown)
 	Windows  []Window
 }
 
@@ -42,15 +51,24 @@ type Tree struct {
 
 // TreeNode is used for the tree browser display
 type TreeNode struct {
-	Type     string // "session", "window", or "pane"
-	Name     string // Display name
-	Target   string // Tmux target (session:window.pane)
-	Expanded bool
-	Level    int
-	Active   bool
-	Attached bool // For sessions
-	Host     string // Remote host label (empty for local)
-	Children []*TreeNode
+	Type         string // "session", "window", or "pane"
+	Name         string // Display name
+	Target       string // Tmux target (session:window.pane)
+	ID           string // Stable tmux id, @N for windows or %N for panes
+	Expanded     bool
+	Level        int
+	Active       bool
+	Attached     bool   // For sessions
+	Created      int64  // Session creation time, Unix seconds (sessions only)
+	Host         string // Remote host label (empty for local)
+	Synchronized bool   // synchronize-panes is on (windows only)
+	Zoomed       bool   // Zoomed window, or the pane zoomed in it
+	Width        int    // Pane width in cells (panes only)
+	Height       int    // Pane height in cells (panes only)
+	Command      string // Foreground command, e.g. "zsh" (panes only)
+	Windows      int    // Number of windows (sessions only)
+	Panes        int    // Number of panes (sessions and windows)
+	Children     []*TreeNode
 }
 
 // FetchTree queries tmux and builds the complete tree
@@ -88,29 +106,77 @@ func FetchTree() (*Tree, error) {
 
 // HostTree holds the tree data for a single host (executor).
 type HostTree struct {
-	Host     string       // Host label ("" for local)
-	Tree     *Tree        // Tree data (nil if fetch failed)
-	Err      error        // Error from fetching (non-fatal for remotes)
-	Executor TmuxExecutor // The executor used to fetch this tree
+	Host     string        // Host label ("" for local)
+	Tree     *Tree         // Tree data (nil if fetch failed)
+	Err      error         // Error from fetching (non-fatal for remotes)
+	Executor TmuxExecutor  // The executor used to fetch this tree
+	CachedAt time.Time     // When a cached tree was fetched (zero for live data)
+	Latency  time.Duration // How long the fetch took
 }
 
-// FetchTreeWithExecutors queries multiple executors and returns per-host trees.
+// FetchTreeWithExecutors queries multiple executors at once and returns
+// per-host trees in executor order, so a slow host only delays its own
+// result. Remote hosts share the remote fetch limit; local never waits.
 // Remote failures are captured as HostTree.Err rather than aborting.
 func FetchTreeWithExecutors(executors []TmuxExecutor) []HostTree {
 	results := make([]HostTree, len(executors))
+	var wg sync.WaitGroup
 	for i, exec := range executors {
-		results[i] = HostTree{
-			Host:     exec.HostLabel(),
-			Executor: exec,
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = FetchHostTree(exec)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// FetchHostTree queries a single executor. A failure, including a remote
+// command hitting its timeout, is captured as HostTree.Err. Remote hosts
+// wait for a slot under the remote fetch limit first.
+func FetchHostTree(exec TmuxExecutor) HostTree {
+	result := HostTree{
+		Host:     exec.HostLabel(),
+		Executor: exec,
+	}
+	release := acquireFetchSlot(exec)
+	defer release()
+	start := time.Now()
+	tree, err := fetchTreeWithExecutor(exec)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Tree = tree
+	return result
+}
+
+// FetchErrorReason returns a short, human-readable reason for a failed fetch.
+// For SSH failures it prefers the last line ssh wrote to stderr, which names
+// the actual cause (e.g. "Connection refused") instead of "exit status 255".
+func FetchErrorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timed out"
+	}
+	if errors.Is(err, ErrTmuxNotFound) {
+		return "tmux not installed"
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		lines := strings.Split(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			return fmt.Sprintf("%s (exit %d)", last, exitErr.ExitCode())
 		}
-		tree, err := fetchTreeWithExecutor(exec)
-		if err != nil {
-			results[i].Err = err
-			continue
+		if exitErr.ExitCode() == -1 {
+			return "timed out"
 		}
-		results[i].Tree = tree
 	}
-	return results
+	return err.Error()
 }
 
 // fetchTreeWithExecutor fetches the full tree for a single executor.
@@ -123,15 +189,21 @@ func fetchTreeWithExecutor(exec TmuxExecutor) (*Tree, error) {
 		return nil, err
 	}
 
+	// A host that stops answering partway fails the whole fetch, rather than
+	// each remaining session and window waiting out its own timeout
 	for _, sess := range sessions {
 		windows, err := listWindowsWithExecutor(exec, sess.Name)
-		if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		} else if err != nil {
 			continue
 		}
 
 		for i := range windows {
 			panes, err := listPanesWithExecutor(exec, sess.Name, windows[i].Index)
-			if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			} else if err != nil {
 				continue
 			}
 			windows[i].Panes = panes
@@ -144,37 +216,50 @@ func fetchTreeWithExecutor(exec TmuxExecutor) (*Tree, error) {
 	return tree, nil
 }
 
-// listAllSessionsWithExecutor returns all tmux sessions via the given executor.
-func listAllSessionsWithExecutor(exec TmuxExecutor) ([]TmuxSession, error) {
-	output, err := exec.Output("list-sessions", "-F", "#{session_name}:#{session_attached}")
-	if err != nil {
-		if isNoServerError(err) {
-			return []TmuxSession{}, nil
-		}
-		return nil, err
-	}
+// sessionTreeFormat is the list-sessions format parsed by parseSessionList.
+const sessionTreeFormat = "#{session_name}:#{session_attached}:#{session_created}"
 
+// parseSessionList parses list-sessions output in sessionTreeFormat. The
+// creation time is optional so output without it still parses.
+func parseSessionList(output string) []TmuxSession {
 	var sessions []TmuxSession
-	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
 		if line == "" {
 			continue
 		}
-		parts := strings.SplitN(line, ":", 2)
+		parts := strings.SplitN(line, ":", 3)
 		if len(parts) < 2 {
 			continue
 		}
-		sessions = append(sessions, TmuxSession{
+		sess := TmuxSession{
 			Name:     parts[0],
 			Attached: parts[1] == "1",
-		})
+		}
+		if len(parts) == 3 {
+			sess.Created, _ = strconv.ParseInt(parts[2], 10, 64)
+		}
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
+
+// listAllSessionsWithExecutor returns all tmux sessions via the given executor.
+func listAllSessionsWithExecutor(exec TmuxExecutor) ([]TmuxSession, error) {
+	output, err := exec.Output("list-sessions", "-F", sessionTreeFormat)
+	if err != nil {
+		if isNoServerError(err) {
+			return []TmuxSession{}, nil
+		}
+		return nil, err
 	}
-	return sessions, nil
+
+	return parseSessionList(string(output)), nil
 }
 
 // listWindowsWithExecutor returns all windows for a session via the given executor.
 func listWindowsWithExecutor(exec TmuxExecutor, sessionName string) ([]Window, error) {
 	output, err := exec.Output("list-windows", "-t", sessionName,
-		"-F", "#{window_id}:#{window_index}:#{window_name}:#{window_active}")
+		"-F", "#{window_id}:#{window_index}:#{window_name}:#{window_active}:#{pane_synchronized}:#{window_zoomed_flag}")
 	if err != nil {
 		return nil, err
 	}
@@ -184,16 +269,18 @@ func listWindowsWithExecutor(exec TmuxExecutor, sessionName string) ([]Window, e
 		if line == "" {
 			continue
 		}
-		parts := strings.SplitN(line, ":", 4)
+		parts := strings.SplitN(line, ":", 6)
 		if len(parts) < 4 {
 			continue
 		}
 		idx, _ := strconv.Atoi(parts[1])
 		windows = append(windows, Window{
-			ID:     parts[0],
-			Index:  idx,
-			Name:   parts[2],
-			Active: parts[3] == "1",
+			ID:           parts[0],
+			Index:        idx,
+			Name:         parts[2],
+			Active:       parts[3] == "1",
+			Synchronized: len(parts) > 4 && parts[4] == "1",
+			Zoomed:       len(parts) > 5 && parts[5] == "1",
 		})
 	}
 	return windows, nil
@@ -245,11 +332,50 @@ func CapturePaneWithExecutor(target string, exec TmuxExecutor) (string, error) {
 	return string(output), nil
 }
 
+// CapturePaneTextWithExecutor captures the plain-text content of a pane
+// (without escape sequences) via the given executor.
+func CapturePaneTextWithExecutor(target string, exec TmuxExecutor) (string, error) {
+	output, err := exec.Output("capture-pane", "-t", target, "-p")
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// captureArgs returns the capture-pane arguments for target, reaching
+// scrollback lines above the visible screen and keeping colors if escapes.
+func captureArgs(target string, scrollback int, escapes bool) []string {
+	args := []string{"capture-pane", "-t", target, "-p"}
+	if escapes {
+		args = append(args, "-e")
+	}
+	if scrollback > 0 {
+		args = append(args, "-S", strconv.Itoa(-scrollback))
+	}
+	return args
+}
+
+// CaptureScrollbackWithExecutor captures a pane's visible content plus up
+// to scrollback lines of history via the given executor. With escapes the
+// output keeps its color escape sequences.
+func CaptureScrollbackWithExecutor(target string, scrollback int, escapes bool, exec TmuxExecutor) (string, error) {
+	output, err := exec.Output(captureArgs(target, scrollback, escapes)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
 // SendEscapeWithExecutor sends an Escape key to a pane via the given executor.
 func SendEscapeWithExecutor(target string, exec TmuxExecutor) error {
 	return exec.Run("send-keys", "-t", target, "Escape")
 }
 
+// SendInterruptWithExecutor sends C-c to a pane via the given executor.
+func SendInterruptWithExecutor(target string, exec TmuxExecutor) error {
+	return exec.Run("send-keys", "-t", target, "C-c")
+}
+
 // KillTargetWithExecutor kills a session, window, or pane via the given executor.
 func KillTargetWithExecutor(nodeType, target string, exec TmuxExecutor) error {
 	switch nodeType {
@@ -266,34 +392,23 @@ func KillTargetWithExecutor(nodeType, target string, exec TmuxExecutor) error {
 
 // listAllSessions returns all tmux sessions (not just agent-* ones)
 func listAllSessions() ([]TmuxSession, error) {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}:#{session_attached}")
+	cmd := exec.Command("tmux", "list-sessions", "-F", sessionTreeFormat)
 	output, err := cmd.Output()
 	if err != nil {
+		if err := localTmuxError(err); errors.Is(err, ErrTmuxNotFound) {
+			return nil, err
+		}
 		// No server running or no sessions
 		return []TmuxSession{}, nil
 	}
 
-	var sessions []TmuxSession
-	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) < 2 {
-			continue
-		}
-		sessions = append(sessions, TmuxSession{
-			Name:     parts[0],
-			Attached: parts[1] == "1",
-		})
-	}
-	return sessions, nil
+	return parseSessionList(string(output)), nil
 }
 
 // listWindows returns all windows for a session
 func listWindows(sessionName string) ([]Window, error) {
 	cmd := exec.Command("tmux", "list-windows", "-t", sessionName,
-		"-F", "#{window_id}:#{window_index}:#{window_name}:#{window_active}")
+		"-F", "#{window_id}:#{window_index}:#{window_name}:#{window_active}:#{pane_synchronized}:#{window_zoomed_flag}")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -304,16 +419,18 @@ func listWindows(sessionName string) ([]Window, error) {
 		if line == "" {
 			continue
 		}
-		parts := strings.SplitN(line, ":", 4)
+		parts := strings.SplitN(line, ":", 6)
 		if len(parts) < 4 {
 			continue
 		}
 		idx, _ := strconv.Atoi(parts[1])
 		windows = append(windows, Window{
-			ID:     parts[0],
-			Index:  idx,
-			Name:   parts[2],
-			Active: parts[3] == "1",
+			ID:           parts[0],
+			Index:        idx,
+			Name:         parts[2],
+			Active:       parts[3] == "1",
+			Synchronized: len(parts) > 4 && parts[4] == "1",
+			Zoomed:       len(parts) > 5 && parts[5] == "1",
 		})
 	}
 	return windows, nil
@@ -369,6 +486,7 @@ func (t *Tree) BuildTreeNodes() []*TreeNode {
 			Expanded: true,
 			Level:    0,
 			Attached: sess.Attached,
+			Created:  sess.Created,
 		}
 		nodes = append(nodes, sessNode)
 
@@ -388,11 +506,12 @@ func (t *Tree) BuildTreeNodes() []*TreeNode {
 				if winNode.Expanded {
 					for _, pane := range win.Panes {
 						paneNode := &TreeNode{
-							Type:   "pane",
-							Name:   pane.Title,
-							Target: pane.Target,
-							Level:  2,
-							Active: pane.Active,
+							Type:    "pane",
+							Name:    pane.Title,
+							Target:  pane.Target,
+							Level:   2,
+							Active:  pane.Active,
+							Command: pane.Command,
 						}
 						if pane.Title == "" {
 							paneNode.Name = pane.Command
@@ -432,6 +551,7 @@ const (
 	SendMethodEnterLiteral                       // text, then literal Enter key
 	SendMethodEnterDelayed                       // text, sleep 500ms, then Enter
 	SendMethodEnterDelayedLong                   // text, sleep 1500ms, then Enter (like tmux-cli)
+	SendMethodBracketedPaste                     // text pasted through a paste buffer, then Enter
 	SendMethodCount                              // number of methods (for cycling)
 )
 
@@ -452,6 +572,8 @@ func (m SendMethod) String() string {
 		return "Enter (500ms delay)"
 	case SendMethodEnterDelayedLong:
 		return "Enter (1500ms delay)"
+	case SendMethodBracketedPaste:
+		return "Bracketed paste"
 	default:
 		return "unknown"
 	}
@@ -474,6 +596,8 @@ func (m SendMethod) Description() string {
 		return "send-keys 'text'; sleep 500ms; send-keys Enter"
 	case SendMethodEnterDelayedLong:
 		return "send-keys 'text'; sleep 1500ms; send-keys Enter"
+	case SendMethodBracketedPaste:
+		return "set-buffer 'text'; paste-buffer -p; send-keys Enter"
 	default:
 		return ""
 	}
@@ -489,6 +613,11 @@ func SendEscape(target string) error {
 	return exec.Command("tmux", "send-keys", "-t", target, "Escape").Run()
 }
 
+// SendInterrupt sends C-c to a pane, interrupting its foreground process.
+func SendInterrupt(target string) error {
+	return exec.Command("tmux", "send-keys", "-t", target, "C-c").Run()
+}
+
 // KillTarget kills a session, window, or pane by target.
 // For sessions: target is the session name
 // For windows: target is session:window_index
@@ -512,8 +641,53 @@ func SwitchToTarget(target string) error {
 	return exec.Command("tmux", "switch-client", "-t", target).Run()
 }
 
-// SendCommandWithMethodAndExecutor sends a command using the specified method and executor.
+// selectTargetCommands returns the tmux commands that make target's window,
+// and pane for a pane target, the current one in its session. A session
+// target needs none.
+func selectTargetCommands(target string) [][]string {
+	idx := strings.Index(target, ":")
+	if idx < 0 {
+		return nil
+	}
+	commands := [][]string{{"select-window", "-t", target}}
+	if strings.Contains(target[idx:], ".") {
+		commands = append(commands, []string{"select-pane", "-t", target})
+	}
+	return commands
+}
+
+// SelectTarget makes the window or pane in target current in its session,
+// so attaching to the session lands on it.
+func SelectTarget(target string) error {
+	for _, args := range selectTargetCommands(target) {
+		if err := exec.Command("tmux", args...).Run(); err != nil {
+			return fmt.Errorf("failed to select %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// WrapCommand returns command as the send functions type it into a pane:
+// joined to the command_prefix and command_suffix from settings, if set.
+func WrapCommand(command string) string {
+	settings, _ := config.LoadSettings()
+	return settings.WrapCommand(command)
+}
+
+// SendCommandWithMethodAndExecutor sends a command using the specified method
+// and executor, wrapped first (see WrapCommand).
 func SendCommandWithMethodAndExecutor(target, command string, method SendMethod, exec TmuxExecutor) error {
+	return sendWithMethod(target, WrapCommand(command), method, exec)
+}
+
+// SendAgentCommandWithExecutor sends one of atmux's own agent commands, like
+// /compact, through exec with the delayed-Enter method. It is never wrapped.
+func SendAgentCommandWithExecutor(target, command string, exec TmuxExecutor) error {
+	return sendWithMethod(target, command, SendMethodEnterDelayed, exec)
+}
+
+// sendWithMethod types text into target through exec as method says.
+func sendWithMethod(target, command string, method SendMethod, exec TmuxExecutor) error {
 	switch method {
 	case SendMethodEnterSeparate:
 		if err := exec.Run("send-keys", "-t", target, command); err != nil {
@@ -546,13 +720,25 @@ func SendCommandWithMethodAndExecutor(target, command string, method SendMethod,
 		}
 		time.Sleep(1500 * time.Millisecond)
 		return exec.Run("send-keys", "-t", target, "Enter")
+	case SendMethodBracketedPaste:
+		if err := PasteToTargetWithExecutor(target, command, exec); err != nil {
+			return err
+		}
+		return exec.Run("send-keys", "-t", target, "Enter")
 	default:
-		return SendCommandWithMethodAndExecutor(target, command, SendMethodEnterSeparate, exec)
+		return sendWithMethod(target, command, SendMethodEnterSeparate, exec)
 	}
 }
 
-// SendCommandWithMethod sends a command using the specified method
+// SendCommandWithMethod sends a command using the specified method, wrapped
+// first (see WrapCommand)
 func SendCommandWithMethod(target, command string, method SendMethod) error {
+	return sendLocallyWithMethod(target, WrapCommand(command), method)
+}
+
+// sendLocallyWithMethod types text into target through the local tmux as
+// method says.
+func sendLocallyWithMethod(target, command string, method SendMethod) error {
 	switch method {
 	case SendMethodEnterSeparate:
 		// Send text, then Enter separately
@@ -599,27 +785,134 @@ func SendCommandWithMethod(target, command string, method SendMethod) error {
 		time.Sleep(1500 * time.Millisecond)
 		return exec.Command("tmux", "send-keys", "-t", target, "Enter").Run()
 
+	case SendMethodBracketedPaste:
+		// Paste text in one piece (bracketed when the program asks for it),
+		// then Enter to submit it
+		if err := PasteToTarget(target, command); err != nil {
+			return err
+		}
+		return exec.Command("tmux", "send-keys", "-t", target, "Enter").Run()
+
 	default:
-		return SendCommandWithMethod(target, command, SendMethodEnterSeparate)
+		return sendLocallyWithMethod(target, command, SendMethodEnterSeparate)
 	}
 }
 
 // CreateNewWindow creates a new window in the specified session
 func CreateNewWindow(sessionTarget string) error {
-	return exec.Command("tmux", "new-window", "-t", sessionTarget).Run()
+	return CreateNewWindowInDir(sessionTarget, "")
+}
+
+// CreateNewWindowInDir creates a new window in the specified session, starting
+// in dir. An empty dir uses tmux's default (the session directory).
+func CreateNewWindowInDir(sessionTarget, dir string) error {
+	args := []string{"new-window", "-t", sessionTarget}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	return exec.Command("tmux", args...).Run()
 }
 
 // CreateNewPane creates a new pane in the specified window/pane target
 // If vertical is true, splits vertically (-v), otherwise horizontally (-h)
 func CreateNewPane(target string, vertical bool) error {
+	return CreateNewPaneInDir(target, vertical, "")
+}
+
+// CreateNewPaneInDir is CreateNewPane starting the new pane in dir. An empty
+// dir uses tmux's default.
+func CreateNewPaneInDir(target string, vertical bool, dir string) error {
 	splitFlag := "-h"
 	if vertical {
 		splitFlag = "-v"
 	}
-	return exec.Command("tmux", "split-window", splitFlag, "-t", target).Run()
+	args := []string{"split-window", splitFlag, "-t", target}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	return exec.Command("tmux", args...).Run()
 }
 
-// ToggleZoom toggles the zoom state of the specified pane
+// ToggleZoom toggles the zoom state of the specified pane. A window target
+// toggles its active pane.
 func ToggleZoom(target string) error {
-	return exec.Command("tmux", "resize-pane", "-t", target, "-Z").Run()
+	return ToggleZoomWithExecutor(target, NewLocalExecutor())
+}
+
+// ToggleZoomWithExecutor toggles a pane's zoom via the given executor.
+func ToggleZoomWithExecutor(target string, exec TmuxExecutor) error {
+	return exec.Run("resize-pane", "-t", target, "-Z")
+}
+
+// MoveWindow moves a window to another session, at the first free index
+// there.
+func MoveWindow(src, destSession string) error {
+	return MoveWindowWithExecutor(src, destSession, NewLocalExecutor())
+}
+
+// MoveWindowWithExecutor moves a window via the given executor.
+func MoveWindowWithExecutor(src, destSession string, exec TmuxExecutor) error {
+	// The trailing colon targets the session, not a window named like it
+	if err := exec.Run("move-window", "-s", src, "-t", destSession+":"); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", src, destSession, err)
+	}
+	return nil
+}
+
+// BreakPane moves a pane out into a new window of its own, leaving the
+// current window selected.
+func BreakPane(target string) error {
+	return BreakPaneWithExecutor(target, NewLocalExecutor())
+}
+
+// BreakPaneWithExecutor breaks a pane into its own window via the given executor.
+func BreakPaneWithExecutor(target string, exec TmuxExecutor) error {
+	if err := exec.Run("break-pane", "-d", "-s", target); err != nil {
+		return fmt.Errorf("failed to break out %s: %w", target, err)
+	}
+	return nil
+}
+
+// SetPaneMarked sets or clears the tmux marked pane. The server has one
+// marked pane, so marking a pane unmarks any other.
+func SetPaneMarked(target string, marked bool) error {
+	return SetPaneMarkedWithExecutor(target, marked, NewLocalExecutor())
+}
+
+// SetPaneMarkedWithExecutor sets or clears the marked pane via the given executor.
+func SetPaneMarkedWithExecutor(target string, marked bool, exec TmuxExecutor) error {
+	flag := "-m"
+	if !marked {
+		flag = "-M"
+	}
+	if err := exec.Run("select-pane", flag, "-t", target); err != nil {
+		return fmt.Errorf("failed to mark %s: %w", target, err)
+	}
+	return nil
+}
+
+// SwapPanes swaps the positions of two panes, which may be in different
+// windows or sessions, without changing the active pane.
+func SwapPanes(a, b string) error {
+	return SwapPanesWithExecutor(a, b, NewLocalExecutor())
+}
+
+// SwapPanesWithExecutor swaps two panes via the given executor.
+func SwapPanesWithExecutor(a, b string, exec TmuxExecutor) error {
+	if err := exec.Run("swap-pane", "-d", "-s", a, "-t", b); err != nil {
+		return fmt.Errorf("failed to swap %s with %s: %w", a, b, err)
+	}
+	return nil
+}
+
+// ToggleSynchronizePanes toggles synchronize-panes on the specified window,
+// so input typed in one pane goes to every pane in the window.
+func ToggleSynchronizePanes(windowTarget string) error {
+	return ToggleSynchronizePanesWithExecutor(windowTarget, NewLocalExecutor())
+}
+
+// ToggleSynchronizePanesWithExecutor toggles synchronize-panes via the given executor.
+func ToggleSynchronizePanesWithExecutor(windowTarget string, exec TmuxExecutor) error {
+	// Omitting the value toggles a flag option
+	return exec.Run("set-window-option", "-t", windowTarget, "synchronize-panes")
 }
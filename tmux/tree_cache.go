@@ -0,0 +1,117 @@
+package tmux
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedHostTree is the on-disk form of a host's last successful tree fetch.
+type cachedHostTree struct {
+	Tree      *Tree     `json:"tree"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// TreeCachePath returns the path of the on-disk host tree cache.
+func TreeCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "atmux", "tree-cache.json"), nil
+}
+
+// loadTreeCache reads the tree cache, keyed by host label ("" for local).
+// A missing or unreadable cache yields an empty map.
+func loadTreeCache() map[string]cachedHostTree {
+	cache := map[string]cachedHostTree{}
+	path, err := TreeCachePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]cachedHostTree{}
+	}
+	return cache
+}
+
+// LoadCachedHostTrees returns the cached tree for each executor that has one,
+// in executor order. Returned HostTrees have CachedAt set to the time the
+// tree was originally fetched.
+func LoadCachedHostTrees(executors []TmuxExecutor) []HostTree {
+	cache := loadTreeCache()
+	var results []HostTree
+	for _, exec := range executors {
+		entry, ok := cache[exec.HostLabel()]
+		if !ok || entry.Tree == nil {
+			continue
+		}
+		results = append(results, HostTree{
+			Host:     exec.HostLabel(),
+			Tree:     entry.Tree,
+			Executor: exec,
+			CachedAt: entry.FetchedAt,
+		})
+	}
+	return results
+}
+
+// SaveTreeCache records the successfully fetched trees in hostTrees.
+// Entries for hosts that failed this time are left untouched.
+func SaveTreeCache(hostTrees []HostTree) error {
+	path, err := TreeCachePath()
+	if err != nil {
+		return err
+	}
+
+	cache := loadTreeCache()
+	now := time.Now()
+	for _, ht := range hostTrees {
+		if ht.Err != nil || ht.Tree == nil || !ht.CachedAt.IsZero() {
+			continue
+		}
+		cache[ht.Host] = cachedHostTree{Tree: ht.Tree, FetchedAt: now}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames
+// it into place, so a concurrent reader or writer never sees a partial file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, perm); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
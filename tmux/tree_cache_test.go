@@ -0,0 +1,56 @@
+package tmux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTreeCacheRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	local := &fakeExecutor{host: ""}
+	remote := &fakeExecutor{host: "devbox", remote: true}
+	down := &fakeExecutor{host: "down", remote: true}
+
+	err := SaveTreeCache([]HostTree{
+		{Host: "", Tree: &Tree{Sessions: []TmuxSession{{Name: "local-sess"}}}},
+		{Host: "devbox", Tree: &Tree{Sessions: []TmuxSession{{Name: "remote-sess"}}}},
+		{Host: "down", Err: errors.New("unreachable")},
+	})
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	cached := LoadCachedHostTrees([]TmuxExecutor{local, remote, down})
+	if len(cached) != 2 {
+		t.Fatalf("expected 2 cached host trees, got %d", len(cached))
+	}
+	if cached[1].Host != "devbox" || cached[1].Tree.Sessions[0].Name != "remote-sess" {
+		t.Fatalf("unexpected cached devbox tree: %+v", cached[1])
+	}
+	if cached[1].CachedAt.IsZero() {
+		t.Fatal("expected CachedAt to be set on cached trees")
+	}
+	if cached[1].Executor != remote {
+		t.Fatal("expected cached tree to carry its executor")
+	}
+
+	// A later failed fetch keeps the previous entry
+	if err := SaveTreeCache([]HostTree{{Host: "devbox", Err: errors.New("timeout")}}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	cached = LoadCachedHostTrees([]TmuxExecutor{remote})
+	if len(cached) != 1 {
+		t.Fatalf("expected devbox cache to survive failed fetch, got %d entries", len(cached))
+	}
+
+	// Writes go through a temporary file that is renamed into place
+	path, _ := TreeCachePath()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 || entries[0].Name() != "tree-cache.json" {
+		t.Fatalf("expected only the cache file to be left, got %v (%v)", entries, err)
+	}
+}
@@ -2,6 +2,7 @@ package tmux
 
 import (
 	"errors"
+	"os/exec"
 	"strings"
 	"testing"
 )
@@ -244,3 +245,104 @@ func TestCapturePaneWithExecutor_Error(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestFetchTreeWithExecutors_RecordsLatency(t *testing.T) {
+	local := &fakeExecutor{
+		responses: map[string]fakeResponse{
+			"list-sessions": {output: []byte("s:0\n")},
+		},
+	}
+	results := FetchTreeWithExecutors([]TmuxExecutor{local})
+	if results[0].Latency <= 0 {
+		t.Fatalf("expected positive latency, got %v", results[0].Latency)
+	}
+}
+
+func TestFetchTreeWithExecutors_ParsesSynchronizedWindows(t *testing.T) {
+	local := &fakeExecutor{
+		responses: map[string]fakeResponse{
+			"list-sessions": {output: []byte("s:0\n")},
+			"list-windows":  {output: []byte("@1:0:agents:1:1:0\n@2:1:logs:0:0:1\n")},
+		},
+	}
+	results := FetchTreeWithExecutors([]TmuxExecutor{local})
+	windows := results[0].Tree.Sessions[0].Windows
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 windows, got %d", len(windows))
+	}
+	if !windows[0].Synchronized || windows[1].Synchronized {
+		t.Fatalf("expected only the first window synchronized, got %+v", windows)
+	}
+	if windows[0].Zoomed || !windows[1].Zoomed {
+		t.Fatalf("expected only the second window zoomed, got %+v", windows)
+	}
+	if windows[0].Name != "agents" || !windows[0].Active {
+		t.Fatalf("unexpected window %+v", windows[0])
+	}
+}
+
+func TestFetchTreeWithExecutors_ParsesSessionCreated(t *testing.T) {
+	local := &fakeExecutor{
+		responses: map[string]fakeResponse{
+			"list-sessions": {output: []byte("work:1:1767225600\nold:0\n")},
+			"list-windows":  {output: []byte("@1:0:agents:1:0\n")},
+		},
+	}
+	sessions := FetchTreeWithExecutors([]TmuxExecutor{local})[0].Tree.Sessions
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].Created != 1767225600 || !sessions[0].Attached {
+		t.Fatalf("unexpected session %+v", sessions[0])
+	}
+	if sessions[1].Created != 0 || sessions[1].Attached {
+		t.Fatalf("expected output without a creation time to still parse, got %+v", sessions[1])
+	}
+}
+
+func TestFetchErrorReason(t *testing.T) {
+	_, err := exec.Command("sh", "-c", "echo 'ssh: connect to host devbox port 22: Connection refused' >&2; exit 255").Output()
+	if got := FetchErrorReason(err); got != "ssh: connect to host devbox port 22: Connection refused (exit 255)" {
+		t.Fatalf("unexpected reason %q", got)
+	}
+	if got := FetchErrorReason(errors.New("boom")); got != "boom" {
+		t.Fatalf("expected plain error text, got %q", got)
+	}
+	if got := FetchErrorReason(nil); got != "" {
+		t.Fatalf("expected empty reason for nil, got %q", got)
+	}
+}
+
+func TestCaptureArgs(t *testing.T) {
+	tests := []struct {
+		scrollback int
+		escapes    bool
+		want       string
+	}{
+		{0, false, "capture-pane -t work:0.1 -p"},
+		{0, true, "capture-pane -t work:0.1 -p -e"},
+		{500, false, "capture-pane -t work:0.1 -p -S -500"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(captureArgs("work:0.1", tt.scrollback, tt.escapes), " "); got != tt.want {
+			t.Errorf("captureArgs(%d, %v) = %q, want %q", tt.scrollback, tt.escapes, got, tt.want)
+		}
+	}
+}
+
+func TestSelectTargetCommands(t *testing.T) {
+	tests := map[string]string{
+		"work":     "",
+		"work:1":   "select-window -t work:1",
+		"work:1.2": "select-window -t work:1.2; select-pane -t work:1.2",
+	}
+	for target, want := range tests {
+		var got []string
+		for _, args := range selectTargetCommands(target) {
+			got = append(got, strings.Join(args, " "))
+		}
+		if strings.Join(got, "; ") != want {
+			t.Errorf("selectTargetCommands(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
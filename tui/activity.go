@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"strings"
+	"time"
+)
+
+// activityWindow is how far back the preview activity sparkline reaches.
+const activityWindow = time.Minute
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// paneActivity samples how much a previewed pane's output changes between
+// successive captures, keeping the most recent samples in a ring buffer.
+type paneActivity struct {
+	key     string   // host + target the samples belong to
+	last    []string // lines of the previous capture
+	primed  bool     // whether last holds a capture to diff against
+	samples []int
+	next    int // ring index the next sample is written to
+	count   int // samples recorded, capped at len(samples)
+}
+
+// newPaneActivity returns a sampler holding enough samples to cover
+// activityWindow at the given refresh interval.
+func newPaneActivity(key string, interval time.Duration) *paneActivity {
+	size := 30
+	if interval > 0 {
+		size = int(activityWindow / interval)
+	}
+	size = min(max(size, 10), 60)
+	return &paneActivity{key: key, samples: make([]int, size)}
+}
+
+// record diffs a capture against the previous one and stores the result.
+// The first capture only sets the baseline.
+func (a *paneActivity) record(content string) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if a.primed {
+		a.samples[a.next] = changedLines(a.last, lines)
+		a.next = (a.next + 1) % len(a.samples)
+		if a.count < len(a.samples) {
+			a.count++
+		}
+	}
+	a.last = lines
+	a.primed = true
+}
+
+// values returns the recorded samples, oldest first.
+func (a *paneActivity) values() []int {
+	out := make([]int, 0, a.count)
+	start := (a.next - a.count + len(a.samples)) % len(a.samples)
+	for i := 0; i < a.count; i++ {
+		out = append(out, a.samples[(start+i)%len(a.samples)])
+	}
+	return out
+}
+
+// changedLines estimates how many lines of output appeared between two
+// captures of the same pane. If the old screen reappears shifted up, the
+// lines below it are new; otherwise lines that differ position by position
+// are counted.
+func changedLines(prev, next []string) int {
+	for shift := 0; shift < len(prev); shift++ {
+		overlap := len(prev) - shift
+		if overlap > len(next) || overlap < len(prev)/2 {
+			continue
+		}
+		if equalLines(prev[shift:], next[:overlap]) {
+			return len(next) - overlap
+		}
+	}
+
+	changed := 0
+	for i := 0; i < max(len(prev), len(next)); i++ {
+		if i >= len(prev) || i >= len(next) || prev[i] != next[i] {
+			changed++
+		}
+	}
+	return changed
+}
+
+func equalLines(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sparkline renders values as unicode blocks scaled to the largest value.
+// Zero renders as the lowest block so idle stretches stay visible.
+func sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 && v > 0 {
+			level = 1 + v*(len(sparkBlocks)-2)/peak
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// recordPreviewActivity samples a fresh preview capture, starting a new
+// sampler when the previewed pane changed.
+func (m *Model) recordPreviewActivity(host, target, content string) {
+	key := host + "\x00" + target
+	if m.activity == nil || m.activity.key != key {
+		m.activity = newPaneActivity(key, m.options.RefreshInterval)
+	}
+	m.activity.record(content)
+}
+
+// activitySparkline renders the previewed pane's recent activity in at most
+// width cells, or "" until there are at least two samples to compare.
+func (m Model) activitySparkline(width int) string {
+	if m.activity == nil || m.activity.count < 2 || width < 2 {
+		return ""
+	}
+	values := m.activity.values()
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	return sparkline(values)
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChangedLinesDetectsScroll(t *testing.T) {
+	prev := []string{"a", "b", "c", "d"}
+	tests := []struct {
+		name string
+		next []string
+		want int
+	}{
+		{"unchanged", []string{"a", "b", "c", "d"}, 0},
+		{"scrolled two lines", []string{"c", "d", "e", "f"}, 2},
+		{"grew without scrolling", []string{"a", "b", "c", "d", "e"}, 1},
+		{"changed in place", []string{"a", "b", "x", "d"}, 1},
+		{"cleared", []string{"p", "q", "r", "s"}, 4},
+	}
+	for _, tt := range tests {
+		if got := changedLines(prev, tt.next); got != tt.want {
+			t.Errorf("%s: changedLines = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPaneActivityRingBufferKeepsNewestSamples(t *testing.T) {
+	a := newPaneActivity("work:0.0", 6*time.Second) // 10 samples
+	content := ""
+	for i := 0; i < 13; i++ {
+		content += "line\n"
+		a.record(content)
+	}
+	values := a.values()
+	if len(values) != 10 {
+		t.Fatalf("expected 10 samples, got %v", values)
+	}
+	for _, v := range values {
+		if v != 1 {
+			t.Fatalf("expected one new line per sample, got %v", values)
+		}
+	}
+}
+
+func TestRecordPreviewActivityResetsOnTargetChange(t *testing.T) {
+	m := NewModel(Options{RefreshInterval: 2 * time.Second})
+	m.recordPreviewActivity("", "work:0.0", "a\n")
+	m.recordPreviewActivity("", "work:0.0", "a\nb\n")
+	m.recordPreviewActivity("", "work:0.0", "a\nb\nc\n")
+	if m.activitySparkline(80) == "" {
+		t.Fatal("expected a sparkline after three captures")
+	}
+
+	m.recordPreviewActivity("devbox", "work:0.0", "a\n")
+	if m.activity.count != 0 || m.activitySparkline(80) != "" {
+		t.Fatal("expected samples to reset for a different pane")
+	}
+}
+
+func TestSparklineScalesToPeak(t *testing.T) {
+	got := []rune(sparkline([]int{0, 1, 4, 8}))
+	want := []rune("▁▂▅█")
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sparkline = %q, want %q", string(got), string(want))
+	}
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// isAgentPane reports whether node is a pane running one of the configured
+// agents, matched on its current command like scheduled all-agent jobs.
+func (m *Model) isAgentPane(node *tmux.TreeNode) bool {
+	return node.Type == "pane" && node.Target != "" && m.agentPrograms[strings.ToLower(node.Command)]
+}
+
+// toggleAgentsView switches between the whole tree and just the agent panes
+// with their hosts, sessions, and windows. Turning it on selects the first
+// agent unless the selection already is one; turning it off keeps the
+// selected node selected.
+func (m *Model) toggleAgentsView() {
+	prev := m.selectedNode()
+	m.agentsOnly = !m.agentsOnly
+	m.rebuildFlatNodes()
+
+	index := -1
+	if prev != nil {
+		index = m.closestNodeIndex(prev)
+	}
+	if m.agentsOnly && (prev == nil || !m.isAgentPane(prev)) {
+		for i, node := range m.flatNodes {
+			if m.isAgentPane(node) {
+				index = i
+				break
+			}
+		}
+	}
+	if index == -1 {
+		index = m.selectedIndex
+	}
+	m.jumpSelection(index)
+	m.calculateButtonZones()
+}
+
+// agentsViewStatus renders the agents view marker and how many agents it
+// found for the status bar, or "" when the view is off.
+func (m Model) agentsViewStatus() string {
+	if !m.agentsOnly {
+		return ""
+	}
+	noun := "agents"
+	if m.agentCount == 1 {
+		noun = "agent"
+	}
+	return lipgloss.NewStyle().Foreground(activeColor).Bold(true).Render("Agents") +
+		lipgloss.NewStyle().Foreground(dimColor).Render(fmt.Sprintf(" %d %s", m.agentCount, noun))
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// attachCommandCopiedMsg is sent after an attach command was copied.
+type attachCommandCopiedMsg struct {
+	command string
+	method  string
+	err     error
+}
+
+// attachCommand returns the command line that attaches to line's session
+// from a fresh terminal: the ssh or mosh invocation for remote sessions,
+// plain tmux for local ones.
+func attachCommand(line tmux.SessionLine, exec tmux.TmuxExecutor) string {
+	args := []string{"attach-session", "-t", line.Name}
+	if remote, ok := exec.(*tmux.RemoteExecutor); ok && line.Host != "" {
+		return remote.InteractiveCommand(args...)
+	}
+	return tmux.ShellJoin(append([]string{"tmux"}, args...))
+}
+
+// copyAttachCommand copies the attach command for the selected active
+// session to the clipboard.
+func (m sessionsModel) copyAttachCommand() tea.Cmd {
+	if m.selectedIndex >= len(m.lines) {
+		return nil
+	}
+	line := m.lines[m.selectedIndex]
+	command := attachCommand(line, m.executorMap[line.Host])
+	return func() tea.Msg {
+		method, err := tmux.CopyToClipboard(command)
+		if err != nil {
+			err = fmt.Errorf("failed to copy attach command: %w", err)
+		}
+		return attachCommandCopiedMsg{command: command, method: method, err: err}
+	}
+}
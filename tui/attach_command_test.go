@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestAttachCommandUsesRemoteExecutor(t *testing.T) {
+	remote := tmux.NewRemoteExecutor("user@devbox", 2222, "ssh", "devbox")
+	got := attachCommand(tmux.SessionLine{Name: "work", Host: "devbox"}, remote)
+	want := "ssh -t -p 2222 user@devbox tmux attach-session -t work"
+	if got != want {
+		t.Fatalf("attachCommand = %q, want %q", got, want)
+	}
+}
+
+func TestAttachCommandLocalSession(t *testing.T) {
+	got := attachCommand(tmux.SessionLine{Name: "my work"}, tmux.NewLocalExecutor())
+	want := "tmux attach-session -t 'my work'"
+	if got != want {
+		t.Fatalf("attachCommand = %q, want %q", got, want)
+	}
+}
+
+func TestSessionsNoticeClearsOnNextKey(t *testing.T) {
+	m := newSessionsModel([]tmux.TmuxExecutor{tmux.NewLocalExecutor()}, false, true)
+	updated, _ := m.Update(attachCommandCopiedMsg{command: "tmux attach-session -t work", method: "pbcopy"})
+	m = updated.(sessionsModel)
+	if m.notice == "" {
+		t.Fatal("expected a copy notice")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if updated.(sessionsModel).notice != "" {
+		t.Fatal("expected the notice to clear on the next key")
+	}
+}
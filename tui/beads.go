@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// beadsTally is a session's beads issue counts. Without a breakdown (bd
+// too old for `count --by-status`) only open is known.
+type beadsTally struct {
+	open       int
+	inProgress int
+	blocked    int
+	breakdown  bool
+}
+
+// parseBeadsByStatus parses `bd count --by-status --json` output, reporting
+// false when it isn't the grouped form so the caller can fall back to the
+// plain open count.
+func parseBeadsByStatus(output []byte) (beadsTally, bool) {
+	var result struct {
+		Groups []struct {
+			Group string `json:"group"`
+			Count int    `json:"count"`
+		} `json:"groups"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil || result.Groups == nil {
+		return beadsTally{}, false
+	}
+	tally := beadsTally{breakdown: true}
+	for _, g := range result.Groups {
+		switch g.Group {
+		case "open":
+			tally.open = g.Count
+		case "in_progress":
+			tally.inProgress = g.Count
+		case "blocked":
+			tally.blocked = g.Count
+		}
+	}
+	return tally, true
+}
+
+// label renders the tally for the sessions list: "bd:3" for a plain open
+// count, or "bd:3◯1▶1✕" (open, in progress, blocked) with a breakdown,
+// leaving out statuses with nothing in them. Blocked issues stand out in
+// the error color.
+func (t beadsTally) label() string {
+	countStyle := beadsCountStyle
+	if t.open+t.inProgress+t.blocked == 0 {
+		countStyle = lipgloss.NewStyle().Foreground(dimColor)
+	}
+	if !t.breakdown || t.open+t.inProgress+t.blocked == 0 {
+		return countStyle.Render(fmt.Sprintf("bd:%d", t.open))
+	}
+
+	label := countStyle.Render("bd:")
+	if t.open > 0 {
+		label += countStyle.Render(fmt.Sprintf("%d◯", t.open))
+	}
+	if t.inProgress > 0 {
+		label += countStyle.Render(fmt.Sprintf("%d▶", t.inProgress))
+	}
+	if t.blocked > 0 {
+		label += lipgloss.NewStyle().Foreground(errorColor).Render(fmt.Sprintf("%d✕", t.blocked))
+	}
+	return label
+}
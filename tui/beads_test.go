@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseBeadsByStatus(t *testing.T) {
+	output := []byte(`{"total":9,"groups":[{"group":"open","count":3},{"group":"in_progress","count":1},{"group":"blocked","count":1},{"group":"closed","count":4}]}`)
+	tally, ok := parseBeadsByStatus(output)
+	if !ok || tally != (beadsTally{open: 3, inProgress: 1, blocked: 1, breakdown: true}) {
+		t.Fatalf("unexpected tally %+v (ok=%v)", tally, ok)
+	}
+
+	// Older bd versions ignore --by-status and print a plain count
+	if _, ok := parseBeadsByStatus([]byte(`{"count":3}`)); ok {
+		t.Fatal("expected a plain count to fall back")
+	}
+	if _, ok := parseBeadsByStatus([]byte("unknown flag: --by-status")); ok {
+		t.Fatal("expected non-JSON output to fall back")
+	}
+}
+
+func TestBeadsTallyLabel(t *testing.T) {
+	tests := []struct {
+		tally beadsTally
+		want  string
+	}{
+		{beadsTally{open: 3}, "bd:3"},
+		{beadsTally{open: 3, inProgress: 1, blocked: 1, breakdown: true}, "bd:3◯1▶1✕"},
+		{beadsTally{blocked: 5, breakdown: true}, "bd:5✕"},
+		{beadsTally{breakdown: true}, "bd:0"},
+	}
+	for _, tt := range tests {
+		if got := ansi.Strip(tt.tally.label()); got != tt.want {
+			t.Errorf("label(%+v) = %q, want %q", tt.tally, got, tt.want)
+		}
+	}
+}
@@ -13,60 +13,75 @@ import (
 
 // Mobile layout styles
 var (
+	mobileHeaderStyle          lipgloss.Style
+	mobileSessionStyle         lipgloss.Style
+	mobileSessionSelectedStyle lipgloss.Style
+	mobileSessionAttachedStyle lipgloss.Style
+	mobileSectionStyle         lipgloss.Style
+	mobileButtonStyle          lipgloss.Style
+	mobileButtonSelectedStyle  lipgloss.Style
+	mobileButtonDangerStyle    lipgloss.Style
+	mobileHintStyle            lipgloss.Style
+	mobileTimeStyle            lipgloss.Style
+	mobileActiveIndicator      string
+)
+
+// buildMobileStyles builds the mobile layout styles from the theme colors.
+func buildMobileStyles() {
 	mobileHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(primaryColor).
-				Padding(0, 1)
+		Bold(true).
+		Foreground(primaryColor).
+		Padding(0, 1)
 
 	mobileSessionStyle = lipgloss.NewStyle().
-				Padding(0, 1)
+		Padding(0, 1)
 
 	mobileSessionSelectedStyle = lipgloss.NewStyle().
-					Background(lipgloss.Color("236")).
-					Foreground(lipgloss.Color("255")).
-					Bold(true).
-					Padding(0, 1)
+		Background(selectionBgColor).
+		Foreground(textColor).
+		Bold(true).
+		Padding(0, 1)
 
 	mobileSessionAttachedStyle = lipgloss.NewStyle().
-					Foreground(activeColor).
-					Padding(0, 1)
+		Foreground(activeColor).
+		Padding(0, 1)
 
 	mobileSectionStyle = lipgloss.NewStyle().
-				Foreground(dimColor).
-				Padding(0, 1)
+		Foreground(dimColor).
+		Padding(0, 1)
 
 	mobileButtonStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")).
-				Background(buttonColor).
-				Bold(true).
-				Padding(0, 2).
-				Margin(0, 1)
+		Foreground(buttonTextColor).
+		Background(buttonColor).
+		Bold(true).
+		Padding(1, 2).
+		Margin(0, 1)
 
 	mobileButtonSelectedStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color("255")).
-					Background(activeColor).
-					Bold(true).
-					Padding(0, 2).
-					Margin(0, 1)
+		Foreground(buttonTextColor).
+		Background(activeColor).
+		Bold(true).
+		Padding(1, 2).
+		Margin(0, 1)
 
 	mobileButtonDangerStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")).
-				Background(errorColor).
-				Bold(true).
-				Padding(0, 2).
-				Margin(0, 1)
+		Foreground(buttonTextColor).
+		Background(errorColor).
+		Bold(true).
+		Padding(1, 2).
+		Margin(0, 1)
 
 	mobileHintStyle = lipgloss.NewStyle().
-			Foreground(dimColor).
-			Align(lipgloss.Center)
+		Foreground(dimColor).
+		Align(lipgloss.Center)
 
 	mobileTimeStyle = lipgloss.NewStyle().
-			Foreground(dimColor)
+		Foreground(dimColor)
 
 	mobileActiveIndicator = lipgloss.NewStyle().
-				Foreground(activeColor).
-				Render("*")
-)
+		Foreground(activeColor).
+		Render("*")
+}
 
 // MobileButton represents a button in the mobile button bar
 type MobileButton int
@@ -78,15 +93,22 @@ const (
 	MobileButtonCount
 )
 
-// shouldUseMobileLayout determines if mobile layout should be used
-func shouldUseMobileLayout(width int, forceMobile bool) bool {
+// mobileButtonLabels holds the button bar labels, indexed by MobileButton
+var mobileButtonLabels = [MobileButtonCount]string{"Attach", "Kill", "New"}
+
+// shouldUseMobileLayout determines if mobile layout should be used. A
+// threshold of 0 uses the default width.
+func shouldUseMobileLayout(width, threshold int, forceMobile bool) bool {
 	if forceMobile {
 		return true
 	}
 	if os.Getenv("ATMUX_MOBILE") == "1" {
 		return true
 	}
-	return width > 0 && width < mobileWidthThreshold
+	if threshold <= 0 {
+		threshold = mobileWidthThreshold
+	}
+	return width > 0 && width < threshold
 }
 
 // renderMobileView renders the mobile-optimized view
@@ -120,6 +142,16 @@ func (m Model) renderMobileView() string {
 		return m.renderMobileKillConfirm(base)
 	}
 
+	// Show "session already exists" prompt if active
+	if m.confirmNewSession {
+		return m.renderNewSessionConfirmOverlay(base)
+	}
+
+	// Show template choice for a new session if active
+	if m.templatePicker != nil {
+		return m.templatePicker.render(base, m.width, m.height)
+	}
+
 	// Show help overlay if active
 	if m.showHelp {
 		return m.renderMobileHelp(base)
@@ -147,16 +179,78 @@ func (m Model) renderMobileHeader() string {
 	return header
 }
 
-// renderMobileSessionsList renders the sessions list for mobile
-func (m Model) renderMobileSessionsList() string {
-	var lines []string
+// mobileRow is a row in the mobile sessions list: a session, or one of the
+// windows of an expanded session
+type mobileRow struct {
+	Session *tmux.TmuxSession
+	Window  *tmux.Window // nil for session rows
+}
+
+// Target returns the attach/kill target for the row ("session" or "session:window")
+func (r mobileRow) Target() string {
+	if r.Window == nil {
+		return r.Session.Name
+	}
+	return fmt.Sprintf("%s:%d", r.Session.Name, r.Window.Index)
+}
+
+// mobileRows flattens the session list, including windows of expanded sessions
+func (m *Model) mobileRows() []mobileRow {
+	if m.tree == nil {
+		return nil
+	}
+	var rows []mobileRow
+	for i := range m.tree.Sessions {
+		sess := &m.tree.Sessions[i]
+		rows = append(rows, mobileRow{Session: sess})
+		if !m.mobileExpanded[sess.Name] {
+			continue
+		}
+		for j := range sess.Windows {
+			rows = append(rows, mobileRow{Session: sess, Window: &sess.Windows[j]})
+		}
+	}
+	return rows
+}
 
-	// Calculate available height for sessions
+// mobileListHeight returns the content height of the bordered sessions list
+func (m Model) mobileListHeight() int {
 	// Total height minus header (1) minus button bar (3) minus hints (2) minus borders (2)
 	availableHeight := m.height - 1 - mobileButtonHeight - 2 - 2
 	if availableHeight < 3 {
 		availableHeight = 3
 	}
+	return availableHeight
+}
+
+// mobileVisibleRows returns the [start, end) range of rows that fit in the
+// list, scrolled so the selection stays visible
+func (m Model) mobileVisibleRows(total int) (int, int) {
+	// Section header and blank line take two lines
+	capacity := m.mobileListHeight() - 2
+	if total > capacity {
+		capacity-- // Room for the "+N more" line
+	}
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	start := 0
+	if m.selectedIndex >= capacity {
+		start = m.selectedIndex - capacity + 1
+	}
+	end := start + capacity
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// renderMobileSessionsList renders the sessions list for mobile
+func (m Model) renderMobileSessionsList() string {
+	var lines []string
+
+	availableHeight := m.mobileListHeight()
 
 	if m.tree == nil || len(m.tree.Sessions) == 0 {
 		emptyMsg := lipgloss.NewStyle().
@@ -171,24 +265,24 @@ func (m Model) renderMobileSessionsList() string {
 		lines = append(lines, sectionHeader)
 		lines = append(lines, "")
 
-		// List sessions - show only session-level items (no window/pane drill-down)
-		sessionIdx := 0
-		for i, sess := range m.tree.Sessions {
-			if len(lines) >= availableHeight-1 {
-				// Show "more..." indicator
-				remaining := len(m.tree.Sessions) - i
-				moreMsg := lipgloss.NewStyle().
-					Foreground(dimColor).
-					Padding(0, 1).
-					Render(fmt.Sprintf("  ... +%d more", remaining))
-				lines = append(lines, moreMsg)
-				break
+		// Sessions, with windows of expanded sessions indented beneath (no pane level)
+		rows := m.mobileRows()
+		start, end := m.mobileVisibleRows(len(rows))
+		for i := start; i < end; i++ {
+			selected := i == m.selectedIndex
+			if rows[i].Window == nil {
+				lines = append(lines, m.renderMobileSessionLine(*rows[i].Session, selected))
+			} else {
+				lines = append(lines, m.renderMobileWindowLine(*rows[i].Window, selected))
 			}
-
-			selected := sessionIdx == m.selectedIndex
-			line := m.renderMobileSessionLine(sess, selected)
-			lines = append(lines, line)
-			sessionIdx++
+		}
+		if remaining := len(rows) - end; remaining > 0 {
+			// Show "more..." indicator
+			moreMsg := lipgloss.NewStyle().
+				Foreground(dimColor).
+				Padding(0, 1).
+				Render(fmt.Sprintf("  ... +%d more", remaining))
+			lines = append(lines, moreMsg)
 		}
 	}
 
@@ -203,11 +297,11 @@ func (m Model) renderMobileSessionsList() string {
 
 // renderMobileSessionLine renders a single session line for mobile view
 func (m Model) renderMobileSessionLine(sess tmux.TmuxSession, selected bool) string {
-	// Format: "> sessionname      2w  *"
-	// Where 2w = 2 windows, * = attached indicator
+	// Format: "> ▸ sessionname      2w  *"
+	// Where ▸/▾ = windows hidden/shown, 2w = 2 windows, * = attached indicator
 
 	name := sess.Name
-	maxNameLen := m.width - 15 // Leave room for windows count and indicators
+	maxNameLen := m.width - 17 // Leave room for marker, windows count and indicators
 	if maxNameLen < 10 {
 		maxNameLen = 10
 	}
@@ -215,6 +309,11 @@ func (m Model) renderMobileSessionLine(sess tmux.TmuxSession, selected bool) str
 		name = name[:maxNameLen-3] + "..."
 	}
 
+	marker := "▸ "
+	if m.mobileExpanded[sess.Name] {
+		marker = "▾ "
+	}
+
 	// Window count
 	windowCount := fmt.Sprintf("%dw", len(sess.Windows))
 
@@ -225,9 +324,9 @@ func (m Model) renderMobileSessionLine(sess tmux.TmuxSession, selected bool) str
 	}
 
 	// Calculate padding
-	lineContent := name
+	lineContent := marker + name
 	rightPart := windowCount + " " + attachedIndicator
-	padding := m.width - 6 - len(lineContent) - len(rightPart)
+	padding := m.width - 6 - lipgloss.Width(lineContent) - lipgloss.Width(rightPart)
 	if padding < 1 {
 		padding = 1
 	}
@@ -251,17 +350,44 @@ func (m Model) renderMobileSessionLine(sess tmux.TmuxSession, selected bool) str
 	return style.Width(m.width - 4).Render(fullLine)
 }
 
-// renderMobileButtonBar renders the large touch-friendly button bar
-func (m Model) renderMobileButtonBar() string {
-	// Three main buttons: [Attach] [Kill] [New]
-	// Each should be large and touch-friendly
+// renderMobileWindowLine renders a window row beneath an expanded session
+func (m Model) renderMobileWindowLine(win tmux.Window, selected bool) string {
+	// Format: ">     1: windowname     *"
+	label := fmt.Sprintf("%d: %s", win.Index, win.Name)
+	maxLen := m.width - 14
+	if maxLen < 10 {
+		maxLen = 10
+	}
+	if len(label) > maxLen {
+		label = label[:maxLen-3] + "..."
+	}
+
+	activeIndicator := "  "
+	if win.Active {
+		activeIndicator = mobileActiveIndicator + " "
+	}
+
+	lineContent := "    " + label
+	padding := m.width - 6 - lipgloss.Width(lineContent) - lipgloss.Width(activeIndicator)
+	if padding < 1 {
+		padding = 1
+	}
+	fullLine := lineContent + strings.Repeat(" ", padding) + activeIndicator
 
-	attachBtn := mobileButtonSelectedStyle.Render("Attach")
-	killBtn := mobileButtonDangerStyle.Render("Kill")
-	newBtn := mobileButtonStyle.Render("New")
+	style := mobileSectionStyle
+	if selected {
+		style = mobileSessionSelectedStyle
+		fullLine = "> " + fullLine
+	} else {
+		fullLine = "  " + fullLine
+	}
+	return style.Width(m.width - 4).Render(fullLine)
+}
 
+// renderMobileButtonBar renders the large touch-friendly button bar
+func (m Model) renderMobileButtonBar() string {
 	// Center the buttons
-	buttons := lipgloss.JoinHorizontal(lipgloss.Center, attachBtn, killBtn, newBtn)
+	buttons := lipgloss.JoinHorizontal(lipgloss.Center, m.renderMobileButtons()...)
 	centered := lipgloss.NewStyle().
 		Width(m.width).
 		Align(lipgloss.Center).
@@ -270,10 +396,85 @@ func (m Model) renderMobileButtonBar() string {
 	return centered
 }
 
+// renderMobileButtons renders each button in the bar, highlighting the one
+// with keyboard focus
+func (m Model) renderMobileButtons() []string {
+	rendered := make([]string, MobileButtonCount)
+	for i, label := range mobileButtonLabels {
+		btn := MobileButton(i)
+		style := mobileButtonStyle
+		if btn == m.mobileFocus {
+			style = mobileButtonSelectedStyle
+		} else if btn == MobileButtonKill {
+			style = mobileButtonDangerStyle
+		}
+		rendered[i] = style.Render(label)
+	}
+	return rendered
+}
+
+// mobileButtonAt returns the button under column x of the button bar.
+// Each button's margin counts towards it so near-misses still register.
+func (m Model) mobileButtonAt(x int) (MobileButton, bool) {
+	buttons := m.renderMobileButtons()
+	total := 0
+	for _, b := range buttons {
+		total += lipgloss.Width(b)
+	}
+
+	left := (m.width - total) / 2
+	if left < 0 {
+		left = 0
+	}
+	for i, b := range buttons {
+		right := left + lipgloss.Width(b)
+		if x >= left && x < right {
+			return MobileButton(i), true
+		}
+		left = right
+	}
+	return 0, false
+}
+
+// mobileButtonBarY returns the first screen row of the button bar
+func (m Model) mobileButtonBarY() int {
+	return m.height - mobileButtonHeight - 2
+}
+
+// moveMobileFocus cycles keyboard focus through the button bar
+func (m *Model) moveMobileFocus(delta int) {
+	m.mobileFocus = MobileButton((int(m.mobileFocus) + delta + int(MobileButtonCount)) % int(MobileButtonCount))
+}
+
+// activateMobileButton performs the action of a button bar button
+func (m Model) activateMobileButton(btn MobileButton) (tea.Model, tea.Cmd) {
+	switch btn {
+	case MobileButtonAttach:
+		if row, ok := m.selectedMobileRow(); ok {
+			return m.quitToAttach(row.Session.Name, row.Target())
+		}
+	case MobileButtonKill:
+		if row, ok := m.selectedMobileRow(); ok {
+			if row.Window != nil {
+				return m.requestKill("window", row.Target(), row.Window.Name, "", false)
+			}
+			return m.requestKill("session", row.Target(), row.Session.Name, "", row.Session.Attached)
+		}
+	case MobileButtonNew:
+		// New session for the current directory
+		return m.startNewSession()
+	}
+	return m, nil
+}
+
 // renderMobileHints renders the keyboard/touch hints
 func (m Model) renderMobileHints() string {
-	hints := mobileHintStyle.Width(m.width).Render("j/k navigate  Enter attach  ? help  q quit")
-	return hints
+	text := "j/k navigate  Tab button  Enter select  ? help"
+	if m.options.RefreshInterval <= 0 {
+		// Auto-refresh is off, so point at the manual refresh
+		text = "j/k navigate  Enter select  r refresh  ? help"
+	}
+	return mobileHintStyle.Width(m.width).Render(text)
 }
 
 // renderMobileHelp renders the mobile help overlay
@@ -283,8 +484,12 @@ func (m Model) renderMobileHelp(base string) string {
 	helpLines := []string{
 		"",
 		helpKeyStyle.Render("j/k or Up/Down") + "  Navigate",
-		helpKeyStyle.Render("Enter") + "          Attach to session",
-		helpKeyStyle.Render("x or d") + "         Kill session",
+		helpKeyStyle.Render("l/h") + "            Show/hide windows",
+		helpKeyStyle.Render("Tab/Shift+Tab") + "  Focus next/prev button",
+		helpKeyStyle.Render("Enter") + "          Press focused button",
+		helpKeyStyle.Render("Tap session") + "    Show/hide windows",
+		helpKeyStyle.Render("Double-tap") + "     Attach",
+		helpKeyStyle.Render("x or d") + "         Kill session/window",
 		helpKeyStyle.Render("n") + "              New session",
 		helpKeyStyle.Render("r") + "              Refresh list",
 		helpKeyStyle.Render("?") + "              Toggle help",
@@ -292,6 +497,11 @@ func (m Model) renderMobileHelp(base string) string {
 		"",
 		helpDescStyle.Render("Press any key to close"),
 	}
+	if m.options.SkipKillConfirm {
+		helpLines = append(helpLines[:len(helpLines)-1],
+			lipgloss.NewStyle().Foreground(gettingStaleColor).Render("Kill confirmations are off"),
+			helpLines[len(helpLines)-1])
+	}
 
 	helpContent := lipgloss.JoinVertical(lipgloss.Left,
 		append([]string{title}, helpLines...)...,
@@ -316,6 +526,9 @@ func (m Model) renderMobileHelp(base string) string {
 // renderMobileKillConfirm renders the kill confirmation overlay for mobile
 func (m Model) renderMobileKillConfirm(base string) string {
 	title := helpTitleStyle.Render("Kill Session?")
+	if m.killNodeType == "window" {
+		title = helpTitleStyle.Render("Kill Window?")
+	}
 
 	nameDisplay := m.killNodeName
 	if nameDisplay == "" {
@@ -323,7 +536,7 @@ func (m Model) renderMobileKillConfirm(base string) string {
 	}
 
 	message := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("15")).
+		Foreground(textColor).
 		Bold(true).
 		Render(fmt.Sprintf("'%s'", nameDisplay))
 
@@ -331,13 +544,8 @@ func (m Model) renderMobileKillConfirm(base string) string {
 		Foreground(dimColor).
 		Render("[y] confirm  [n] cancel")
 
-	confirmContent := lipgloss.JoinVertical(lipgloss.Center,
-		title,
-		"",
-		message,
-		"",
-		hint,
-	)
+	lines := append([]string{title, "", message}, killImpactLines(m.killImpact)...)
+	confirmContent := lipgloss.JoinVertical(lipgloss.Center, append(lines, "", hint)...)
 
 	confirmBox := helpOverlayStyle.
 		Width(m.width - 8).
@@ -373,6 +581,16 @@ func (m Model) handleMobileKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle "session already exists" prompt if active
+	if m.confirmNewSession {
+		return m.handleNewSessionConfirmKeys(msg)
+	}
+
+	// Handle template choice for a new session if active
+	if m.templatePicker != nil {
+		return m.handleTemplatePickerKeys(msg)
+	}
+
 	// Close help overlay if open
 	if m.showHelp {
 		m.showHelp = false
@@ -391,28 +609,27 @@ func (m Model) handleMobileKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "down", "j":
 		m.moveMobileSelection(1)
 		return m, nil
-	case "enter", " ":
-		// Attach to selected session
-		if sess := m.selectedMobileSession(); sess != nil {
-			m.attachSession = sess.Name
-			return m, tea.Quit
-		}
+	case "right", "l":
+		m.setMobileExpanded(true)
 		return m, nil
-	case "x", "d":
-		// Kill selected session
-		if sess := m.selectedMobileSession(); sess != nil {
-			m.confirmKill = true
-			m.killNodeType = "session"
-			m.killNodeTarget = sess.Name
-			m.killNodeName = sess.Name
-			return m, nil
-		}
+	case "left", "h":
+		m.setMobileExpanded(false)
 		return m, nil
+	case "tab":
+		m.moveMobileFocus(1)
+		return m, nil
+	case "shift+tab":
+		m.moveMobileFocus(-1)
+		return m, nil
+	case "enter", " ":
+		return m.activateMobileButton(m.mobileFocus)
+	case "x", "d":
+		return m.activateMobileButton(MobileButtonKill)
 	case "r":
+		m.resetHostRetries()
 		return m, m.fetchTreeCmd()
 	case "n":
-		// New session - for now just refresh (could add new session wizard later)
-		return m, m.fetchTreeCmd()
+		return m.activateMobileButton(MobileButtonNew)
 	}
 
 	return m, nil
@@ -432,55 +649,52 @@ func (m Model) handleMobileMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Dismiss the "session already exists" prompt on click
+	if m.confirmNewSession && msg.Action == tea.MouseActionPress {
+		m.confirmNewSession = false
+		return m, nil
+	}
+
+	// Dismiss the template choice on click
+	if m.templatePicker != nil && msg.Action == tea.MouseActionPress {
+		m.templatePicker = nil
+		return m, nil
+	}
+
 	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
 		// Check if clicking in session list area
 		// Header is 1 line, session list starts at line 2
-		sessionListStart := 4 // header + section header + empty line + border
-		sessionListEnd := m.height - mobileButtonHeight - 2 - 2
+		sessionListStart := 4                      // header + section header + empty line + border
+		sessionListEnd := m.mobileButtonBarY() - 1 // bottom border
 
 		if msg.Y >= sessionListStart && msg.Y < sessionListEnd {
-			clickedIdx := msg.Y - sessionListStart
-			if m.tree != nil && clickedIdx >= 0 && clickedIdx < len(m.tree.Sessions) {
+			rows := m.mobileRows()
+			start, end := m.mobileVisibleRows(len(rows))
+			clickedIdx := start + msg.Y - sessionListStart
+			if clickedIdx >= start && clickedIdx < end {
 				// Check for double-click
 				if clickedIdx == m.selectedIndex &&
 					time.Since(m.lastClickAt) <= doubleClickThreshold {
 					// Double-click: attach
-					if sess := m.selectedMobileSession(); sess != nil {
-						m.attachSession = sess.Name
-						return m, tea.Quit
-					}
+					return m.quitToAttach(rows[clickedIdx].Session.Name, rows[clickedIdx].Target())
 				}
 				m.selectedIndex = clickedIdx
 				m.lastClickIdx = clickedIdx
 				m.lastClickAt = time.Now()
+				// Tapping a session reveals or hides its windows
+				if rows[clickedIdx].Window == nil {
+					m.setMobileExpanded(!m.mobileExpanded[rows[clickedIdx].Session.Name])
+				}
 				return m, nil
 			}
 		}
 
 		// Check if clicking button bar
-		buttonBarY := m.height - mobileButtonHeight - 2
+		buttonBarY := m.mobileButtonBarY()
 		if msg.Y >= buttonBarY && msg.Y < buttonBarY+mobileButtonHeight {
-			// Determine which button was clicked based on X position
-			// Buttons are roughly evenly distributed
-			buttonWidth := m.width / 3
-			buttonIdx := msg.X / buttonWidth
-
-			switch MobileButton(buttonIdx) {
-			case MobileButtonAttach:
-				if sess := m.selectedMobileSession(); sess != nil {
-					m.attachSession = sess.Name
-					return m, tea.Quit
-				}
-			case MobileButtonKill:
-				if sess := m.selectedMobileSession(); sess != nil {
-					m.confirmKill = true
-					m.killNodeType = "session"
-					m.killNodeTarget = sess.Name
-					m.killNodeName = sess.Name
-				}
-			case MobileButtonNew:
-				// Refresh for now
-				return m, m.fetchTreeCmd()
+			if btn, ok := m.mobileButtonAt(msg.X); ok {
+				m.mobileFocus = btn
+				return m.activateMobileButton(btn)
 			}
 			return m, nil
 		}
@@ -495,9 +709,10 @@ func (m Model) handleMobileMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// moveMobileSelection moves the selection in mobile mode (sessions only)
+// moveMobileSelection moves the selection in mobile mode (sessions and revealed windows)
 func (m *Model) moveMobileSelection(delta int) {
-	if m.tree == nil || len(m.tree.Sessions) == 0 {
+	rows := m.mobileRows()
+	if len(rows) == 0 {
 		return
 	}
 
@@ -505,15 +720,35 @@ func (m *Model) moveMobileSelection(delta int) {
 	if m.selectedIndex < 0 {
 		m.selectedIndex = 0
 	}
-	if m.selectedIndex >= len(m.tree.Sessions) {
-		m.selectedIndex = len(m.tree.Sessions) - 1
+	if m.selectedIndex >= len(rows) {
+		m.selectedIndex = len(rows) - 1
 	}
 }
 
-// selectedMobileSession returns the currently selected session in mobile mode
-func (m *Model) selectedMobileSession() *tmux.TmuxSession {
-	if m.tree == nil || m.selectedIndex < 0 || m.selectedIndex >= len(m.tree.Sessions) {
-		return nil
+// selectedMobileRow returns the currently selected row in mobile mode
+func (m *Model) selectedMobileRow() (mobileRow, bool) {
+	rows := m.mobileRows()
+	if m.selectedIndex < 0 || m.selectedIndex >= len(rows) {
+		return mobileRow{}, false
+	}
+	return rows[m.selectedIndex], true
+}
+
+// setMobileExpanded reveals or hides the windows of the selected row's session.
+// Collapsing from a window row moves the selection back to its session.
+func (m *Model) setMobileExpanded(expanded bool) {
+	row, ok := m.selectedMobileRow()
+	if !ok {
+		return
+	}
+	m.mobileExpanded[row.Session.Name] = expanded
+	if expanded {
+		return
+	}
+	for i, r := range m.mobileRows() {
+		if r.Window == nil && r.Session.Name == row.Session.Name {
+			m.selectedIndex = i
+			break
+		}
 	}
-	return &m.tree.Sessions[m.selectedIndex]
 }
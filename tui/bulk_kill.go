@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// bulkKillListMax is how many targets the bulk kill confirmation lists
+// before summarizing the rest.
+const bulkKillListMax = 12
+
+// killItem is a session, window, or pane marked for a bulk kill.
+type killItem struct {
+	nodeType string
+	target   string
+	id       string // tmux window or pane id, which survives renumbering
+	name     string
+	host     string
+}
+
+// killTarget returns what to pass to tmux to kill the item: its id when
+// known, since killing a sibling renumbers the panes (and, with
+// renumber-windows, the windows) after it.
+func (k killItem) killTarget() string {
+	if k.id != "" {
+		return k.id
+	}
+	return k.target
+}
+
+// key identifies the item's target across hosts.
+func (k killItem) key() string {
+	return k.host + "\x00" + k.target
+}
+
+// label describes the item in the confirmation list.
+func (k killItem) label() string {
+	label := fmt.Sprintf("%-7s %s", k.nodeType, k.target)
+	if k.name != "" && k.name != k.target {
+		label += " (" + k.name + ")"
+	}
+	if k.host != "" {
+		label += " @ " + k.host
+	}
+	return label
+}
+
+// killItemFor returns the kill item for a tree node.
+func killItemFor(node *tmux.TreeNode) killItem {
+	return killItem{nodeType: node.Type, target: node.Target, id: node.ID, name: node.Name, host: node.Host}
+}
+
+// isMarked reports whether node is marked for a bulk kill.
+func (m *Model) isMarked(node *tmux.TreeNode) bool {
+	_, ok := m.killMarks[killItemFor(node).key()]
+	return ok
+}
+
+// toggleMark marks or unmarks node for a bulk kill.
+func (m *Model) toggleMark(node *tmux.TreeNode) {
+	if node == nil || node.Type == "host" || node.Target == "" {
+		return
+	}
+	item := killItemFor(node)
+	if _, ok := m.killMarks[item.key()]; ok {
+		delete(m.killMarks, item.key())
+		return
+	}
+	if m.killMarks == nil {
+		m.killMarks = make(map[string]killItem)
+	}
+	m.killMarks[item.key()] = item
+}
+
+// markInactiveSiblings marks every window in the selected window's session,
+// or every pane in the selected pane's window, except the active one.
+func (m *Model) markInactiveSiblings(node *tmux.TreeNode) {
+	if node == nil || (node.Type != "window" && node.Type != "pane") {
+		return
+	}
+	session := sessionFromTarget(node.Target)
+	for _, ht := range m.searchHostTrees() {
+		if ht.Host != node.Host || ht.Tree == nil {
+			continue
+		}
+		for _, sess := range ht.Tree.Sessions {
+			if sess.Name != session {
+				continue
+			}
+			for _, win := range sess.Windows {
+				windowTarget := sess.Name + ":" + strconv.Itoa(win.Index)
+				if node.Type == "window" {
+					if !win.Active {
+						m.mark(killItem{nodeType: "window", target: windowTarget, id: win.ID, name: win.Name, host: node.Host})
+					}
+					continue
+				}
+				if windowTarget != windowTargetOf(node) {
+					continue
+				}
+				for _, pane := range win.Panes {
+					if !pane.Active {
+						m.mark(killItem{nodeType: "pane", target: pane.Target, id: pane.ID, name: pane.Title, host: node.Host})
+					}
+				}
+			}
+		}
+	}
+}
+
+// mark adds item to the bulk kill marks.
+func (m *Model) mark(item killItem) {
+	if m.killMarks == nil {
+		m.killMarks = make(map[string]killItem)
+	}
+	m.killMarks[item.key()] = item
+}
+
+// bulkKillItems returns the marked targets in kill order: panes, then
+// windows, then sessions, highest index first within a window or session so
+// that a target without an id isn't renumbered by an earlier kill. Targets
+// inside a marked window or session are dropped since killing the parent
+// takes them too.
+func (m *Model) bulkKillItems() []killItem {
+	covered := func(item killItem) bool {
+		if item.nodeType != "session" {
+			if _, ok := m.killMarks[item.host+"\x00"+sessionFromTarget(item.target)]; ok {
+				return true
+			}
+		}
+		if item.nodeType == "pane" {
+			window := item.target[:max(0, strings.LastIndex(item.target, "."))]
+			if _, ok := m.killMarks[item.host+"\x00"+window]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var items []killItem
+	for _, item := range m.killMarks {
+		if !covered(item) {
+			items = append(items, item)
+		}
+	}
+	rank := map[string]int{"pane": 0, "window": 1, "session": 2}
+	sort.Slice(items, func(i, j int) bool {
+		if rank[items[i].nodeType] != rank[items[j].nodeType] {
+			return rank[items[i].nodeType] < rank[items[j].nodeType]
+		}
+		if items[i].host != items[j].host {
+			return items[i].host < items[j].host
+		}
+		return targetAfter(items[i].target, items[j].target)
+	})
+	return items
+}
+
+// targetAfter orders targets by session name, then by descending window
+// and pane index compared as numbers, so "x:0.10" comes before "x:0.2".
+func targetAfter(a, b string) bool {
+	sessA, idxA := splitTargetIndexes(a)
+	sessB, idxB := splitTargetIndexes(b)
+	if sessA != sessB {
+		return sessA < sessB
+	}
+	for k := 0; k < len(idxA) && k < len(idxB); k++ {
+		if idxA[k] != idxB[k] {
+			return idxA[k] > idxB[k]
+		}
+	}
+	return len(idxA) > len(idxB)
+}
+
+// splitTargetIndexes splits "session:window.pane" into the session name
+// and its numeric indexes.
+func splitTargetIndexes(target string) (string, []int) {
+	colon := strings.LastIndex(target, ":")
+	if colon < 0 {
+		return target, nil
+	}
+	var indexes []int
+	for _, part := range strings.Split(target[colon+1:], ".") {
+		n, _ := strconv.Atoi(part)
+		indexes = append(indexes, n)
+	}
+	return target[:colon], indexes
+}
+
+// requestBulkKill asks for confirmation before killing every marked target.
+func (m Model) requestBulkKill() (tea.Model, tea.Cmd) {
+	items := m.bulkKillItems()
+	if len(items) == 0 {
+		return m, nil
+	}
+	m.pendingBulkKill = items
+	return m, nil
+}
+
+// killItemsCmd kills each item in turn through its host's executor,
+// reporting one BulkKillCompletedMsg for the batch.
+func (m *Model) killItemsCmd(items []killItem) tea.Cmd {
+	execs := make([]tmux.TmuxExecutor, len(items))
+	for i, item := range items {
+		if item.host != "" {
+			execs[i] = m.executorForHost(item.host)
+		}
+	}
+	return func() tea.Msg {
+		var errs []error
+		for i, item := range items {
+			var err error
+			if execs[i] != nil {
+				err = tmux.KillTargetWithExecutor(item.nodeType, item.killTarget(), execs[i])
+			} else {
+				err = tmux.KillTarget(item.nodeType, item.killTarget())
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to kill %s %s: %w", item.nodeType, item.target, err))
+			}
+		}
+		return BulkKillCompletedMsg{Count: len(items), Err: errors.Join(errs...)}
+	}
+}
+
+// handleBulkKillConfirmKeys handles keys while confirming a bulk kill.
+func (m Model) handleBulkKillConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		items := m.pendingBulkKill
+		m.pendingBulkKill = nil
+		m.killMarks = nil
+		return m, m.killItemsCmd(items)
+	case "n", "N", "esc":
+		m.pendingBulkKill = nil
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderBulkKillConfirmOverlay lists everything a bulk kill will remove.
+func (m Model) renderBulkKillConfirmOverlay(base string) string {
+	items := m.pendingBulkKill
+	rows := []string{
+		helpTitleStyle.Render("Kill Marked Targets"),
+		"",
+		lipgloss.NewStyle().Foreground(textColor).Bold(true).
+			Render(fmt.Sprintf("Kill these %d targets?", len(items))),
+	}
+	for i, item := range items {
+		if i == bulkKillListMax {
+			rows = append(rows, lipgloss.NewStyle().Foreground(dimColor).
+				Render(fmt.Sprintf("  ...and %d more", len(items)-bulkKillListMax)))
+			break
+		}
+		rows = append(rows, "  "+truncate(item.label(), 52))
+	}
+	rows = append(rows, "", lipgloss.NewStyle().Foreground(dimColor).
+		Render("Press [y] to kill, [n] or [Esc] to cancel"))
+
+	box := helpOverlayStyle.Width(60).Render(strings.Join(rows, "\n"))
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
+
+// markGlyph renders the marker shown after nodes marked for a bulk kill.
+func (m *Model) markGlyph(node *tmux.TreeNode) string {
+	if !m.isMarked(node) {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(" ✗")
+}
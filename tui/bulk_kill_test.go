@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func remoteBulkKillModel(t *testing.T, exec *recordingExecutor) Model {
+	t.Helper()
+	sess := windowWithPanes("work", 3)
+	sess.Windows[0].Active = true
+	sess.Windows[0].Panes[0].Active = true
+	sess.Windows = append(sess.Windows, tmux.Window{Index: 1, Name: "logs"}, tmux.Window{Index: 2, Name: "build"})
+
+	m := NewModel(Options{})
+	m.executors = []tmux.TmuxExecutor{exec}
+	m.hostTrees = []tmux.HostTree{{
+		Host:     "devbox",
+		Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{sess}},
+		Executor: exec,
+	}}
+	m.liveTree = true
+	m.rebuildFlatNodes()
+	return m
+}
+
+func TestBulkKillInactivePanesThroughExecutor(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}})
+	updated, _ = updated.(Model).handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.pendingBulkKill) != 2 {
+		t.Fatalf("expected 2 panes awaiting confirmation, got %+v", m.pendingBulkKill)
+	}
+
+	updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if m.pendingBulkKill != nil || len(m.killMarks) != 0 {
+		t.Fatal("expected confirmation and marks to be cleared")
+	}
+	msg := cmd().(BulkKillCompletedMsg)
+	if msg.Err != nil || msg.Count != 2 {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	want := []string{"kill-pane -t work:0.2", "kill-pane -t work:0.1"}
+	if !reflect.DeepEqual(exec.calls, want) {
+		t.Fatalf("calls = %v, want %v", exec.calls, want)
+	}
+}
+
+func TestBulkKillSkipsTargetsInsideMarkedParents(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	m.toggleMark(m.flatNodes[nodeIndex(t, m, "pane", "work:0.1")])
+	m.toggleMark(m.flatNodes[nodeIndex(t, m, "window", "work:1")])
+	m.toggleMark(m.flatNodes[nodeIndex(t, m, "window", "work:0")])
+
+	items := m.bulkKillItems()
+	if len(items) != 2 || items[0].target != "work:1" || items[1].target != "work:0" {
+		t.Fatalf("expected only the two windows, got %+v", items)
+	}
+
+	m.toggleMark(m.flatNodes[nodeIndex(t, m, "session", "work")])
+	items = m.bulkKillItems()
+	if len(items) != 1 || items[0].nodeType != "session" {
+		t.Fatalf("expected only the session, got %+v", items)
+	}
+}
+
+func TestBulkKillMarksToggleAndEscClears(t *testing.T) {
+	m := remoteBulkKillModel(t, &recordingExecutor{host: "devbox"})
+	node := m.flatNodes[nodeIndex(t, m, "window", "work:1")]
+	m.toggleMark(node)
+	if !m.isMarked(node) {
+		t.Fatal("expected window to be marked")
+	}
+	m.toggleMark(node)
+	if m.isMarked(node) {
+		t.Fatal("expected second toggle to unmark")
+	}
+
+	m.markInactiveSiblings(node)
+	if len(m.killMarks) != 2 {
+		t.Fatalf("expected the two inactive windows marked, got %+v", m.killMarks)
+	}
+	updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(updated.(Model).killMarks) != 0 || cmd != nil {
+		t.Fatal("expected Esc to clear marks without quitting")
+	}
+}
+
+func TestBulkKillSiblingPanesSurvivesRenumbering(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	panes := windowWithPanes("work", 12).Windows[0].Panes
+	panes[0].Active = true
+	for i := range panes {
+		panes[i].ID = fmt.Sprintf("%%%d", 40+i)
+	}
+	m.hostTrees[0].Tree.Sessions[0].Windows[0].Panes = panes
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}})
+	m = updated.(Model)
+	items := m.bulkKillItems()
+	if len(items) != 11 || items[0].target != "work:0.11" || items[1].target != "work:0.10" || items[10].target != "work:0.1" {
+		t.Fatalf("expected the panes highest index first, got %+v", items)
+	}
+
+	msg := m.killItemsCmd(items)().(BulkKillCompletedMsg)
+	if msg.Err != nil || msg.Count != 11 {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	var want []string
+	for i := 11; i >= 1; i-- {
+		want = append(want, fmt.Sprintf("kill-pane -t %%%d", 40+i))
+	}
+	if !reflect.DeepEqual(exec.calls, want) {
+		t.Fatalf("calls = %v, want %v", exec.calls, want)
+	}
+}
@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// copyTargetCmd copies a node's target string to the clipboard
+func copyTargetCmd(target string) tea.Cmd {
+	return func() tea.Msg {
+		method, err := tmux.CopyToClipboard(target)
+		return ClipboardCopiedMsg{What: "target " + target, Method: method, Err: err}
+	}
+}
+
+// copyContentCmd captures a target's pane content and copies it to the
+// clipboard, routing the capture through the node's executor
+func (m *Model) copyContentCmd(host, target string) tea.Cmd {
+	var exec tmux.TmuxExecutor = tmux.NewLocalExecutor()
+	if host != "" {
+		if e := m.executorForHost(host); e != nil {
+			exec = e
+		}
+	}
+	return func() tea.Msg {
+		content, err := tmux.CapturePaneTextWithExecutor(target, exec)
+		if err != nil {
+			return ClipboardCopiedMsg{Err: fmt.Errorf("failed to capture %s: %w", target, err)}
+		}
+		method, err := tmux.CopyToClipboard(content)
+		return ClipboardCopiedMsg{What: "content of " + target, Method: method, Err: err}
+	}
+}
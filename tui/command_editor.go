@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// commandEditorRows is the number of text rows in the multi-line editor.
+const commandEditorRows = 5
+
+// newCommandEditor creates the multi-line command editor, hidden until
+// opened with Ctrl+O from the command input.
+func newCommandEditor() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "Enter a multi-line prompt..."
+	ta.Prompt = ""
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.SetHeight(commandEditorRows)
+	ta.SetWidth(50)
+	return ta
+}
+
+// inputAreaHeight returns the rows the input bar takes, borders included:
+// one line of text, or the editor's rows while it is open.
+func (m *Model) inputAreaHeight() int {
+	if m.multiline {
+		return commandEditorRows + 2
+	}
+	return inputHeight
+}
+
+// focusCommandInput focuses the input bar: the editor while it is open,
+// otherwise the single-line input.
+func (m *Model) focusCommandInput() {
+	m.focused = FocusInput
+	if m.multiline {
+		m.commandArea.Focus()
+	} else {
+		m.commandInput.Focus()
+	}
+}
+
+// openCommandEditor expands the command input into the multi-line editor,
+// carrying over any typed text.
+func (m Model) openCommandEditor() (tea.Model, tea.Cmd) {
+	if m.mobileMode {
+		return m, nil
+	}
+	if value := m.commandInput.Value(); value != "" {
+		m.commandArea.SetValue(value)
+	}
+	m.commandInput.SetValue("")
+	m.commandInput.Blur()
+	m.multiline = true
+	m.calculateLayout()
+	m.calculateButtonZones()
+	return m, m.commandArea.Focus()
+}
+
+// closeCommandEditor shrinks the editor back to the single-line input. A
+// one-line draft moves back into the input; a longer one is kept for the
+// next time the editor opens.
+func (m *Model) closeCommandEditor() {
+	m.multiline = false
+	m.commandArea.Blur()
+	if value := m.commandArea.Value(); !strings.Contains(value, "\n") {
+		m.commandInput.SetValue(value)
+		m.commandInput.CursorEnd()
+		m.commandArea.Reset()
+	}
+	if m.focused == FocusInput {
+		m.commandInput.Focus()
+	}
+	m.calculateLayout()
+	m.calculateButtonZones()
+}
+
+// handleCommandEditorKeys handles keys while the editor is open and
+// focused. Enter inserts a newline and Ctrl+S sends; keys it doesn't handle
+// (focus cycling, the palette, a second Ctrl+C) fall through to the global
+// keys.
+func (m Model) handleCommandEditorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+s", "alt+enter":
+		node := m.selectedNode()
+		text := strings.TrimRight(m.commandArea.Value(), "\n")
+		if node == nil || node.Type != "pane" || strings.TrimSpace(text) == "" {
+			return m, nil, true
+		}
+		// after_send applies as it does to the single-line input
+		switch m.options.AfterSend {
+		case config.AfterSendClear:
+			m.commandArea.Reset()
+		case config.AfterSendClearOnSuccess:
+			m.clearOnSent = text
+		}
+		m.closeCommandEditor()
+		// A bracketed paste keeps the newlines from submitting each line
+		model, cmd := m.requestSendWithMethod(node, text, tmux.SendMethodBracketedPaste)
+		return model, cmd, true
+	case "esc":
+		m.closeCommandEditor()
+		m.ctrlCPrimed = false
+		return m, nil, true
+	case "ctrl+c":
+		if value := m.commandArea.Value(); value != "" {
+			m.pushInputHistory(value)
+			m.commandArea.Reset()
+			m.ctrlCPrimed = true
+			return m, nil, true
+		}
+		return m, nil, false
+	case "tab", "shift+tab", "ctrl+p":
+		return m, nil, false
+	}
+
+	var cmd tea.Cmd
+	m.commandArea, cmd = m.commandArea.Update(msg)
+	return m, cmd, true
+}
+
+// renderCommandEditor renders the input bar's content while the editor is
+// open, with its keys listed under the label.
+func (m *Model) renderCommandEditor(label string) string {
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+	labels := lipgloss.NewStyle().Width(lipgloss.Width(label)).Render(strings.Join([]string{
+		label,
+		dim.Render("^S send"),
+		dim.Render("Esc done"),
+	}, "\n"))
+	helpBtn := helpButtonStyle.Render("?")
+	return lipgloss.JoinHorizontal(lipgloss.Top, labels, m.commandArea.View(), " ", helpBtn)
+}
+
+// commandSummary shortens a multi-line command to its first line for the
+// status bar and confirmations.
+func commandSummary(command string) string {
+	first, rest, found := strings.Cut(command, "\n")
+	if !found {
+		return command
+	}
+	if more := strings.Count(rest, "\n") + 1; more > 1 {
+		return fmt.Sprintf("%s (+%d lines)", first, more)
+	}
+	return first + " (+1 line)"
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestCommandEditorSendsMultiLinePaste(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := NewModel(Options{})
+	m.width, m.height = 120, 40
+	m.executors = []tmux.TmuxExecutor{exec}
+	m.hostTrees = []tmux.HostTree{{
+		Host:     "devbox",
+		Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 2)}},
+		Executor: exec,
+	}}
+	m.liveTree = true
+	m.rebuildFlatNodes()
+	m.calculateLayout()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.1")
+	m.focusCommandInput()
+	m.commandInput.SetValue("Review this")
+
+	press := func(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+		updated, cmd := m.handleKeyMsg(msg)
+		return updated.(Model), cmd
+	}
+
+	treeRows := m.treeViewHeight()
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !m.multiline || m.commandArea.Value() != "Review this" {
+		t.Fatalf("expected the editor to open with the typed text, got %q", m.commandArea.Value())
+	}
+	if m.inputAreaHeight() != commandEditorRows+2 || m.treeViewHeight() != treeRows-(commandEditorRows-1) {
+		t.Fatal("expected the input bar to grow and the tree to shrink")
+	}
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("and the tests?")})
+	if m.commandArea.Value() != "Review this\nand the tests?" || m.showHelp {
+		t.Fatalf("expected Enter to insert a newline, got %q", m.commandArea.Value())
+	}
+
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("expected Ctrl+S to send")
+	}
+	if m.multiline || m.inputAreaHeight() != inputHeight || m.treeViewHeight() != treeRows {
+		t.Fatal("expected the editor to close after sending")
+	}
+	msg := cmd().(CommandSentMsg)
+	if msg.Err != nil || msg.Target != "work:0.1" {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	if len(exec.calls) != 3 || !strings.HasSuffix(exec.calls[0], "Review this\nand the tests?") || !strings.HasPrefix(exec.calls[1], "paste-buffer -p") {
+		t.Fatalf("expected a bracketed paste, got %q", exec.calls)
+	}
+	if commandSummary(msg.Command) != "Review this (+1 line)" {
+		t.Fatalf("unexpected summary %q", commandSummary(msg.Command))
+	}
+
+	// Esc keeps a multi-line draft for later
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	m.commandArea.SetValue("one\ntwo")
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.multiline || m.focused != FocusInput || m.commandInput.Value() != "" {
+		t.Fatal("expected Esc to close the editor and stay in the input")
+	}
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.commandArea.Value() != "one\ntwo" {
+		t.Fatalf("expected the draft back, got %q", m.commandArea.Value())
+	}
+}
+
+func TestCommandEditorSendFollowsAfterSend(t *testing.T) {
+	for _, tc := range []struct {
+		afterSend  config.AfterSend
+		afterPress string // Editor draft right after Ctrl+S
+		afterSent  string // Editor draft once the send result arrives
+	}{
+		{afterSend: "", afterPress: "one\ntwo", afterSent: "one\ntwo"},
+		{afterSend: config.AfterSendClear, afterPress: "", afterSent: ""},
+		{afterSend: config.AfterSendClearOnSuccess, afterPress: "one\ntwo", afterSent: ""},
+	} {
+		exec := &recordingExecutor{host: "devbox"}
+		m := NewModel(Options{AfterSend: tc.afterSend})
+		m.width, m.height = 120, 40
+		m.executors = []tmux.TmuxExecutor{exec}
+		m.hostTrees = []tmux.HostTree{{
+			Host:     "devbox",
+			Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 1)}},
+			Executor: exec,
+		}}
+		m.liveTree = true
+		m.rebuildFlatNodes()
+		m.calculateLayout()
+		m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+		m.focusCommandInput()
+
+		updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlO})
+		m = updated.(Model)
+		m.commandArea.SetValue("one\ntwo")
+		updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlS})
+		m = updated.(Model)
+		if cmd == nil || m.commandArea.Value() != tc.afterPress {
+			t.Fatalf("%q: draft after Ctrl+S = %q, want %q", tc.afterSend, m.commandArea.Value(), tc.afterPress)
+		}
+		updated, _ = m.Update(cmd().(CommandSentMsg))
+		if got := updated.(Model).commandArea.Value(); got != tc.afterSent {
+			t.Fatalf("%q: draft after send = %q, want %q", tc.afterSend, got, tc.afterSent)
+		}
+	}
+}
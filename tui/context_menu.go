@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
 )
 
 // ContextMenu represents a right-click context menu
@@ -35,32 +36,43 @@ type MenuItem struct {
 
 // Menu styles
 var (
+	menuBorderStyle       lipgloss.Style
+	menuItemStyle         lipgloss.Style
+	menuItemSelectedStyle lipgloss.Style
+	menuItemDisabledStyle lipgloss.Style
+	menuShortcutStyle     lipgloss.Style
+	menuDividerStyle      lipgloss.Style
+)
+
+// buildMenuStyles builds the context menu styles from the theme colors.
+func buildMenuStyles() {
 	menuBorderStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 1)
 
 	menuItemStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252"))
+		Foreground(textColor)
 
 	menuItemSelectedStyle = lipgloss.NewStyle().
-				Background(primaryColor).
-				Foreground(lipgloss.Color("255"))
+		Background(primaryColor).
+		Foreground(buttonTextColor)
 
 	menuItemDisabledStyle = lipgloss.NewStyle().
-				Foreground(dimColor)
+		Foreground(dimColor)
 
 	menuShortcutStyle = lipgloss.NewStyle().
-				Foreground(dimColor)
+		Foreground(dimColor)
 
 	menuDividerStyle = lipgloss.NewStyle().
-				Foreground(dimColor)
-)
+		Foreground(dimColor)
+}
 
 // Menu action constants
 const (
 	MenuActionAttach       = "attach"
 	MenuActionAttachPopup  = "attach_popup"
+	MenuActionAttachRO     = "attach_read_only"
 	MenuActionNewWindow    = "new_window"
 	MenuActionRename       = "rename"
 	MenuActionKillSession  = "kill_session"
@@ -69,29 +81,36 @@ const (
 	MenuActionNewPaneV     = "new_pane_v"
 	MenuActionMoveWindow   = "move_window"
 	MenuActionKillWindow   = "kill_window"
+	MenuActionSendAllPanes = "send_all_panes"
+	MenuActionSyncPanes    = "synchronize_panes"
 	MenuActionSelectPane   = "select_pane"
 	MenuActionZoomPane     = "zoom_pane"
 	MenuActionSendKeys     = "send_keys"
+	MenuActionSendFile     = "send_file"
 	MenuActionSwapPane     = "swap_pane"
+	MenuActionBreakPane    = "break_pane"
+	MenuActionInterrupt    = "interrupt"
 	MenuActionKillPane     = "kill_pane"
+	MenuActionCopyTarget   = "copy_target"
+	MenuActionCopyContent  = "copy_content"
 )
 
-// NewContextMenu creates a new context menu for the given node type
-func NewContextMenu(nodeType, target, name string, x, y int) *ContextMenu {
+// NewContextMenu creates a new context menu for the given node
+func NewContextMenu(node *tmux.TreeNode, x, y int) *ContextMenu {
 	menu := &ContextMenu{
 		Position: Position{X: x, Y: y},
 		Selected: 0,
 		Visible:  true,
-		NodeType: nodeType,
-		Target:   target,
-		NodeName: name,
+		NodeType: node.Type,
+		Target:   node.Target,
+		NodeName: node.Name,
 	}
 
-	switch nodeType {
+	switch node.Type {
 	case "session":
 		menu.Items = sessionMenuItems()
 	case "window":
-		menu.Items = windowMenuItems()
+		menu.Items = windowMenuItems(node.Synchronized)
 	case "pane":
 		menu.Items = paneMenuItems()
 	}
@@ -105,23 +124,34 @@ func sessionMenuItems() []MenuItem {
 	return []MenuItem{
 		{Label: "Attach", Shortcut: "a", Action: MenuActionAttach},
 		{Label: "Attach (popup)", Action: MenuActionAttachPopup},
+		{Label: "Attach (read-only)", Action: MenuActionAttachRO},
 		{Divider: true},
 		{Label: "New window", Action: MenuActionNewWindow},
 		{Label: "Rename...", Action: MenuActionRename},
+		{Label: "Copy target", Shortcut: "y", Action: MenuActionCopyTarget},
 		{Divider: true},
 		{Label: "Kill session", Shortcut: "x", Action: MenuActionKillSession},
 	}
 }
 
-// windowMenuItems returns the menu items for a window context menu
-func windowMenuItems() []MenuItem {
+// windowMenuItems returns the menu items for a window context menu. The
+// synchronize item reflects whether synchronize-panes is currently on.
+func windowMenuItems(synchronized bool) []MenuItem {
+	syncLabel := "Synchronize panes"
+	if synchronized {
+		syncLabel = "Stop synchronizing panes"
+	}
 	return []MenuItem{
 		{Label: "Select window", Action: MenuActionSelectWindow},
 		{Divider: true},
 		{Label: "New pane (horizontal)", Shortcut: "h", Action: MenuActionNewPaneH},
 		{Label: "New pane (vertical)", Shortcut: "v", Action: MenuActionNewPaneV},
+		{Label: "Send input to all panes", Shortcut: "S", Action: MenuActionSendAllPanes},
+		{Label: syncLabel, Action: MenuActionSyncPanes},
+		{Label: "Zoom active pane", Shortcut: "z", Action: MenuActionZoomPane},
 		{Label: "Rename...", Action: MenuActionRename},
-		{Label: "Move to session...", Action: MenuActionMoveWindow, Disabled: true},
+		{Label: "Move to session...", Action: MenuActionMoveWindow},
+		{Label: "Copy target", Shortcut: "y", Action: MenuActionCopyTarget},
 		{Divider: true},
 		{Label: "Kill window", Shortcut: "x", Action: MenuActionKillWindow},
 	}
@@ -134,7 +164,13 @@ func paneMenuItems() []MenuItem {
 		{Label: "Zoom toggle", Shortcut: "z", Action: MenuActionZoomPane},
 		{Divider: true},
 		{Label: "Send keys...", Action: MenuActionSendKeys},
-		{Label: "Swap with...", Action: MenuActionSwapPane, Disabled: true},
+		{Label: "Send file...", Shortcut: "P", Action: MenuActionSendFile},
+		{Label: "Interrupt (C-c)", Shortcut: "^", Action: MenuActionInterrupt},
+		{Label: "Swap with...", Action: MenuActionSwapPane},
+		{Label: "Break to new window", Action: MenuActionBreakPane},
+		{Divider: true},
+		{Label: "Copy target", Shortcut: "y", Action: MenuActionCopyTarget},
+		{Label: "Copy content", Shortcut: "Y", Action: MenuActionCopyContent},
 		{Divider: true},
 		{Label: "Kill pane", Shortcut: "x", Action: MenuActionKillPane},
 	}
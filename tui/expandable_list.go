@@ -225,17 +225,24 @@ func (e *ExpandableList) SelectByIndex(index int) bool {
 
 // Styles for the expandable list footer
 var (
+	expandFooterStyle         lipgloss.Style
+	expandFooterSelectedStyle lipgloss.Style
+	expandFooterDimStyle      lipgloss.Style
+)
+
+// buildExpandFooterStyles builds the expandable list footer styles from the theme colors.
+func buildExpandFooterStyles() {
 	expandFooterStyle = lipgloss.NewStyle().
-				Foreground(primaryColor)
+		Foreground(primaryColor)
 
 	expandFooterSelectedStyle = lipgloss.NewStyle().
-					Foreground(primaryColor).
-					Bold(true).
-					Background(lipgloss.Color("236"))
+		Foreground(primaryColor).
+		Bold(true).
+		Background(selectionBgColor)
 
 	expandFooterDimStyle = lipgloss.NewStyle().
-				Foreground(dimColor)
-)
+		Foreground(dimColor)
+}
 
 // View renders the expandable list.
 func (e *ExpandableList) View(width int) string {
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/porganisciak/agent-tmux/history"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// RememberFocus records target, a window or pane, as the last one focused
+// in its local session, so RestoreFocus can return to it. Best-effort: a
+// history failure never keeps the user from their session.
+func RememberFocus(target string) {
+	session, rest, _ := strings.Cut(target, ":")
+	if rest == "" {
+		return // A bare session has no window or pane to remember
+	}
+	store, err := history.Open()
+	if err != nil {
+		return
+	}
+	defer store.Close()
+	store.SetLastFocus(history.NoteKey{SessionName: session}, target) //nolint:errcheck
+}
+
+// RestoreFocus makes the window or pane last remembered for a local
+// session current in it again, ahead of attaching so the client lands
+// there rather than on tmux's own last window. A target that has since
+// gone is left alone.
+func RestoreFocus(session string) {
+	store, err := history.Open()
+	if err != nil {
+		return
+	}
+	target, err := store.LastFocus(history.NoteKey{SessionName: session})
+	store.Close()
+	if err != nil || target == "" {
+		return
+	}
+	tmux.SelectTarget(target) //nolint:errcheck
+}
@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/porganisciak/agent-tmux/history"
+)
+
+func TestRememberFocusKeepsLastWindowOrPane(t *testing.T) {
+	t.Setenv(history.DBPathEnv, filepath.Join(t.TempDir(), "history.sqlite3"))
+
+	RememberFocus("agent-api:1")
+	RememberFocus("agent-api:2.1")
+	RememberFocus("agent-api") // A bare session doesn't replace it
+
+	store, err := history.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	target, err := store.LastFocus(history.NoteKey{SessionName: "agent-api"})
+	if err != nil || target != "agent-api:2.1" {
+		t.Fatalf("expected agent-api:2.1 remembered, got %q, %v", target, err)
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// historyPickerWidth is the width of the command history picker box.
+const historyPickerWidth = 64
+
+// historyChoice is a past command in the history picker.
+type historyChoice string
+
+func (h historyChoice) ID() string { return string(h) }
+
+// Render shows the command on one line, marking where a multi-line prompt
+// breaks.
+func (h historyChoice) Render(selected bool, width int) string {
+	line := ansi.Truncate(strings.ReplaceAll(string(h), "\n", " ⏎ "), width-2, "...")
+	if selected {
+		return selectedStyle.Render("> " + line)
+	}
+	return "  " + line
+}
+
+// historyPicker fuzzy-searches the commands sent this session, opened with
+// Ctrl+R from the command input.
+type historyPicker struct {
+	input   textinput.Model
+	history []string // Distinct commands, most recent first
+	list    *ExpandableList
+	chosen  string // Command picked, once Enter is pressed on one
+}
+
+// openHistoryPicker opens the picker over the input history.
+func (m Model) openHistoryPicker() (tea.Model, tea.Cmd) {
+	if len(m.inputHistory) == 0 {
+		return m, nil
+	}
+	ti := textinput.New()
+	ti.Placeholder = "Search sent commands..."
+	ti.Width = historyPickerWidth - 8
+	ti.Focus()
+
+	p := &historyPicker{input: ti, list: NewExpandableList(nil)}
+	seen := make(map[string]bool)
+	for i := len(m.inputHistory) - 1; i >= 0; i-- {
+		if entry := m.inputHistory[i]; !seen[entry] {
+			seen[entry] = true
+			p.history = append(p.history, entry)
+		}
+	}
+	p.list.MaxCollapsed = 10
+	p.list.OnSelect = func(item ListItem) { p.chosen = item.ID() }
+	p.filter()
+	m.historyPicker = p
+	return m, textinput.Blink
+}
+
+// filter lists the commands matching the query, best matches first and
+// most recent first among equals.
+func (p *historyPicker) filter() {
+	query := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	type scored struct {
+		entry string
+		score int
+	}
+	var matches []scored
+	for _, entry := range p.history {
+		if score, ok := fuzzyScore(query, strings.ToLower(entry)); ok {
+			matches = append(matches, scored{entry, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+
+	items := make([]ListItem, len(matches))
+	for i, s := range matches {
+		items[i] = historyChoice(s.entry)
+	}
+	p.list.Items = items
+	p.list.SelectedIndex = 0
+}
+
+// handleHistoryPickerKeys handles keys while the history picker is open.
+// Picking a command puts it in the input to edit or send; a multi-line one
+// opens in the editor.
+func (m Model) handleHistoryPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.historyPicker
+	switch msg.String() {
+	case "esc", "ctrl+c", "ctrl+r":
+		m.historyPicker = nil
+		return m, nil
+	case "up", "ctrl+p":
+		p.list.MoveSelection(-1)
+		return m, nil
+	case "down", "ctrl+n":
+		p.list.MoveSelection(1)
+		return m, nil
+	case "enter":
+		p.list.Update(msg)
+		if p.chosen == "" {
+			return m, nil
+		}
+		m.historyPicker = nil
+		m.historyIndex = -1
+		if strings.Contains(p.chosen, "\n") && !m.mobileMode {
+			// The single-line input would flatten the newlines
+			m.commandInput.SetValue("")
+			m.commandArea.SetValue(p.chosen)
+			return m.openCommandEditor()
+		}
+		m.commandInput.SetValue(p.chosen)
+		m.commandInput.CursorEnd()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return m, cmd
+}
+
+// renderHistoryPickerOverlay draws the history picker over base.
+func (m Model) renderHistoryPickerOverlay(base string) string {
+	p := m.historyPicker
+	width := min(historyPickerWidth, max(m.width-8, 30))
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+
+	rows := []string{helpTitleStyle.Render("Command History"), "", p.input.View(), ""}
+	if len(p.list.Items) == 0 {
+		rows = append(rows, dim.Render("No matching commands"))
+	} else {
+		rows = append(rows, p.list.View(width-4))
+	}
+	rows = append(rows, "", dim.Render("[Enter] use  [↑/↓] select  [Esc] close"))
+
+	box := helpOverlayStyle.Width(width).Render(strings.Join(rows, "\n"))
+	x := max((m.width-lipgloss.Width(box))/2, 0)
+	y := max((m.height-lipgloss.Height(box))/3, 0)
+	return placeOverlay(x, y, box, base)
+}
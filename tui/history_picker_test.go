@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func historyPickerModel() Model {
+	m := NewModel(Options{})
+	m.width, m.height = 120, 40
+	m.calculateLayout()
+	m.focusCommandInput()
+	m.inputHistory = []string{"git status", "make deploy", "npm test", "git status", "review\nthe diff"}
+	return m
+}
+
+func pressKeys(t *testing.T, m Model, keys ...tea.KeyMsg) Model {
+	t.Helper()
+	for _, key := range keys {
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func typed(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestHistoryPickerListsDistinctCommandsNewestFirst(t *testing.T) {
+	m := pressKeys(t, historyPickerModel(), tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m.historyPicker == nil {
+		t.Fatal("expected Ctrl+R to open the history picker")
+	}
+	var got []string
+	for _, item := range m.historyPicker.list.Items {
+		got = append(got, item.ID())
+	}
+	want := []string{"review\nthe diff", "git status", "npm test", "make deploy"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "review ⏎ the diff") {
+		t.Fatalf("expected a multi-line command on one row, got:\n%s", view)
+	}
+}
+
+func TestHistoryPickerFiltersAndFillsInput(t *testing.T) {
+	m := pressKeys(t, historyPickerModel(), tea.KeyMsg{Type: tea.KeyCtrlR}, typed("mdp"))
+	if items := m.historyPicker.list.Items; len(items) != 1 || items[0].ID() != "make deploy" {
+		t.Fatalf("expected a fuzzy match on make deploy, got %v", items)
+	}
+
+	m = pressKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.historyPicker != nil || m.commandInput.Value() != "make deploy" {
+		t.Fatalf("expected Enter to put the command in the input, got %q", m.commandInput.Value())
+	}
+}
+
+func TestHistoryPickerOpensMultilineInEditor(t *testing.T) {
+	m := pressKeys(t, historyPickerModel(), tea.KeyMsg{Type: tea.KeyCtrlR}, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.multiline || m.commandArea.Value() != "review\nthe diff" {
+		t.Fatalf("expected the prompt in the editor, got multiline=%v %q", m.multiline, m.commandArea.Value())
+	}
+}
+
+func TestHistoryPickerEscKeepsInput(t *testing.T) {
+	m := historyPickerModel()
+	m.commandInput.SetValue("draft")
+	m = pressKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlR}, typed("git"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.historyPicker != nil || m.commandInput.Value() != "draft" {
+		t.Fatalf("expected Esc to close and keep the draft, got %q", m.commandInput.Value())
+	}
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// hostDownProbeInterval is how often auto-refresh tries a host it has
+// given up on, so a host that comes back shows up without hammering it.
+const hostDownProbeInterval = time.Minute
+
+// hostRetryMsg fires when a host's retry backoff has passed.
+type hostRetryMsg struct {
+	host    string
+	attempt int
+}
+
+// hostRetryCmd waits out delay before retrying host.
+func hostRetryCmd(host string, attempt int, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return hostRetryMsg{host: host, attempt: attempt}
+	})
+}
+
+// trackHostFailure updates a remote host's retry state from a fetch: a
+// transient failure schedules a retry with backoff, and one that keeps
+// failing, or fails permanently (e.g. authentication), marks the host down.
+func (m *Model) trackHostFailure(ht tmux.HostTree) tea.Cmd {
+	host := ht.Host
+	if host == "" {
+		return nil
+	}
+	if ht.Err == nil {
+		delete(m.hostRetries, host)
+		delete(m.hostsDown, host)
+		return nil
+	}
+	if _, down := m.hostsDown[host]; !down && tmux.IsTransientFetchError(ht.Err) && m.hostRetries[host] < tmux.MaxFetchRetries {
+		m.hostRetries[host]++
+		attempt := m.hostRetries[host]
+		return hostRetryCmd(host, attempt, tmux.FetchRetryDelay(attempt))
+	}
+	delete(m.hostRetries, host)
+	m.hostsDown[host] = time.Now()
+	return nil
+}
+
+// retryHost runs a scheduled retry, unless a manual refresh has reset the
+// host's retries since it was scheduled.
+func (m *Model) retryHost(msg hostRetryMsg) tea.Cmd {
+	if m.hostRetries[msg.host] != msg.attempt || m.treeFetching[msg.host] {
+		return nil
+	}
+	for _, exec := range m.executors {
+		if exec.HostLabel() == msg.host {
+			return m.fetchHostCmd(exec)
+		}
+	}
+	return nil
+}
+
+// dueHostProbe reports whether auto-refresh should fetch host: always,
+// unless it is down and was last tried within hostDownProbeInterval.
+func (m *Model) dueHostProbe(host string) bool {
+	downAt, down := m.hostsDown[host]
+	return !down || time.Since(downAt) >= hostDownProbeInterval
+}
+
+// resetHostRetries forgets retry and down state, so a manual refresh
+// fetches every host straight away.
+func (m *Model) resetHostRetries() {
+	clear(m.hostRetries)
+	clear(m.hostsDown)
+}
+
+// hostRetryNote describes a failed host's retry state for its error node.
+func (m *Model) hostRetryNote(host string) string {
+	if n := m.hostRetries[host]; n > 0 {
+		return fmt.Sprintf(" (retrying %d/%d)", n, tmux.MaxFetchRetries)
+	}
+	if _, down := m.hostsDown[host]; down {
+		return " (r to retry)"
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func retryTestModel() Model {
+	return NewModel(Options{
+		Executors:        []tmux.TmuxExecutor{tmux.NewLocalExecutor(), tmux.NewRemoteExecutor("devbox", 0, "", "")},
+		RefreshInterval:  time.Second,
+		DisableTreeCache: true,
+	})
+}
+
+func TestBrowseRetriesTransientHostFailure(t *testing.T) {
+	m := retryTestModel()
+	refused := tmux.HostTree{Host: "devbox", Err: errors.New("ssh: connect to host devbox port 22: Connection refused")}
+
+	for attempt := 1; attempt <= tmux.MaxFetchRetries; attempt++ {
+		m.treeFetching["devbox"] = true
+		updated, cmd := m.Update(HostTreeRefreshedMsg{HostTree: refused})
+		m = updated.(Model)
+		if cmd == nil || m.hostRetries["devbox"] != attempt {
+			t.Fatalf("attempt %d: expected a retry scheduled, got retries=%d", attempt, m.hostRetries["devbox"])
+		}
+		if m.fetchTreeCmd(); m.treeFetching["devbox"] {
+			t.Fatalf("attempt %d: expected auto-refresh to leave a retrying host alone", attempt)
+		}
+
+		// A retry scheduled before a manual refresh is dropped
+		if _, cmd := m.Update(hostRetryMsg{host: "devbox", attempt: attempt - 1}); cmd != nil {
+			t.Fatalf("attempt %d: expected a stale retry to be ignored", attempt)
+		}
+		updated, cmd = m.Update(hostRetryMsg{host: "devbox", attempt: attempt})
+		m = updated.(Model)
+		if cmd == nil || !m.treeFetching["devbox"] {
+			t.Fatalf("attempt %d: expected the retry to fetch devbox", attempt)
+		}
+	}
+
+	// Out of retries: the host is down and auto-refresh stops fetching it
+	updated, _ := m.Update(HostTreeRefreshedMsg{HostTree: refused})
+	m = updated.(Model)
+	if _, down := m.hostsDown["devbox"]; !down || m.hostRetries["devbox"] != 0 {
+		t.Fatalf("expected devbox given up on, got retries=%d down=%v", m.hostRetries["devbox"], m.hostsDown)
+	}
+	if last := m.flatNodes[len(m.flatNodes)-1]; last.Name != "unreachable: ssh: connect to host devbox port 22: Connection refused (r to retry)" {
+		t.Fatalf("expected the error node to offer a manual retry, got %q", last.Name)
+	}
+	if m.fetchTreeCmd(); m.treeFetching["devbox"] {
+		t.Fatal("expected auto-refresh to skip a down host")
+	}
+
+	// r fetches it again straight away
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+	if !m.treeFetching["devbox"] || len(m.hostsDown) != 0 {
+		t.Fatal("expected a manual refresh to retry the down host")
+	}
+}
+
+func TestBrowseDoesNotRetryPermanentHostFailure(t *testing.T) {
+	m := retryTestModel()
+	m.treeFetching["devbox"] = true
+	auth := tmux.HostTree{Host: "devbox", Err: &exec.ExitError{Stderr: []byte("user@devbox: Permission denied (publickey).\n")}}
+
+	updated, _ := m.Update(HostTreeRefreshedMsg{HostTree: auth})
+	m = updated.(Model)
+	if m.hostRetries["devbox"] != 0 {
+		t.Fatal("expected no retry for an authentication failure")
+	}
+	if _, down := m.hostsDown["devbox"]; !down {
+		t.Fatal("expected the host marked down")
+	}
+
+	// Once it answers, it is back to normal
+	m.hostsDown["devbox"] = time.Now().Add(-hostDownProbeInterval)
+	if m.fetchTreeCmd(); !m.treeFetching["devbox"] {
+		t.Fatal("expected a down host to be probed again after the interval")
+	}
+	updated, _ = m.Update(HostTreeRefreshedMsg{HostTree: tmux.HostTree{Host: "devbox", Tree: &tmux.Tree{}}})
+	m = updated.(Model)
+	if len(m.hostsDown) != 0 || len(m.hostRetries) != 0 {
+		t.Fatalf("expected retry state cleared, got down=%v retries=%v", m.hostsDown, m.hostRetries)
+	}
+}
+
+func TestTrackHostFailureCapsRetries(t *testing.T) {
+	m := retryTestModel()
+	refused := tmux.HostTree{Host: "devbox", Err: errors.New("ssh: connect to host devbox port 22: Connection refused")}
+
+	for attempt := 1; attempt <= tmux.MaxFetchRetries; attempt++ {
+		if cmd := m.trackHostFailure(refused); cmd == nil || m.hostRetries["devbox"] != attempt {
+			t.Fatalf("attempt %d: expected a retry scheduled, got retries=%d", attempt, m.hostRetries["devbox"])
+		}
+	}
+	if cmd := m.trackHostFailure(refused); cmd != nil || m.hostRetries["devbox"] != 0 {
+		t.Fatalf("expected no retry past %d, got retries=%d", tmux.MaxFetchRetries, m.hostRetries["devbox"])
+	}
+	if _, down := m.hostsDown["devbox"]; !down {
+		t.Fatal("expected the host marked down once out of retries")
+	}
+
+	// The local host is never retried or marked down
+	if cmd := m.trackHostFailure(tmux.HostTree{Err: errors.New("Connection refused")}); cmd != nil || len(m.hostsDown) != 1 {
+		t.Fatal("expected local failures left alone")
+	}
+}
+
+func TestRetryHostFetchesOnlyTheScheduledHost(t *testing.T) {
+	m := retryTestModel()
+	m.hostRetries["devbox"] = 2
+
+	if cmd := m.retryHost(hostRetryMsg{host: "devbox", attempt: 1}); cmd != nil {
+		t.Fatal("expected a superseded retry to be dropped")
+	}
+	if cmd := m.retryHost(hostRetryMsg{host: "elsewhere", attempt: 0}); cmd != nil {
+		t.Fatal("expected no fetch for a host without an executor")
+	}
+	m.treeFetching["devbox"] = true
+	if cmd := m.retryHost(hostRetryMsg{host: "devbox", attempt: 2}); cmd != nil {
+		t.Fatal("expected no second fetch while one is running")
+	}
+	m.treeFetching["devbox"] = false
+	if cmd := m.retryHost(hostRetryMsg{host: "devbox", attempt: 2}); cmd == nil || !m.treeFetching["devbox"] {
+		t.Fatal("expected the retry to fetch devbox")
+	}
+}
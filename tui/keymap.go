@@ -0,0 +1,160 @@
+package tui
+
+// keyScope identifies which part of browse handles a key.
+type keyScope int
+
+const (
+	scopeGlobal  keyScope = iota // handleKeyMsg, before focus dispatch
+	scopeTree                    // handleTreeKeys
+	scopeRecent                  // handleRecentKeys
+	scopeInput                   // handleInputKeys
+	scopePreview                 // handlePreviewKeys
+)
+
+// keyHelp documents one key binding in browse. The table mirrors the key
+// handlers in update.go; keep it in sync when adding or changing keys.
+type keyHelp struct {
+	keys  string
+	desc  string
+	scope keyScope
+	when  func(m *Model) bool // nil = always available in its scope
+}
+
+func notInInput(m *Model) bool   { return m.focused != FocusInput }
+func inInput(m *Model) bool      { return m.focused == FocusInput }
+func multiHost(m *Model) bool    { return len(m.executors) > 0 }
+func singleHost(m *Model) bool   { return len(m.executors) == 0 }
+func killConfirms(m *Model) bool { return !m.options.SkipKillConfirm }
+func killSkips(m *Model) bool    { return m.options.SkipKillConfirm }
+func hasMarks(m *Model) bool     { return len(m.killMarks) > 0 }
+func inEditor(m *Model) bool     { return m.multiline }
+func singleLine(m *Model) bool   { return !m.multiline }
+func swapping(m *Model) bool     { return m.swapFrom != nil }
+
+// browseKeys lists the browse key bindings in display order.
+var browseKeys = []keyHelp{
+	// Tree
+	{keys: "↑/↓ or j/k", desc: "Navigate tree", scope: scopeTree},
+	{keys: "PgUp/PgDn", desc: "Move selection by a page", scope: scopeTree},
+	{keys: "Home/End", desc: "Jump to first/last item", scope: scopeTree},
+	{keys: "1-9", desc: "Jump to numbered node (type digits quickly for 10+)", scope: scopeTree},
+	{keys: "[ / ]", desc: "Jump to previous/next host", scope: scopeTree, when: multiHost},
+	{keys: "Enter/Space", desc: "Expand/collapse node", scope: scopeTree, when: singleHost},
+	{keys: "Enter/Space", desc: "Expand/collapse host, session, or window", scope: scopeTree, when: multiHost},
+	{keys: "+ / -", desc: "Expand all / collapse to sessions", scope: scopeTree, when: singleHost},
+	{keys: "+ / -", desc: "Expand all / collapse to hosts", scope: scopeTree, when: multiHost},
+	{keys: "a", desc: "Attach to selected session, window, or pane", scope: scopeTree},
+	{keys: "s", desc: "Send command input to selected pane", scope: scopeTree},
+	{keys: "S", desc: "Send command input to all panes in window", scope: scopeTree},
+	{keys: "P", desc: "Paste a file's contents into selected pane", scope: scopeTree},
+	{keys: "^", desc: "Interrupt selected pane (send C-c)", scope: scopeTree},
+	{keys: "x or d", desc: "Kill selected session/window/pane", scope: scopeTree, when: killConfirms},
+	{keys: "x or d", desc: "Kill selected item (no confirmation)", scope: scopeTree, when: killSkips},
+	{keys: "v", desc: "Mark/unmark item for bulk kill", scope: scopeTree},
+	{keys: "`", desc: "Set/clear the tmux marked pane", scope: scopeTree},
+	{keys: "'", desc: "Jump to the marked pane", scope: scopeTree, when: func(m *Model) bool { return m.markedPane != nil }},
+	{keys: "V", desc: "Mark inactive sibling windows/panes", scope: scopeTree},
+	{keys: "x or d", desc: "Kill all marked items (with confirmation)", scope: scopeTree, when: hasMarks},
+	{keys: "c", desc: "Show context menu", scope: scopeTree},
+	{keys: "n", desc: "New session for current directory", scope: scopeTree, when: func(m *Model) bool {
+		return m.options.SessionName != "" && m.options.WorkingDir != ""
+	}},
+	{keys: "w", desc: "Watch pane, notify when it goes idle", scope: scopeTree},
+	{keys: "z", desc: "Zoom/unzoom selected pane (or a window's active pane)", scope: scopeTree},
+	{keys: "A", desc: "Show only agent panes", scope: scopeTree, when: func(m *Model) bool { return !m.agentsOnly }},
+	{keys: "A", desc: "Show the whole tree again", scope: scopeTree, when: func(m *Model) bool { return m.agentsOnly }},
+	{keys: "f", desc: "Search pane contents", scope: scopeTree, when: singleHost},
+	{keys: "f", desc: "Search pane contents (all hosts)", scope: scopeTree, when: multiHost},
+	{keys: "y / Y", desc: "Copy target / pane content to clipboard", scope: scopeTree},
+	{keys: "i", desc: "Show/hide pane sizes", scope: scopeTree},
+	{keys: "< / >", desc: "Shrink/grow the tree panel", scope: scopeTree},
+	{keys: "Enter", desc: "Swap the marked pane with the selected pane", scope: scopeTree, when: swapping},
+	{keys: "Esc", desc: "Cancel the pane swap", scope: scopeTree, when: swapping},
+	{keys: "Esc", desc: "Clear bulk kill marks", scope: scopeTree, when: hasMarks},
+	{keys: "Esc", desc: "Clear tree filter", scope: scopeTree, when: func(m *Model) bool {
+		return m.treeFilterQuery() != ""
+	}},
+
+	// Recent sessions
+	{keys: "↑/↓ or j/k", desc: "Navigate recent sessions", scope: scopeRecent},
+	{keys: "Enter or a", desc: "Revive selected recent session", scope: scopeRecent},
+	{keys: "x or d", desc: "Remove entry from history", scope: scopeRecent},
+
+	// Command input
+	{keys: "Enter", desc: "Send command to selected pane", scope: scopeInput, when: singleLine},
+	{keys: "↑/↓", desc: "Recall previous commands", scope: scopeInput, when: singleLine},
+	{keys: "Ctrl+R", desc: "Search previous commands", scope: scopeInput, when: singleLine},
+	{keys: "Ctrl+O", desc: "Expand into a multi-line editor", scope: scopeInput, when: singleLine},
+	{keys: "Enter", desc: "Insert a newline", scope: scopeInput, when: inEditor},
+	{keys: "Ctrl+S", desc: "Send as one paste to selected pane", scope: scopeInput, when: inEditor},
+	{keys: "Esc", desc: "Close editor (keeps the draft)", scope: scopeInput, when: inEditor},
+
+	// Preview
+	{keys: "↑/↓ or j/k", desc: "Scroll preview", scope: scopePreview},
+	{keys: "PgUp/PgDn", desc: "Scroll preview by page", scope: scopePreview},
+	{keys: "z", desc: "Zoom preview to full screen", scope: scopePreview, when: func(m *Model) bool {
+		return !m.previewZoomed
+	}},
+	{keys: "z or Esc", desc: "Restore split layout", scope: scopePreview, when: func(m *Model) bool {
+		return m.previewZoomed
+	}},
+
+	// Global
+	{keys: "Ctrl+P", desc: "Command palette (all actions)", scope: scopeGlobal},
+	{keys: "Tab/Shift+Tab", desc: "Cycle focus (Tree → Input → Preview)", scope: scopeGlobal},
+	{keys: "/", desc: "Filter tree by name or target", scope: scopeGlobal, when: notInInput},
+	{keys: "r", desc: "Refresh tree", scope: scopeGlobal, when: notInInput},
+	{keys: "g then l / s", desc: "Switch to landing / sessions, keeping the selected session", scope: scopeGlobal, when: notInInput},
+	{keys: "M", desc: "Toggle mouse support", scope: scopeGlobal, when: notInInput},
+	{keys: "m", desc: "Cycle send method (debug)", scope: scopeGlobal, when: func(m *Model) bool {
+		return m.options.DebugMode && m.focused != FocusInput
+	}},
+	{keys: "Esc", desc: "Clear input, then return to tree", scope: scopeGlobal, when: func(m *Model) bool {
+		return m.focused == FocusInput && !m.multiline
+	}},
+	{keys: "Esc or q", desc: "Quit", scope: scopeGlobal, when: notInInput},
+	{keys: "Ctrl+C", desc: "Press twice to quit", scope: scopeGlobal},
+	{keys: "?", desc: "Toggle this help", scope: scopeGlobal},
+}
+
+// focusScope returns the key scope that handles keys for the current focus.
+func (m *Model) focusScope() keyScope {
+	switch m.focused {
+	case FocusInput:
+		return scopeInput
+	case FocusPreview:
+		return scopePreview
+	}
+	if m.focusRecent {
+		return scopeRecent
+	}
+	return scopeTree
+}
+
+// activeKeys returns the bindings in scope that are currently available.
+func (m *Model) activeKeys(scope keyScope) []keyHelp {
+	var keys []keyHelp
+	for _, k := range browseKeys {
+		if k.scope != scope {
+			continue
+		}
+		if k.when != nil && !k.when(m) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// focusScopeTitle names a focus scope for the help overlay.
+func focusScopeTitle(scope keyScope) string {
+	switch scope {
+	case scopeRecent:
+		return "Recent Sessions"
+	case scopeInput:
+		return "Command Input"
+	case scopePreview:
+		return "Preview"
+	}
+	return "Tree"
+}
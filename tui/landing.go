@@ -14,21 +14,28 @@ import (
 
 // LandingResult contains the outcome of the landing page interaction
 type LandingResult struct {
-	Action     string // "resume", "attach", "revive", or "" (quit)
-	Target     string // Session name for attach
-	WorkingDir string // Working directory for revive
-	Changed    bool   // Whether settings were changed
+	Action     string        // "resume", "attach", "revive", or "" (quit)
+	Target     string        // Session name for attach
+	WorkingDir string        // Working directory for revive
+	Changed    bool          // Whether settings were changed
+	Template   string        // Session template chosen for "resume" ("" = default layout)
+	Switch     *ScreenSwitch // Another screen to open instead (nil unless asked for)
 }
 
 // LandingOptions configures the landing page behavior
 type LandingOptions struct {
-	SessionName string // Session name derived from current directory
-	AltScreen   bool   // Whether to use alternate screen
+	SessionName string   // Session name derived from current directory
+	AltScreen   bool     // Whether to use alternate screen
+	Templates   []string // Session template names offered when starting a new session
+	Select      string   // Session to select once listed, e.g. carried over from browse
 }
 
 // RunLanding runs the landing page TUI and returns the user's selection
 func RunLanding(opts LandingOptions) (*LandingResult, error) {
+	loadTheme()
 	m := newLandingModel(opts.SessionName)
+	m.templates = opts.Templates
+	m.selectSession = opts.Select
 	programOptions := []tea.ProgramOption{
 		tea.WithMouseCellMotion(),
 	}
@@ -46,6 +53,8 @@ func RunLanding(opts LandingOptions) (*LandingResult, error) {
 			Target:     model.attachSession,
 			WorkingDir: model.reviveDir,
 			Changed:    model.settingsChanged,
+			Template:   model.template,
+			Switch:     model.switchTo,
 		}, nil
 	}
 	return &LandingResult{}, nil
@@ -81,6 +90,7 @@ type landingModel struct {
 	sessions        []tmux.SessionLine // All existing sessions
 	recentSessions  []history.Entry    // Recent sessions from history
 	recentExpanded  bool               // Whether recent section is expanded
+	missingDirs     map[int64]bool     // Recent entry IDs whose directory no longer exists
 	selectedIndex   int                // Selection within current section
 	focusedSection  int                // 0=resume, 1=sessions, 2=recent, 3=options
 	options         [3]bool            // Checkbox states [resume, sessions, landing]
@@ -91,17 +101,32 @@ type landingModel struct {
 	action          string // "resume", "attach", "revive", or ""
 	lastError       error
 	historyError    error
+	sessionsLoaded  bool
+	historyLoaded   bool
 	settingsChanged bool
 	clickZones      []clickZone // Clickable areas calculated during render
 	confirmKill     bool        // Whether kill confirmation is active
 	killSessionName string      // Session name pending kill confirmation
 	lineJump        lineJumpState
 
+	// Session templates
+	templates      []string        // Template names from config
+	templatePicker *templatePicker // Template choice for a new session, nil if not showing
+	template       string          // Chosen template ("" = default layout)
+
 	// Staleness
 	stalenessDisabled bool
 	freshThreshold    time.Duration
 	staleThreshold    time.Duration
 
+	absoluteTimes bool // Show last-used timestamps instead of "3h ago"
+
+	// g then l/s/b switches screens; selectSession is a session carried
+	// over from the last screen, selected once the list loads
+	screenJump    screenJump
+	switchTo      *ScreenSwitch
+	selectSession string
+
 	// Section visibility (computed from window height)
 	showRecent  bool
 	showOptions bool
@@ -149,6 +174,7 @@ func newLandingModel(sessionName string) landingModel {
 		stalenessDisabled: stalenessDisabled,
 		freshThreshold:    freshThreshold,
 		staleThreshold:    staleThreshold,
+		absoluteTimes:     settings.AbsoluteTimes,
 		showRecent:        true, // recomputed on WindowSizeMsg
 		showOptions:       true, // recomputed on WindowSizeMsg
 	}
@@ -194,23 +220,68 @@ func (m landingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Handle template choice for a new session if active
+	if m.templatePicker != nil {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			done, cancelled := m.templatePicker.handleKey(msg.String())
+			if cancelled {
+				m.templatePicker = nil
+			} else if done {
+				m.template = m.templatePicker.choice()
+				m.templatePicker = nil
+				m.action = "resume"
+				m.attachSession = m.sessionName
+				return m, tea.Quit
+			}
+			return m, nil
+		case tea.MouseMsg:
+			if msg.Action == tea.MouseActionPress {
+				m.templatePicker = nil
+			}
+			return m, nil
+		}
+	}
+
 	switch msg := msg.(type) {
 	case executorSessionsMsg:
 		m.sessions = msg.lines
 		m.lastError = msg.err
+		m.sessionsLoaded = true
 		m.filterRecentSessions()
+		m.selectCarriedSession()
 		m.updateVisibility()
 		m.calculateClickZones()
 		return m, nil
 
 	case landingHistoryLoadedMsg:
 		m.historyError = msg.err
+		m.historyLoaded = true
 		if msg.err == nil {
 			m.recentSessions = msg.entries
 			m.filterRecentSessions()
 		}
 		m.updateVisibility()
 		m.calculateClickZones()
+		return m, checkMissingDirs(msg.entries)
+
+	case missingDirsMsg:
+		m.missingDirs = msg.missing
+		return m, nil
+
+	case missingDirsDeletedMsg:
+		if msg.err != nil {
+			m.historyError = msg.err
+		}
+		m.recentSessions = removeHistoryEntries(m.recentSessions, msg.ids)
+		for _, id := range msg.ids {
+			delete(m.missingDirs, id)
+		}
+		if m.focusedSection == sectionRecent && m.selectedIndex >= m.visibleRecentCount() {
+			m.selectedIndex = max(0, m.visibleRecentCount()-1)
+		}
+		m.updateVisibility()
+		m.calculateClickZones()
 		return m, nil
 
 	case landingKillMsg:
@@ -373,6 +444,16 @@ func (m landingModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.selectedIndex = idx
 		return m, nil
 	}
+	if to, ok := m.screenJump.consume(msg.String(), ScreenLanding); ok {
+		if to == "" {
+			return m, nil
+		}
+		m.switchTo = &ScreenSwitch{To: to}
+		if m.focusedSection == sectionSessions && m.selectedIndex < len(m.sessions) {
+			m.switchTo.Session = m.sessions[m.selectedIndex].Name
+		}
+		return m, tea.Quit
+	}
 
 	switch msg.String() {
 	case "q", "esc", "ctrl+c":
@@ -435,6 +516,21 @@ func (m landingModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m, nil
+
+	case "X":
+		// Remove every recent entry whose directory is gone
+		if ids := missingEntryIDs(m.recentSessions, m.missingDirs); len(ids) > 0 {
+			return m, deleteHistoryEntries(ids)
+		}
+		return m, nil
+
+	case "t":
+		// Switch recent sessions between "3h ago" and timestamps
+		m.absoluteTimes = !m.absoluteTimes
+		if err := saveAbsoluteTimes(m.absoluteTimes); err != nil {
+			m.lastError = err
+		}
+		return m, nil
 	}
 	return m, nil
 }
@@ -584,11 +680,13 @@ func (m landingModel) moveDown() (tea.Model, tea.Cmd) {
 func (m landingModel) handleEnter() (tea.Model, tea.Cmd) {
 	switch m.focusedSection {
 	case sectionResume:
-		m.action = "resume"
-		m.attachSession = m.sessionName
-		return m, tea.Quit
+		return m.startResume()
 
 	case sectionSessions:
+		if m.showEmptyState() {
+			// The empty state's call to action stands in for the list
+			return m.startResume()
+		}
 		if m.selectedIndex >= 0 && m.selectedIndex < len(m.sessions) {
 			m.action = "attach"
 			m.attachSession = m.sessions[m.selectedIndex].Name
@@ -626,6 +724,28 @@ func (m landingModel) handleEnter() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// startResume starts or resumes the session for the current directory. When
+// the session doesn't exist yet and templates are configured, it first asks
+// which template to create it with.
+func (m landingModel) startResume() (tea.Model, tea.Cmd) {
+	exists := false
+	for _, s := range m.sessions {
+		if s.Name == m.sessionName {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		if picker := newTemplatePicker(m.templates); picker != nil {
+			m.templatePicker = picker
+			return m, nil
+		}
+	}
+	m.action = "resume"
+	m.attachSession = m.sessionName
+	return m, tea.Quit
+}
+
 func (m landingModel) toggleOption(index int) (tea.Model, tea.Cmd) {
 	// Options are mutually exclusive
 	for i := range m.options {
@@ -634,8 +754,11 @@ func (m landingModel) toggleOption(index int) (tea.Model, tea.Cmd) {
 	m.options[index] = true
 	m.settingsChanged = true
 
-	// Save settings
-	settings := &config.Settings{}
+	// Save settings, keeping the other preferences in settings.json
+	settings, err := config.LoadSettings()
+	if err != nil {
+		settings = config.DefaultSettings()
+	}
 	switch index {
 	case optionResume:
 		settings.DefaultAction = "resume"
@@ -664,9 +787,7 @@ func (m landingModel) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			switch zone.section {
 			case sectionResume:
 				m.selectedIndex = 0
-				m.action = "resume"
-				m.attachSession = m.sessionName
-				return m, tea.Quit
+				return m.startResume()
 
 			case sectionSessions:
 				if zone.index >= 0 && zone.index < len(m.sessions) {
@@ -741,8 +862,11 @@ func (m landingModel) View() string {
 		sections = append(sections, m.renderStatusBar())
 	}
 
-	result := lipgloss.JoinVertical(lipgloss.Left, sections...)
-	return truncateToHeight(result, m.height)
+	result := truncateToHeight(lipgloss.JoinVertical(lipgloss.Left, sections...), m.height)
+	if m.templatePicker != nil {
+		return m.templatePicker.render(result, m.width, m.height)
+	}
+	return result
 }
 
 // calculateClickZones updates the click zones based on current layout
@@ -768,6 +892,16 @@ func (m *landingModel) calculateClickZones() {
 		sessionItems = 1 // "No active sessions" line
 	}
 	sessionListStart := currentY + 3 // border + header + divider
+	if m.showEmptyState() {
+		// The call-to-action line starts a session like the resume box
+		sessionItems = len(m.renderEmptyStateRows())
+		m.clickZones = append(m.clickZones, clickZone{
+			y1:      sessionListStart + 1,
+			y2:      sessionListStart + 2,
+			section: sectionResume,
+			index:   -1,
+		})
+	}
 	for i := 0; i < len(m.sessions); i++ {
 		m.clickZones = append(m.clickZones, clickZone{
 			y1:      sessionListStart + i,
@@ -872,6 +1006,9 @@ func (m landingModel) renderSessionsSection() string {
 		Foreground(secondaryColor)
 
 	header := headerStyle.Render("Attach active session")
+	if m.showEmptyState() {
+		header = headerStyle.Render("Get started")
+	}
 	divider := lipgloss.NewStyle().Foreground(dimColor).Render(strings.Repeat("─", 12))
 
 	var rows []string
@@ -879,7 +1016,9 @@ func (m landingModel) renderSessionsSection() string {
 	rows = append(rows, divider)
 	numberWidth := len(fmt.Sprintf("%d", max(1, len(m.sessions))))
 
-	if m.lastError != nil {
+	if m.showEmptyState() {
+		rows = append(rows, m.renderEmptyStateRows()...)
+	} else if m.lastError != nil {
 		errStyle := lipgloss.NewStyle().Foreground(errorColor)
 		rows = append(rows, errStyle.Render("  Error: "+m.lastError.Error()))
 	} else if len(m.sessions) == 0 {
@@ -935,12 +1074,38 @@ func (m landingModel) renderSessionsSection() string {
 	return boxStyle.Render(content)
 }
 
+// showEmptyState reports whether there is nothing to list yet: no active
+// sessions and no history, both loaded without error. New users land here.
+func (m landingModel) showEmptyState() bool {
+	return m.sessionsLoaded && m.historyLoaded &&
+		m.lastError == nil && m.historyError == nil &&
+		len(m.sessions) == 0 && len(m.recentSessions) == 0
+}
+
+// renderEmptyStateRows renders the first-run guidance shown in place of the
+// session list: the resume action as a call to action (the second row, which
+// calculateClickZones makes clickable) and a pointer to atmux init.
+func (m landingModel) renderEmptyStateRows() []string {
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+	action := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+	code := lipgloss.NewStyle().Foreground(secondaryColor)
+	return []string{
+		dim.Render("  No sessions yet."),
+		action.Render("  → Press Enter to start ") +
+			formatSessionName(m.sessionName, action) +
+			action.Render(" in this directory"),
+		dim.Render("  Run ") + code.Render("atmux init") +
+			dim.Render(" to configure windows for this project"),
+	}
+}
+
 func (m landingModel) renderRecentSection() string {
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(secondaryColor)
 
-	header := headerStyle.Render("Recent Sessions")
+	header := headerStyle.Render("Recent Sessions") +
+		missingCleanupHint(len(missingEntryIDs(m.recentSessions, m.missingDirs)))
 	divider := lipgloss.NewStyle().Foreground(dimColor).Render(strings.Repeat("─", 12))
 
 	var rows []string
@@ -967,6 +1132,9 @@ func (m landingModel) renderRecentSection() string {
 			// Format: session name (time ago) directory
 			formattedName := formatSessionName(entry.Name, nameStyle)
 			ago := landingTimeAgo(entry.LastUsedAt)
+			if m.absoluteTimes {
+				ago = formatLastUsed(entry.LastUsedAt)
+			}
 			var metaColor lipgloss.Color
 			if m.stalenessDisabled {
 				metaColor = dimColor
@@ -975,6 +1143,9 @@ func (m landingModel) renderRecentSection() string {
 			}
 			meta := lipgloss.NewStyle().Foreground(metaColor).Render(" (" + ago + ")")
 			dir := lipgloss.NewStyle().Foreground(dimColor).Render("  " + entry.WorkingDirectory)
+			if m.missingDirs[entry.ID] {
+				dir += missingTag()
+			}
 
 			rows = append(rows, prefixStyle.Render(prefix)+formattedName+meta+dir)
 		}
@@ -997,7 +1168,7 @@ func (m landingModel) renderRecentSection() string {
 			iconStyled := lipgloss.NewStyle().Foreground(primaryColor).Render(icon)
 			style := lipgloss.NewStyle().Foreground(dimColor)
 			if footerSelected {
-				style = style.Bold(true).Background(lipgloss.Color("236"))
+				style = style.Bold(true).Background(selectionBgColor)
 			}
 
 			rows = append(rows, "  "+iconStyled+" "+style.Render(footerText))
@@ -1096,12 +1267,12 @@ func (m landingModel) renderStatusBar() string {
 		}
 	case sectionRecent:
 		if len(m.recentSessions) > 0 {
-			hints = append(hints, "x remove")
+			hints = append(hints, "x remove", "t times")
 		}
 	}
 
 	hintStyle := lipgloss.NewStyle().Foreground(dimColor)
-	separator := lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(" │ ")
+	separator := lipgloss.NewStyle().Foreground(dimColor).Render(" │ ")
 
 	var styledHints []string
 	for _, hint := range hints {
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/history"
+)
+
+func loadedLandingModel(entries []history.Entry) landingModel {
+	m := newLandingModel("agent-new")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	updated, _ = updated.(landingModel).Update(executorSessionsMsg{})
+	updated, _ = updated.(landingModel).Update(landingHistoryLoadedMsg{entries: entries})
+	return updated.(landingModel)
+}
+
+func TestLandingEmptyStateGuidesNewUsers(t *testing.T) {
+	m := loadedLandingModel(nil)
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"Get started", "Press Enter to start agent-new", "atmux init"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the empty state, got:\n%s", want, view)
+		}
+	}
+
+	// Enter starts the session from the sessions section too
+	m.focusedSection = sectionSessions
+	updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := updated.(landingModel); got.action != "resume" || got.attachSession != "agent-new" {
+		t.Fatalf("expected Enter to start agent-new, got action %q", got.action)
+	}
+}
+
+func TestLandingEmptyStateCallToActionIsClickable(t *testing.T) {
+	m := loadedLandingModel(nil)
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	row := -1
+	for i, line := range lines {
+		if strings.Contains(line, "Press Enter to start") {
+			row = i
+		}
+	}
+	updated, _ := m.handleMouseMsg(tea.MouseMsg{Y: row, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if got := updated.(landingModel); got.action != "resume" {
+		t.Fatalf("expected clicking row %d to start the session, got action %q", row, got.action)
+	}
+}
+
+func TestLandingEmptyStateHiddenWithHistory(t *testing.T) {
+	m := loadedLandingModel([]history.Entry{{ID: 1, Name: "old", SessionName: "agent-old", WorkingDirectory: "/tmp"}})
+	if view := ansi.Strip(m.View()); strings.Contains(view, "atmux init") {
+		t.Fatalf("expected no empty state once there is history, got:\n%s", view)
+	}
+}
+
+func TestLandingTimeToggleShowsTimestamps(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	used := time.Now().Add(-3 * time.Hour)
+	m := loadedLandingModel([]history.Entry{{ID: 1, Name: "old", SessionName: "agent-old", WorkingDirectory: "/tmp", LastUsedAt: used}})
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "(3h ago)") {
+		t.Fatalf("expected a relative time, got:\n%s", view)
+	}
+
+	updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = updated.(landingModel)
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "("+used.Format("2006-01-02 15:04")+")") {
+		t.Fatalf("expected a timestamp after pressing t, got:\n%s", view)
+	}
+	if settings, err := config.LoadSettings(); err != nil || !settings.AbsoluteTimes {
+		t.Fatalf("expected the choice to be saved, got %+v (err %v)", settings, err)
+	}
+	if !newLandingModel("agent-new").absoluteTimes || !newSessionsModel(nil, false, false).absoluteTimes {
+		t.Fatal("expected the sessions list and landing page to pick up the saved choice")
+	}
+}
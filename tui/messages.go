@@ -14,6 +14,13 @@ type TreeRefreshedMsg struct {
 // MultiTreeRefreshedMsg is sent when multi-executor tree data is fetched
 type MultiTreeRefreshedMsg struct {
 	HostTrees []tmux.HostTree
+	Cached    bool // True when loaded from the on-disk cache rather than fetched live
+}
+
+// HostTreeRefreshedMsg is sent when one host's live tree is fetched, so
+// fast hosts render while slower ones are still answering or timing out.
+type HostTreeRefreshedMsg struct {
+	HostTree tmux.HostTree
 }
 
 // PreviewUpdatedMsg is sent when pane preview is captured
@@ -27,12 +34,16 @@ type PreviewUpdatedMsg struct {
 type CommandSentMsg struct {
 	Target  string
 	Command string
+	Panes   []string // Panes the command was typed into (none for tmux actions)
 	Err     error
 }
 
 // TickMsg for auto-refresh
 type TickMsg struct{}
 
+// PreviewTickMsg for the preview's own refresh timer
+type PreviewTickMsg struct{}
+
 // AttachMsg is sent after attempting to switch to a target
 type AttachMsg struct {
 	Target string
@@ -51,12 +62,32 @@ type KillCompletedMsg struct {
 	Err      error
 }
 
+// BulkKillCompletedMsg is sent after the marked targets have been killed.
+// Err joins the failures; targets that did die are gone either way.
+type BulkKillCompletedMsg struct {
+	Count int
+	Err   error
+}
+
 // RecentSessionsMsg is sent when recent history entries are loaded
 type RecentSessionsMsg struct {
 	Entries []history.Entry
 	Err     error
 }
 
+// PaneSearchResultsMsg is sent when a pane content search completes
+type PaneSearchResultsMsg struct {
+	Query   string
+	Matches []tmux.PaneMatch
+}
+
+// ClipboardCopiedMsg is sent after copying a target or pane content to the clipboard
+type ClipboardCopiedMsg struct {
+	What   string // Description of what was copied
+	Method string // Clipboard tool used
+	Err    error
+}
+
 // RecentDeletedMsg is sent after deleting a recent history entry
 type RecentDeletedMsg struct {
 	ID  int64
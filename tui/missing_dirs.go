@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/history"
+)
+
+// missingDirsMsg reports which history entries point at directories that no
+// longer exist.
+type missingDirsMsg struct {
+	missing map[int64]bool // Entry IDs with a missing working directory
+}
+
+// missingDirsDeletedMsg is returned after removing entries with missing directories.
+type missingDirsDeletedMsg struct {
+	ids []int64
+	err error
+}
+
+// checkMissingDirs stats each local entry's working directory in the
+// background, so slow network mounts don't block the UI. Remote entries are
+// skipped since their directories live on another host.
+func checkMissingDirs(entries []history.Entry) tea.Cmd {
+	if len(entries) == 0 {
+		return nil
+	}
+	// Copy so the command doesn't share the model's slice
+	entries = append([]history.Entry(nil), entries...)
+	return func() tea.Msg {
+		missing := make(map[int64]bool)
+		for _, e := range entries {
+			if e.Host != "" || e.WorkingDirectory == "" {
+				continue
+			}
+			// Only a definite "not found" counts; permission errors or
+			// unreachable mounts leave the entry alone.
+			if _, err := os.Stat(e.WorkingDirectory); errors.Is(err, os.ErrNotExist) {
+				missing[e.ID] = true
+			}
+		}
+		return missingDirsMsg{missing: missing}
+	}
+}
+
+// deleteHistoryEntries removes the given history entries.
+func deleteHistoryEntries(ids []int64) tea.Cmd {
+	return func() tea.Msg {
+		store, err := history.Open()
+		if err != nil {
+			return missingDirsDeletedMsg{err: err}
+		}
+		defer store.Close()
+		var deleted []int64
+		for _, id := range ids {
+			if err := store.DeleteEntry(id); err != nil {
+				return missingDirsDeletedMsg{ids: deleted, err: fmt.Errorf("failed to remove history entry: %w", err)}
+			}
+			deleted = append(deleted, id)
+		}
+		return missingDirsDeletedMsg{ids: deleted}
+	}
+}
+
+// missingEntryIDs returns the IDs of entries marked missing, in list order.
+func missingEntryIDs(entries []history.Entry, missing map[int64]bool) []int64 {
+	var ids []int64
+	for _, e := range entries {
+		if missing[e.ID] {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+// removeHistoryEntries returns entries without the given IDs.
+func removeHistoryEntries(entries []history.Entry, ids []int64) []history.Entry {
+	for _, id := range ids {
+		entries = removeHistoryEntry(entries, id)
+	}
+	return entries
+}
+
+// missingTag renders the marker shown after entries whose directory is gone.
+func missingTag() string {
+	return lipgloss.NewStyle().Foreground(dimColor).Render(" (missing)")
+}
+
+// missingCleanupHint renders the header note offering to remove missing entries.
+func missingCleanupHint(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimColor).Render(
+		fmt.Sprintf("  %d missing — press X to remove", count))
+}
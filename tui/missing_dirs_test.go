@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/history"
+)
+
+func TestCheckMissingDirsSkipsRemoteAndExistingEntries(t *testing.T) {
+	existing := t.TempDir()
+	gone := filepath.Join(existing, "deleted")
+	entries := []history.Entry{
+		{ID: 1, WorkingDirectory: existing},
+		{ID: 2, WorkingDirectory: gone},
+		{ID: 3, WorkingDirectory: gone, Host: "devbox"},
+	}
+
+	msg := checkMissingDirs(entries)().(missingDirsMsg)
+	if len(msg.missing) != 1 || !msg.missing[2] {
+		t.Fatalf("expected only entry 2 to be missing, got %v", msg.missing)
+	}
+}
+
+func TestSessionsMarksMissingDirsAndRefusesRevive(t *testing.T) {
+	m := sessionsModel{width: 120, height: 40, stalenessDisabled: true}
+	m.historyEntries = []history.Entry{
+		{ID: 1, Name: "kept", SessionName: "agent-kept", WorkingDirectory: "/work/kept", LastUsedAt: time.Now()},
+		{ID: 2, Name: "gone", SessionName: "agent-gone", WorkingDirectory: "/work/gone", LastUsedAt: time.Now()},
+	}
+	updated, _ := m.Update(missingDirsMsg{missing: map[int64]bool{2: true}})
+	m = updated.(sessionsModel)
+
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "/work/gone (missing)") || strings.Contains(view, "/work/kept (missing)") {
+		t.Fatalf("expected only the deleted directory to be tagged:\n%s", view)
+	}
+	if !strings.Contains(view, "1 missing") {
+		t.Fatalf("expected cleanup hint in the Recent header:\n%s", view)
+	}
+
+	m.selectedIndex = 1
+	updated, cmd := m.selectCurrent()
+	m = updated.(sessionsModel)
+	if cmd != nil || m.reviveDir != "" || m.lastError == nil {
+		t.Fatal("expected revive of a missing directory to be refused with an error")
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	if cmd == nil {
+		t.Fatal("expected X to remove missing entries")
+	}
+	updated, _ = m.Update(missingDirsDeletedMsg{ids: []int64{2}})
+	m = updated.(sessionsModel)
+	if len(m.historyEntries) != 1 || m.historyEntries[0].ID != 1 {
+		t.Fatalf("expected only the existing entry to remain, got %+v", m.historyEntries)
+	}
+}
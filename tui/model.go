@@ -1,13 +1,18 @@
 package tui
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/config"
 	"github.com/porganisciak/agent-tmux/history"
 	"github.com/porganisciak/agent-tmux/tmux"
 )
@@ -22,24 +27,53 @@ const (
 )
 
 const (
-	buttonActionSend       = "send"
-	buttonActionEscape     = "escape"
-	buttonActionAttach     = "attach"
-	buttonActionHelp       = "help"
-	buttonActionRefresh    = "refresh"
-	buttonActionKillHint   = "killhint"
-	buttonActionFocusInput = "focusinput"
+	buttonActionSend      = "send"
+	buttonActionEscape    = "escape"
+	buttonActionInterrupt = "interrupt"
+	buttonActionAttach    = "attach"
+	buttonActionHelp      = "help"
+	buttonActionRefresh   = "refresh"
+	buttonActionKillHint  = "killhint"
+	buttonActionFilter    = "filter"
 )
 
 const doubleClickThreshold = 400 * time.Millisecond
 
 // Options for initializing the TUI
 type Options struct {
-	RefreshInterval time.Duration
-	PopupMode       bool
-	DebugMode       bool
-	MobileMode      bool // Force mobile layout (auto-detected if width < 60)
-	Executors       []tmux.TmuxExecutor // Executors for multi-host browsing (nil = local only)
+	RefreshInterval  time.Duration
+	PopupMode        bool
+	DebugMode        bool
+	MobileMode       bool                 // Force mobile layout (auto-detected if width < MobileWidth)
+	MobileWidth      int                  // Width below which the mobile layout is used (0 = default)
+	Executors        []tmux.TmuxExecutor  // Executors for multi-host browsing (nil = local only)
+	SessionName      string               // Session name derived from current directory (for "new session here")
+	WorkingDir       string               // Current working directory
+	TreeCacheTTL     time.Duration        // Age after which cached host trees are marked stale (0 = default)
+	DisableTreeCache bool                 // Skip showing and saving cached host trees
+	SkipKillConfirm  bool                 // Kill without confirmation (attached sessions still confirm)
+	SkipShellConfirm bool                 // Send to shell panes without confirmation
+	NewInPaneDir     bool                 // Start new windows/panes in the current pane's directory
+	Templates        []string             // Session template names offered for "new session here"
+	TreeWidthPercent int                  // Tree share of the window width (0 = default)
+	Layout           config.BrowseLayout  // Tree beside or above the preview (empty = auto)
+	AfterSend        config.AfterSend     // Whether to clear the command input after sending (empty = keep)
+	StatusClock      bool                 // Show the time at the right of the status bar
+	StatusCounts     bool                 // Show session/window/pane counts at the right of the status bar
+	Agents           []config.AgentConfig // Agents shown by the agents view (nil = default agents)
+	WatchIdleAfter   time.Duration        // Quiet time before a watched pane counts as idle (0 = default)
+	WatchNotify      bool                 // Also send a desktop notification when a watched pane goes idle
+	RestoreFocus     bool                 // Remember the window/pane attached to and return to it on resume
+	NoHistory        bool                 // Don't save sessions revived from browse to history (--no-history)
+
+	// How often the previewed pane is re-captured on its own timer
+	// (0 = with each tree refresh)
+	PreviewRefreshInterval time.Duration
+
+	// Session to select once the tree loads, carried over from the screen
+	// browse was switched to from ("" = none)
+	SelectSession string
+	SelectHost    string
 }
 
 // Model is the main TUI state
@@ -51,9 +85,26 @@ type Model struct {
 	// Selection
 	selectedIndex int
 	hoverIndex    int // For mouse hover
+	treeScroll    int // Index of the first flat node shown in the tree
+	lineJump      lineJumpState
+
+	// Tree filter
+	treeFilter        textinput.Model
+	filteringTree     bool // Filter input has focus
+	treeFilterMatches int  // Nodes matching the filter, ancestors excluded
+
+	// Agents view
+	agentsOnly    bool            // Show only panes running a configured agent
+	agentPrograms map[string]bool // Programs the configured agents run
+	agentCount    int             // Agent panes found for the agents view
+
+	// Panes watched for going idle, by host and target
+	watches map[string]*paneWatch
 
 	// Components
 	commandInput textinput.Model
+	commandArea  textarea.Model // Multi-line editor, shown while multiline is set
+	multiline    bool
 	previewPort  viewport.Model
 
 	// State
@@ -61,6 +112,8 @@ type Model struct {
 	command        string
 	previewContent string
 	previewTarget  string
+	activity       *paneActivity     // Output activity of the previewed pane
+	previewSelect  *previewSelection // Mouse drag selection in the preview, nil if none
 
 	// Dimensions
 	width        int
@@ -68,21 +121,69 @@ type Model struct {
 	treeWidth    int
 	previewWidth int
 
+	stacked           bool // Tree above the preview instead of beside it
+	stackedTreeHeight int  // Tree rows while stacked (0 = default share)
+
+	previewZoomed bool // Preview fills the main area with the tree hidden
+
 	// Options
 	options Options
 
 	// Multi-host support
-	executors  []tmux.TmuxExecutor // Executors (nil = local-only)
-	hostTrees  []tmux.HostTree     // Per-host tree data (used for routing)
-	hostErrors map[string]error    // Per-host errors from last fetch
+	executors   []tmux.TmuxExecutor      // Executors (nil = local-only)
+	hostTrees   []tmux.HostTree          // Per-host tree data (used for routing)
+	hostErrors  map[string]error         // Per-host errors from last fetch
+	hostLatency map[string]time.Duration // Per-host fetch duration from last live fetch
+	liveTree    bool                     // True once a live (non-cached) multi-host fetch has arrived
+
+	// Time shown by the status bar clock, updated each tick
+	clock time.Time
+
+	// Hosts with a live tree fetch in flight. A map, so fetchTreeCmd can
+	// mark them on any copy of the model.
+	treeFetching map[string]bool
+
+	// Each host's tree as last written to the on-disk cache, so a refresh
+	// round only rewrites the cache when a tree has changed
+	cachedTrees map[string]*tmux.Tree
+
+	// Remote hosts retrying after a transient failure (attempt number), and
+	// hosts given up on (when), which auto-refresh only probes now and then
+	hostRetries map[string]int
+	hostsDown   map[string]time.Time
+
+	// An auto-refresh tick is pending, so refreshes don't start a second
+	// chain of ticks
+	tickArmed bool
+
+	// g then l/s/b switches screens; switchTo is set when quitting to do so,
+	// and selectSession/selectHost wait for the tree to select the session
+	// carried over from the last screen
+	screenJump    screenJump
+	switchTo      *ScreenSwitch
+	selectSession string
+	selectHost    string
+
+	// A timed preview capture is in flight, so the next preview tick skips
+	// rather than stacking another capture behind a slow remote
+	previewFetching bool
 
 	// Status
 	lastError     error
 	lastSent      string // Last command sent (for status display)
+	clearOnSent   string // Input to clear once its send succeeds (after_send clear-on-success)
+	lastNotice    string // Last informational status (e.g. clipboard copy)
+	nextRun       string // Next scheduled job indicator ("" when none enabled)
 	ctrlCPrimed   bool   // Tracks double Ctrl-C to exit
 	attachSession string
+	attachTarget  string // Window or pane to land on in attachSession ("" = its current one)
+	attachRO      bool   // Attach to attachSession as a read-only client
 	reviveDir     string // Working directory for reviving a recent session
 
+	// Per-pane command log for the preview header
+	sentLog       map[string][]sentCommand // Recent sends by sentLogKey
+	scheduledJobs []config.ScheduledJob    // For the scheduled sends' last runs
+
 	// Debug mode
 	sendMethod tmux.SendMethod
 
@@ -102,22 +203,67 @@ type Model struct {
 	// Tree expansion state
 	expanded map[string]bool
 
+	// Show pane dimensions (e.g. 80x24) on pane rows
+	showPaneSize bool
+
 	// Help overlay
 	showHelp bool
 
 	// Kill confirmation state
-	confirmKill    bool   // Whether we're showing kill confirmation
-	killNodeType   string // Type of node being killed (session/window/pane)
-	killNodeTarget string // Target of node being killed
-	killNodeName   string // Name of node being killed (for display)
-	killNodeHost   string // Host of node being killed (for executor routing)
+	confirmKill    bool            // Whether we're showing kill confirmation
+	killNodeType   string          // Type of node being killed (session/window/pane)
+	killNodeTarget string          // Target of node being killed
+	killNodeName   string          // Name of node being killed (for display)
+	killNodeHost   string          // Host of node being killed (for executor routing)
+	killImpact     tmux.KillImpact // What the kill destroys, from the loaded tree
+
+	// Bulk kill state: marked targets keyed by host and target, and the
+	// ordered list awaiting confirmation (nil if not showing)
+	killMarks       map[string]killItem
+	pendingBulkKill []killItem
+
+	// New session confirmation (session for current directory already exists)
+	confirmNewSession bool
+
+	// Send to all panes awaiting confirmation, nil if not showing
+	pendingSendAll *sendAllRequest
+
+	// Send to a shell pane awaiting confirmation, nil if not showing
+	pendingShellSend *shellSendRequest
+
+	// File to paste into a pane being chosen, nil if not showing
+	sendFile *sendFilePrompt
+
+	// Session picker for moving a window (nil if not showing)
+	moveWindow *moveWindowPicker
+
+	// Pane marked as the first half of a swap (nil if none)
+	swapFrom *paneRef
+
+	// The tmux marked pane, as set from browse (nil if none)
+	markedPane *paneRef
+
+	// Template choice for "new session here", nil if not showing
+	templatePicker  *templatePicker
+	sessionTemplate string // Template for the session created on quit ("" = default)
 
 	// Context menu state
 	contextMenu *ContextMenu // Active context menu, nil if not showing
 
+	// Pane search overlay state
+	search *paneSearch // Active pane search, nil if not showing
+
+	// Command palette overlay state
+	palette *commandPalette // Active command palette, nil if not showing
+
+	// Searchable input history, nil if not showing
+	historyPicker *historyPicker
+
 	// Mobile mode
-	mobileMode       bool // True when using mobile-optimized layout
-	mobileForcedMode bool // True when --mobile flag was passed (prevents auto-switching)
+	mobileMode       bool            // True when using mobile-optimized layout
+	mobileForcedMode bool            // True when --mobile flag was passed (prevents auto-switching)
+	mobileFocus      MobileButton    // Button in the mobile button bar that has keyboard focus
+	mobileExpanded   map[string]bool // Sessions whose windows are revealed in mobile mode
 
 	// Recent sessions (history entries not currently active)
 	recentSessions      []history.Entry
@@ -142,8 +288,11 @@ func NewModel(opts Options) Model {
 	vp := viewport.New(40, 20)
 	mouseEnabled := os.Getenv("TMUX") == ""
 
-	return Model{
+	m := Model{
 		commandInput:     ti,
+		commandArea:      newCommandEditor(),
+		clock:            time.Now(),
+		treeFilter:       newTreeFilterInput(),
 		previewPort:      vp,
 		focused:          FocusTree,
 		options:          opts,
@@ -157,30 +306,120 @@ func NewModel(opts Options) Model {
 		mobileMode:       opts.MobileMode,
 		mobileForcedMode: opts.MobileMode,
 		hostErrors:       map[string]error{},
+		hostLatency:      map[string]time.Duration{},
+		mobileExpanded:   map[string]bool{},
+		treeFetching:     map[string]bool{},
+		cachedTrees:      map[string]*tmux.Tree{},
+		hostRetries:      map[string]int{},
+		hostsDown:        map[string]time.Time{},
+		selectSession:    opts.SelectSession,
+		selectHost:       opts.SelectHost,
+	}
+	agents := opts.Agents
+	if len(agents) == 0 {
+		agents = tmux.DefaultAgents()
 	}
+	m.agentPrograms = tmux.AgentPrograms(agents)
+	// Show pending host nodes until the first fetch completes
+	m.rebuildFlatNodes()
+	return m
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		m.fetchTreeCmd(),
 		fetchRecentSessions,
+		loadNextRun,
 		tea.SetWindowTitle("atmux browse"),
-	)
+	}
+	if len(m.executors) > 0 && !m.options.DisableTreeCache {
+		cmds = append(cmds, loadCachedTrees(m.executors))
+	}
+	if d := m.previewInterval(); d > 0 {
+		cmds = append(cmds, previewTickCmd(d))
+	}
+	return tea.Batch(cmds...)
 }
 
-// fetchTreeCmd returns a command that fetches the tree, using executors if available.
+// fetchTreeCmd returns a command that fetches the tree, using executors if
+// available. Each host is fetched separately and reported as it answers;
+// a host whose last fetch is still running, e.g. one waiting out its
+// timeout, is skipped rather than queued up behind itself, as is one
+// waiting on a retry or given up on as down.
 func (m *Model) fetchTreeCmd() tea.Cmd {
 	if len(m.executors) > 0 {
-		execs := m.executors
-		return func() tea.Msg {
-			hostTrees := tmux.FetchTreeWithExecutors(execs)
-			return MultiTreeRefreshedMsg{HostTrees: hostTrees}
+		var cmds []tea.Cmd
+		for _, exec := range m.executors {
+			host := exec.HostLabel()
+			if m.treeFetching[host] || m.hostRetries[host] > 0 || !m.dueHostProbe(host) {
+				continue
+			}
+			cmds = append(cmds, m.fetchHostCmd(exec))
 		}
+		return tea.Batch(cmds...)
 	}
 	return fetchTree
 }
 
+// fetchHostCmd fetches one host's tree, marking it in flight.
+func (m *Model) fetchHostCmd(exec tmux.TmuxExecutor) tea.Cmd {
+	m.treeFetching[exec.HostLabel()] = true
+	return func() tea.Msg {
+		return HostTreeRefreshedMsg{HostTree: tmux.FetchHostTree(exec)}
+	}
+}
+
+// saveTreeCacheCmd writes the live host trees to the on-disk cache.
+func saveTreeCacheCmd(hostTrees []tmux.HostTree) tea.Cmd {
+	return func() tea.Msg {
+		tmux.SaveTreeCache(hostTrees) // best-effort
+		return nil
+	}
+}
+
+// treeCacheChanged reports whether any live host tree differs from the one
+// last written to the cache, and records the live trees as written.
+func (m *Model) treeCacheChanged() bool {
+	changed := false
+	for _, ht := range m.hostTrees {
+		if ht.Err != nil || ht.Tree == nil || !ht.CachedAt.IsZero() {
+			continue
+		}
+		if saved, ok := m.cachedTrees[ht.Host]; !ok || !reflect.DeepEqual(saved, ht.Tree) {
+			m.cachedTrees[ht.Host] = ht.Tree
+			changed = true
+		}
+	}
+	return changed
+}
+
+// loadCachedTrees loads the last cached tree for each executor so remote
+// hosts can be shown before the live fetch completes.
+func loadCachedTrees(execs []tmux.TmuxExecutor) tea.Cmd {
+	return func() tea.Msg {
+		return MultiTreeRefreshedMsg{HostTrees: tmux.LoadCachedHostTrees(execs), Cached: true}
+	}
+}
+
+// treeCacheTTL returns the age after which cached host trees are marked stale.
+func (m *Model) treeCacheTTL() time.Duration {
+	if m.options.TreeCacheTTL > 0 {
+		return m.options.TreeCacheTTL
+	}
+	return (&config.TreeCacheConfig{}).ParsedTTL()
+}
+
+// hostCachedAt returns when the tree shown for host was cached (zero if live).
+func (m *Model) hostCachedAt(host string) time.Time {
+	for _, ht := range m.hostTrees {
+		if ht.Host == host {
+			return ht.CachedAt
+		}
+	}
+	return time.Time{}
+}
+
 // fetchTree fetches the tmux tree structure (local only)
 func fetchTree() tea.Msg {
 	tree, err := tmux.FetchTree()
@@ -243,16 +482,9 @@ func (m *Model) maxVisibleRecentEntries() int {
 	if len(m.recentSessions) == 0 {
 		return 0
 	}
-	treeHeight := m.height - inputHeight - statusHeight - 4
-	if treeHeight < 1 {
-		treeHeight = 1
-	}
+	treeHeight := m.treeViewHeight()
 	// Tree nodes take up space, plus 2 lines for separator + header
-	nodeCount := len(m.flatNodes)
-	if nodeCount > treeHeight {
-		nodeCount = treeHeight
-	}
-	remaining := treeHeight - nodeCount - 2 // -2 for blank line + header
+	remaining := treeHeight - m.visibleTreeNodeCount() - 2 // -2 for blank line + header
 	if remaining < 0 {
 		return 0
 	}
@@ -282,7 +514,7 @@ func fetchPreviewWithExecutor(target string, exec tmux.TmuxExecutor) tea.Cmd {
 func sendCommand(target, command string, method tmux.SendMethod) tea.Cmd {
 	return func() tea.Msg {
 		err := tmux.SendCommandWithMethod(target, command, method)
-		return CommandSentMsg{Target: target, Command: command, Err: err}
+		return CommandSentMsg{Target: target, Command: tmux.WrapCommand(command), Panes: []string{target}, Err: err}
 	}
 }
 
@@ -290,7 +522,7 @@ func sendCommand(target, command string, method tmux.SendMethod) tea.Cmd {
 func sendCommandWithExecutor(target, command string, method tmux.SendMethod, exec tmux.TmuxExecutor) tea.Cmd {
 	return func() tea.Msg {
 		err := tmux.SendCommandWithMethodAndExecutor(target, command, method, exec)
-		return CommandSentMsg{Target: target, Command: command, Err: err}
+		return CommandSentMsg{Target: target, Command: tmux.WrapCommand(command), Panes: []string{target}, Err: err}
 	}
 }
 
@@ -310,6 +542,22 @@ func sendEscapeWithExecutor(target string, exec tmux.TmuxExecutor) tea.Cmd {
 	}
 }
 
+// sendInterrupt sends C-c to a pane.
+func sendInterrupt(target string) tea.Cmd {
+	return func() tea.Msg {
+		err := tmux.SendInterrupt(target)
+		return CommandSentMsg{Target: target, Command: "C-c", Err: err}
+	}
+}
+
+// sendInterruptWithExecutor sends C-c via a specific executor.
+func sendInterruptWithExecutor(target string, exec tmux.TmuxExecutor) tea.Cmd {
+	return func() tea.Msg {
+		err := tmux.SendInterruptWithExecutor(target, exec)
+		return CommandSentMsg{Target: target, Command: "C-c", Err: err}
+	}
+}
+
 // killTarget kills a session, window, or pane.
 func killTarget(nodeType, target string) tea.Cmd {
 	return func() tea.Msg {
@@ -333,6 +581,45 @@ func tickCmd(d time.Duration) tea.Cmd {
 	})
 }
 
+// armRefreshTick schedules the next auto-refresh tick. Every refresh,
+// including one pressed with r, calls it, so it arms a tick only when none
+// is pending; with a refresh interval of 0 it never does.
+func (m *Model) armRefreshTick() tea.Cmd {
+	if m.options.RefreshInterval <= 0 || m.tickArmed {
+		return nil
+	}
+	m.tickArmed = true
+	return tickCmd(m.options.RefreshInterval)
+}
+
+// previewInterval returns how often the preview's own timer fires, or 0
+// when it has none. Turning auto-refresh off turns this timer off too.
+func (m *Model) previewInterval() time.Duration {
+	if m.options.RefreshInterval <= 0 {
+		return 0
+	}
+	return m.options.PreviewRefreshInterval
+}
+
+// previewTickCmd creates a tick for the preview's own refresh timer
+func previewTickCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return PreviewTickMsg{}
+	})
+}
+
+// treePreviewCmd re-captures the selected pane along with a tree refresh,
+// unless the preview has a timer of its own.
+func (m *Model) treePreviewCmd() tea.Cmd {
+	if m.previewInterval() > 0 {
+		return nil
+	}
+	if node := m.selectedNode(); node != nil && node.Type == "pane" {
+		return m.fetchPreviewForNode(node)
+	}
+	return nil
+}
+
 // selectedNode returns the currently selected node
 func (m *Model) selectedNode() *tmux.TreeNode {
 	if m.selectedIndex >= 0 && m.selectedIndex < len(m.flatNodes) {
@@ -353,11 +640,20 @@ func (m *Model) nodeForTarget(target string) *tmux.TreeNode {
 
 // rebuildFlatNodes rebuilds the flat node list from the tree
 func (m *Model) rebuildFlatNodes() {
-	if m.tree == nil {
+	if m.tree == nil && len(m.executors) == 0 {
 		m.flatNodes = []*tmux.TreeNode{}
 		return
 	}
 	m.flatNodes = m.buildFlatNodes()
+	m.treeFilterMatches = 0
+	if query := m.treeFilterQuery(); query != "" {
+		m.flatNodes, m.treeFilterMatches = filterTreeNodes(m.flatNodes, query)
+	}
+	if m.agentsOnly {
+		m.flatNodes, m.agentCount = filterNodes(m.flatNodes, m.isAgentPane)
+	}
+	m.selectCarriedSession()
+	m.scrollTreeToSelection()
 }
 
 // toggleExpand toggles expansion of the selected node
@@ -377,6 +673,62 @@ func (m *Model) toggleExpand() {
 	}
 }
 
+// setAllExpanded expands or collapses every host, session, and window.
+// Collapsing leaves only sessions, or only host headers with several hosts,
+// and moves the selection to its nearest ancestor still shown.
+func (m *Model) setAllExpanded(expand bool) {
+	selected := m.selectedNode()
+	// Expand a level at a time until nothing new appears. Collapsing does
+	// this too so nested windows are reset, not just hidden.
+	for {
+		grew := false
+		for _, node := range m.flatNodes {
+			if isExpandable(node) && !node.Expanded {
+				m.expanded[m.expandKey(node)] = true
+				grew = true
+			}
+		}
+		if !grew {
+			break
+		}
+		m.rebuildFlatNodes()
+	}
+	if !expand {
+		for _, node := range m.flatNodes {
+			if isExpandable(node) {
+				m.expanded[m.expandKey(node)] = false
+			}
+		}
+		m.rebuildFlatNodes()
+	}
+	if selected != nil {
+		m.selectNearest(selected)
+	}
+}
+
+func isExpandable(node *tmux.TreeNode) bool {
+	return node.Type == "host" || node.Type == "session" || node.Type == "window"
+}
+
+// selectNearest selects node, or its closest ancestor when it's hidden.
+func (m *Model) selectNearest(node *tmux.TreeNode) {
+	matches := []func(n *tmux.TreeNode) bool{
+		func(n *tmux.TreeNode) bool { return n.Type == node.Type && n.Target == node.Target },
+		func(n *tmux.TreeNode) bool { return n.Type == "window" && n.Target == windowTargetOf(node) },
+		func(n *tmux.TreeNode) bool { return n.Type == "session" && n.Target == sessionFromNode(node) },
+		func(n *tmux.TreeNode) bool { return n.Type == "host" },
+	}
+	for _, match := range matches {
+		for i, n := range m.flatNodes {
+			if n.Host == node.Host && match(n) {
+				m.jumpSelection(i)
+				return
+			}
+		}
+	}
+	m.jumpSelection(m.selectedIndex)
+}
+
 // expandKey returns the expansion key for a node, including host prefix for multi-host mode.
 func (m *Model) expandKey(node *tmux.TreeNode) string {
 	if node.Type == "host" {
@@ -407,6 +759,7 @@ func (m *Model) moveSelection(delta int) {
 			if m.selectedIndex < 0 {
 				m.selectedIndex = 0
 			}
+			m.scrollTreeToSelection()
 			return
 		}
 		if m.recentSelectedIndex >= maxVisible {
@@ -436,13 +789,206 @@ func (m *Model) moveSelection(delta int) {
 		newIndex = 0
 	}
 	m.selectedIndex = newIndex
+	m.scrollTreeToSelection()
+}
+
+// treeViewHeight returns the number of rows available for tree content.
+func (m *Model) treeViewHeight() int {
+	treeHeight := m.height - m.inputAreaHeight() - statusHeight - 4
+	if m.stacked {
+		treeHeight = m.stackedTreeHeight
+	}
+	if treeHeight < 1 {
+		treeHeight = 1
+	}
+	return treeHeight
+}
+
+// treeStartY returns the screen row of the first visible tree node: below
+// the input bar, the tree's top border (1), and its content padding (1). The
+// tree is on top whether the panels are side by side or stacked.
+func (m *Model) treeStartY() int {
+	return m.inputAreaHeight() + 2
+}
+
+// visibleTreeNodeCount returns how many flat nodes fit in the tree from
+// treeScroll onward.
+func (m *Model) visibleTreeNodeCount() int {
+	count := len(m.flatNodes) - m.treeScroll
+	if count > m.treeViewHeight() {
+		count = m.treeViewHeight()
+	}
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+// scrollTreeToSelection adjusts treeScroll so the selected node is visible
+// and the tree doesn't scroll past its last node. Button zones follow the
+// rows when the tree scrolls.
+func (m *Model) scrollTreeToSelection() {
+	prev := m.treeScroll
+	height := m.treeViewHeight()
+	if m.selectedIndex < m.treeScroll {
+		m.treeScroll = m.selectedIndex
+	}
+	if m.selectedIndex >= m.treeScroll+height {
+		m.treeScroll = m.selectedIndex - height + 1
+	}
+	if maxScroll := len(m.flatNodes) - height; m.treeScroll > maxScroll {
+		m.treeScroll = maxScroll
+	}
+	if m.treeScroll < 0 {
+		m.treeScroll = 0
+	}
+	if m.treeScroll != prev {
+		m.calculateButtonZones()
+	}
+}
+
+// jumpSelection moves the tree selection to index, clamped to the tree
+// (paging never moves into the recent section).
+func (m *Model) jumpSelection(index int) {
+	if index >= len(m.flatNodes) {
+		index = len(m.flatNodes) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	m.selectedIndex = index
+	m.scrollTreeToSelection()
+}
+
+// adjacentGroupStart returns the group start after current (dir > 0) or
+// before it (dir < 0), wrapping around at the ends. starts is ascending.
+func adjacentGroupStart(starts []int, current, dir int) (int, bool) {
+	if len(starts) == 0 {
+		return 0, false
+	}
+	if dir > 0 {
+		for _, start := range starts {
+			if start > current {
+				return start, true
+			}
+		}
+		return starts[0], true
+	}
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] < current {
+			return starts[i], true
+		}
+	}
+	return starts[len(starts)-1], true
+}
+
+// hostHeaderIndices returns the flat indices of the host headers, empty
+// outside multi-host mode.
+func (m *Model) hostHeaderIndices() []int {
+	var indices []int
+	for i, node := range m.flatNodes {
+		if node.Type == "host" {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// jumpTargets returns the flat indices of the on-screen nodes that can be
+// reached by typing their number. Host headers are skipped so the numbers
+// stay on sessions, windows, and panes.
+func (m *Model) jumpTargets() []int {
+	var targets []int
+	end := m.treeScroll + m.visibleTreeNodeCount()
+	for i := m.treeScroll; i < end; i++ {
+		if m.flatNodes[i].Type != "host" {
+			targets = append(targets, i)
+		}
+	}
+	return targets
+}
+
+// jumpGutterWidth returns the width of the jump-number column that precedes
+// each tree row, including its trailing space.
+func (m *Model) jumpGutterWidth() int {
+	return len(strconv.Itoa(max(1, len(m.jumpTargets())))) + 1
+}
+
+// previewViewHeight returns the number of rows inside the preview border.
+func (m *Model) previewViewHeight() int {
+	previewHeight := m.height - m.inputAreaHeight() - statusHeight - 4
+	if m.stacked && !m.previewZoomed {
+		previewHeight -= m.stackedTreeHeight + 2
+	}
+	if previewHeight < 1 {
+		previewHeight = 1
+	}
+	return previewHeight
+}
+
+// useStackedLayout reports whether the tree goes above the preview. The
+// layout option can force either arrangement; otherwise windows taller than
+// they are wide stack (a cell is about twice as tall as it is wide). Windows
+// too short for two stacked panels stay side by side.
+func (m *Model) useStackedLayout() bool {
+	if m.stackedRows() < minStackedTree+minStackedPreview {
+		return false
+	}
+	switch m.options.Layout {
+	case config.BrowseLayoutSide:
+		return false
+	case config.BrowseLayoutStacked:
+		return true
+	}
+	return m.width < 2*m.height
+}
+
+// stackedRows returns the content rows the tree and preview share when
+// stacked: the main area less both panels' borders.
+func (m *Model) stackedRows() int {
+	return m.height - m.inputAreaHeight() - statusHeight - 6
+}
+
+// setStackedTreeHeight moves the stacked divider so the tree has rows rows,
+// clamped so neither panel drops below its minimum. 0 picks the default
+// share.
+func (m *Model) setStackedTreeHeight(rows int) {
+	available := m.stackedRows()
+	if rows <= 0 {
+		rows = available * stackedTreePercent / 100
+	}
+	rows = min(max(rows, minStackedTree), available-minStackedPreview)
+	if rows == m.stackedTreeHeight {
+		return
+	}
+	m.stackedTreeHeight = rows
+	m.sizePreviewPort()
+	m.scrollTreeToSelection()
 }
 
 // calculateLayout calculates panel widths based on terminal size
 func (m *Model) calculateLayout() {
+	m.stacked = m.useStackedLayout()
+	if m.stacked {
+		// Both panels span the window, less their borders
+		m.treeWidth = m.width - 2
+		m.previewWidth = m.width - 2
+		m.setStackedTreeHeight(m.stackedTreeHeight)
+		m.sizePreviewPort()
+		m.scrollTreeToSelection()
+		return
+	}
+
 	// Account for borders
 	availableWidth := m.width - 4
-	m.treeWidth = (availableWidth * treeWidthPercent) / 100
+	percent := treeWidthPercent
+	if m.options.TreeWidthPercent > 0 {
+		percent = m.options.TreeWidthPercent
+	}
+	m.treeWidth = (availableWidth * percent) / 100
+	if maxTreeWidth := availableWidth - minPreviewWidth; m.treeWidth > maxTreeWidth {
+		m.treeWidth = maxTreeWidth
+	}
 	m.previewWidth = availableWidth - m.treeWidth
 
 	if m.treeWidth < minTreeWidth {
@@ -452,8 +998,17 @@ func (m *Model) calculateLayout() {
 		m.previewWidth = minPreviewWidth
 	}
 
-	// Update viewport dimensions
-	previewHeight := m.height - inputHeight - statusHeight - 4
+	m.sizePreviewPort()
+	m.scrollTreeToSelection()
+}
+
+// sizePreviewPort updates the viewport dimensions for the preview panel,
+// widening it to the full terminal width while the preview is zoomed.
+func (m *Model) sizePreviewPort() {
+	if m.previewZoomed {
+		m.previewWidth = m.width - 2
+	}
+	previewHeight := m.previewViewHeight()
 	if previewHeight < 5 {
 		previewHeight = 5
 	}
@@ -461,6 +1016,23 @@ func (m *Model) calculateLayout() {
 	m.previewPort.Height = previewHeight
 }
 
+// setPreviewZoomed zooms the preview to fill the main area (hiding the tree)
+// or restores the split layout, keeping any divider resize.
+func (m *Model) setPreviewZoomed(zoomed bool) {
+	if m.previewZoomed == zoomed {
+		return
+	}
+	m.previewZoomed = zoomed
+	if !zoomed && !m.stacked {
+		m.previewWidth = m.width - 4 - m.treeWidth
+		if m.previewWidth < minPreviewWidth {
+			m.previewWidth = minPreviewWidth
+		}
+	}
+	m.sizePreviewPort()
+	m.calculateButtonZones()
+}
+
 // findButtonAt returns the button at the given coordinates, if any
 func (m *Model) findButtonAt(x, y int) (buttonZone, bool) {
 	for i := range m.buttonZones {
@@ -493,30 +1065,29 @@ func (m *Model) calculateButtonZones() {
 	})
 
 	// Tree node buttons
-	treeHeight := m.height - inputHeight - statusHeight - 4
-	if treeHeight < 1 {
-		treeHeight = 1
-	}
+	treeHeight := m.treeViewHeight()
 
-	// inputHeight (3) + tree top border (1) + tree content padding (1) = 5
-	buttonYOffset := inputHeight + 2
 	buttonGap := 1
 
 	// Button widths (text + padding(0,1) on each side)
 	sendWidth := 6 // " SEND "
 	escWidth := 5  // " ESC "
+	intWidth := 4  // " ^C "
 	attWidth := 5  // " ATT "
 
-	for i, node := range m.flatNodes {
-		if i >= treeHeight {
+	for i := m.treeScroll; i < len(m.flatNodes); i++ {
+		node := m.flatNodes[i]
+		if i-m.treeScroll >= treeHeight || m.previewZoomed {
 			break
 		}
 
-		nodeY := buttonYOffset + i
+		nodeY := m.treeStartY() + i - m.treeScroll
 
 		if node.Type == "pane" {
-			// Panes get SEND, ESC, and ATT buttons
-			buttonsWidth := sendWidth + buttonGap + escWidth + buttonGap + attWidth
+			// Panes get SEND, ESC, ^C, and ATT buttons. Buttons are right-aligned and
+			// renderTree shortens the name to fit the optional pane size, so the
+			// size text never shifts these positions.
+			buttonsWidth := sendWidth + buttonGap + escWidth + buttonGap + intWidth + buttonGap + attWidth
 			buttonStartX := m.treeWidth - buttonsWidth
 
 			m.buttonZones = append(m.buttonZones, buttonZone{
@@ -538,7 +1109,17 @@ func (m *Model) calculateButtonZones() {
 				action: buttonActionEscape,
 			})
 
-			attStartX := escStartX + escWidth + buttonGap
+			intStartX := escStartX + escWidth + buttonGap
+			m.buttonZones = append(m.buttonZones, buttonZone{
+				x:      intStartX,
+				y:      nodeY,
+				width:  intWidth,
+				height: 1,
+				target: node.Target,
+				action: buttonActionInterrupt,
+			})
+
+			attStartX := intStartX + intWidth + buttonGap
 			m.buttonZones = append(m.buttonZones, buttonZone{
 				x:      attStartX,
 				y:      nodeY,
@@ -563,11 +1144,12 @@ func (m *Model) calculateButtonZones() {
 
 	// Status bar hint zones (only shown when not in input mode)
 	if m.focused != FocusInput {
-		// Status bar Y: inputHeight + mainContent (treeHeight + 2 borders)
-		statusY := inputHeight + treeHeight + 2
+		// Status bar Y: input bar + mainContent (panel height + 2 borders),
+		// the same whether the panels are side by side or stacked
+		statusY := m.height - statusHeight - 2
 
 		// Status bar has Padding(0,1), so content starts at x=1
-		// Hints: [r]efresh [a]ttach [x]kill [/]input [?]help
+		// Hints: [r]efresh [a]ttach [x]kill [/]filter [?]help
 		// Each hint is rendered individually with spacing between them
 		type hintDef struct {
 			text   string
@@ -577,7 +1159,7 @@ func (m *Model) calculateButtonZones() {
 			{"[r]efresh", buttonActionRefresh},
 			{"[a]ttach", buttonActionAttach},
 			{"[x]kill", buttonActionKillHint},
-			{"[/]input", buttonActionFocusInput},
+			{"[/]filter", buttonActionFilter},
 			{"[?]help", buttonActionHelp},
 		}
 
@@ -604,6 +1186,10 @@ func nodeKey(nodeType, target string) string {
 }
 
 func (m *Model) isExpanded(nodeType, target string, defaultValue bool) bool {
+	// Filtering searches the whole tree without touching the expansion state
+	if m.treeFilterQuery() != "" || m.agentsOnly {
+		return true
+	}
 	if val, ok := m.expanded[nodeKey(nodeType, target)]; ok {
 		return val
 	}
@@ -612,7 +1198,7 @@ func (m *Model) isExpanded(nodeType, target string, defaultValue bool) bool {
 
 func (m *Model) buildFlatNodes() []*tmux.TreeNode {
 	// Multi-host mode: build from hostTrees with host grouping
-	if len(m.hostTrees) > 0 {
+	if len(m.hostTrees) > 0 || len(m.executors) > 0 {
 		return m.buildMultiHostFlatNodes()
 	}
 
@@ -627,6 +1213,9 @@ func (m *Model) buildFlatNodes() []*tmux.TreeNode {
 			Expanded: sessExpanded,
 			Level:    0,
 			Attached: sess.Attached,
+			Created:  sess.Created,
+			Windows:  len(sess.Windows),
+			Panes:    sessionPaneCount(sess),
 		}
 		nodes = append(nodes, sessNode)
 
@@ -635,12 +1224,16 @@ func (m *Model) buildFlatNodes() []*tmux.TreeNode {
 				winTarget := sess.Name + ":" + strconv.Itoa(win.Index)
 				winExpanded := m.isExpanded("window", winTarget, true)
 				winNode := &tmux.TreeNode{
-					Type:     "window",
-					Name:     win.Name,
-					Target:   winTarget,
-					Expanded: winExpanded,
-					Level:    1,
-					Active:   win.Active,
+					Type:         "window",
+					Name:         win.Name,
+					Target:       winTarget,
+					ID:           win.ID,
+					Expanded:     winExpanded,
+					Level:        1,
+					Active:       win.Active,
+					Synchronized: win.Synchronized,
+					Zoomed:       win.Zoomed,
+					Panes:        len(win.Panes),
 				}
 				sessNode.Children = append(sessNode.Children, winNode)
 				nodes = append(nodes, winNode)
@@ -648,11 +1241,16 @@ func (m *Model) buildFlatNodes() []*tmux.TreeNode {
 				if winExpanded {
 					for _, pane := range win.Panes {
 						paneNode := &tmux.TreeNode{
-							Type:   "pane",
-							Name:   pane.Title,
-							Target: pane.Target,
-							Level:  2,
-							Active: pane.Active,
+							Type:    "pane",
+							Name:    pane.Title,
+							Target:  pane.Target,
+							ID:      pane.ID,
+							Level:   2,
+							Active:  pane.Active,
+							Width:   pane.Width,
+							Height:  pane.Height,
+							Command: pane.Command,
+							Zoomed:  win.Zoomed && pane.Active,
 						}
 						if paneNode.Name == "" {
 							paneNode.Name = pane.Command
@@ -671,11 +1269,20 @@ func (m *Model) buildFlatNodes() []*tmux.TreeNode {
 	return nodes
 }
 
+// sessionPaneCount returns how many panes a session has across its windows.
+func sessionPaneCount(sess tmux.TmuxSession) int {
+	count := 0
+	for _, win := range sess.Windows {
+		count += len(win.Panes)
+	}
+	return count
+}
+
 // buildMultiHostFlatNodes builds flat nodes from multiple host trees with host headers.
 func (m *Model) buildMultiHostFlatNodes() []*tmux.TreeNode {
 	var nodes []*tmux.TreeNode
 
-	for _, ht := range m.hostTrees {
+	for _, ht := range m.displayHostTrees() {
 		hostLabel := ht.Host
 		if hostLabel == "" {
 			hostLabel = "local"
@@ -699,7 +1306,7 @@ func (m *Model) buildMultiHostFlatNodes() []*tmux.TreeNode {
 			if hostExpanded {
 				errNode := &tmux.TreeNode{
 					Type:  "pane", // Use pane type for leaf rendering
-					Name:  "unreachable: " + ht.Err.Error(),
+					Name:  "unreachable: " + tmux.FetchErrorReason(ht.Err) + m.hostRetryNote(ht.Host),
 					Level: 1,
 					Host:  ht.Host,
 				}
@@ -708,6 +1315,19 @@ func (m *Model) buildMultiHostFlatNodes() []*tmux.TreeNode {
 			continue
 		}
 
+		if ht.Tree == nil && !m.liveTree {
+			// Still waiting on the first fetch for this host
+			if hostExpanded {
+				nodes = append(nodes, &tmux.TreeNode{
+					Type:  "pane",
+					Name:  "connecting…",
+					Level: 1,
+					Host:  ht.Host,
+				})
+			}
+			continue
+		}
+
 		if ht.Tree == nil || !hostExpanded {
 			continue
 		}
@@ -721,7 +1341,10 @@ func (m *Model) buildMultiHostFlatNodes() []*tmux.TreeNode {
 				Expanded: sessExpanded,
 				Level:    1,
 				Attached: sess.Attached,
+				Created:  sess.Created,
 				Host:     ht.Host,
+				Windows:  len(sess.Windows),
+				Panes:    sessionPaneCount(sess),
 			}
 			nodes = append(nodes, sessNode)
 
@@ -730,13 +1353,17 @@ func (m *Model) buildMultiHostFlatNodes() []*tmux.TreeNode {
 					winTarget := sess.Name + ":" + strconv.Itoa(win.Index)
 					winExpanded := m.isExpanded("window", hostLabel+"/"+winTarget, true)
 					winNode := &tmux.TreeNode{
-						Type:     "window",
-						Name:     win.Name,
-						Target:   winTarget,
-						Expanded: winExpanded,
-						Level:    2,
-						Active:   win.Active,
-						Host:     ht.Host,
+						Type:         "window",
+						Name:         win.Name,
+						Target:       winTarget,
+						ID:           win.ID,
+						Expanded:     winExpanded,
+						Level:        2,
+						Active:       win.Active,
+						Synchronized: win.Synchronized,
+						Zoomed:       win.Zoomed,
+						Host:         ht.Host,
+						Panes:        len(win.Panes),
 					}
 					sessNode.Children = append(sessNode.Children, winNode)
 					nodes = append(nodes, winNode)
@@ -744,12 +1371,17 @@ func (m *Model) buildMultiHostFlatNodes() []*tmux.TreeNode {
 					if winExpanded {
 						for _, pane := range win.Panes {
 							paneNode := &tmux.TreeNode{
-								Type:   "pane",
-								Name:   pane.Title,
-								Target: pane.Target,
-								Level:  3,
-								Active: pane.Active,
-								Host:   ht.Host,
+								Type:    "pane",
+								Name:    pane.Title,
+								Target:  pane.Target,
+								ID:      pane.ID,
+								Level:   3,
+								Active:  pane.Active,
+								Host:    ht.Host,
+								Width:   pane.Width,
+								Height:  pane.Height,
+								Command: pane.Command,
+								Zoomed:  win.Zoomed && pane.Active,
 							}
 							if paneNode.Name == "" {
 								paneNode.Name = pane.Command
@@ -769,6 +1401,91 @@ func (m *Model) buildMultiHostFlatNodes() []*tmux.TreeNode {
 	return nodes
 }
 
+// displayHostTrees returns the host trees to render. Before the first live
+// fetch, executors without (cached) data are included as pending entries.
+func (m *Model) displayHostTrees() []tmux.HostTree {
+	if m.liveTree || len(m.executors) == 0 {
+		return m.hostTrees
+	}
+	var result []tmux.HostTree
+	for _, exec := range m.executors {
+		ht := tmux.HostTree{Host: exec.HostLabel(), Executor: exec}
+		for _, known := range m.hostTrees {
+			if known.Host == ht.Host {
+				ht = known
+				break
+			}
+		}
+		result = append(result, ht)
+	}
+	return result
+}
+
+// mergeHostTree returns a copy of trees with ht in place of its host's
+// entry. A cached tree never replaces a live one.
+func mergeHostTree(trees []tmux.HostTree, ht tmux.HostTree) []tmux.HostTree {
+	trees = append([]tmux.HostTree(nil), trees...)
+	for i, known := range trees {
+		if known.Host != ht.Host {
+			continue
+		}
+		if !ht.CachedAt.IsZero() && isLiveHostTree(known) {
+			return trees
+		}
+		trees[i] = ht
+		return trees
+	}
+	return append(trees, ht)
+}
+
+// isLiveHostTree reports whether ht is the result of a live fetch, as
+// opposed to a cached tree or a host still waiting on its first fetch.
+func isLiveHostTree(ht tmux.HostTree) bool {
+	return ht.CachedAt.IsZero() && (ht.Tree != nil || ht.Err != nil)
+}
+
+// localSessionExists reports whether a local session with the given name is in the tree.
+func (m *Model) localSessionExists(name string) bool {
+	tree := m.tree
+	if len(m.hostTrees) > 0 {
+		tree = nil
+		for _, ht := range m.hostTrees {
+			if ht.Host == "" {
+				tree = ht.Tree
+				break
+			}
+		}
+	}
+	if tree == nil {
+		return false
+	}
+	for _, sess := range tree.Sessions {
+		if sess.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// startNewSession creates a session for the current directory and attaches
+// to it on quit. If that session already exists, asks to attach instead.
+func (m Model) startNewSession() (tea.Model, tea.Cmd) {
+	if m.options.WorkingDir == "" || m.options.SessionName == "" {
+		return m, nil
+	}
+	if m.localSessionExists(m.options.SessionName) {
+		m.confirmNewSession = true
+		return m, nil
+	}
+	if picker := newTemplatePicker(m.options.Templates); picker != nil {
+		m.templatePicker = picker
+		return m, nil
+	}
+	m.attachSession = m.options.SessionName
+	m.reviveDir = m.options.WorkingDir
+	return m, tea.Quit
+}
+
 // executorForHost returns the executor for the given host label.
 // Returns nil if no matching executor is found.
 func (m *Model) executorForHost(host string) tmux.TmuxExecutor {
@@ -796,15 +1513,21 @@ func (m *Model) fetchPreviewForNode(node *tmux.TreeNode) tea.Cmd {
 
 // sendCommandForNode sends a command to the correct executor for a node.
 func (m *Model) sendCommandForNode(node *tmux.TreeNode, command string) tea.Cmd {
+	return m.sendCommandForNodeWithMethod(node, command, m.sendMethod)
+}
+
+// sendCommandForNodeWithMethod is sendCommandForNode using the given send
+// method.
+func (m *Model) sendCommandForNodeWithMethod(node *tmux.TreeNode, command string, method tmux.SendMethod) tea.Cmd {
 	if node == nil || node.Type != "pane" {
 		return nil
 	}
 	if node.Host != "" {
 		if exec := m.executorForHost(node.Host); exec != nil {
-			return sendCommandWithExecutor(node.Target, command, m.sendMethod, exec)
+			return sendCommandWithExecutor(node.Target, command, method, exec)
 		}
 	}
-	return sendCommand(node.Target, command, m.sendMethod)
+	return sendCommand(node.Target, command, method)
 }
 
 // sendEscapeForNode sends escape to the correct executor for a node.
@@ -820,6 +1543,61 @@ func (m *Model) sendEscapeForNode(node *tmux.TreeNode) tea.Cmd {
 	return sendEscape(node.Target)
 }
 
+// sendInterruptForNode sends C-c to the correct executor for a node.
+func (m *Model) sendInterruptForNode(node *tmux.TreeNode) tea.Cmd {
+	if node == nil || node.Type != "pane" {
+		return nil
+	}
+	if node.Host != "" {
+		if exec := m.executorForHost(node.Host); exec != nil {
+			return sendInterruptWithExecutor(node.Target, exec)
+		}
+	}
+	return sendInterrupt(node.Target)
+}
+
+// requestKill kills the given node, asking for confirmation first unless
+// confirmations are disabled. Attached sessions always confirm.
+func (m Model) requestKill(nodeType, target, name, host string, attached bool) (tea.Model, tea.Cmd) {
+	if m.options.SkipKillConfirm && !(nodeType == "session" && attached) {
+		return m, m.killTargetForNode(nodeType, target, host)
+	}
+	m.confirmKill = true
+	m.killNodeType = nodeType
+	m.killNodeTarget = target
+	m.killNodeName = name
+	m.killNodeHost = host
+	m.killImpact = m.killImpactFor(nodeType, target, host)
+	return m, nil
+}
+
+// killImpactFor counts what killing a node would destroy, from the tree
+// loaded for its host.
+func (m *Model) killImpactFor(nodeType, target, host string) tmux.KillImpact {
+	return m.treeForHost(host).KillImpact(nodeType, target, m.agentPrograms)
+}
+
+// treeForHost returns the tree loaded for host ("" for local).
+func (m *Model) treeForHost(host string) *tmux.Tree {
+	for _, ht := range m.searchHostTrees() {
+		if ht.Host == host {
+			return ht.Tree
+		}
+	}
+	return m.tree
+}
+
+// executorOrLocal returns the executor for host, or the local one when host
+// is "" or unknown.
+func (m *Model) executorOrLocal(host string) tmux.TmuxExecutor {
+	if host != "" {
+		if exec := m.executorForHost(host); exec != nil {
+			return exec
+		}
+	}
+	return tmux.NewLocalExecutor()
+}
+
 // killTargetForNode kills a target via the correct executor.
 func (m *Model) killTargetForNode(nodeType, target, host string) tea.Cmd {
 	if host != "" {
@@ -830,8 +1608,10 @@ func (m *Model) killTargetForNode(nodeType, target, host string) tea.Cmd {
 	return killTarget(nodeType, target)
 }
 
-// Run starts the TUI
-func Run(opts Options) error {
+// Run starts the TUI. A non-nil ScreenSwitch means the user asked for
+// another entry screen rather than attaching or quitting.
+func Run(opts Options) (*ScreenSwitch, error) {
+	loadTheme()
 	m := NewModel(opts)
 	p := tea.NewProgram(m,
 		tea.WithAltScreen(),
@@ -839,23 +1619,102 @@ func Run(opts Options) error {
 	)
 	finalModel, err := p.Run()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	model, ok := finalModel.(Model)
-	if !ok || model.attachSession == "" {
+	if !ok {
+		return nil, nil
+	}
+	if model.liveTree && !opts.DisableTreeCache {
+		// Refresh the cached fetch times, which unchanged rounds don't write
+		tmux.SaveTreeCache(model.hostTrees) // best-effort
+	}
+	if model.switchTo != nil {
+		return model.switchTo, nil
+	}
+	return nil, attachChosen(model, opts)
+}
+
+// recordsRevive reports whether a session revived from browse is saved to
+// history: not with --no-history, nor when the project sets no_history.
+func recordsRevive(opts Options, cfg *config.Config) bool {
+	return !opts.NoHistory && (cfg == nil || !cfg.NoHistory)
+}
+
+// saveRevivedHistory records a session revived from history. When it had to
+// be revived under a new name, the entry recorded as previousName moves to
+// it, so the next revive finds the same session.
+func saveRevivedHistory(s *tmux.Session, previousName string) {
+	store, err := history.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to open history: %v\n", err)
+		return
+	}
+	defer store.Close()
+
+	if previousName != "" && previousName != s.Name {
+		if err := store.RenameSession(previousName, s.WorkingDir, s.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save history: %v\n", err)
+			return
+		}
+	}
+	if err := store.SaveEntry(filepath.Base(s.WorkingDir), s.WorkingDir, s.Name, "", ""); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save history: %v\n", err)
+	}
+}
+
+// attachChosen attaches to, or revives, the session chosen in browse.
+func attachChosen(model Model, opts Options) error {
+	if model.attachSession == "" {
 		return nil
 	}
 
 	if model.reviveDir != "" {
-		session := tmux.NewSession(model.reviveDir)
-		if !session.Exists() {
-			if err := session.Create(nil); err != nil {
+		session := tmux.ReviveSession(model.attachSession, model.reviveDir)
+		if session.Name != model.attachSession {
+			fmt.Printf("%s is in use by another directory; reviving as %s\n", model.attachSession, session.Name)
+		}
+		localConfigPath := filepath.Join(model.reviveDir, config.DefaultConfigName)
+		cfg, _ := config.LoadConfig(localConfigPath)
+		if session.Exists() {
+			if opts.RestoreFocus {
+				RestoreFocus(session.Name)
+			}
+		} else {
+			// Create with merged global + project config, like `atmux` does
+			if model.sessionTemplate != "" {
+				var err error
+				if cfg, err = cfg.WithTemplate(model.sessionTemplate); err != nil {
+					return err
+				}
+			}
+			if err := session.Create(cfg); err != nil {
 				return err
 			}
+			if cfg != nil {
+				session.ApplyConfig(cfg)
+			}
 			session.SelectDefault()
 		}
+		if recordsRevive(opts, cfg) {
+			saveRevivedHistory(session, model.attachSession)
+		}
 		return tmux.AttachToSession(session.Name)
 	}
 
+	// Land on the chosen window or pane; if it has gone, the session's
+	// current window is still worth attaching to. With RestoreFocus, a
+	// chosen target is remembered and a bare session returns to the last.
+	if model.attachTarget != "" {
+		tmux.SelectTarget(model.attachTarget)
+		if opts.RestoreFocus {
+			RememberFocus(model.attachTarget)
+		}
+	} else if opts.RestoreFocus {
+		RestoreFocus(model.attachSession)
+	}
+	if model.attachRO {
+		return tmux.AttachReadOnly(model.attachSession)
+	}
 	return tmux.AttachToSession(model.attachSession)
 }
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// sessionChoice is a destination session in the move-window picker.
+type sessionChoice string
+
+func (s sessionChoice) ID() string { return string(s) }
+
+func (s sessionChoice) Render(selected bool, width int) string {
+	if selected {
+		return selectedStyle.Render("> " + string(s))
+	}
+	return "  " + string(s)
+}
+
+// moveWindowPicker asks which session to move a window to.
+type moveWindowPicker struct {
+	source string // Window target being moved
+	host   string // Host of the window, for executor routing
+	list   *ExpandableList
+	chosen string // Destination session once picked
+}
+
+// openMoveWindow opens the session picker for moving the window at target,
+// listing the other sessions on its host.
+func (m Model) openMoveWindow(target, host string) (tea.Model, tea.Cmd) {
+	source := sessionFromTarget(target)
+	var items []ListItem
+	if tree := m.treeForHost(host); tree != nil {
+		for _, sess := range tree.Sessions {
+			if sess.Name != source {
+				items = append(items, sessionChoice(sess.Name))
+			}
+		}
+	}
+	if len(items) == 0 {
+		m.lastError = fmt.Errorf("no other session to move %s to", target)
+		return m, nil
+	}
+
+	picker := &moveWindowPicker{source: target, host: host, list: NewExpandableList(items)}
+	picker.list.MaxCollapsed = 10
+	picker.list.OnSelect = func(item ListItem) { picker.chosen = item.ID() }
+	m.moveWindow = picker
+	return m, nil
+}
+
+// handleMoveWindowKeys handles keys while choosing where to move a window;
+// choosing a session moves it there and refreshes the tree.
+func (m Model) handleMoveWindowKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.moveWindow
+	switch msg.String() {
+	case "esc", "q", "ctrl+c":
+		m.moveWindow = nil
+		return m, nil
+	case "tab":
+		p.list.MoveSelection(1)
+		return m, nil
+	case "shift+tab":
+		p.list.MoveSelection(-1)
+		return m, nil
+	}
+	p.list.Update(msg)
+	if p.chosen == "" {
+		return m, nil
+	}
+	m.moveWindow = nil
+	return m, tea.Sequence(moveWindowCmd(p.source, p.chosen, m.executorOrLocal(p.host)), m.fetchTreeCmd())
+}
+
+// moveWindowCmd moves a window to destSession via exec.
+func moveWindowCmd(src, destSession string, exec tmux.TmuxExecutor) tea.Cmd {
+	return func() tea.Msg {
+		err := tmux.MoveWindowWithExecutor(src, destSession, exec)
+		return CommandSentMsg{Target: destSession, Command: "move-window " + src, Err: err}
+	}
+}
+
+// renderMoveWindowOverlay draws the session picker centered over base.
+func (m Model) renderMoveWindowOverlay(base string) string {
+	p := m.moveWindow
+	rows := []string{
+		helpTitleStyle.Render("Move Window"),
+		"",
+		fmt.Sprintf("Move %s to session:", p.source),
+		p.list.View(40),
+		"",
+		lipgloss.NewStyle().Foreground(dimColor).Render("[Enter] move  [↑/↓] select  [Esc] cancel"),
+	}
+
+	box := helpOverlayStyle.Width(44).Render(strings.Join(rows, "\n"))
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestMoveWindowPicksSessionAndMovesThroughExecutor(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := NewModel(Options{})
+	m.width, m.height = 100, 30
+	m.executors = []tmux.TmuxExecutor{exec}
+	m.hostTrees = []tmux.HostTree{{
+		Host: "devbox",
+		Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{
+			windowWithPanes("work", 1), windowWithPanes("api", 1), windowWithPanes("docs", 1),
+		}},
+		Executor: exec,
+	}}
+	m.liveTree = true
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "window", "work:0")
+	m.contextMenu = NewContextMenu(m.selectedNode(), 0, 0)
+
+	updated, _ := m.executeMenuAction(MenuActionMoveWindow)
+	m = updated.(Model)
+	if m.moveWindow == nil || len(m.moveWindow.list.Items) != 2 {
+		t.Fatal("expected a picker listing the two other sessions")
+	}
+	base := strings.Repeat(strings.Repeat(" ", m.width)+"\n", m.height)
+	if view := m.renderMoveWindowOverlay(base); !strings.Contains(view, "> api") || strings.Contains(view, "work\n") {
+		t.Fatalf("unexpected picker:\n%s", view)
+	}
+
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := updated.(Model).handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if updated.(Model).moveWindow != nil || cmd == nil {
+		t.Fatal("expected Enter to close the picker and move the window")
+	}
+	if msg := runFirst(t, cmd); msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if len(exec.calls) != 1 || exec.calls[0] != "move-window -s work:0 -t docs:" {
+		t.Fatalf("unexpected tmux calls %v", exec.calls)
+	}
+}
+
+func TestMoveWindowNeedsAnotherSession(t *testing.T) {
+	m := NewModel(Options{})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 1)}}
+	m.rebuildFlatNodes()
+	updated, _ := m.openMoveWindow("work:0", "")
+	if m = updated.(Model); m.moveWindow != nil || m.lastError == nil {
+		t.Fatal("expected an error instead of an empty picker")
+	}
+}
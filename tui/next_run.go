@@ -0,0 +1,46 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// maxNextRunNameLen caps the job name shown in the next-run indicator.
+const maxNextRunNameLen = 20
+
+// nextRunMsg carries the next-run indicator text ("" when no job is enabled)
+// and the jobs it was chosen from.
+type nextRunMsg struct {
+	label string
+	jobs  []config.ScheduledJob
+}
+
+// loadNextRun finds the next scheduled job to fire. Schedule errors simply
+// hide the indicator.
+func loadNextRun() tea.Msg {
+	schedule, err := config.LoadSchedule()
+	if err != nil {
+		return nextRunMsg{}
+	}
+	jobs := schedule.SortedJobs()
+	return nextRunMsg{label: nextRunLabel(jobs), jobs: jobs}
+}
+
+// nextRunLabel describes the next enabled job, e.g. "next: backup in 12 min".
+// Jobs must be in SortedJobs order (enabled jobs first, soonest first).
+func nextRunLabel(jobs []config.ScheduledJob) string {
+	for _, job := range jobs {
+		if !job.Enabled {
+			break
+		}
+		if _, err := job.NextRun(); err != nil {
+			continue
+		}
+		name := job.Name
+		if name == "" {
+			name = job.Command
+		}
+		return "next: " + truncate(name, maxNextRunNameLen) + " " + job.FormatNextRun()
+	}
+	return ""
+}
@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
 )
 
 // OnboardResult contains the outcome of the onboard interaction.
@@ -25,6 +26,7 @@ type OnboardResult struct {
 
 // RunOnboard runs the interactive onboard TUI.
 func RunOnboard() (*OnboardResult, error) {
+	loadTheme()
 	m := newOnboardModel()
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	finalModel, err := p.Run()
@@ -50,6 +52,7 @@ type agentChoice struct {
 	enabled  bool
 	yolo     bool
 	flags    string
+	missing  bool // command not found on PATH
 }
 
 // keybindOption represents a single keybinding the user can toggle on/off.
@@ -70,6 +73,7 @@ type onboardModel struct {
 	step         int // 0=welcome, 1=agent selection, 2=flags, 3=confirm, 4=keybinding
 	cursor       int
 	agents       []agentChoice
+	registry     []config.AgentSpec // known agents (nil = the built-in ones)
 	completed    bool
 	keybindError string
 
@@ -114,13 +118,18 @@ func newOnboardModel() onboardModel {
 		sessionsOpt.conflict = cmd
 	}
 
+	// Start from the agents saved last time, so re-running onboard edits them
+	settings, _ := config.LoadSettings()
+	var agents []agentChoice
+	for _, p := range settings.EffectiveAgents() {
+		missing := !(config.AgentSpec{Command: p.Command}).Installed()
+		agents = append(agents, agentChoice{name: p.Name, command: p.Command, enabled: p.Enabled, yolo: p.Yolo, flags: p.Flags, missing: missing})
+	}
+
 	return onboardModel{
-		step: 0,
-		agents: []agentChoice{
-			{name: "Claude", command: "claude", enabled: true, yolo: true},
-			{name: "Codex", command: "codex", enabled: true, yolo: true},
-			{name: "Gemini CLI", command: "gemini", enabled: false, yolo: false},
-		},
+		step:           0,
+		agents:         agents,
+		registry:       settings.KnownAgents(),
 		keybindOptions: []keybindOption{browseOpt, sessionsOpt},
 	}
 }
@@ -185,6 +194,12 @@ func (m onboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "shift+up":
+			return m.moveAgent(-1)
+
+		case "shift+down":
+			return m.moveAgent(1)
+
 		case " ", "space":
 			return m.handleSpace()
 
@@ -330,6 +345,19 @@ func (m onboardModel) handleSpace() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// moveAgent swaps the agent under the cursor with its neighbour delta
+// places away in the agent-selection step. Agent order is pane order in
+// new sessions, so the first agent gets the focused pane.
+func (m onboardModel) moveAgent(delta int) (tea.Model, tea.Cmd) {
+	to := m.cursor + delta
+	if m.step != 1 || m.cursor >= len(m.agents) || to < 0 || to >= len(m.agents) {
+		return m, nil
+	}
+	m.agents[m.cursor], m.agents[to] = m.agents[to], m.agents[m.cursor]
+	m.cursor = to
+	return m, nil
+}
+
 func (m onboardModel) handleTab() (tea.Model, tea.Cmd) {
 	// Tab cycles through steps forward
 	if m.step < 3 {
@@ -419,40 +447,55 @@ func (m onboardModel) handleEditingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// knownAgents returns the agent registry the choices are checked against.
+func (m onboardModel) knownAgents() []config.AgentSpec {
+	if m.registry == nil {
+		return config.BuiltinAgents()
+	}
+	return m.registry
+}
+
+// agentPreferences returns the agent choices as they are saved in settings.
+func (m onboardModel) agentPreferences() []config.AgentPreference {
+	prefs := make([]config.AgentPreference, len(m.agents))
+	for i, a := range m.agents {
+		prefs[i] = config.AgentPreference{Name: a.name, Command: a.command, Enabled: a.enabled, Yolo: a.yolo, Flags: a.flags}
+	}
+	return prefs
+}
+
+// buildAgents returns the enabled agents' commands, built the same way as
+// the agent defaults in settings.
 func (m onboardModel) buildAgents() []config.AgentConfig {
 	var agents []config.AgentConfig
-	for _, a := range m.agents {
-		if !a.enabled {
-			continue
+	for _, p := range m.agentPreferences() {
+		if p.Enabled {
+			agents = append(agents, config.AgentConfig{Command: p.CommandLine(m.knownAgents())})
 		}
-		cmd := a.command
-		if a.yolo {
-			if a.command == "claude" {
-				cmd += " --dangerously-skip-permissions"
-			} else if a.command == "codex" {
-				cmd += " --yolo"
-			}
-			// Gemini CLI: no known yolo flag yet, use base command as-is
-		}
-		if a.flags != "" {
-			cmd += " " + a.flags
-		}
-		agents = append(agents, config.AgentConfig{Command: cmd})
 	}
 	return agents
 }
 
+// saveConfig saves the agent choices to settings.json, where new sessions
+// and the settings screen pick them up, and writes the global config. The
+// config lists the agents commented out, since agent: lines there would
+// override later changes in settings.
 func (m onboardModel) saveConfig() error {
-	agents := m.buildAgents()
+	settings, _ := config.LoadSettings()
+	settings.Agents = m.agentPreferences()
+	if err := settings.Save(); err != nil {
+		return err
+	}
 
 	// Build config content
 	var lines []string
 	lines = append(lines, "# atmux global configuration")
 	lines = append(lines, "# Generated by atmux onboard")
 	lines = append(lines, "")
-	lines = append(lines, "# Core agent panes")
-	for _, a := range agents {
-		lines = append(lines, "agent:"+a.Command)
+	lines = append(lines, "# Core agent panes come from settings.json (change them with `atmux settings`).")
+	lines = append(lines, "# Uncomment agent: lines to pin them here instead:")
+	for _, a := range m.buildAgents() {
+		lines = append(lines, "# agent:"+a.Command)
 	}
 	lines = append(lines, "")
 
@@ -524,7 +567,7 @@ func (m onboardModel) viewWelcome() string {
 
 func (m onboardModel) viewAgentSelection() string {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
-	checkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	checkStyle := lipgloss.NewStyle().Foreground(activeColor)
 	uncheckStyle := lipgloss.NewStyle().Foreground(dimColor)
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -534,7 +577,7 @@ func (m onboardModel) viewAgentSelection() string {
 	var lines []string
 	lines = append(lines, titleStyle.Render("Select Your Agents"))
 	lines = append(lines, "")
-	lines = append(lines, lipgloss.NewStyle().Foreground(dimColor).Render("Space to toggle, Enter to continue"))
+	lines = append(lines, lipgloss.NewStyle().Foreground(dimColor).Render("Space to toggle, Shift+↑↓ to reorder, Enter to continue"))
 	lines = append(lines, "")
 
 	for i, agent := range m.agents {
@@ -546,6 +589,9 @@ func (m onboardModel) viewAgentSelection() string {
 		}
 
 		line := fmt.Sprintf("%s %s", checkbox, agent.name)
+		if agent.missing {
+			line += " (not installed)"
+		}
 		if i == m.cursor {
 			line = selectedStyle.Render("> " + line)
 		} else {
@@ -554,18 +600,17 @@ func (m onboardModel) viewAgentSelection() string {
 		lines = append(lines, line)
 	}
 
-	// Show caution footnote if Gemini is enabled
-	geminiEnabled := false
+	// Show a caution footnote for each enabled experimental agent
+	var cautions []string
+	cautionStyle := lipgloss.NewStyle().Foreground(gettingStaleColor)
 	for _, agent := range m.agents {
-		if agent.command == "gemini" && agent.enabled {
-			geminiEnabled = true
-			break
+		if spec, ok := config.LookupAgent(m.knownAgents(), agent.command); ok && spec.Experimental && agent.enabled {
+			cautions = append(cautions, cautionStyle.Render("⚠ "+agent.name+" support is experimental and has not been extensively tested."))
 		}
 	}
-	if geminiEnabled {
+	if len(cautions) > 0 {
 		lines = append(lines, "")
-		cautionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
-		lines = append(lines, cautionStyle.Render("⚠ Gemini CLI support is experimental and has not been extensively tested."))
+		lines = append(lines, cautions...)
 	}
 
 	lines = append(lines, "")
@@ -583,7 +628,7 @@ func (m onboardModel) viewAgentSelection() string {
 
 func (m onboardModel) viewFlags() string {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
-	checkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	checkStyle := lipgloss.NewStyle().Foreground(activeColor)
 	uncheckStyle := lipgloss.NewStyle().Foreground(dimColor)
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -602,13 +647,9 @@ func (m onboardModel) viewFlags() string {
 			continue
 		}
 
-		yoloLabel := "Auto-approve (YOLO mode)"
-		if agent.command == "claude" {
-			yoloLabel = "--dangerously-skip-permissions"
-		} else if agent.command == "codex" {
-			yoloLabel = "--yolo"
-		} else if agent.command == "gemini" {
-			yoloLabel = "Auto-approve (not yet supported)"
+		yoloLabel := config.YoloFlag(m.knownAgents(), agent.command)
+		if yoloLabel == "" {
+			yoloLabel = "Auto-approve (YOLO mode)"
 		}
 
 		checkbox := "[ ]"
@@ -643,7 +684,7 @@ func (m onboardModel) viewFlags() string {
 
 func (m onboardModel) viewConfirm() string {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
-	codeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	codeStyle := lipgloss.NewStyle().Foreground(activeColor)
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(primaryColor).
@@ -716,10 +757,10 @@ func (m onboardModel) viewConfirm() string {
 
 func (m onboardModel) viewKeybind() string {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
-	codeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
-	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	codeStyle := lipgloss.NewStyle().Foreground(activeColor)
+	warnStyle := lipgloss.NewStyle().Foreground(gettingStaleColor)
 	descStyle := lipgloss.NewStyle().Foreground(dimColor)
-	checkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	checkStyle := lipgloss.NewStyle().Foreground(activeColor)
 	uncheckStyle := lipgloss.NewStyle().Foreground(dimColor)
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -810,7 +851,7 @@ func (m *onboardModel) addKeybindings() error {
 		existingContent = string(content)
 	}
 
-	// Build all selected binding lines
+	// Collect the selected binding lines for the managed block
 	var toAdd []string
 	for i, opt := range m.keybindOptions {
 		if !opt.enabled {
@@ -827,8 +868,7 @@ func (m *onboardModel) addKeybindings() error {
 			}
 			continue
 		}
-		commentLine := fmt.Sprintf("# atmux: %s (%s)", opt.label, opt.description)
-		toAdd = append(toAdd, commentLine, bindingLine)
+		toAdd = append(toAdd, bindingLine)
 		if i == 0 {
 			m.browseBindAdded = true
 			m.browseBindEnabled = true
@@ -842,28 +882,16 @@ func (m *onboardModel) addKeybindings() error {
 		return nil
 	}
 
-	// Append all bindings at once
-	f, err := os.OpenFile(tmuxConfPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("could not open %s for writing: %w", tmuxConfPath, err)
-	}
-	defer f.Close()
-
-	fullBinding := "\n" + strings.Join(toAdd, "\n") + "\n"
-	if _, err := f.WriteString(fullBinding); err != nil {
-		return fmt.Errorf("could not write to %s: %w", tmuxConfPath, err)
-	}
-
-	return nil
+	// Write all bindings at once, inside the block `atmux keybind remove` deletes
+	return tmux.UpdateTmuxConf(tmuxConfPath, func(content string) string {
+		for _, line := range toAdd {
+			content = tmux.AddManagedKeybinding(content, line)
+		}
+		return content
+	})
 }
 
 // findDuplicateKeybinding checks if the key is already bound in the config
 func findDuplicateKeybinding(content, key string) (bool, string) {
-	// Match bind-key or bind followed by the key
-	pattern := regexp.MustCompile(`(?m)^\s*bind(?:-key)?\s+` + regexp.QuoteMeta(key) + `\s+.*$`)
-	match := pattern.FindString(content)
-	if match != "" {
-		return true, strings.TrimSpace(match)
-	}
-	return false, ""
+	return tmux.FindDuplicateKeybinding(content, key)
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/config"
 )
 
 func TestOnboardSpaceTogglesAgentEnabled(t *testing.T) {
@@ -365,3 +366,109 @@ func TestOnboardKeybindIdempotent(t *testing.T) {
 		t.Fatal("expected browseBindAdded to be true even for existing binding")
 	}
 }
+
+func TestOnboardSavesAgentsToSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	settings := config.DefaultSettings()
+	settings.Agents = []config.AgentPreference{
+		{Name: "Claude", Command: "claude", Enabled: true, Flags: "--model opus"},
+		{Name: "Gemini CLI", Command: "gemini", Enabled: true},
+	}
+	if err := settings.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Onboard starts from the saved agents, then offers the rest disabled
+	m := newOnboardModel()
+	if len(m.agents) != 3 || m.agents[0].flags != "--model opus" || m.agents[0].yolo {
+		t.Fatalf("expected the saved agents, got %+v", m.agents)
+	}
+	if m.agents[2].name != "Codex" || m.agents[2].enabled {
+		t.Fatalf("expected Codex offered disabled after the saved agents, got %+v", m.agents[2])
+	}
+
+	m.agents[0].yolo = true
+	if err := m.saveConfig(); err != nil {
+		t.Fatalf("saveConfig: %v", err)
+	}
+	saved, err := config.LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.AgentCommands(); len(got) != 2 || got[0].Command != "claude --dangerously-skip-permissions --model opus" {
+		t.Fatalf("expected the choices saved to settings, got %+v", got)
+	}
+
+	// The config leaves the agents to settings
+	path, _ := config.GlobalConfigPath()
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.CoreAgents) != 0 {
+		t.Fatalf("expected no agent: lines overriding settings, got %+v", cfg.CoreAgents)
+	}
+}
+
+func TestOnboardGeminiYoloAddsFlag(t *testing.T) {
+	m := onboardModel{agents: []agentChoice{{name: "Gemini CLI", command: "gemini", enabled: true, yolo: true}}}
+	if got := m.buildAgents(); len(got) != 1 || got[0].Command != "gemini --yolo" {
+		t.Fatalf("expected gemini --yolo, got %+v", got)
+	}
+	m.width, m.height, m.step = 100, 40, 2
+	if view := m.View(); strings.Contains(view, "not yet supported") || !strings.Contains(view, "--yolo") {
+		t.Fatalf("expected the flags step to name gemini's --yolo flag, got:\n%s", view)
+	}
+}
+
+func TestOnboardUsesRegistryForCustomAgents(t *testing.T) {
+	m := onboardModel{
+		agents:   []agentChoice{{name: "Aider", command: "aider", enabled: true, yolo: true, missing: true}},
+		registry: []config.AgentSpec{{Name: "Aider", Command: "aider", YoloFlag: "--yes-always", Experimental: true}},
+		width:    100, height: 40, step: 1,
+	}
+	view := m.View()
+	if !strings.Contains(view, "Aider (not installed)") || !strings.Contains(view, "Aider support is experimental") {
+		t.Fatalf("expected the registry's install check and caution, got:\n%s", view)
+	}
+	if got := m.buildAgents(); len(got) != 1 || got[0].Command != "aider --yes-always" {
+		t.Fatalf("expected aider --yes-always, got %+v", got)
+	}
+}
+
+func TestOnboardShiftArrowsReorderAgents(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := onboardModel{step: 1, agents: []agentChoice{
+		{name: "Claude", command: "claude", enabled: true},
+		{name: "Codex", command: "codex", enabled: true},
+		{name: "Gemini CLI", command: "gemini"},
+	}}
+	press := func(key tea.KeyType) {
+		updated, _ := m.Update(tea.KeyMsg{Type: key})
+		m = updated.(onboardModel)
+	}
+
+	press(tea.KeyShiftUp) // already first: no change
+	press(tea.KeyDown)
+	press(tea.KeyShiftUp)
+	if m.agents[0].name != "Codex" || m.agents[1].name != "Claude" || m.cursor != 0 {
+		t.Fatalf("expected Codex moved first with the cursor on it, got cursor %d, %+v", m.cursor, m.agents)
+	}
+	press(tea.KeyShiftDown)
+	press(tea.KeyShiftDown)
+	press(tea.KeyShiftDown) // already last: no change
+	if names := []string{m.agents[0].name, m.agents[1].name, m.agents[2].name}; strings.Join(names, ",") != "Claude,Gemini CLI,Codex" || m.cursor != 2 {
+		t.Fatalf("expected Codex moved last, got cursor %d, %q", m.cursor, names)
+	}
+
+	if err := m.saveConfig(); err != nil {
+		t.Fatalf("saveConfig: %v", err)
+	}
+	saved, err := config.LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.AgentCommands(); len(got) != 2 || got[0].Command != "claude" || got[1].Command != "codex" {
+		t.Fatalf("expected the reordered agents saved in order, got %+v", got)
+	}
+}
@@ -19,6 +19,7 @@ type OpenResult struct {
 
 // RunOpen runs the quick open TUI.
 func RunOpen() (*OpenResult, error) {
+	loadTheme()
 	m := newOpenModel()
 	p := tea.NewProgram(m, tea.WithMouseCellMotion())
 	finalModel, err := p.Run()
@@ -210,7 +211,7 @@ func (m openModel) View() string {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
 	tabStyle := lipgloss.NewStyle().Foreground(dimColor)
 	activeTabStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Underline(true)
-	numStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	numStyle := lipgloss.NewStyle().Foreground(dimColor)
 	hintStyle := lipgloss.NewStyle().Foreground(dimColor)
 	numberWidth := len(fmt.Sprintf("%d", max(1, m.currentTabLen())))
 
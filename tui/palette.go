@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// maxPaletteItemsShown caps how many commands are listed in the palette.
+const maxPaletteItemsShown = 12
+
+// Palette-only actions (node actions reuse the context menu actions)
+const (
+	paletteActionRefresh     = "refresh"
+	paletteActionSearch      = "search"
+	paletteActionNewSession  = "new_session_here"
+	paletteActionToggleMouse = "toggle_mouse"
+	paletteActionPaneSize    = "toggle_pane_size"
+	paletteActionExpandAll   = "expand_all"
+	paletteActionCollapseAll = "collapse_all"
+	paletteActionAgentsView  = "toggle_agents_view"
+	paletteActionWatch       = "toggle_watch"
+	paletteActionSendMethod  = "cycle_send_method"
+	paletteActionHelp        = "help"
+	paletteActionQuit        = "quit"
+)
+
+// commandPalette holds state for the ctrl+p command palette overlay.
+type commandPalette struct {
+	input    textinput.Model
+	items    []MenuItem // All commands available for the current node
+	filtered []MenuItem // Commands matching the current query
+	selected int
+}
+
+// newCommandPalette creates a focused palette listing items.
+func newCommandPalette(items []MenuItem) *commandPalette {
+	ti := textinput.New()
+	ti.Placeholder = "Type a command..."
+	ti.CharLimit = 64
+	ti.Width = 40
+	ti.Focus()
+	p := &commandPalette{input: ti, items: items}
+	p.filter()
+	return p
+}
+
+// paletteItems returns the commands for the selected node (the same actions
+// as its context menu) followed by the global commands.
+func (m *Model) paletteItems() []MenuItem {
+	var items []MenuItem
+	if node := m.selectedNode(); node != nil {
+		var nodeItems []MenuItem
+		switch node.Type {
+		case "session":
+			nodeItems = sessionMenuItems()
+		case "window":
+			nodeItems = windowMenuItems(node.Synchronized)
+		case "pane":
+			nodeItems = paneMenuItems()
+		}
+		for _, item := range nodeItems {
+			if !item.Divider && !item.Disabled {
+				items = append(items, item)
+			}
+		}
+	}
+
+	items = append(items,
+		MenuItem{Label: "Refresh tree", Shortcut: "r", Action: paletteActionRefresh},
+		MenuItem{Label: "Search pane contents", Shortcut: "f", Action: paletteActionSearch},
+		MenuItem{Label: "New session for current directory", Shortcut: "n", Action: paletteActionNewSession},
+		MenuItem{Label: "Toggle mouse support", Shortcut: "M", Action: paletteActionToggleMouse},
+		MenuItem{Label: "Show/hide pane sizes", Shortcut: "i", Action: paletteActionPaneSize},
+		MenuItem{Label: "Expand all", Shortcut: "+", Action: paletteActionExpandAll},
+		MenuItem{Label: "Collapse all", Shortcut: "-", Action: paletteActionCollapseAll},
+		MenuItem{Label: "Show/hide non-agent panes", Shortcut: "A", Action: paletteActionAgentsView},
+		MenuItem{Label: "Watch pane until idle", Shortcut: "w", Action: paletteActionWatch},
+	)
+	if m.options.DebugMode {
+		items = append(items, MenuItem{Label: "Cycle send method", Shortcut: "m", Action: paletteActionSendMethod})
+	}
+	items = append(items,
+		MenuItem{Label: "Help", Shortcut: "?", Action: paletteActionHelp},
+		MenuItem{Label: "Quit", Shortcut: "q", Action: paletteActionQuit},
+	)
+	return items
+}
+
+// filter recomputes the filtered list for the current query, best matches first.
+func (p *commandPalette) filter() {
+	query := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	type scored struct {
+		item  MenuItem
+		score int
+	}
+	var matches []scored
+	for _, item := range p.items {
+		if score, ok := fuzzyScore(query, strings.ToLower(item.Label)); ok {
+			matches = append(matches, scored{item, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+
+	p.filtered = p.filtered[:0]
+	for _, s := range matches {
+		p.filtered = append(p.filtered, s.item)
+	}
+	if p.selected >= len(p.filtered) {
+		p.selected = len(p.filtered) - 1
+	}
+	if p.selected < 0 {
+		p.selected = 0
+	}
+}
+
+// fuzzyScore reports whether query's characters appear in order in label.
+// Lower scores are better: substring matches score by position, other
+// subsequence matches by how spread out the characters are.
+func fuzzyScore(query, label string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	if idx := strings.Index(label, query); idx != -1 {
+		return idx, true
+	}
+
+	qi := 0
+	first, last := -1, -1
+	q := []rune(query)
+	for i, r := range []rune(label) {
+		if qi < len(q) && r == q[qi] {
+			if first == -1 {
+				first = i
+			}
+			last = i
+			qi++
+		}
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Rank all subsequence matches after substring matches
+	return len(label) + (last - first), true
+}
+
+// handlePaletteKeys handles keys while the command palette is open.
+func (m Model) handlePaletteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.palette
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.palette = nil
+		return m, nil
+	case "up", "ctrl+p":
+		if p.selected > 0 {
+			p.selected--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if p.selected < len(p.filtered)-1 {
+			p.selected++
+		}
+		return m, nil
+	case "enter":
+		if p.selected >= 0 && p.selected < len(p.filtered) {
+			action := p.filtered[p.selected].Action
+			m.palette = nil
+			return m.runPaletteAction(action)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return m, cmd
+}
+
+// runPaletteAction executes a palette command against the selected node.
+func (m Model) runPaletteAction(action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case paletteActionRefresh:
+		m.resetHostRetries()
+		return m, tea.Batch(m.fetchTreeCmd(), fetchRecentSessions)
+	case paletteActionSearch:
+		m.search = newPaneSearch()
+		return m, textinput.Blink
+	case paletteActionNewSession:
+		return m.startNewSession()
+	case paletteActionToggleMouse:
+		return m, m.toggleMouse()
+	case paletteActionPaneSize:
+		m.showPaneSize = !m.showPaneSize
+		return m, nil
+	case paletteActionExpandAll, paletteActionCollapseAll:
+		m.setAllExpanded(action == paletteActionExpandAll)
+		m.calculateButtonZones()
+		return m, m.updatePreviewForSelection()
+	case paletteActionWatch:
+		m.toggleWatch()
+		return m, nil
+	case paletteActionAgentsView:
+		m.toggleAgentsView()
+		return m, m.updatePreviewForSelection()
+	case paletteActionSendMethod:
+		m.sendMethod = (m.sendMethod + 1) % tmux.SendMethodCount
+		return m, nil
+	case paletteActionHelp:
+		m.showHelp = true
+		return m, nil
+	case paletteActionQuit:
+		return m, tea.Quit
+	}
+
+	// Node actions run through the context menu for the selected node
+	node := m.selectedNode()
+	if node == nil {
+		return m, nil
+	}
+	m.contextMenu = NewContextMenu(node, 0, 0)
+	return m.executeMenuAction(action)
+}
+
+// renderPaletteOverlay renders the command palette on top of the base view.
+func (m Model) renderPaletteOverlay(base string) string {
+	p := m.palette
+	title := helpTitleStyle.Render("Commands")
+
+	width := m.width - 8
+	if width > 60 {
+		width = 60
+	}
+	if width < 30 {
+		width = 30
+	}
+
+	var rows []string
+	rows = append(rows, title, "", p.input.View(), "")
+
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+	if len(p.filtered) == 0 {
+		rows = append(rows, dim.Render("No matching commands"))
+	}
+	start := 0
+	if p.selected >= maxPaletteItemsShown {
+		start = p.selected - maxPaletteItemsShown + 1
+	}
+	for i := start; i < len(p.filtered) && i < start+maxPaletteItemsShown; i++ {
+		item := p.filtered[i]
+		line := item.Label
+		if item.Shortcut != "" {
+			line += "  " + menuShortcutStyle.Render(item.Shortcut)
+		}
+		line = ansi.Truncate(line, width-6, "...")
+		if i == p.selected {
+			line = selectedStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		rows = append(rows, line)
+	}
+
+	rows = append(rows, "", dim.Render("[Enter] run  [↑/↓] select  [Esc] close"))
+
+	box := helpOverlayStyle.Width(width).Render(strings.Join(rows, "\n"))
+
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 3
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
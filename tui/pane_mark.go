@@ -0,0 +1,62 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// isMarkedPane reports whether node is the marked pane.
+func (m *Model) isMarkedPane(node *tmux.TreeNode) bool {
+	return m.markedPane != nil && node.Type == "pane" &&
+		node.Host == m.markedPane.host && node.Target == m.markedPane.target
+}
+
+// toggleMarkedPane makes the selected pane the tmux marked pane, or clears
+// the mark if it already is.
+func (m Model) toggleMarkedPane() (tea.Model, tea.Cmd) {
+	node := m.selectedNode()
+	if node == nil || node.Type != "pane" {
+		return m, nil
+	}
+	marked := !m.isMarkedPane(node)
+	if marked {
+		m.markedPane = &paneRef{host: node.Host, target: node.Target}
+		m.lastNotice = "Marked " + node.Target + " (' jumps back to it)"
+	} else {
+		m.markedPane = nil
+		m.lastNotice = "Cleared the mark on " + node.Target
+	}
+	return m, setPaneMarkedCmd(node.Target, marked, m.executorOrLocal(node.Host))
+}
+
+// jumpToMarkedPane selects the marked pane, expanding the tree down to it.
+// A mark whose pane has closed is dropped.
+func (m Model) jumpToMarkedPane() (tea.Model, tea.Cmd) {
+	if m.markedPane == nil {
+		m.lastNotice = "No marked pane (` marks the selected pane)"
+		return m, nil
+	}
+	cmd := m.revealPane(m.markedPane.host, m.markedPane.target)
+	if node := m.selectedNode(); node == nil || !m.isMarkedPane(node) {
+		m.lastNotice = "The marked pane " + m.markedPane.target + " is gone"
+		m.markedPane = nil
+	}
+	return m, cmd
+}
+
+// paneMarkGlyph renders the marker shown after the marked pane.
+func (m *Model) paneMarkGlyph(node *tmux.TreeNode) string {
+	if !m.isMarkedPane(node) {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(activeColor).Bold(true).Render(" ◆")
+}
+
+// setPaneMarkedCmd sets or clears the marked pane via exec.
+func setPaneMarkedCmd(target string, marked bool, exec tmux.TmuxExecutor) tea.Cmd {
+	return func() tea.Msg {
+		err := tmux.SetPaneMarkedWithExecutor(target, marked, exec)
+		return CommandSentMsg{Target: target, Command: "select-pane -m", Err: err}
+	}
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMarkPaneAndJumpBack(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.2")
+	press := func(m Model, key string) (Model, tea.Cmd) {
+		updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(Model), cmd
+	}
+
+	m, cmd := press(m, "`")
+	if msg := cmd().(CommandSentMsg); msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if m.paneMarkGlyph(m.selectedNode()) == "" || exec.calls[0] != "select-pane -m -t work:0.2" {
+		t.Fatalf("expected work:0.2 marked, got calls %v", exec.calls)
+	}
+
+	// Collapse the tree so the jump has to reveal the pane again
+	m.setAllExpanded(false)
+	m.selectedIndex = 0
+	m, _ = press(m, "'")
+	if node := m.selectedNode(); node == nil || node.Target != "work:0.2" {
+		t.Fatalf("expected the jump to select work:0.2, got %+v", node)
+	}
+
+	m, cmd = press(m, "`")
+	cmd()
+	if m.markedPane != nil || exec.calls[1] != "select-pane -M -t work:0.2" {
+		t.Fatalf("expected the mark cleared, got calls %v", exec.calls)
+	}
+}
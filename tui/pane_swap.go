@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// paneRef identifies a pane across hosts.
+type paneRef struct {
+	host   string
+	target string
+}
+
+// swapPaneWith runs the two-step pane swap: the first call marks node, and
+// a call on another pane swaps the two and refreshes the tree. Picking the
+// marked pane again cancels.
+func (m Model) swapPaneWith(node *tmux.TreeNode) (tea.Model, tea.Cmd) {
+	from := m.swapFrom
+	if from == nil {
+		m.swapFrom = &paneRef{host: node.Host, target: node.Target}
+		m.lastNotice = fmt.Sprintf("Swapping %s: select the other pane and press Enter (Esc cancels)", node.Target)
+		return m, nil
+	}
+	m.swapFrom = nil
+	m.lastNotice = ""
+	if from.host == node.Host && from.target == node.Target {
+		return m, nil
+	}
+	if from.host != node.Host {
+		m.lastError = fmt.Errorf("can't swap panes on different hosts")
+		return m, nil
+	}
+	return m, tea.Sequence(swapPanesCmd(from.target, node.Target, m.executorOrLocal(node.Host)), m.fetchTreeCmd())
+}
+
+// swapGlyph renders the marker shown after the pane waiting to be swapped.
+func (m *Model) swapGlyph(node *tmux.TreeNode) string {
+	if m.swapFrom == nil || node.Type != "pane" || node.Host != m.swapFrom.host || node.Target != m.swapFrom.target {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(primaryColor).Bold(true).Render(" ⇄")
+}
+
+// swapPanesCmd swaps two panes via exec.
+func swapPanesCmd(a, b string, exec tmux.TmuxExecutor) tea.Cmd {
+	return func() tea.Msg {
+		err := tmux.SwapPanesWithExecutor(a, b, exec)
+		return CommandSentMsg{Target: b, Command: "swap-pane " + a, Err: err}
+	}
+}
+
+// breakPaneCmd breaks a pane into its own window via exec.
+func breakPaneCmd(target string, exec tmux.TmuxExecutor) tea.Cmd {
+	return func() tea.Msg {
+		err := tmux.BreakPaneWithExecutor(target, exec)
+		return CommandSentMsg{Target: target, Command: "break-pane", Err: err}
+	}
+}
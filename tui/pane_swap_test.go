@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runFirst runs the first command of a tea.Sequence, the action before the
+// tree refresh.
+func runFirst(t *testing.T, cmd tea.Cmd) CommandSentMsg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	return reflect.ValueOf(cmd()).Index(0).Interface().(tea.Cmd)().(CommandSentMsg)
+}
+
+func TestSwapPanesMarksThenSwapsThroughExecutor(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+	m.contextMenu = NewContextMenu(m.selectedNode(), 0, 0)
+
+	updated, cmd := m.executeMenuAction(MenuActionSwapPane)
+	m = updated.(Model)
+	if cmd != nil || m.swapFrom == nil || m.swapGlyph(m.selectedNode()) == "" {
+		t.Fatal("expected the first pane to be marked for the swap")
+	}
+
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.2")
+	updated, cmd = m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if updated.(Model).swapFrom != nil {
+		t.Fatal("expected the swap mark to clear")
+	}
+	if msg := runFirst(t, cmd); msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if len(exec.calls) != 1 || exec.calls[0] != "swap-pane -d -s work:0.0 -t work:0.2" {
+		t.Fatalf("unexpected tmux calls %v", exec.calls)
+	}
+
+	// Esc cancels a marked swap
+	m.swapFrom = &paneRef{host: "devbox", target: "work:0.1"}
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).swapFrom != nil {
+		t.Fatal("expected Esc to cancel the swap")
+	}
+}
+
+func TestZoomKeyTogglesPaneAndShowsGlyph(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	m.hostTrees[0].Tree.Sessions[0].Windows[0].Zoomed = true
+	m.rebuildFlatNodes()
+
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+	if zoomGlyph(m.selectedNode()) == "" || zoomGlyph(m.flatNodes[nodeIndex(t, m, "pane", "work:0.1")]) != "" {
+		t.Fatal("expected the glyph on the zoomed (active) pane only")
+	}
+
+	m.selectedIndex = nodeIndex(t, m, "window", "work:0")
+	if zoomGlyph(m.selectedNode()) == "" {
+		t.Fatal("expected the glyph on the zoomed window")
+	}
+	_, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if msg := runFirst(t, cmd); msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if len(exec.calls) != 1 || exec.calls[0] != "resize-pane -t work:0 -Z" {
+		t.Fatalf("unexpected tmux calls %v", exec.calls)
+	}
+}
+
+func TestInterruptKeySendsCtrlCThroughExecutor(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.1")
+
+	_, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'^'}})
+	msg := cmd().(CommandSentMsg)
+	if msg.Err != nil || msg.Target != "work:0.1" {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	if len(exec.calls) != 1 || exec.calls[0] != "send-keys -t work:0.1 C-c" {
+		t.Fatalf("unexpected tmux calls %v", exec.calls)
+	}
+
+	updated, _ := m.Update(msg)
+	if sent := updated.(Model).lastSent; sent != "C-c -> work:0.1" {
+		t.Fatalf("expected the interrupt in the status bar, got %q", sent)
+	}
+}
+
+func TestBreakPaneThroughExecutor(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := remoteBulkKillModel(t, exec)
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.1")
+	m.contextMenu = NewContextMenu(m.selectedNode(), 0, 0)
+
+	_, cmd := m.executeMenuAction(MenuActionBreakPane)
+	if msg := runFirst(t, cmd); msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if len(exec.calls) != 1 || exec.calls[0] != "break-pane -d -s work:0.1" {
+		t.Fatalf("unexpected tmux calls %v", exec.calls)
+	}
+}
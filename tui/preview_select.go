@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// previewSelection is a mouse drag over the preview content, in content
+// line and column coordinates. anchor is where the drag started.
+type previewSelection struct {
+	anchorLine, anchorCol int
+	line, col             int
+}
+
+// bounds returns the selection start and end, start first. The end column
+// is inclusive.
+func (s previewSelection) bounds() (startLine, startCol, endLine, endCol int) {
+	if s.line < s.anchorLine || (s.line == s.anchorLine && s.col < s.anchorCol) {
+		return s.line, s.col, s.anchorLine, s.anchorCol
+	}
+	return s.anchorLine, s.anchorCol, s.line, s.col
+}
+
+// empty reports whether the drag never left the cell it started on, which
+// is a plain click.
+func (s previewSelection) empty() bool {
+	return s.line == s.anchorLine && s.col == s.anchorCol
+}
+
+// columns returns the half-open column range selected on line i.
+func (s previewSelection) columns(i, width int) (from, to int) {
+	startLine, startCol, endLine, endCol := s.bounds()
+	from, to = 0, width
+	if i == startLine {
+		from = startCol
+	}
+	if i == endLine {
+		to = min(endCol+1, width)
+	}
+	return from, max(from, to)
+}
+
+// text returns the selected plain text of content.
+func (s previewSelection) text(content string) string {
+	lines := strings.Split(content, "\n")
+	startLine, _, endLine, _ := s.bounds()
+	var out []string
+	for i := startLine; i <= endLine && i < len(lines); i++ {
+		plain := ansi.Strip(lines[i])
+		from, to := s.columns(i, ansi.StringWidth(plain))
+		out = append(out, strings.TrimRight(ansi.Cut(plain, from, to), " "))
+	}
+	return strings.Join(out, "\n")
+}
+
+// highlight returns content with the selection shown in reverse video.
+func (s previewSelection) highlight(content string) string {
+	lines := strings.Split(content, "\n")
+	startLine, _, endLine, _ := s.bounds()
+	style := lipgloss.NewStyle().Reverse(true)
+	for i := startLine; i <= endLine && i < len(lines); i++ {
+		line := lines[i]
+		width := ansi.StringWidth(line)
+		from, to := s.columns(i, width)
+		if from >= to {
+			continue
+		}
+		lines[i] = ansi.Cut(line, 0, from) +
+			style.Render(ansi.Strip(ansi.Cut(line, from, to))) +
+			ansi.Cut(line, to, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// previewContentOrigin returns the screen position of the first preview
+// content cell: inside the border, below the target header.
+func (m *Model) previewContentOrigin() (x, y int) {
+	x, y = 1, m.inputAreaHeight()+2
+	switch {
+	case m.previewZoomed:
+	case m.stacked:
+		y += m.stackedTreeHeight + 2
+	default:
+		x += m.treeWidth + 2
+	}
+	return x, y
+}
+
+// previewPosAt maps a screen position to a preview content line and
+// column, clamped to the visible content. ok is false when there is no
+// pane content to select, or (unless clamp is set) the position is
+// outside it.
+func (m *Model) previewPosAt(x, y int, clamp bool) (line, col int, ok bool) {
+	node := m.selectedNode()
+	if node == nil || node.Type != "pane" || m.previewContent == "" {
+		return 0, 0, false
+	}
+	ox, oy := m.previewContentOrigin()
+	row, col := y-oy, x-ox
+	if !clamp && (row < 0 || row >= m.previewPort.Height || col < 0 || col >= m.previewPort.Width) {
+		return 0, 0, false
+	}
+	row = min(max(row, 0), m.previewPort.Height-1)
+	col = min(max(col, 0), m.previewPort.Width-1)
+	lastLine := strings.Count(m.previewContent, "\n")
+	return min(m.previewPort.YOffset+row, lastLine), col, true
+}
+
+// startPreviewSelection begins a drag selection if (x, y) is on preview
+// content.
+func (m *Model) startPreviewSelection(x, y int) {
+	if line, col, ok := m.previewPosAt(x, y, false); ok {
+		m.previewSelect = &previewSelection{anchorLine: line, anchorCol: col, line: line, col: col}
+	}
+}
+
+// extendPreviewSelection moves the end of the drag selection.
+func (m *Model) extendPreviewSelection(x, y int) {
+	if line, col, ok := m.previewPosAt(x, y, true); ok {
+		m.previewSelect.line, m.previewSelect.col = line, col
+	}
+}
+
+// finishPreviewSelection ends the drag and copies the selection, like
+// tmux copy mode does on release. A plain click copies nothing.
+func (m *Model) finishPreviewSelection() tea.Cmd {
+	sel := *m.previewSelect
+	m.previewSelect = nil
+	if sel.empty() {
+		return nil
+	}
+	text := sel.text(m.previewContent)
+	startLine, _, endLine, _ := sel.bounds()
+	what := fmt.Sprintf("%d lines from the preview", endLine-startLine+1)
+	if startLine == endLine {
+		what = "selection from the preview"
+	}
+	return func() tea.Msg {
+		method, err := tmux.CopyToClipboard(text)
+		return ClipboardCopiedMsg{What: what, Method: method, Err: err}
+	}
+}
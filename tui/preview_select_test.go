@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestPreviewSelectionText(t *testing.T) {
+	content := "\x1b[32m$ make\x1b[0m test\nok  pkg 0.1s\nPASS"
+	sel := previewSelection{anchorLine: 1, anchorCol: 3, line: 0, col: 2}
+	if got, want := sel.text(content), "make test\nok"; got != want {
+		t.Fatalf("text = %q, want %q", got, want)
+	}
+
+	// The highlight keeps the line text intact around the selection
+	highlighted := sel.highlight(content)
+	if got := (previewSelection{line: 0, col: 0, anchorLine: 2, anchorCol: 3}).text(highlighted); got != "$ make test\nok  pkg 0.1s\nPASS" {
+		t.Fatalf("highlight changed the text: %q", got)
+	}
+}
+
+func previewSelectModel(t *testing.T) Model {
+	t.Helper()
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 40
+	m.calculateLayout()
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 1)}}
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+	m.previewTarget = "work:0.0"
+	m.previewContent = "first line\nsecond line\nthird line"
+	m.previewPort.SetContent(m.previewContent)
+	m.previewPort.GotoTop()
+	return m
+}
+
+func mouse(action tea.MouseAction, x, y int) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Action: action, Button: tea.MouseButtonLeft}
+}
+
+func TestPreviewDragSelectsAndCopiesOnRelease(t *testing.T) {
+	m := previewSelectModel(t)
+	ox, oy := m.previewContentOrigin()
+
+	updated, _ := m.handleMouseMsg(mouse(tea.MouseActionPress, ox+6, oy))
+	updated, _ = updated.(Model).handleMouseMsg(mouse(tea.MouseActionMotion, ox+5, oy+1))
+	m = updated.(Model)
+	if m.focused != FocusPreview || m.previewSelect == nil {
+		t.Fatal("expected a drag selection in the focused preview")
+	}
+	if got := m.previewSelect.text(m.previewContent); got != "line\nsecond" {
+		t.Fatalf("selected %q", got)
+	}
+
+	// Preview refreshes wait until the drag ends
+	updated, _ = m.Update(PreviewUpdatedMsg{Target: "work:0.0", Content: "changed"})
+	m = updated.(Model)
+	if m.previewContent == "changed" {
+		t.Fatal("expected the content to hold still during the drag")
+	}
+
+	updated, cmd := m.handleMouseMsg(mouse(tea.MouseActionRelease, ox+5, oy+1))
+	if updated.(Model).previewSelect != nil || cmd == nil {
+		t.Fatal("expected release to end the selection and copy it")
+	}
+}
+
+func TestPreviewClickDoesNotCopy(t *testing.T) {
+	m := previewSelectModel(t)
+	ox, oy := m.previewContentOrigin()
+	updated, _ := m.handleMouseMsg(mouse(tea.MouseActionPress, ox+2, oy))
+	updated, cmd := updated.(Model).handleMouseMsg(mouse(tea.MouseActionRelease, ox+2, oy))
+	if updated.(Model).previewSelect != nil || cmd != nil {
+		t.Fatal("expected a plain click to copy nothing")
+	}
+}
+
+func TestPreviewSelectionIgnoredWhileResizing(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // Ending a resize saves the width
+	m := previewSelectModel(t)
+	ox, oy := m.previewContentOrigin()
+	m.resizing = true
+	updated, _ := m.handleMouseMsg(mouse(tea.MouseActionMotion, ox+5, oy+1))
+	updated, _ = updated.(Model).handleMouseMsg(mouse(tea.MouseActionRelease, ox+5, oy+1))
+	m = updated.(Model)
+	if m.previewSelect != nil || m.resizing {
+		t.Fatal("expected the divider drag to take the mouse")
+	}
+}
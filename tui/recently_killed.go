@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/history"
+)
+
+// killUndoWindow is how long the quick revive is offered after a kill.
+const killUndoWindow = 10 * time.Second
+
+// killUndoExpiredMsg withdraws the quick revive for kill number seq, unless
+// a later kill has replaced it.
+type killUndoExpiredMsg struct {
+	seq int
+}
+
+// offerKillUndo offers to revive a session that was just killed from its
+// history entry, if it has a local one to recreate it from. The offer
+// clears on the next key or after killUndoWindow.
+func (m *sessionsModel) offerKillUndo(name string) tea.Cmd {
+	m.recentlyKilled = nil
+	for _, entry := range m.rawHistoryEntries {
+		if entry.SessionName == name && entry.Host == "" && entry.WorkingDirectory != "" {
+			m.recentlyKilled = &entry
+			break
+		}
+	}
+	if m.recentlyKilled == nil {
+		return nil
+	}
+	m.killUndoSeq++
+	seq := m.killUndoSeq
+	return tea.Tick(killUndoWindow, func(time.Time) tea.Msg {
+		return killUndoExpiredMsg{seq: seq}
+	})
+}
+
+// reviveKilled recreates the session just killed in its old working
+// directory and attaches to it, as reviving its recent entry would.
+func (m sessionsModel) reviveKilled(entry history.Entry) (tea.Model, tea.Cmd) {
+	m.attachSession = entry.SessionName
+	m.reviveDir = entry.WorkingDirectory
+	m.isHistorySelection = true
+	m.selectedHost = ""
+	return m, tea.Quit
+}
+
+// killUndoFooter renders the quick revive offer shown in place of the tip.
+func (m sessionsModel) killUndoFooter() string {
+	entry := m.recentlyKilled
+	text := fmt.Sprintf("Killed %s · u to revive in %s", entry.SessionName, shortenHomePath(entry.WorkingDirectory))
+	return lipgloss.NewStyle().Foreground(activeColor).Render(text)
+}
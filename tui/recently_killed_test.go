@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/history"
+)
+
+func killedSessionsModel() sessionsModel {
+	m := newSessionsModel(nil, false, false)
+	m.width, m.height = 120, 40
+	m.rawHistoryEntries = []history.Entry{
+		{ID: 1, SessionName: "agent-api", WorkingDirectory: "/work/api"},
+		{ID: 2, SessionName: "agent-web", WorkingDirectory: "/work/web", Host: "devbox"},
+	}
+	return m
+}
+
+func TestKillOffersQuickRevive(t *testing.T) {
+	m := killedSessionsModel()
+	updated, _ := m.Update(killSessionMsg{sessionName: "agent-api"})
+	m = updated.(sessionsModel)
+	if m.recentlyKilled == nil || m.recentlyKilled.WorkingDirectory != "/work/api" {
+		t.Fatalf("expected a quick revive offered for agent-api, got %+v", m.recentlyKilled)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Killed agent-api · u to revive in /work/api") {
+		t.Fatalf("expected the offer in the footer, got:\n%s", view)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	m = updated.(sessionsModel)
+	if cmd == nil || !m.isHistorySelection || m.attachSession != "agent-api" || m.reviveDir != "/work/api" {
+		t.Fatalf("expected u to revive agent-api in /work/api, got session %q dir %q", m.attachSession, m.reviveDir)
+	}
+}
+
+func TestKillUndoClearsOnNextKeyOrTimeout(t *testing.T) {
+	m := killedSessionsModel()
+	updated, _ := m.Update(killSessionMsg{sessionName: "agent-api"})
+	updated, _ = updated.(sessionsModel).Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.(sessionsModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if m := updated.(sessionsModel); m.recentlyKilled != nil || m.attachSession != "" {
+		t.Fatalf("expected the offer gone after another key, got %+v", m.recentlyKilled)
+	}
+
+	updated, _ = updated.(sessionsModel).Update(killSessionMsg{sessionName: "agent-api"})
+	m = updated.(sessionsModel)
+	stale := killUndoExpiredMsg{seq: m.killUndoSeq - 1}
+	if updated, _ = m.Update(stale); updated.(sessionsModel).recentlyKilled == nil {
+		t.Fatal("expected an earlier kill's expiry to leave the offer alone")
+	}
+	if updated, _ = m.Update(killUndoExpiredMsg{seq: m.killUndoSeq}); updated.(sessionsModel).recentlyKilled != nil {
+		t.Fatal("expected the offer to time out")
+	}
+}
+
+func TestKillWithoutLocalHistoryOffersNothing(t *testing.T) {
+	m := killedSessionsModel()
+	for _, name := range []string{"agent-web", "scratch"} {
+		updated, _ := m.Update(killSessionMsg{sessionName: name})
+		if got := updated.(sessionsModel).recentlyKilled; got != nil {
+			t.Fatalf("%s: expected no quick revive, got %+v", name, got)
+		}
+	}
+}
@@ -27,6 +27,7 @@ type RecentsResult struct {
 
 // RunRecents runs the recents TUI and returns the selected session.
 func RunRecents(opts RecentsOptions) (*RecentsResult, error) {
+	loadTheme()
 	m := newRecentsModel(opts)
 	programOptions := []tea.ProgramOption{
 		tea.WithMouseCellMotion(),
@@ -359,7 +360,7 @@ func (m recentsModel) renderEntry(entry history.Entry, selected bool) string {
 	// Show host label for remote entries
 	hostLabel := ""
 	if entry.Host != "" {
-		hostStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		hostStyle := lipgloss.NewStyle().Foreground(remoteHostColor)
 		hostLabel = hostStyle.Render("@"+entry.Host) + "  "
 	}
 
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// selectedRemoteProject returns the remote project under the cursor. Remote
+// projects are listed after the active sessions and recent entries.
+func (m sessionsModel) selectedRemoteProject() (config.ResolvedRemoteProject, bool) {
+	idx := m.selectedIndex - len(m.lines) - len(m.historyEntries)
+	if idx < 0 || idx >= len(m.remoteProjects) {
+		return config.ResolvedRemoteProject{}, false
+	}
+	return m.remoteProjects[idx], true
+}
+
+// remoteProjectDestination describes where a remote project connects, e.g.
+// "user@devbox:2222 via mosh".
+func remoteProjectDestination(rh config.RemoteHostConfig) string {
+	dest := rh.Host
+	if rh.Port > 0 && rh.Port != 22 {
+		dest = fmt.Sprintf("%s:%d", dest, rh.Port)
+	}
+	return dest + " via " + rh.AttachMethod
+}
+
+// renderRemoteProjectRows renders the Remote Projects section rows, showing
+// the resolved host and working directory for each entry.
+func (m sessionsModel) renderRemoteProjectRows() []string {
+	offset := len(m.lines) + len(m.historyEntries)
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+	var rows []string
+	for i, project := range m.remoteProjects {
+		details := dim.Render(remoteProjectDestination(project.Remote) + "  " + project.WorkingDir)
+		if offset+i == m.selectedIndex {
+			rows = append(rows, selectedStyle.Render("> "+project.Name)+"  "+details)
+		} else {
+			rows = append(rows, "  "+project.Name+"  "+details)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/history"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestRemoteProjectSelectionReturnsProject(t *testing.T) {
+	project := config.ResolvedRemoteProject{
+		RemoteProjectConfig: config.RemoteProjectConfig{
+			Name:        "atmux",
+			Host:        "devbox",
+			WorkingDir:  "/home/user/atmux",
+			SessionName: "agent-atmux",
+		},
+		Remote: config.RemoteHostConfig{Host: "user@devbox", Port: 2222, AttachMethod: "mosh", Alias: "devbox"},
+	}
+	m := sessionsModel{
+		lines:          []tmux.SessionLine{{Name: "agent-api", Line: "agent-api: 1 windows"}},
+		historyEntries: []history.Entry{{ID: 1, Name: "web", SessionName: "agent-web", WorkingDirectory: "/tmp/web"}},
+		remoteProjects: []config.ResolvedRemoteProject{project},
+		width:          120,
+		height:         30,
+	}
+
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "Remote Projects") || !strings.Contains(view, "user@devbox:2222 via mosh  /home/user/atmux") {
+		t.Fatalf("expected the remote project with its resolved host and dir, got:\n%s", view)
+	}
+
+	m.selectedIndex = m.totalItems() - 1
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(sessionsModel)
+	if m.remoteProject == nil || m.remoteProject.Remote.Host != "user@devbox" || m.attachSession != "agent-atmux" {
+		t.Fatalf("expected Enter to select the remote project, got %+v", m.remoteProject)
+	}
+	if m.isHistorySelection {
+		t.Fatal("expected a remote project not to be treated as a history revival")
+	}
+}
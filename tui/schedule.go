@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
 )
 
 // SchedulerOptions configures the scheduler TUI
@@ -16,6 +17,7 @@ type SchedulerOptions struct {
 
 // RunScheduler runs the scheduler management TUI
 func RunScheduler(opts SchedulerOptions) error {
+	loadTheme()
 	m := newSchedulerModel()
 	programOptions := []tea.ProgramOption{
 		tea.WithMouseCellMotion(),
@@ -41,6 +43,13 @@ type schedulerModel struct {
 	confirmDelete bool
 	deleteJobID   string
 
+	// Run history of the selected job, from the scheduler's run log
+	runs     []config.ScheduleRun
+	showRuns bool
+
+	// IDs of jobs whose target is gone from tmux, flagged in the list
+	missingTargets map[string]bool
+
 	// Sub-model for add/edit wizard
 	wizardActive bool
 	wizard       *scheduleWizardModel
@@ -60,13 +69,39 @@ func (m schedulerModel) Init() tea.Cmd {
 // loadSchedule loads the schedule from disk
 func loadSchedule() tea.Msg {
 	schedule, err := config.LoadSchedule()
-	return scheduleLoadedMsg{schedule: schedule, err: err}
+	runs, runsErr := config.LoadScheduleRuns()
+	if err == nil {
+		err = runsErr
+	}
+	return scheduleLoadedMsg{schedule: schedule, runs: runs, missingTargets: missingJobTargets(schedule), err: err}
 }
 
+// missingJobTargets returns the IDs of jobs whose target no longer exists,
+// or nil when tmux can't be queried.
+func missingJobTargets(schedule *config.Schedule) map[string]bool {
+	tree, err := tmux.FetchTree()
+	if err != nil {
+		return nil
+	}
+	missing := make(map[string]bool)
+	for _, job := range schedule.Jobs {
+		if tmux.MissingJobTarget(job, tree) {
+			missing[job.ID] = true
+		}
+	}
+	return missing
+}
+
+// maxShownRuns is how many recent runs the history panel lists
+const maxShownRuns = 10
+
 // scheduleLoadedMsg is sent when schedule is loaded
 type scheduleLoadedMsg struct {
 	schedule *config.Schedule
+	runs     []config.ScheduleRun
 	err      error
+
+	missingTargets map[string]bool
 }
 
 // jobDeletedMsg is sent after a job is deleted
@@ -96,6 +131,8 @@ func (m schedulerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.schedule = msg.schedule
 			m.jobs = msg.schedule.SortedJobs()
 		}
+		m.runs = msg.runs
+		m.missingTargets = msg.missingTargets
 		m.clampSelection()
 		return m, nil
 
@@ -189,6 +226,11 @@ func (m schedulerModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "h":
+		// Show or hide the selected job's recent runs
+		m.showRuns = !m.showRuns
+		return m, nil
+
 	case "d", "x":
 		// Delete job
 		if m.selectedIndex >= 0 && m.selectedIndex < len(m.jobs) {
@@ -288,7 +330,7 @@ func (m schedulerModel) View() string {
 	sections = append(sections, subtitle)
 
 	// Hints
-	hints := schedHintStyle.Render("[a]dd [Enter]edit [e]nable/disable [d]elete [q]uit")
+	hints := schedHintStyle.Render("[a]dd [Enter]edit [e]nable/disable [d]elete [h]istory [q]uit")
 	sections = append(sections, hints)
 
 	// Error display
@@ -320,6 +362,15 @@ func (m schedulerModel) View() string {
 			row := m.renderJobRow(job, i == m.selectedIndex)
 			sections = append(sections, row)
 		}
+		if len(m.missingTargets) > 0 {
+			note := "⚠ Target no longer exists; runs are skipped (new-session jobs recreate it)"
+			sections = append(sections, lipgloss.NewStyle().Foreground(gettingStaleColor).Render(note))
+		}
+
+		if m.showRuns && m.selectedIndex < len(m.jobs) {
+			sections = append(sections, "")
+			sections = append(sections, m.renderRuns(m.jobs[m.selectedIndex]))
+		}
 	}
 
 	// Tips at bottom
@@ -346,12 +397,34 @@ func (m schedulerModel) renderDeleteConfirm() string {
 	return schedConfirmStyle.Render(text)
 }
 
+// renderRuns lists the job's most recent runs, newest first, with the pane
+// each went to and any error.
+func (m schedulerModel) renderRuns(job config.ScheduledJob) string {
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("Recent runs")}
+	runs := config.RecentRuns(m.runs, job.ID, maxShownRuns)
+	if len(runs) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(dimColor).Italic(true).Render("No runs recorded yet."))
+	}
+	for _, run := range runs {
+		status := schedStatusActiveStyle.Render("ok  ")
+		if !run.Succeeded() {
+			status = lipgloss.NewStyle().Foreground(errorColor).Render("fail")
+		}
+		row := status + " " + run.At.Local().Format("Jan 2 15:04") + "  " + schedTargetStyle.Render(truncate(run.Target, 29))
+		if !run.Succeeded() {
+			row += "  " + lipgloss.NewStyle().Foreground(errorColor).Render(truncate(run.Error, 50))
+		}
+		lines = append(lines, row)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m schedulerModel) renderJobHeader() string {
 	statusCol := lipgloss.NewStyle().Width(8).Render("Status")
 	schedCol := lipgloss.NewStyle().Width(20).Render("Schedule")
 	targetCol := lipgloss.NewStyle().Width(20).Render("Target")
 	commandCol := lipgloss.NewStyle().Width(30).Render("Command")
-	nextCol := lipgloss.NewStyle().Width(15).Render("Next Run")
+	nextCol := lipgloss.NewStyle().Width(24).Render("Next Run")
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, statusCol, schedCol, targetCol, commandCol, nextCol)
 }
@@ -371,7 +444,10 @@ func (m schedulerModel) renderJobRow(job config.ScheduledJob, selected bool) str
 	schedCol := lipgloss.NewStyle().Width(20).Render(truncate(schedDesc, 19))
 
 	// Target
-	targetCol := schedTargetStyle.Width(20).Render(truncate(job.Target, 19))
+	targetCol := schedTargetStyle.Width(20).Render(truncate(job.TargetLabel(), 19))
+	if m.missingTargets[job.ID] {
+		targetCol = lipgloss.NewStyle().Foreground(gettingStaleColor).Width(20).Render("⚠ " + truncate(job.TargetLabel(), 17))
+	}
 
 	// Command
 	cmdDisplay := job.Command
@@ -381,11 +457,11 @@ func (m schedulerModel) renderJobRow(job config.ScheduledJob, selected bool) str
 	commandCol := lipgloss.NewStyle().Width(30).Render(truncate(cmdDisplay, 29))
 
 	// Next run
-	nextRun := config.FormatNextRun(job.CronExpr)
+	nextRun := job.FormatNextRun()
 	if !job.Enabled {
 		nextRun = "-"
 	}
-	nextCol := lipgloss.NewStyle().Width(15).Render(nextRun)
+	nextCol := lipgloss.NewStyle().Width(24).Render(nextRun)
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top, statusCol, schedCol, targetCol, commandCol, nextCol)
 
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,6 +12,9 @@ import (
 	"github.com/porganisciak/agent-tmux/tmux"
 )
 
+// allAgentsNodeType marks the target list entry that sends to every agent pane
+const allAgentsNodeType = "all_agents"
+
 // FormField identifies which section of the form is focused
 type FormField int
 
@@ -19,7 +23,10 @@ const (
 	FieldTarget
 	FieldCommand
 	FieldName
+	FieldTimezone
 	FieldPreAction
+	FieldSendMethod
+	FieldCatchUp
 	FieldButtons
 )
 
@@ -44,17 +51,32 @@ type scheduleWizardModel struct {
 	flatNodes      []*tmux.TreeNode
 	targetIndex    int
 	targetExpand   map[string]bool
-	selectedTarget string // stored target string for display when unfocused
+	selectedTarget string            // stored target string for display when unfocused
+	targetMode     config.TargetMode // TargetModeAllAgents when "All agent panes" is chosen
 
 	// Command input
 	commandInput textinput.Model
 	nameInput    textinput.Model
 
+	// Timezone the schedule is read in; empty means local time
+	timezoneInput textinput.Model
+	timezoneError string
+
 	// Pre-action
 	preActions      []config.PreAction
 	preActionIndex  int
 	preActionLabels []string
 
+	// How the command is put into the pane
+	sendAsMethods []config.JobSendMethod
+	sendAsIndex   int
+	sendAsLabels  []string
+
+	// Catch-up policy for runs missed while the machine was off
+	catchUps      []config.CatchUpPolicy
+	catchUpIndex  int
+	catchUpLabels []string
+
 	// Buttons
 	buttonFocusIdx int // 0=save, 1=cancel
 
@@ -77,6 +99,11 @@ func newScheduleWizardModel(existingJob *config.ScheduledJob) *scheduleWizardMod
 	nameInput.CharLimit = 50
 	nameInput.Width = 40
 
+	timezoneInput := textinput.New()
+	timezoneInput.Placeholder = "Local time (or e.g. UTC, Europe/Berlin)"
+	timezoneInput.CharLimit = 64
+	timezoneInput.Width = 40
+
 	preActions := []config.PreAction{
 		config.PreActionNone,
 		config.PreActionCompact,
@@ -87,6 +114,22 @@ func newScheduleWizardModel(existingJob *config.ScheduledJob) *scheduleWizardMod
 		"Compact first - Run /compact before sending",
 		"New session - Create new session first",
 	}
+	sendAsMethods := []config.JobSendMethod{
+		config.JobSendKeys,
+		config.JobSendBracketedPaste,
+	}
+	sendAsLabels := []string{
+		"Type - Type the command, then press Enter",
+		"Paste - Insert multi-line text in one piece, then press Enter",
+	}
+	catchUps := []config.CatchUpPolicy{
+		config.CatchUpSkip,
+		config.CatchUpRunOnce,
+	}
+	catchUpLabels := []string{
+		"Skip - Wait for the next scheduled time",
+		"Run once - Send once when possible, however many were missed",
+	}
 
 	m := &scheduleWizardModel{
 		focusedField:    FieldSchedule,
@@ -95,8 +138,13 @@ func newScheduleWizardModel(existingJob *config.ScheduledJob) *scheduleWizardMod
 		cronFields:      [5]string{"*", "*", "*", "*", "*"},
 		commandInput:    cmdInput,
 		nameInput:       nameInput,
+		timezoneInput:   timezoneInput,
 		preActions:      preActions,
 		preActionLabels: preActionLabels,
+		sendAsMethods:   sendAsMethods,
+		sendAsLabels:    sendAsLabels,
+		catchUps:        catchUps,
+		catchUpLabels:   catchUpLabels,
 		targetExpand:    make(map[string]bool),
 	}
 
@@ -105,6 +153,7 @@ func newScheduleWizardModel(existingJob *config.ScheduledJob) *scheduleWizardMod
 		m.editingID = existingJob.ID
 		m.commandInput.SetValue(existingJob.Command)
 		m.nameInput.SetValue(existingJob.Name)
+		m.timezoneInput.SetValue(existingJob.Timezone)
 
 		// Find matching preset or use custom
 		found := false
@@ -133,9 +182,16 @@ func newScheduleWizardModel(existingJob *config.ScheduledJob) *scheduleWizardMod
 				break
 			}
 		}
+		if existingJob.PastesCommand() {
+			m.sendAsIndex = 1
+		}
+		if existingJob.CatchesUp() {
+			m.catchUpIndex = 1
+		}
 
 		// Store the target for display
 		m.selectedTarget = existingJob.Target
+		m.targetMode = existingJob.TargetMode
 	}
 
 	return m
@@ -198,6 +254,11 @@ func (m scheduleWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.nameInput, cmd = m.nameInput.Update(msg)
 		cmds = append(cmds, cmd)
 	}
+	if m.focusedField == FieldTimezone && m.timezoneInput.Focused() {
+		var cmd tea.Cmd
+		m.timezoneInput, cmd = m.timezoneInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
 	return m, tea.Batch(cmds...)
 }
@@ -251,6 +312,10 @@ func (m *scheduleWizardModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.nameInput.Blur()
 			return *m, nil
 		}
+		if m.focusedField == FieldTimezone && m.timezoneInput.Focused() {
+			m.timezoneInput.Blur()
+			return *m, nil
+		}
 		// Otherwise cancel
 		m.done = true
 		m.cancelled = true
@@ -289,8 +354,14 @@ func (m *scheduleWizardModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return m.handleCommandField(msg)
 	case FieldName:
 		return m.handleNameField(msg)
+	case FieldTimezone:
+		return m.handleTimezoneField(msg)
 	case FieldPreAction:
 		return m.handlePreActionField(msg)
+	case FieldSendMethod:
+		return m.handleSendMethodField(msg)
+	case FieldCatchUp:
+		return m.handleCatchUpField(msg)
 	case FieldButtons:
 		return m.handleButtonsField(msg)
 	}
@@ -302,6 +373,7 @@ func (m *scheduleWizardModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 func (m *scheduleWizardModel) blurInputs() {
 	m.commandInput.Blur()
 	m.nameInput.Blur()
+	m.timezoneInput.Blur()
 }
 
 // onFieldFocus is called when a field gains focus
@@ -311,6 +383,8 @@ func (m *scheduleWizardModel) onFieldFocus() {
 		m.commandInput.Focus()
 	case FieldName:
 		m.nameInput.Focus()
+	case FieldTimezone:
+		m.timezoneInput.Focus()
 	case FieldTarget:
 		// Update selectedTarget from current tree selection
 		m.updateSelectedTarget()
@@ -319,7 +393,7 @@ func (m *scheduleWizardModel) onFieldFocus() {
 
 // focusCmd returns the appropriate tea.Cmd for the newly focused field
 func (m *scheduleWizardModel) focusCmd() tea.Cmd {
-	if m.focusedField == FieldCommand || m.focusedField == FieldName {
+	if m.focusedField == FieldCommand || m.focusedField == FieldName || m.focusedField == FieldTimezone {
 		return textinput.Blink
 	}
 	return nil
@@ -328,10 +402,20 @@ func (m *scheduleWizardModel) focusCmd() tea.Cmd {
 // updateSelectedTarget stores the currently selected target string
 func (m *scheduleWizardModel) updateSelectedTarget() {
 	if m.targetIndex >= 0 && m.targetIndex < len(m.flatNodes) {
-		node := m.flatNodes[m.targetIndex]
-		if node.Type == "pane" {
-			m.selectedTarget = node.Target
-		}
+		m.selectTargetNode(m.flatNodes[m.targetIndex])
+	}
+}
+
+// selectTargetNode makes node the job's target if it is a pane or the
+// "All agent panes" option.
+func (m *scheduleWizardModel) selectTargetNode(node *tmux.TreeNode) {
+	switch node.Type {
+	case allAgentsNodeType:
+		m.targetMode = config.TargetModeAllAgents
+		m.selectedTarget = ""
+	case "pane":
+		m.targetMode = config.TargetModePane
+		m.selectedTarget = node.Target
 	}
 }
 
@@ -443,9 +527,9 @@ func (m *scheduleWizardModel) handleTargetField(msg tea.KeyMsg) (tea.Model, tea.
 	case "enter":
 		if m.targetIndex >= 0 && m.targetIndex < len(m.flatNodes) {
 			node := m.flatNodes[m.targetIndex]
-			if node.Type == "pane" {
-				// Select pane and store it
-				m.selectedTarget = node.Target
+			if node.Type == "pane" || node.Type == allAgentsNodeType {
+				// Select pane (or all agent panes) and store it
+				m.selectTargetNode(node)
 				return *m, nil
 			}
 			// Toggle expand for non-panes
@@ -482,15 +566,63 @@ func (m *scheduleWizardModel) handleNameField(msg tea.KeyMsg) (tea.Model, tea.Cm
 	if key == "enter" {
 		// Move to next field
 		m.blurInputs()
+		m.focusedField = FieldTimezone
+		m.onFieldFocus()
+		return *m, textinput.Blink
+	}
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return *m, cmd
+}
+
+// --- Timezone field ---
+
+func (m *scheduleWizardModel) handleTimezoneField(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "enter" {
+		if !m.validateTimezone() {
+			return *m, nil
+		}
+		m.blurInputs()
 		m.focusedField = FieldPreAction
 		m.onFieldFocus()
 		return *m, nil
 	}
 	var cmd tea.Cmd
-	m.nameInput, cmd = m.nameInput.Update(msg)
+	m.timezoneInput, cmd = m.timezoneInput.Update(msg)
+	m.timezoneError = ""
 	return *m, cmd
 }
 
+// validateTimezone checks the timezone input, recording an error to show
+// when it isn't a known IANA name.
+func (m *scheduleWizardModel) validateTimezone() bool {
+	if _, err := config.LoadTimezone(m.timezoneInput.Value()); err != nil {
+		m.timezoneError = err.Error()
+		return false
+	}
+	m.timezoneError = ""
+	return true
+}
+
+// location returns the timezone the schedule preview is shown in.
+func (m scheduleWizardModel) location() *time.Location {
+	loc, err := config.LoadTimezone(m.timezoneInput.Value())
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// formatNextRun previews an expression's next run, in the chosen timezone
+// when one is set.
+func (m scheduleWizardModel) formatNextRun(expr string) string {
+	if strings.TrimSpace(m.timezoneInput.Value()) == "" {
+		return config.FormatNextRun(expr)
+	}
+	return config.FormatNextRunIn(expr, m.location())
+}
+
 // --- Pre-action field ---
 
 func (m *scheduleWizardModel) handlePreActionField(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -506,6 +638,50 @@ func (m *scheduleWizardModel) handlePreActionField(msg tea.KeyMsg) (tea.Model, t
 			m.preActionIndex++
 		}
 		return *m, nil
+	case "enter":
+		m.focusedField = FieldSendMethod
+		return *m, nil
+	}
+	return *m, nil
+}
+
+// --- Send method field ---
+
+func (m *scheduleWizardModel) handleSendMethodField(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "up", "k":
+		if m.sendAsIndex > 0 {
+			m.sendAsIndex--
+		}
+		return *m, nil
+	case "down", "j":
+		if m.sendAsIndex < len(m.sendAsMethods)-1 {
+			m.sendAsIndex++
+		}
+		return *m, nil
+	case "enter":
+		m.focusedField = FieldCatchUp
+		return *m, nil
+	}
+	return *m, nil
+}
+
+// --- Catch-up field ---
+
+func (m *scheduleWizardModel) handleCatchUpField(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "up", "k":
+		if m.catchUpIndex > 0 {
+			m.catchUpIndex--
+		}
+		return *m, nil
+	case "down", "j":
+		if m.catchUpIndex < len(m.catchUps)-1 {
+			m.catchUpIndex++
+		}
+		return *m, nil
 	case "enter":
 		// Move to buttons
 		m.focusedField = FieldButtons
@@ -526,10 +702,20 @@ func (m *scheduleWizardModel) handleButtonsField(msg tea.KeyMsg) (tea.Model, tea
 		m.buttonFocusIdx = 1
 		return *m, nil
 	case "enter":
+		if m.buttonFocusIdx == 0 && !m.validateTimezone() {
+			m.focusedField = FieldTimezone
+			m.onFieldFocus()
+			return *m, textinput.Blink
+		}
 		m.done = true
 		m.cancelled = m.buttonFocusIdx == 1
 		return *m, nil
 	case "s":
+		if !m.validateTimezone() {
+			m.focusedField = FieldTimezone
+			m.onFieldFocus()
+			return *m, textinput.Blink
+		}
 		m.done = true
 		m.cancelled = false
 		return *m, nil
@@ -600,7 +786,11 @@ func (m *scheduleWizardModel) rebuildFlatNodes() {
 		return
 	}
 
-	var nodes []*tmux.TreeNode
+	// Offer every agent pane first; the panes are found when the job runs
+	nodes := []*tmux.TreeNode{{
+		Type: allAgentsNodeType,
+		Name: "All agent panes (found when the job runs)",
+	}}
 	for _, sess := range m.tree.Sessions {
 		sessKey := "session:" + sess.Name
 		sessExpanded := m.targetExpand[sessKey]
@@ -652,30 +842,37 @@ func (m *scheduleWizardModel) rebuildFlatNodes() {
 	m.flatNodes = nodes
 }
 
-func (m *scheduleWizardModel) buildJob() config.ScheduledJob {
-	var cronExpr string
+// cronExpr returns the chosen schedule's cron expression.
+func (m scheduleWizardModel) cronExpr() string {
 	if m.usingCustom {
-		cronExpr = strings.Join(m.cronFields[:], " ")
-	} else {
-		cronExpr = m.presets[m.presetIndex].Expr
+		return strings.Join(m.cronFields[:], " ")
 	}
+	return m.presets[m.presetIndex].Expr
+}
 
-	target := m.selectedTarget
-	if target == "" && m.targetIndex >= 0 && m.targetIndex < len(m.flatNodes) {
-		node := m.flatNodes[m.targetIndex]
-		if node.Type == "pane" {
-			target = node.Target
-		}
+func (m *scheduleWizardModel) buildJob() config.ScheduledJob {
+	cronExpr := m.cronExpr()
+
+	if m.selectedTarget == "" && m.targetMode != config.TargetModeAllAgents && m.targetIndex >= 0 && m.targetIndex < len(m.flatNodes) {
+		m.selectTargetNode(m.flatNodes[m.targetIndex])
+	}
+	targetMode := m.targetMode
+	if targetMode == "" {
+		targetMode = config.TargetModePane
 	}
 
 	return config.ScheduledJob{
-		ID:        m.editingID,
-		Name:      m.nameInput.Value(),
-		CronExpr:  cronExpr,
-		Target:    target,
-		Command:   m.commandInput.Value(),
-		PreAction: m.preActions[m.preActionIndex],
-		Enabled:   true,
+		ID:         m.editingID,
+		Name:       m.nameInput.Value(),
+		Timezone:   strings.TrimSpace(m.timezoneInput.Value()),
+		CronExpr:   cronExpr,
+		Target:     m.selectedTarget,
+		TargetMode: targetMode,
+		Command:    m.commandInput.Value(),
+		PreAction:  m.preActions[m.preActionIndex],
+		SendMethod: m.sendAsMethods[m.sendAsIndex],
+		CatchUp:    m.catchUps[m.catchUpIndex],
+		Enabled:    true,
 	}
 }
 
@@ -683,24 +880,33 @@ func (m *scheduleWizardModel) buildJob() config.ScheduledJob {
 
 // Styles local to the form rendering
 var (
+	formSectionFocusedBorder  lipgloss.Style
+	formSectionUnfocusedStyle lipgloss.Style
+	formSectionLabelFocused   lipgloss.Style
+	formSectionLabelUnfocused lipgloss.Style
+	formSummaryValue          lipgloss.Style
+)
+
+// buildFormStyles builds the schedule form styles from the theme colors.
+func buildFormStyles() {
 	formSectionFocusedBorder = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(primaryColor).
-				Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 1)
 
 	formSectionUnfocusedStyle = lipgloss.NewStyle().
-					PaddingLeft(2)
+		PaddingLeft(2)
 
 	formSectionLabelFocused = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(primaryColor)
+		Bold(true).
+		Foreground(primaryColor)
 
 	formSectionLabelUnfocused = lipgloss.NewStyle().
-					Foreground(dimColor)
+		Foreground(dimColor)
 
 	formSummaryValue = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("252"))
-)
+		Foreground(textColor)
+}
 
 func (m scheduleWizardModel) View() string {
 	var sections []string
@@ -719,7 +925,10 @@ func (m scheduleWizardModel) View() string {
 	sections = append(sections, m.viewTargetSection())
 	sections = append(sections, m.viewCommandSection())
 	sections = append(sections, m.viewNameSection())
+	sections = append(sections, m.viewTimezoneSection())
 	sections = append(sections, m.viewPreActionSection())
+	sections = append(sections, m.viewSendMethodSection())
+	sections = append(sections, m.viewCatchUpSection())
 	sections = append(sections, "")
 	sections = append(sections, m.viewButtons())
 
@@ -792,7 +1001,7 @@ func (m scheduleWizardModel) viewScheduleSection() string {
 		expr := strings.Join(m.cronFields[:], " ")
 		if m.cronValid {
 			english := config.CronToEnglish(expr)
-			nextRun := config.FormatNextRun(expr)
+			nextRun := m.formatNextRun(expr)
 			lines = append(lines, wizPreviewOKStyle.Render("Preview: "+english))
 			lines = append(lines, wizPreviewOKStyle.Render("Next run: "+nextRun))
 		} else {
@@ -842,7 +1051,9 @@ func (m scheduleWizardModel) viewTargetSection() string {
 	if !focused {
 		label := formSectionLabelUnfocused.Render("Target: ")
 		target := m.selectedTarget
-		if target == "" {
+		if m.targetMode == config.TargetModeAllAgents {
+			target = "All agent panes"
+		} else if target == "" {
 			target = "(none selected)"
 		}
 		value := formSummaryValue.Render(target)
@@ -876,7 +1087,7 @@ func (m scheduleWizardModel) viewTargetSection() string {
 			var row string
 			if i == m.targetIndex {
 				row = selectedStyle.Render("> " + indent + icon + " " + name)
-				if node.Type == "pane" {
+				if node.Type == "pane" || node.Type == allAgentsNodeType {
 					row += schedTargetStyle.Render(" <- select")
 				}
 			} else {
@@ -918,6 +1129,18 @@ func (m scheduleWizardModel) viewCommandSection() string {
 	}
 	lines = append(lines, cmdStyle.Render(m.commandInput.View()))
 
+	// Preview template tokens as they would expand right now
+	if cmd := m.commandInput.Value(); strings.Contains(cmd, "{{") {
+		expanded, warnings := config.ExpandCommand(cmd, time.Now())
+		if expanded != cmd {
+			lines = append(lines, wizPreviewOKStyle.Render("Preview: "+expanded))
+		}
+		for _, warning := range warnings {
+			lines = append(lines, wizPreviewErrStyle.Render("Warning: "+warning))
+		}
+	}
+	lines = append(lines, wizRefStyle.Render("Tokens: {{now:15:04}} {{date:2006-01-02}} (Go time layouts)"))
+
 	content := strings.Join(lines, "\n")
 	return formSectionFocusedBorder.Render(content)
 }
@@ -951,6 +1174,44 @@ func (m scheduleWizardModel) viewNameSection() string {
 	return formSectionFocusedBorder.Render(content)
 }
 
+// --- Timezone section ---
+
+func (m scheduleWizardModel) viewTimezoneSection() string {
+	focused := m.focusedField == FieldTimezone
+
+	if !focused {
+		label := formSectionLabelUnfocused.Render("Timezone: ")
+		tz := strings.TrimSpace(m.timezoneInput.Value())
+		if tz == "" {
+			tz = "Local time"
+		}
+		value := formSummaryValue.Render(tz)
+		if m.timezoneError != "" {
+			value = wizPreviewErrStyle.Render(tz + " (unknown)")
+		}
+		return formSectionUnfocusedStyle.Render(label + value)
+	}
+
+	var lines []string
+	header := formSectionLabelFocused.Render("Timezone (optional)")
+	lines = append(lines, header)
+
+	tzStyle := wizInputStyle
+	if m.timezoneInput.Focused() {
+		tzStyle = tzStyle.BorderForeground(activeColor)
+	}
+	lines = append(lines, tzStyle.Render(m.timezoneInput.View()))
+
+	if m.timezoneError != "" {
+		lines = append(lines, wizPreviewErrStyle.Render("Error: "+m.timezoneError))
+	} else if expr := m.cronExpr(); config.ParseCron(expr) == nil {
+		lines = append(lines, wizPreviewOKStyle.Render("Next run: "+m.formatNextRun(expr)))
+	}
+
+	content := strings.Join(lines, "\n")
+	return formSectionFocusedBorder.Render(content)
+}
+
 // --- Pre-action section ---
 
 func (m scheduleWizardModel) viewPreActionSection() string {
@@ -981,6 +1242,66 @@ func (m scheduleWizardModel) viewPreActionSection() string {
 	return formSectionFocusedBorder.Render(content)
 }
 
+// --- Send method section ---
+
+func (m scheduleWizardModel) viewSendMethodSection() string {
+	focused := m.focusedField == FieldSendMethod
+
+	if !focused {
+		label := formSectionLabelUnfocused.Render("Send As: ")
+		value := formSummaryValue.Render(m.sendAsLabels[m.sendAsIndex])
+		return formSectionUnfocusedStyle.Render(label + value)
+	}
+
+	var lines []string
+	header := formSectionLabelFocused.Render("Send As")
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	for i, label := range m.sendAsLabels {
+		var row string
+		if i == m.sendAsIndex {
+			row = selectedStyle.Render("> ") + lipgloss.NewStyle().Bold(true).Render(label)
+		} else {
+			row = "  " + label
+		}
+		lines = append(lines, row)
+	}
+
+	content := strings.Join(lines, "\n")
+	return formSectionFocusedBorder.Render(content)
+}
+
+// --- Catch-up section ---
+
+func (m scheduleWizardModel) viewCatchUpSection() string {
+	focused := m.focusedField == FieldCatchUp
+
+	if !focused {
+		label := formSectionLabelUnfocused.Render("Missed Runs: ")
+		value := formSummaryValue.Render(m.catchUpLabels[m.catchUpIndex])
+		return formSectionUnfocusedStyle.Render(label + value)
+	}
+
+	var lines []string
+	header := formSectionLabelFocused.Render("Missed Runs")
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	for i, label := range m.catchUpLabels {
+		var row string
+		if i == m.catchUpIndex {
+			row = selectedStyle.Render("> ") + lipgloss.NewStyle().Bold(true).Render(label)
+		} else {
+			row = "  " + label
+		}
+		lines = append(lines, row)
+	}
+
+	content := strings.Join(lines, "\n")
+	return formSectionFocusedBorder.Render(content)
+}
+
 // --- Buttons ---
 
 func (m scheduleWizardModel) viewButtons() string {
@@ -0,0 +1,106 @@
+package tui
+
+// Screen names one of atmux's entry screens.
+type Screen string
+
+const (
+	ScreenLanding  Screen = "landing"
+	ScreenSessions Screen = "sessions"
+	ScreenBrowse   Screen = "browse"
+)
+
+// screenKeys maps the key pressed after g to the screen it opens.
+var screenKeys = map[string]Screen{
+	"l": ScreenLanding,
+	"s": ScreenSessions,
+	"b": ScreenBrowse,
+}
+
+// ScreenSwitch asks the caller to open another entry screen rather than
+// exit, carrying the selected session over where there is one.
+type ScreenSwitch struct {
+	To      Screen
+	Session string // Session selected when switching ("" = none)
+	Host    string // Host of Session ("" for local)
+}
+
+// screenJump tracks a pending g, so g followed by l, s, or b switches
+// screens.
+type screenJump struct {
+	pending bool
+}
+
+// consume handles key as part of a g sequence. It returns the screen to
+// switch to, if any, and whether the key was used; a g followed by any
+// other key, or by the current screen's own key, is dropped.
+func (j *screenJump) consume(key string, current Screen) (Screen, bool) {
+	if j.pending {
+		j.pending = false
+		if to, ok := screenKeys[key]; ok && to != current {
+			return to, true
+		}
+		return "", true
+	}
+	if key == "g" {
+		j.pending = true
+		return "", true
+	}
+	return "", false
+}
+
+// screenSwitch quits browse for screen to, carrying over the session of the
+// selected node.
+func (m Model) screenSwitch(to Screen) *ScreenSwitch {
+	sw := &ScreenSwitch{To: to}
+	if node := m.selectedNode(); node != nil && node.Type != "host" {
+		sw.Session = sessionFromNode(node)
+		sw.Host = node.Host
+	}
+	return sw
+}
+
+// selectCarriedSession selects the session carried over from another
+// screen once it shows up in the tree.
+func (m *Model) selectCarriedSession() {
+	if m.selectSession == "" {
+		return
+	}
+	for i, node := range m.flatNodes {
+		if node.Type == "session" && node.Name == m.selectSession && node.Host == m.selectHost {
+			m.selectedIndex = i
+			m.selectSession = ""
+			return
+		}
+	}
+}
+
+// selectCarriedSession selects the session carried over from another
+// screen. Later hosts re-sort the list, so it is reapplied until every
+// host has answered.
+func (m *sessionsModel) selectCarriedSession() {
+	if m.selectSession == "" {
+		return
+	}
+	for i, line := range m.lines {
+		if line.Name == m.selectSession && line.Host == m.selectHost {
+			m.selectedIndex = i
+			break
+		}
+	}
+	if m.pendingExecutors <= 0 {
+		m.selectSession = ""
+	}
+}
+
+// selectCarriedSession focuses the session carried over from another
+// screen in the sessions section.
+func (m *landingModel) selectCarriedSession() {
+	for i, line := range m.sessions {
+		if line.Name == m.selectSession {
+			m.focusedSection = sectionSessions
+			m.selectedIndex = i
+			break
+		}
+	}
+	m.selectSession = ""
+}
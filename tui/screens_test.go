@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestScreenJumpConsume(t *testing.T) {
+	var j screenJump
+	if _, ok := j.consume("s", ScreenBrowse); ok {
+		t.Fatal("expected s alone to be left to the screen")
+	}
+	if to, ok := j.consume("g", ScreenBrowse); !ok || to != "" {
+		t.Fatalf("expected g to start a sequence, got %q %v", to, ok)
+	}
+	if to, ok := j.consume("s", ScreenBrowse); !ok || to != ScreenSessions {
+		t.Fatalf("expected g s to switch to sessions, got %q %v", to, ok)
+	}
+
+	// The current screen's own key and unknown keys end the sequence
+	for _, key := range []string{"b", "x"} {
+		j.consume("g", ScreenBrowse)
+		if to, ok := j.consume(key, ScreenBrowse); !ok || to != "" {
+			t.Fatalf("expected g %s to be dropped, got %q %v", key, to, ok)
+		}
+		if _, ok := j.consume("s", ScreenBrowse); ok {
+			t.Fatalf("expected g %s to clear the pending g", key)
+		}
+	}
+}
+
+func sessionLines(names ...string) []tmux.SessionLine {
+	lines := make([]tmux.SessionLine, len(names))
+	for i, name := range names {
+		lines[i] = tmux.SessionLine{Name: name}
+	}
+	return lines
+}
+
+func TestSessionsScreenSwitchCarriesSelection(t *testing.T) {
+	m := newSessionsModel(nil, false, false)
+	m.selectSession = "beta"
+	m.pendingExecutors = 1
+	updated, _ := m.Update(executorSessionsMsg{lines: sessionLines("alpha", "beta", "gamma")})
+	m = updated.(sessionsModel)
+	if line := m.lines[m.selectedIndex]; line.Name != "beta" {
+		t.Fatalf("expected the carried session selected, got %q", line.Name)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	updated, cmd := updated.(sessionsModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(sessionsModel)
+	if cmd == nil || m.switchTo == nil || m.switchTo.To != ScreenBrowse || m.switchTo.Session != "beta" {
+		t.Fatalf("expected g b to quit for browse with beta, got %+v", m.switchTo)
+	}
+}
+
+func TestLandingScreenSwitchCarriesSelection(t *testing.T) {
+	m := newLandingModel("agent-new")
+	m.selectSession = "beta"
+	updated, _ := m.Update(executorSessionsMsg{lines: sessionLines("alpha", "beta")})
+	m = updated.(landingModel)
+	if m.focusedSection != sectionSessions || m.sessions[m.selectedIndex].Name != "beta" {
+		t.Fatalf("expected the carried session focused, got section %d index %d", m.focusedSection, m.selectedIndex)
+	}
+
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	updated, cmd := updated.(landingModel).handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(landingModel)
+	if cmd == nil || m.switchTo == nil || m.switchTo.To != ScreenSessions || m.switchTo.Session != "beta" {
+		t.Fatalf("expected g s to quit for sessions with beta, got %+v", m.switchTo)
+	}
+}
+
+func TestBrowseScreenSwitchCarriesSelection(t *testing.T) {
+	m := NewModel(Options{SelectSession: "beta", DisableTreeCache: true})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "alpha"}, {Name: "beta"}}}
+	m.rebuildFlatNodes()
+	if node := m.selectedNode(); node == nil || node.Name != "beta" {
+		t.Fatalf("expected the carried session selected, got %+v", node)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	m = updated.(Model)
+	if cmd == nil || m.switchTo == nil || m.switchTo.To != ScreenLanding || m.switchTo.Session != "beta" {
+		t.Fatalf("expected g l to quit for landing with beta, got %+v", m.switchTo)
+	}
+}
@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// maxSearchResultsShown caps how many matches are listed in the overlay.
+const maxSearchResultsShown = 12
+
+// paneSearch holds state for the global pane search overlay.
+type paneSearch struct {
+	input     textinput.Model
+	query     string // Query of the last completed (or running) search
+	results   []tmux.PaneMatch
+	selected  int
+	searching bool
+}
+
+// newPaneSearch creates a focused search overlay state.
+func newPaneSearch() *paneSearch {
+	ti := textinput.New()
+	ti.Placeholder = "Search pane contents..."
+	ti.CharLimit = 256
+	ti.Width = 40
+	ti.Focus()
+	return &paneSearch{input: ti}
+}
+
+// searchHostTrees returns the host trees to search. In local-only mode the
+// single tree is wrapped with a local executor.
+func (m *Model) searchHostTrees() []tmux.HostTree {
+	if len(m.hostTrees) > 0 {
+		return m.hostTrees
+	}
+	if m.tree == nil {
+		return nil
+	}
+	return []tmux.HostTree{{Tree: m.tree, Executor: tmux.NewLocalExecutor()}}
+}
+
+// searchPanesCmd runs a pane content search across all host trees.
+func searchPanesCmd(hostTrees []tmux.HostTree, query string) tea.Cmd {
+	return func() tea.Msg {
+		return PaneSearchResultsMsg{Query: query, Matches: tmux.SearchPanes(hostTrees, query)}
+	}
+}
+
+// handleSearchKeys handles keys while the search overlay is open.
+func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.search
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.search = nil
+		return m, nil
+	case "up", "ctrl+p":
+		if s.selected > 0 {
+			s.selected--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if s.selected < len(s.results)-1 {
+			s.selected++
+		}
+		return m, nil
+	case "enter":
+		query := strings.TrimSpace(s.input.Value())
+		if query == "" || s.searching {
+			return m, nil
+		}
+		// Re-run the search when the query changed, otherwise jump to the result
+		if query != s.query {
+			s.query = query
+			s.searching = true
+			s.results = nil
+			s.selected = 0
+			return m, searchPanesCmd(m.searchHostTrees(), query)
+		}
+		if s.selected >= 0 && s.selected < len(s.results) {
+			match := s.results[s.selected]
+			m.search = nil
+			return m, m.revealPane(match.Host, match.Target)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	return m, cmd
+}
+
+// revealPane expands the tree down to the given pane, selects it, and
+// returns a command to refresh the preview.
+func (m *Model) revealPane(host, target string) tea.Cmd {
+	session := sessionFromTarget(target)
+	window := target
+	if idx := strings.LastIndex(target, "."); idx != -1 {
+		window = target[:idx]
+	}
+
+	if len(m.hostTrees) > 0 {
+		hostLabel := host
+		if hostLabel == "" {
+			hostLabel = "local"
+		}
+		m.expanded[nodeKey("host", "host:"+hostLabel)] = true
+		m.expanded[nodeKey("session", hostLabel+"/"+session)] = true
+		m.expanded[nodeKey("window", hostLabel+"/"+window)] = true
+	} else {
+		m.expanded[nodeKey("session", session)] = true
+		m.expanded[nodeKey("window", window)] = true
+	}
+	m.rebuildFlatNodes()
+
+	for i, node := range m.flatNodes {
+		if node.Type == "pane" && node.Target == target && node.Host == host {
+			m.selectedIndex = i
+			m.scrollTreeToSelection()
+			m.focusRecent = false
+			m.focused = FocusTree
+			m.commandInput.Blur()
+			m.calculateButtonZones()
+			return m.updatePreviewForSelection()
+		}
+	}
+	m.calculateButtonZones()
+	return nil
+}
+
+// renderSearchOverlay renders the pane search overlay on top of the base view.
+func (m Model) renderSearchOverlay(base string) string {
+	s := m.search
+	title := helpTitleStyle.Render("Search Panes")
+
+	width := m.width - 8
+	if width > 90 {
+		width = 90
+	}
+	if width < 30 {
+		width = 30
+	}
+
+	var rows []string
+	rows = append(rows, title, "", s.input.View(), "")
+
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+	switch {
+	case s.searching:
+		rows = append(rows, dim.Render("Searching..."))
+	case s.query == "":
+		rows = append(rows, dim.Render("Type a query and press Enter"))
+	case len(s.results) == 0:
+		rows = append(rows, dim.Render(fmt.Sprintf("No panes match %q", s.query)))
+	default:
+		rows = append(rows, dim.Render(fmt.Sprintf("%d matching pane(s)", len(s.results))))
+		start := 0
+		if s.selected >= maxSearchResultsShown {
+			start = s.selected - maxSearchResultsShown + 1
+		}
+		for i := start; i < len(s.results) && i < start+maxSearchResultsShown; i++ {
+			match := s.results[i]
+			label := match.Target
+			if match.Host != "" {
+				label = remoteIndicatorStyle.Render("@"+match.Host) + " " + label
+			}
+			line := label + "  " + dim.Render(match.Snippet)
+			line = ansi.Truncate(line, width-6, "...")
+			if i == s.selected {
+				line = selectedStyle.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+			rows = append(rows, line)
+		}
+	}
+
+	rows = append(rows, "", dim.Render("[Enter] search/jump  [↑/↓] select  [Esc] close"))
+
+	box := helpOverlayStyle.Width(width).Render(strings.Join(rows, "\n"))
+
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
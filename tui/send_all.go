@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// sendAllConfirmThreshold is the pane count above which sending to every
+// pane of a window asks for confirmation first.
+const sendAllConfirmThreshold = 4
+
+// sendAllRequest is a pending "send to all panes" awaiting confirmation.
+type sendAllRequest struct {
+	window  string // Window target (session:index)
+	command string
+	panes   []string // Pane targets in the window
+	exec    tmux.TmuxExecutor
+}
+
+// windowPanes returns the pane targets of a window and the executor for its
+// host, looked up in the fetched tree so collapsed windows work too.
+func (m *Model) windowPanes(host, window string) ([]string, tmux.TmuxExecutor) {
+	for _, ht := range m.searchHostTrees() {
+		if ht.Host != host || ht.Tree == nil {
+			continue
+		}
+		for _, sess := range ht.Tree.Sessions {
+			for _, win := range sess.Windows {
+				if sess.Name+":"+strconv.Itoa(win.Index) != window {
+					continue
+				}
+				var panes []string
+				for _, pane := range win.Panes {
+					panes = append(panes, pane.Target)
+				}
+				return panes, ht.Executor
+			}
+		}
+	}
+	return nil, nil
+}
+
+// requestSendAll sends command to every pane in the window, asking first
+// when the window has more than sendAllConfirmThreshold panes.
+func (m Model) requestSendAll(host, window, command string) (tea.Model, tea.Cmd) {
+	if command == "" {
+		// Nothing to send yet: let the user type the command first
+		m.focused = FocusInput
+		m.commandInput.Focus()
+		return m, nil
+	}
+	panes, exec := m.windowPanes(host, window)
+	if len(panes) == 0 {
+		m.lastError = fmt.Errorf("no panes found in window %s", window)
+		return m, nil
+	}
+	m.pushInputHistory(command)
+	req := &sendAllRequest{window: window, command: command, panes: panes, exec: exec}
+	if len(panes) > sendAllConfirmThreshold {
+		m.pendingSendAll = req
+		return m, nil
+	}
+	return m, sendToPanes(req, m.sendMethod)
+}
+
+// sendToPanes sends the request's command to each pane in turn, reporting
+// one CommandSentMsg for the whole window.
+func sendToPanes(req *sendAllRequest, method tmux.SendMethod) tea.Cmd {
+	return func() tea.Msg {
+		var errs []error
+		for _, target := range req.panes {
+			var err error
+			if req.exec != nil {
+				err = tmux.SendCommandWithMethodAndExecutor(target, req.command, method, req.exec)
+			} else {
+				err = tmux.SendCommandWithMethod(target, req.command, method)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to send to %s: %w", target, err))
+			}
+		}
+		return CommandSentMsg{Target: req.window, Command: tmux.WrapCommand(req.command), Panes: req.panes, Err: errors.Join(errs...)}
+	}
+}
+
+// windowTargetOf returns the window target for a window or pane node.
+func windowTargetOf(node *tmux.TreeNode) string {
+	switch node.Type {
+	case "window":
+		return node.Target
+	case "pane":
+		if idx := strings.LastIndex(node.Target, "."); idx != -1 {
+			return node.Target[:idx]
+		}
+	}
+	return ""
+}
+
+// handleSendAllConfirmKeys handles keys while confirming a send to many panes.
+func (m Model) handleSendAllConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		req := m.pendingSendAll
+		m.pendingSendAll = nil
+		return m, sendToPanes(req, m.sendMethod)
+	case "n", "N", "esc":
+		m.pendingSendAll = nil
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderSendAllConfirmOverlay renders the send-to-all-panes confirmation.
+func (m Model) renderSendAllConfirmOverlay(base string) string {
+	req := m.pendingSendAll
+	title := helpTitleStyle.Render("Send to All Panes")
+
+	command := req.command
+	if len(command) > 30 {
+		command = command[:27] + "..."
+	}
+	message := lipgloss.NewStyle().
+		Foreground(textColor).
+		Bold(true).
+		Render(fmt.Sprintf("Send '%s' to %d panes in %s?", command, len(req.panes), req.window))
+
+	hint := lipgloss.NewStyle().
+		Foreground(dimColor).
+		Render("Press [y] to send, [n] or [Esc] to cancel")
+
+	content := strings.Join([]string{title, "", message, "", hint}, "\n")
+	box := helpOverlayStyle.Width(50).Render(content)
+
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// recordingExecutor records tmux commands instead of running them.
+type recordingExecutor struct {
+	host  string
+	calls []string
+}
+
+func (r *recordingExecutor) Run(args ...string) error {
+	r.calls = append(r.calls, strings.Join(args, " "))
+	return nil
+}
+func (r *recordingExecutor) Output(args ...string) ([]byte, error)       { return nil, nil }
+func (r *recordingExecutor) RunWithDir(dir string, args ...string) error { return nil }
+func (r *recordingExecutor) Interactive(args ...string) error            { return nil }
+func (r *recordingExecutor) RunGeneric(command string, args ...string) ([]byte, error) {
+	return nil, nil
+}
+func (r *recordingExecutor) HostLabel() string { return r.host }
+func (r *recordingExecutor) IsRemote() bool    { return r.host != "" }
+func (r *recordingExecutor) Close() error      { return nil }
+
+func windowWithPanes(session string, n int) tmux.TmuxSession {
+	win := tmux.Window{Index: 0, Name: "agents"}
+	for i := 0; i < n; i++ {
+		win.Panes = append(win.Panes, tmux.Pane{Index: i, Target: fmt.Sprintf("%s:0.%d", session, i)})
+	}
+	return tmux.TmuxSession{Name: session, Windows: []tmux.Window{win}}
+}
+
+func TestSendAllPanesRoutesThroughRemoteExecutor(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := NewModel(Options{})
+	m.executors = []tmux.TmuxExecutor{exec}
+	m.hostTrees = []tmux.HostTree{{
+		Host:     "devbox",
+		Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 3)}},
+		Executor: exec,
+	}}
+	m.liveTree = true
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "window", "work:0")
+	m.commandInput.SetValue("make test")
+	m.sendMethod = tmux.SendMethodEnterSeparate
+
+	updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	if updated.(Model).pendingSendAll != nil {
+		t.Fatal("expected no confirmation for 3 panes")
+	}
+	if cmd == nil {
+		t.Fatal("expected a send command")
+	}
+	msg := cmd().(CommandSentMsg)
+	if msg.Err != nil || msg.Target != "work:0" {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	for i := 0; i < 3; i++ {
+		want := fmt.Sprintf("send-keys -t work:0.%d make test", i)
+		found := false
+		for _, call := range exec.calls {
+			if call == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected %q in %v", want, exec.calls)
+		}
+	}
+}
+
+func TestSendAllPanesConfirmsAboveThreshold(t *testing.T) {
+	m := NewModel(Options{})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", sendAllConfirmThreshold+1)}}
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.2")
+	m.commandInput.SetValue("git pull")
+
+	updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	um := updated.(Model)
+	if cmd != nil || um.pendingSendAll == nil {
+		t.Fatal("expected confirmation before sending to many panes")
+	}
+	if um.pendingSendAll.window != "work:0" || len(um.pendingSendAll.panes) != sendAllConfirmThreshold+1 {
+		t.Fatalf("unexpected pending send %+v", um.pendingSendAll)
+	}
+
+	updated, cmd = um.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if updated.(Model).pendingSendAll != nil || cmd != nil {
+		t.Fatal("expected n to cancel without sending")
+	}
+}
+
+func TestSynchronizedWindowMenuAndGlyph(t *testing.T) {
+	exec := &recordingExecutor{host: "devbox"}
+	m := NewModel(Options{})
+	m.executors = []tmux.TmuxExecutor{exec}
+	session := windowWithPanes("work", 2)
+	session.Windows[0].Synchronized = true
+	m.hostTrees = []tmux.HostTree{{
+		Host:     "devbox",
+		Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{session}},
+		Executor: exec,
+	}}
+	m.liveTree = true
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "window", "work:0")
+	node := m.selectedNode()
+
+	if syncGlyph(node) == "" {
+		t.Fatal("expected a glyph on the synchronized window")
+	}
+	menu := NewContextMenu(node, 0, 0)
+	found := false
+	for _, item := range menu.Items {
+		if item.Action == MenuActionSyncPanes {
+			found = item.Label == "Stop synchronizing panes"
+		}
+	}
+	if !found {
+		t.Fatalf("expected menu to offer turning synchronization off, got %+v", menu.Items)
+	}
+
+	m.contextMenu = menu
+	_, cmd := m.executeMenuAction(MenuActionSyncPanes)
+	if cmd == nil {
+		t.Fatal("expected a toggle command")
+	}
+	msg := toggleSynchronizePanes("work:0", exec)().(CommandSentMsg)
+	if msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if len(exec.calls) != 1 || exec.calls[0] != "set-window-option -t work:0 synchronize-panes" {
+		t.Fatalf("unexpected tmux calls %v", exec.calls)
+	}
+}
+
+func TestSendToShellPaneConfirmsFirst(t *testing.T) {
+	session := windowWithPanes("work", 2)
+	session.Windows[0].Panes[0].Command = "-zsh"
+	session.Windows[0].Panes[1].Command = "claude"
+	m := NewModel(Options{})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{session}}
+	m.rebuildFlatNodes()
+	m.sendMethod = tmux.SendMethodEnterSeparate
+	m.commandInput.SetValue("/compact")
+
+	// An agent pane sends right away
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.1")
+	updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if updated.(Model).pendingShellSend != nil || cmd == nil {
+		t.Fatal("expected an agent pane to send without confirmation")
+	}
+
+	// A shell pane asks first, and n cancels
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+	updated, cmd = m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	um := updated.(Model)
+	if cmd != nil || um.pendingShellSend == nil || um.pendingShellSend.node.Target != "work:0.0" {
+		t.Fatal("expected confirmation before sending to a shell pane")
+	}
+	updated, cmd = um.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if updated.(Model).pendingShellSend != nil || cmd != nil {
+		t.Fatal("expected n to cancel without sending")
+	}
+
+	// The setting turns the check off
+	m.options.SkipShellConfirm = true
+	updated, cmd = m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if updated.(Model).pendingShellSend != nil || cmd == nil {
+		t.Fatal("expected skip_shell_confirm to send without asking")
+	}
+}
+
+func TestSendWrapsCommandInPrefixAndSuffix(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	settings := config.DefaultSettings()
+	settings.CommandPrefix = "[atmux]"
+	settings.CommandSuffix = "#done"
+	if err := settings.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := &recordingExecutor{host: "devbox"}
+	m := NewModel(Options{})
+	m.executors = []tmux.TmuxExecutor{exec}
+	m.hostTrees = []tmux.HostTree{{
+		Host:     "devbox",
+		Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 1)}},
+		Executor: exec,
+	}}
+	m.liveTree = true
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+	m.commandInput.SetValue("make test")
+	m.sendMethod = tmux.SendMethodEnterSeparate
+
+	updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if cmd == nil {
+		t.Fatal("expected a send command")
+	}
+	if got := updated.(Model).commandInput.Value(); got != "make test" {
+		t.Fatalf("expected the input to keep the typed text, got %q", got)
+	}
+	msg := cmd().(CommandSentMsg)
+	if msg.Command != "[atmux] make test #done" {
+		t.Fatalf("expected the wrapped command to be reported as sent, got %q", msg.Command)
+	}
+	if len(exec.calls) == 0 || exec.calls[0] != "send-keys -t work:0.0 [atmux] make test #done" {
+		t.Fatalf("expected the wrapped command to be typed, got %v", exec.calls)
+	}
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// sendFileConfirmBytes is the file size above which sending asks first.
+const sendFileConfirmBytes = 16 * 1024
+
+// sendFilePrompt asks for a file whose contents are pasted into a pane.
+type sendFilePrompt struct {
+	node  *tmux.TreeNode
+	input textinput.Model
+	err   string
+
+	// Set once a large file has been read and awaits confirmation
+	path    string
+	content string
+}
+
+// openSendFile starts asking for a file to paste into the selected pane.
+func (m Model) openSendFile() (tea.Model, tea.Cmd) {
+	node := m.selectedNode()
+	if node == nil || node.Type != "pane" {
+		return m, nil
+	}
+	ti := textinput.New()
+	ti.Placeholder = "Path to a file (or drop one here)"
+	ti.CharLimit = 1024
+	ti.Width = 44
+	ti.Focus()
+	m.sendFile = &sendFilePrompt{node: node, input: ti}
+	return m, textinput.Blink
+}
+
+// cleanDroppedPath turns what a terminal types when a file is dropped on it
+// (quoted, or with backslash-escaped spaces) or a ~ path into a plain path.
+func cleanDroppedPath(path string) string {
+	path = strings.TrimSpace(path)
+	if len(path) >= 2 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	} else {
+		path = strings.ReplaceAll(path, `\ `, " ")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// readSendFile reads a file to paste, rejecting ones that aren't text.
+func readSendFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if !utf8.Valid(data) || strings.ContainsRune(string(data), 0) {
+		return "", fmt.Errorf("%s doesn't look like a text file", filepath.Base(path))
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("%s is empty", filepath.Base(path))
+	}
+	return string(data), nil
+}
+
+// handleSendFileKeys handles keys while asking for a file or confirming a
+// large one.
+func (m Model) handleSendFileKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.sendFile
+	if p.content != "" {
+		switch msg.String() {
+		case "y", "Y", "enter":
+			m.sendFile = nil
+			return m, m.pasteFileForNode(p.node, p.path, p.content)
+		case "n", "N", "esc":
+			// Back to the path, to pick another file
+			p.path, p.content = "", ""
+			return m, nil
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.sendFile = nil
+		return m, nil
+	case "enter":
+		path := cleanDroppedPath(p.input.Value())
+		if path == "" {
+			return m, nil
+		}
+		content, err := readSendFile(path)
+		if err != nil {
+			p.err = err.Error()
+			return m, nil
+		}
+		if len(content) > sendFileConfirmBytes {
+			p.path, p.content, p.err = path, content, ""
+			return m, nil
+		}
+		m.sendFile = nil
+		return m, m.pasteFileForNode(p.node, path, content)
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.err = ""
+	return m, cmd
+}
+
+// pasteFileForNode pastes a file's contents into a pane through the pane's
+// executor.
+func (m *Model) pasteFileForNode(node *tmux.TreeNode, path, content string) tea.Cmd {
+	executor := m.executorForHost(node.Host)
+	if executor == nil {
+		executor = tmux.NewLocalExecutor()
+	}
+	target := node.Target
+	label := "paste " + filepath.Base(path)
+	return func() tea.Msg {
+		err := tmux.PasteToTargetWithExecutor(target, content, executor)
+		return CommandSentMsg{Target: target, Command: label, Panes: []string{target}, Err: err}
+	}
+}
+
+// renderSendFileOverlay renders the file prompt or the large-file confirmation.
+func (m Model) renderSendFileOverlay(base string) string {
+	p := m.sendFile
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+
+	var rows []string
+	if p.content != "" {
+		rows = append(rows,
+			helpTitleStyle.Render("Send Large File?"),
+			"",
+			lipgloss.NewStyle().Foreground(textColor).Bold(true).Render(fmt.Sprintf(
+				"%s is %d KB (%d lines). Paste it into %s?",
+				filepath.Base(p.path), (len(p.content)+1023)/1024, strings.Count(strings.TrimRight(p.content, "\n"), "\n")+1, p.node.Target)),
+			"",
+			dim.Render("Press [y] to send, [n] or [Esc] to pick another file"),
+		)
+	} else {
+		rows = append(rows,
+			helpTitleStyle.Render("Send File to "+p.node.Target),
+			"",
+			p.input.View(),
+		)
+		if p.err != "" {
+			rows = append(rows, "", lipgloss.NewStyle().Foreground(errorColor).Render(ansi.Truncate(p.err, 50, "...")))
+		}
+		rows = append(rows, "", dim.Render("Pasted as one block, not submitted. [Enter] send  [Esc] cancel"))
+	}
+
+	box := helpOverlayStyle.Width(54).Render(strings.Join(rows, "\n"))
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
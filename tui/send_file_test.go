@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestSendFilePastesIntoPane(t *testing.T) {
+	dir := t.TempDir()
+	small := filepath.Join(dir, "my prompt.md")
+	if err := os.WriteFile(small, []byte("Review the diff\nand summarize it\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	large := filepath.Join(dir, "large.md")
+	if err := os.WriteFile(large, []byte(strings.Repeat("line\n", sendFileConfirmBytes)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := &recordingExecutor{host: "devbox"}
+	m := NewModel(Options{})
+	m.executors = []tmux.TmuxExecutor{exec}
+	m.hostTrees = []tmux.HostTree{{
+		Host:     "devbox",
+		Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 2)}},
+		Executor: exec,
+	}}
+	m.liveTree = true
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.1")
+
+	submit := func(m Model, path string) (Model, tea.Cmd) {
+		updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+		m = updated.(Model)
+		if m.sendFile == nil {
+			t.Fatal("expected the send-file prompt")
+		}
+		m.sendFile.input.SetValue(path)
+		updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+		return updated.(Model), cmd
+	}
+
+	// A dropped path arrives with its spaces escaped
+	m, cmd := submit(m, strings.ReplaceAll(small, " ", `\ `))
+	if m.sendFile != nil || cmd == nil {
+		t.Fatal("expected a small file to be sent right away")
+	}
+	msg := cmd().(CommandSentMsg)
+	if msg.Err != nil || msg.Target != "work:0.1" || msg.Command != "paste my prompt.md" {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	if len(exec.calls) != 2 || !strings.HasPrefix(exec.calls[0], "set-buffer") || !strings.HasSuffix(exec.calls[1], "-t work:0.1") {
+		t.Fatalf("expected a buffer paste, got %v", exec.calls)
+	}
+
+	m, cmd = submit(m, large)
+	if m.sendFile == nil || m.sendFile.content == "" || cmd != nil {
+		t.Fatal("expected a large file to ask first")
+	}
+	updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if updated.(Model).sendFile != nil || cmd == nil {
+		t.Fatal("expected confirming to send the large file")
+	}
+
+	m, _ = submit(m, filepath.Join(dir, "missing.md"))
+	if m.sendFile == nil || !strings.Contains(m.sendFile.err, "missing.md") {
+		t.Fatal("expected an error for a missing file")
+	}
+}
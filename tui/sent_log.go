@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	sentLogSize      = 5  // Commands remembered per pane
+	sentLogShown     = 3  // Commands shown in the preview header
+	maxSentLogCmdLen = 24 // Longer commands are truncated in the header
+)
+
+// sentCommand is a command typed into a pane, from the input bar or by a
+// scheduled job.
+type sentCommand struct {
+	command   string
+	at        time.Time
+	scheduled bool
+}
+
+// sentLogKey identifies a pane across hosts.
+func sentLogKey(host, target string) string {
+	return host + "\x00" + target
+}
+
+// recordSent remembers command as sent to each of panes on host, keeping
+// the newest sentLogSize per pane.
+func (m *Model) recordSent(host string, panes []string, command string) {
+	if m.sentLog == nil {
+		m.sentLog = make(map[string][]sentCommand)
+	}
+	now := time.Now()
+	for _, target := range panes {
+		key := sentLogKey(host, target)
+		log := append(m.sentLog[key], sentCommand{command: command, at: now})
+		if len(log) > sentLogSize {
+			log = log[len(log)-sentLogSize:]
+		}
+		m.sentLog[key] = log
+	}
+}
+
+// sentHistory returns up to n commands last sent to the pane, newest first:
+// this session's sends merged with the last run of each scheduled job
+// aimed at it. Schedules only run locally, and jobs that pick their agent
+// panes at run time aren't attributed to any one pane.
+func (m Model) sentHistory(host, target string, n int) []sentCommand {
+	entries := append([]sentCommand(nil), m.sentLog[sentLogKey(host, target)]...)
+	if host == "" {
+		for _, job := range m.scheduledJobs {
+			if job.TargetsAllAgents() || job.Target != target || job.LastRunAt.IsZero() {
+				continue
+			}
+			entries = append(entries, sentCommand{command: job.Command, at: job.LastRunAt, scheduled: true})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].at.After(entries[j].at)
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// renderSentLog renders the pane's recent commands for the preview header
+// in at most width cells, dropping the oldest ones that don't fit. Scheduled
+// sends are marked ⟳, manual ones ›.
+func (m Model) renderSentLog(host, target string, width int) string {
+	var parts []string
+	for _, sent := range m.sentHistory(host, target, sentLogShown) {
+		mark := "›"
+		if sent.scheduled {
+			mark = "⟳"
+		}
+		part := fmt.Sprintf("%s %s (%s)", mark, truncate(sent.command, maxSentLogCmdLen), sessionsTimeAgo(sent.at))
+		if lipgloss.Width(strings.Join(append(parts, part), "  ")) > width {
+			break
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimColor).Render(strings.Join(parts, "  "))
+}
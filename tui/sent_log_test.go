@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestSentHistoryMergesScheduledRuns(t *testing.T) {
+	m := NewModel(Options{})
+	m.recordSent("", []string{"work:0.0"}, "make test")
+	m.scheduledJobs = []config.ScheduledJob{
+		{Command: "/compact", Target: "work:0.0", LastRunAt: time.Now().Add(-time.Hour)},
+		{Command: "never ran", Target: "work:0.0"},
+		{Command: "elsewhere", Target: "work:1.0", LastRunAt: time.Now()},
+		{Command: "all agents", TargetMode: config.TargetModeAllAgents, LastRunAt: time.Now()},
+	}
+
+	got := m.sentHistory("", "work:0.0", sentLogShown)
+	if len(got) != 2 || got[0].command != "make test" || got[1].command != "/compact" || !got[1].scheduled {
+		t.Fatalf("expected the manual send then the scheduled run, got %+v", got)
+	}
+	if remote := m.sentHistory("devbox", "work:0.0", sentLogShown); len(remote) != 0 {
+		t.Fatalf("expected no history for the same target on another host, got %+v", remote)
+	}
+}
+
+func TestRecordSentKeepsNewestPerPane(t *testing.T) {
+	m := NewModel(Options{})
+	for _, cmd := range []string{"1", "2", "3", "4", "5", "6"} {
+		m.recordSent("", []string{"work:0.0"}, cmd)
+	}
+	log := m.sentLog[sentLogKey("", "work:0.0")]
+	if len(log) != sentLogSize || log[0].command != "2" || log[len(log)-1].command != "6" {
+		t.Fatalf("expected the newest %d sends, got %+v", sentLogSize, log)
+	}
+}
+
+func TestCommandSentRecordsEachPane(t *testing.T) {
+	m := NewModel(Options{})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 2)}}
+	m.rebuildFlatNodes()
+
+	updated, _ := m.Update(CommandSentMsg{Target: "work:0", Command: "git pull", Panes: []string{"work:0.0", "work:0.1"}})
+	m = updated.(Model)
+	for _, pane := range []string{"work:0.0", "work:0.1"} {
+		if got := m.sentHistory("", pane, 1); len(got) != 1 || got[0].command != "git pull" {
+			t.Fatalf("expected git pull logged for %s, got %+v", pane, got)
+		}
+	}
+
+	// tmux actions like zoom aren't typed into the pane
+	updated, _ = m.Update(CommandSentMsg{Target: "work:0.0", Command: "zoom"})
+	if got := updated.(Model).sentHistory("", "work:0.0", sentLogShown); len(got) != 1 {
+		t.Fatalf("expected zoom not to be logged, got %+v", got)
+	}
+}
+
+func TestRenderSentLogFitsWidth(t *testing.T) {
+	m := NewModel(Options{})
+	m.recordSent("", []string{"work:0.0"}, "first")
+	m.recordSent("", []string{"work:0.0"}, "second")
+
+	full := ansi.Strip(m.renderSentLog("", "work:0.0", 80))
+	if !strings.HasPrefix(full, "› second (0m ago)") || !strings.Contains(full, "› first") {
+		t.Fatalf("unexpected sent log %q", full)
+	}
+	if narrow := ansi.Strip(m.renderSentLog("", "work:0.0", 20)); narrow != "› second (0m ago)" {
+		t.Fatalf("expected only the newest send to fit, got %q", narrow)
+	}
+	if m.renderSentLog("", "work:0.0", 5) != "" {
+		t.Fatal("expected nothing when no send fits")
+	}
+}
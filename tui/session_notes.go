@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/history"
+)
+
+// notesLoadedMsg carries the saved session notes.
+type notesLoadedMsg struct {
+	notes map[history.NoteKey]string
+	err   error
+}
+
+// noteSavedMsg is returned after saving (or clearing) a session note.
+type noteSavedMsg struct {
+	key  history.NoteKey
+	note string
+	err  error
+}
+
+// loadSessionNotes reads the session notes from the history database.
+func loadSessionNotes() tea.Msg {
+	store, err := history.Open()
+	if err != nil {
+		return notesLoadedMsg{err: err}
+	}
+	defer store.Close()
+	notes, err := store.LoadNotes()
+	return notesLoadedMsg{notes: notes, err: err}
+}
+
+// saveSessionNote stores note for the session, removing it when empty.
+func saveSessionNote(key history.NoteKey, note string) tea.Cmd {
+	return func() tea.Msg {
+		store, err := history.Open()
+		if err != nil {
+			return noteSavedMsg{key: key, err: err}
+		}
+		defer store.Close()
+		note = strings.TrimSpace(note)
+		if err := store.SetNote(key, note); err != nil {
+			return noteSavedMsg{key: key, err: fmt.Errorf("failed to save note: %w", err)}
+		}
+		return noteSavedMsg{key: key, note: note}
+	}
+}
+
+// newNoteInput creates the input used to edit a session note.
+func newNoteInput(note string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "e.g. waiting on review (empty to remove)"
+	ti.CharLimit = 80
+	ti.Width = 50
+	ti.SetValue(note)
+	ti.CursorEnd()
+	ti.Focus()
+	return ti
+}
+
+// selectedNoteKey returns the note key for the selected active session or
+// recent entry.
+func (m sessionsModel) selectedNoteKey() (history.NoteKey, bool) {
+	if m.selectedIndex < len(m.lines) {
+		line := m.lines[m.selectedIndex]
+		return history.NoteKey{SessionName: line.Name, Host: line.Host}, true
+	}
+	if idx := m.selectedIndex - len(m.lines); idx >= 0 && idx < len(m.historyEntries) {
+		entry := m.historyEntries[idx]
+		return history.NoteKey{SessionName: entry.SessionName, Host: entry.Host}, true
+	}
+	return history.NoteKey{}, false
+}
+
+// handleNoteKeys handles keys while a session note is being edited.
+func (m sessionsModel) handleNoteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		key := *m.editingNote
+		m.editingNote = nil
+		return m, saveSessionNote(key, m.noteInput.Value())
+	case "esc", "ctrl+c":
+		m.editingNote = nil
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
+// noteText renders a session's note for the end of its row.
+func (m sessionsModel) noteText(key history.NoteKey) string {
+	note := m.notes[key]
+	if note == "" {
+		return ""
+	}
+	return "  " + lipgloss.NewStyle().Foreground(dimColor).Italic(true).Render("— "+note)
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/porganisciak/agent-tmux/config"
@@ -28,27 +30,36 @@ const (
 
 type SessionsOptions struct {
 	AltScreen        bool
-	Executors        []tmux.TmuxExecutor // Executors for local + remote hosts
-	ShowBeads        bool                // Show beads issue counts per session
-	DisableStaleness bool                // Disable staleness indicators
+	Executors        []tmux.TmuxExecutor            // Executors for local + remote hosts
+	ShowBeads        bool                           // Show beads issue counts per session
+	DisableStaleness bool                           // Disable staleness indicators
+	RemoteProjects   []config.ResolvedRemoteProject // Configured remote projects to offer for quick launch
+	SelectSession    string                         // Session to select once listed, e.g. carried over from browse
+	SelectHost       string                         // Host of SelectSession ("" for local)
 }
 
 // SessionsResult contains the outcome of the sessions list interaction.
 type SessionsResult struct {
-	SessionName   string            // Session selected for attach, empty if quit
-	WorkingDir    string            // Working directory for revival (if from history)
-	IsFromHistory bool              // True if reviving from history rather than attaching
-	Host          string            // Host label for remote sessions ("" for local)
-	Executor      tmux.TmuxExecutor // The executor for the selected session
+	SessionName   string                        // Session selected for attach, empty if quit
+	WorkingDir    string                        // Working directory for revival (if from history)
+	IsFromHistory bool                          // True if reviving from history rather than attaching
+	ReadOnly      bool                          // True to attach as a read-only client
+	Host          string                        // Host label for remote sessions ("" for local)
+	Executor      tmux.TmuxExecutor             // The executor for the selected session
+	RemoteProject *config.ResolvedRemoteProject // Remote project to connect to (nil unless one was selected)
+	Switch        *ScreenSwitch                 // Another screen to open instead (nil unless asked for)
 }
 
 // RunSessionsList runs a simple session list UI and returns the selected session.
 func RunSessionsList(opts SessionsOptions) (*SessionsResult, error) {
+	loadTheme()
 	executors := opts.Executors
 	if len(executors) == 0 {
 		executors = []tmux.TmuxExecutor{tmux.NewLocalExecutor()}
 	}
 	m := newSessionsModel(executors, opts.ShowBeads, opts.DisableStaleness)
+	m.remoteProjects = opts.RemoteProjects
+	m.selectSession, m.selectHost = opts.SelectSession, opts.SelectHost
 	programOptions := []tea.ProgramOption{
 		tea.WithMouseCellMotion(),
 	}
@@ -74,8 +85,11 @@ func RunSessionsList(opts SessionsOptions) (*SessionsResult, error) {
 			SessionName:   model.attachSession,
 			WorkingDir:    model.reviveDir,
 			IsFromHistory: model.isHistorySelection,
+			ReadOnly:      model.readOnly,
 			Host:          model.selectedHost,
 			Executor:      exec,
+			RemoteProject: model.remoteProject,
+			Switch:        model.switchTo,
 		}, nil
 	}
 	return &SessionsResult{}, nil
@@ -85,7 +99,7 @@ type sessionsModel struct {
 	lines              []tmux.SessionLine
 	historyEntries     []history.Entry
 	memoryBySession    map[string]tmux.SessionMemory
-	beadsCounts        map[string]*int // nil value = not loaded yet; *int distinguishes "not loaded" from "0 open"
+	beadsCounts        map[string]*beadsTally // nil value = not loaded yet, distinct from "0 open"
 	showBeads          bool
 	width              int
 	height             int
@@ -93,25 +107,58 @@ type sessionsModel struct {
 	attachSession      string
 	reviveDir          string
 	isHistorySelection bool
+	readOnly           bool
 	selectedHost       string
 	lastError          error
 	historyError       error
 	memoryError        error
 	executors          []tmux.TmuxExecutor
 	executorMap        map[string]tmux.TmuxExecutor
-	rawHistoryEntries  []history.Entry   // Unfiltered history (for re-filtering)
-	pendingExecutors   int               // Executors still loading
+	rawHistoryEntries  []history.Entry  // Unfiltered history (for re-filtering)
+	missingDirs        map[int64]bool   // History entry IDs whose directory no longer exists
+	pendingExecutors   int              // Executors still loading
+	hostErrors         map[string]error // Hosts whose session list failed, e.g. timed out
 	confirmKill        bool
 	killSessionName    string
+	killImpact         *tmux.KillImpact // Panes and agents the kill destroys, nil until listed
+	agentPrograms      map[string]bool  // Programs that mark a pane as running an agent
+	skipKillConfirm    bool             // Kill without confirmation (attached sessions still confirm)
+	symbolIndicators   bool             // Mark staleness with symbols, not just color (accessibility / NO_COLOR)
+	sortMode           config.SessionSort
 	lineJump           lineJumpState
+	nextRun            string // Next scheduled job indicator ("" when none enabled)
+	notes              map[history.NoteKey]string
+	noteInput          textinput.Model
+	editingNote        *history.NoteKey // Session whose note is being edited, nil if none
+	remoteProjects     []config.ResolvedRemoteProject
+	remoteProject      *config.ResolvedRemoteProject // Selected remote project, nil if none
+	notice             string                        // Transient confirmation, cleared on the next key
+	recentlyKilled     *history.Entry                // Session just killed, offered for a quick revive
+	killUndoSeq        int                           // Counts kills, so a stale expiry leaves a newer offer alone
+
+	beadsCommand string // Run in a new window when a beads label is clicked
+
+	pinned map[string]bool // Pinned sessions by pinKey, listed first in their host group
+
+	absoluteTimes bool // Show last-used timestamps instead of "3h ago"
+
+	// g then l/s/b switches screens. selectSession/selectHost name a session
+	// carried over from the last screen, selected as its host's list arrives.
+	screenJump    screenJump
+	switchTo      *ScreenSwitch
+	selectSession string
+	selectHost    string
+
+	spinner     spinner.Model   // Spins while hosts are loading
+	loadedHosts map[string]bool // Hosts whose session list has arrived
 
 	// Staleness
-	stalenessDisabled    bool
-	freshThreshold       time.Duration
-	staleThreshold       time.Duration
-	suggestionThreshold  int
-	confirmKillStale     bool
-	staleSessionNames    []string
+	stalenessDisabled   bool
+	freshThreshold      time.Duration
+	staleThreshold      time.Duration
+	suggestionThreshold int
+	confirmKillStale    bool
+	staleSessionNames   []string
 }
 
 func newSessionsModel(executors []tmux.TmuxExecutor, showBeads bool, disableStaleness bool) sessionsModel {
@@ -137,6 +184,12 @@ func newSessionsModel(executors []tmux.TmuxExecutor, showBeads bool, disableStal
 	if disableStaleness {
 		stalenessDisabled = true
 	}
+	skipKillConfirm := err == nil && settings.SkipKillConfirm
+	symbolIndicators := settings.SymbolIndicatorsEnabled()
+	sortMode := config.SessionSortActivity
+	if err == nil && config.ValidSessionSort(settings.SessionsSort) {
+		sortMode = settings.SessionsSort
+	}
 
 	return sessionsModel{
 		selectedIndex:       0,
@@ -148,12 +201,23 @@ func newSessionsModel(executors []tmux.TmuxExecutor, showBeads bool, disableStal
 		freshThreshold:      freshThreshold,
 		staleThreshold:      staleThreshold,
 		suggestionThreshold: suggestionThreshold,
+		skipKillConfirm:     skipKillConfirm,
+		symbolIndicators:    symbolIndicators,
+		sortMode:            sortMode,
+		beadsCommand:        settings.EffectiveBeadsCommand(),
+		pinned:              pinnedSet(settings.PinnedSessions),
+		absoluteTimes:       settings.AbsoluteTimes,
+		agentPrograms:       tmux.AgentPrograms(tmux.DefaultAgents()),
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot),
+			spinner.WithStyle(lipgloss.NewStyle().Foreground(dimColor))),
 	}
 }
 
 func (m sessionsModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.fetchAllSessions(),
+		loadNextRun,
+		loadSessionNotes,
 		func() tea.Msg {
 			// Only fetch memory for local sessions
 			memory, err := tmux.FetchSessionMemory()
@@ -179,12 +243,141 @@ func (m sessionsModel) fetchAllSessions() tea.Cmd {
 		executor := exec // capture for closure
 		cmds = append(cmds, func() tea.Msg {
 			lines, err := tmux.ListSessionsRawWithExecutor(executor)
-			return executorSessionsMsg{lines: lines, err: err}
+			return executorSessionsMsg{host: executor.HostLabel(), lines: lines, err: err}
 		})
 	}
+	cmds = append(cmds, m.spinner.Tick)
 	return tea.Batch(cmds...)
 }
 
+// sortSessionLines orders sessions by mode, most relevant first, with pinned
+// sessions ahead of the rest. Ties fall back to activity so the order stays
+// stable between refreshes.
+func sortSessionLines(lines []tmux.SessionLine, mode config.SessionSort, pinned map[string]bool) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i], lines[j]
+		if ap, bp := pinned[pinKey(a.Host, a.Name)], pinned[pinKey(b.Host, b.Name)]; ap != bp {
+			return ap
+		}
+		switch mode {
+		case config.SessionSortName:
+			if an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name); an != bn {
+				return an < bn
+			}
+		case config.SessionSortCreated:
+			if a.Created != b.Created {
+				return a.Created > b.Created
+			}
+		case config.SessionSortWindows:
+			if a.Windows != b.Windows {
+				return a.Windows > b.Windows
+			}
+		case config.SessionSortAttached:
+			if a.LastAttached != b.LastAttached {
+				return a.LastAttached > b.LastAttached
+			}
+		}
+		return a.Activity > b.Activity
+	})
+}
+
+// cycleSortMode switches to the next sort order, re-sorts the list keeping
+// the selected session selected, and saves the choice to settings.
+func (m sessionsModel) cycleSortMode() sessionsModel {
+	next := config.SessionSorts[0]
+	for i, mode := range config.SessionSorts {
+		if mode == m.sortMode {
+			next = config.SessionSorts[(i+1)%len(config.SessionSorts)]
+			break
+		}
+	}
+	m.sortMode = next
+	m.resortKeepingSelection()
+
+	settings, err := config.LoadSettings()
+	if err == nil {
+		settings.SessionsSort = m.sortMode
+		err = settings.Save()
+	}
+	if err != nil {
+		m.lastError = fmt.Errorf("failed to save sort order: %w", err)
+	}
+	return m
+}
+
+// resortKeepingSelection re-sorts the list after the sort order or pins
+// change, keeping the selected session selected.
+func (m *sessionsModel) resortKeepingSelection() {
+	var selected string
+	if m.selectedIndex < len(m.lines) {
+		selected = m.lines[m.selectedIndex].Host + "/" + m.lines[m.selectedIndex].Name
+	}
+	sortSessionLines(m.lines, m.sortMode, m.pinned)
+	m.lines = groupSessionsByHost(m.lines)
+	for i, line := range m.lines {
+		if line.Host+"/"+line.Name == selected {
+			m.selectedIndex = i
+			break
+		}
+	}
+}
+
+// pinGlyph marks pinned sessions in the list.
+const pinGlyph = "📌"
+
+// pinKey identifies a session in the pinned_sessions setting: its name, or
+// "host/name" for a remote session.
+func pinKey(host, name string) string {
+	if host == "" {
+		return name
+	}
+	return host + "/" + name
+}
+
+// pinnedSet indexes the pinned_sessions setting.
+func pinnedSet(keys []string) map[string]bool {
+	pinned := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		pinned[key] = true
+	}
+	return pinned
+}
+
+// togglePin pins or unpins the selected session, re-sorts the list, and
+// saves the pins to settings.
+func (m sessionsModel) togglePin() sessionsModel {
+	if m.selectedIndex >= len(m.lines) {
+		return m
+	}
+	line := m.lines[m.selectedIndex]
+	key := pinKey(line.Host, line.Name)
+	if m.pinned == nil {
+		m.pinned = make(map[string]bool)
+	}
+	if m.pinned[key] {
+		delete(m.pinned, key)
+		m.notice = "Unpinned " + line.Name
+	} else {
+		m.pinned[key] = true
+		m.notice = "Pinned " + line.Name
+	}
+	m.resortKeepingSelection()
+
+	settings, err := config.LoadSettings()
+	if err == nil {
+		settings.PinnedSessions = nil
+		for key := range m.pinned {
+			settings.PinnedSessions = append(settings.PinnedSessions, key)
+		}
+		sort.Strings(settings.PinnedSessions)
+		err = settings.Save()
+	}
+	if err != nil {
+		m.lastError = fmt.Errorf("failed to save pinned sessions: %w", err)
+	}
+	return m
+}
+
 // groupSessionsByHost reorders sessions so local sessions come first, then
 // each remote host group, preserving activity order within each group.
 // This keeps m.lines indices consistent with the display order when the
@@ -213,6 +406,7 @@ func groupSessionsByHost(lines []tmux.SessionLine) []tmux.SessionLine {
 
 // executorSessionsMsg is sent when a single executor finishes loading sessions.
 type executorSessionsMsg struct {
+	host  string // Host label ("" for local)
 	lines []tmux.SessionLine
 	err   error
 }
@@ -232,13 +426,39 @@ type killSessionMsg struct {
 	err         error
 }
 
+// killImpactMsg reports the panes a session kill would destroy.
+type killImpactMsg struct {
+	sessionName string
+	impact      tmux.KillImpact
+	err         error
+}
+
 type beadsCountMsg struct {
 	sessionName string
-	count       int
+	tally       beadsTally
 	hasBeads    bool
 	err         error
 }
 
+// beadsOpenedMsg reports the beads window opening in a session.
+type beadsOpenedMsg struct {
+	sessionName string
+	err         error
+}
+
+// openBeads runs command in a new "beads" window in the session's directory,
+// so a clicked beads label lands straight on the issue list.
+func openBeads(sessionName, command string) tea.Cmd {
+	return func() tea.Msg {
+		path := tmux.GetSessionPath(sessionName)
+		if path == "" {
+			return beadsOpenedMsg{sessionName: sessionName, err: fmt.Errorf("failed to find the directory of %s", sessionName)}
+		}
+		err := tmux.RunInNewWindow(sessionName, "beads", path, command)
+		return beadsOpenedMsg{sessionName: sessionName, err: err}
+	}
+}
+
 func fetchBeadsCount(sessionName string) tea.Cmd {
 	return func() tea.Msg {
 		path := tmux.GetSessionPath(sessionName)
@@ -248,7 +468,15 @@ func fetchBeadsCount(sessionName string) tea.Cmd {
 		if _, err := os.Stat(filepath.Join(path, ".beads")); err != nil {
 			return beadsCountMsg{sessionName: sessionName, hasBeads: false}
 		}
-		cmd := exec.Command("bd", "count", "--status=open", "--json")
+		// Prefer the per-status breakdown; older bd versions only count
+		cmd := exec.Command("bd", "count", "--by-status", "--json")
+		cmd.Dir = path
+		if output, err := cmd.Output(); err == nil {
+			if tally, ok := parseBeadsByStatus(output); ok {
+				return beadsCountMsg{sessionName: sessionName, tally: tally, hasBeads: true}
+			}
+		}
+		cmd = exec.Command("bd", "count", "--status=open", "--json")
 		cmd.Dir = path
 		output, err := cmd.Output()
 		if err != nil {
@@ -258,11 +486,21 @@ func fetchBeadsCount(sessionName string) tea.Cmd {
 			Count int `json:"count"`
 		}
 		json.Unmarshal(output, &result)
-		return beadsCountMsg{sessionName: sessionName, count: result.Count, hasBeads: true}
+		return beadsCountMsg{sessionName: sessionName, tally: beadsTally{open: result.Count}, hasBeads: true}
 	}
 }
 
 func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Handle note editing if active
+	if m.editingNote != nil {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			return m.handleNoteKeys(msg)
+		case tea.MouseMsg:
+			return m, nil
+		}
+	}
+
 	// Handle kill confirmation if active
 	if m.confirmKill {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
@@ -297,19 +535,43 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case attachCommandCopiedMsg:
+		if msg.err != nil {
+			m.lastError = msg.err
+		} else {
+			m.notice = fmt.Sprintf("Copied (%s): %s", msg.method, msg.command)
+		}
+		return m, nil
+	case spinner.TickMsg:
+		// Let the spinner stop once every host has answered
+		if m.pendingExecutors <= 0 {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
 	case executorSessionsMsg:
 		m.pendingExecutors--
+		if m.loadedHosts == nil {
+			m.loadedHosts = make(map[string]bool)
+		}
+		m.loadedHosts[msg.host] = true
+		if msg.err != nil && msg.host != "" {
+			if m.hostErrors == nil {
+				m.hostErrors = make(map[string]error)
+			}
+			m.hostErrors[msg.host] = msg.err
+		}
 		if msg.err == nil && len(msg.lines) > 0 {
 			m.lines = append(m.lines, msg.lines...)
-			sort.SliceStable(m.lines, func(i, j int) bool {
-				return m.lines[i].Activity > m.lines[j].Activity
-			})
+			sortSessionLines(m.lines, m.sortMode, m.pinned)
 			m.lines = groupSessionsByHost(m.lines)
 			// Re-filter history against updated session list
 			if m.rawHistoryEntries != nil {
 				m.historyEntries = m.filterHistory(m.rawHistoryEntries)
 			}
 			m.clampSelection()
+			m.selectCarriedSession()
 			// Trigger beads loading for newly arrived local sessions
 			if m.showBeads {
 				var cmds []tea.Cmd
@@ -324,18 +586,48 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case beadsOpenedMsg:
+		if msg.err != nil {
+			m.lastError = msg.err
+			return m, nil
+		}
+		// Attach to the session, which now shows the beads window
+		m.attachSession = msg.sessionName
+		m.selectedHost = ""
+		m.isHistorySelection = false
+		return m, tea.Quit
 	case beadsCountMsg:
 		if !msg.hasBeads {
 			return m, nil
 		}
 		if m.beadsCounts == nil {
-			m.beadsCounts = make(map[string]*int)
+			m.beadsCounts = make(map[string]*beadsTally)
 		}
 		if msg.err != nil {
 			return m, nil
 		}
-		count := msg.count
-		m.beadsCounts[msg.sessionName] = &count
+		tally := msg.tally
+		m.beadsCounts[msg.sessionName] = &tally
+		return m, nil
+	case nextRunMsg:
+		m.nextRun = msg.label
+		return m, nil
+	case notesLoadedMsg:
+		m.notes = msg.notes
+		return m, nil
+	case noteSavedMsg:
+		if msg.err != nil {
+			m.lastError = msg.err
+			return m, nil
+		}
+		if m.notes == nil {
+			m.notes = make(map[history.NoteKey]string)
+		}
+		if msg.note == "" {
+			delete(m.notes, msg.key)
+		} else {
+			m.notes[msg.key] = msg.note
+		}
 		return m, nil
 	case memoryLoadedMsg:
 		m.memoryBySession = msg.memory
@@ -346,6 +638,20 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.historyEntries = m.filterHistory(msg.entries)
 		m.historyError = msg.err
 		m.clampSelection()
+		return m, checkMissingDirs(msg.entries)
+	case missingDirsMsg:
+		m.missingDirs = msg.missing
+		return m, nil
+	case missingDirsDeletedMsg:
+		if msg.err != nil {
+			m.historyError = msg.err
+		}
+		m.historyEntries = removeHistoryEntries(m.historyEntries, msg.ids)
+		m.rawHistoryEntries = removeHistoryEntries(m.rawHistoryEntries, msg.ids)
+		for _, id := range msg.ids {
+			delete(m.missingDirs, id)
+		}
+		m.clampSelection()
 		return m, nil
 	case historyDeletedMsg:
 		if msg.err != nil {
@@ -355,6 +661,12 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.historyEntries = removeHistoryEntry(m.historyEntries, msg.id)
 		m.clampSelection()
 		return m, nil
+	case killImpactMsg:
+		if msg.err == nil && m.confirmKill && msg.sessionName == m.killSessionName {
+			m.killImpact = &msg.impact
+		}
+		return m, nil
+
 	case killSessionMsg:
 		if msg.err != nil {
 			m.lastError = msg.err
@@ -363,8 +675,11 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Refresh sessions and history after killing
 		m.killSessionName = ""
 		m.lines = nil
+		m.hostErrors = nil
+		m.loadedHosts = nil
 		m.pendingExecutors = len(m.executors)
 		return m, tea.Batch(
+			m.offerKillUndo(msg.sessionName),
 			m.fetchAllSessions(),
 			func() tea.Msg {
 				store, err := history.Open()
@@ -382,6 +697,8 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.lines = nil
+		m.hostErrors = nil
+		m.loadedHosts = nil
 		m.pendingExecutors = len(m.executors)
 		return m, tea.Batch(
 			m.fetchAllSessions(),
@@ -399,11 +716,34 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	case killUndoExpiredMsg:
+		if msg.seq == m.killUndoSeq {
+			m.recentlyKilled = nil
+		}
+		return m, nil
 	case tea.KeyMsg:
+		m.notice = ""
+		if entry := m.recentlyKilled; entry != nil {
+			m.recentlyKilled = nil
+			if msg.String() == "u" {
+				return m.reviveKilled(*entry)
+			}
+		}
 		if idx, ok := m.lineJump.consumeKey(msg, len(m.lines)); ok {
 			m.selectedIndex = idx
 			return m, nil
 		}
+		if to, ok := m.screenJump.consume(msg.String(), ScreenSessions); ok {
+			if to == "" {
+				return m, nil
+			}
+			m.switchTo = &ScreenSwitch{To: to}
+			if m.selectedIndex < len(m.lines) {
+				line := m.lines[m.selectedIndex]
+				m.switchTo.Session, m.switchTo.Host = line.Name, line.Host
+			}
+			return m, tea.Quit
+		}
 		switch msg.String() {
 		case "q", "esc", "ctrl+c":
 			return m, tea.Quit
@@ -418,8 +758,64 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectedIndex++
 			}
 			return m, nil
+		case "pgup":
+			m.selectedIndex -= m.pageSize()
+			m.clampSelection()
+			return m, nil
+		case "pgdown":
+			m.selectedIndex += m.pageSize()
+			m.clampSelection()
+			return m, nil
+		case "home":
+			m.selectedIndex = 0
+			return m, nil
+		case "end":
+			// Last item across both the active and recent sections
+			m.selectedIndex = m.totalItems() - 1
+			m.clampSelection()
+			return m, nil
 		case "enter":
 			return m.selectCurrent()
+		case "r":
+			// Read-only attach (active sessions only)
+			if m.selectedIndex < len(m.lines) {
+				m.readOnly = true
+				return m.selectCurrent()
+			}
+			return m, nil
+		case "[", "]":
+			// Jump to the first session of the previous/next host
+			dir := 1
+			if msg.String() == "[" {
+				dir = -1
+			}
+			if index, ok := adjacentGroupStart(m.hostGroupStarts(), m.selectedIndex, dir); ok {
+				m.selectedIndex = index
+			}
+			return m, nil
+		case "o":
+			return m.cycleSortMode(), nil
+		case "p":
+			// Pin or unpin the selected session
+			return m.togglePin(), nil
+		case "t":
+			// Switch recent sessions between "3h ago" and timestamps
+			m.absoluteTimes = !m.absoluteTimes
+			if err := saveAbsoluteTimes(m.absoluteTimes); err != nil {
+				m.lastError = err
+			}
+			return m, nil
+		case "y":
+			// Copy the ssh/mosh (or tmux) attach command for the selected session
+			return m, m.copyAttachCommand()
+		case "n":
+			// Edit the note for the selected session or recent entry
+			if key, ok := m.selectedNoteKey(); ok {
+				m.editingNote = &key
+				m.noteInput = newNoteInput(m.notes[key])
+				return m, textinput.Blink
+			}
+			return m, nil
 		case "S":
 			if !m.stalenessDisabled {
 				stale := m.staleSessions()
@@ -431,84 +827,81 @@ func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case "x", "delete", "backspace":
 			if m.selectedIndex < len(m.lines) {
-				// Active session: prompt to kill
+				// Active session: prompt to kill (the attached session always prompts)
 				line := m.lines[m.selectedIndex]
+				if m.skipKillConfirm && !strings.Contains(line.Line, "(attached)") {
+					return m, m.killSession(line.Name)
+				}
 				m.confirmKill = true
 				m.killSessionName = line.Name
-				return m, nil
+				m.killImpact = nil
+				return m, m.fetchKillImpact(line)
 			}
 			// History entry: delete from history
 			if cmd := m.deleteSelectedHistoryEntry(); cmd != nil {
 				return m, cmd
 			}
 			return m, nil
+		case "X":
+			// Remove every recent entry whose directory is gone
+			if ids := missingEntryIDs(m.historyEntries, m.missingDirs); len(ids) > 0 {
+				return m, deleteHistoryEntries(ids)
+			}
+			return m, nil
 		}
 	case tea.MouseMsg:
 		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
-			// Build a Y-position → item-index mapping that accounts for
-			// all non-selectable rows (title, subtitle, headers, banners).
-			y := 0
-			y += 3 // title + subtitle + blank line
-
-			// Staleness suggestion banner
-			if !m.stalenessDisabled && len(m.lines) >= m.suggestionThreshold && m.staleSessionCount() > 0 {
-				y += 2 // banner + blank
-			}
-
-			// Error lines
-			if m.lastError != nil {
-				y++
-			}
-			if m.historyError != nil {
-				y++
-			}
-
-			// Active sessions with host group headers
-			total := m.totalItems()
-			lastHost := "\x00"
-			hasRemote := false
-			for _, line := range m.lines {
-				if line.Host != "" {
-					hasRemote = true
-					break
-				}
-			}
-			activeStartY := y
-			for i, line := range m.lines {
-				if hasRemote && line.Host != lastHost {
-					y++ // host group header row
-					lastHost = line.Host
-				} else if !hasRemote && i == 0 {
-					y++ // "Active" header
-				}
-				if msg.Y == y {
-					m.selectedIndex = i
-					return m.selectCurrent()
-				}
-				y++
+			m.recentlyKilled = nil
+			// Map the click through the same layout View draws
+			header, rows := m.listLayout()
+			i := msg.Y - lipgloss.Height(lipgloss.JoinVertical(lipgloss.Left, header...))
+			if i < 0 || i >= len(rows) || rows[i].item < 0 {
+				return m, nil
 			}
-
-			// Recent history area: blank line + "Recent" header
-			if len(m.historyEntries) > 0 {
-				y += 2 // spacing + "Recent" header
-				for i := range m.historyEntries {
-					globalIdx := len(m.lines) + i
-					if msg.Y == y && globalIdx < total {
-						m.selectedIndex = globalIdx
-						return m.selectCurrent()
-					}
-					y++
+			m.selectedIndex = rows[i].item
+			if m.selectedIndex < len(m.lines) && rows[i].inBeads(msg.X) {
+				line := m.lines[m.selectedIndex]
+				if line.Host == "" {
+					return m, openBeads(line.Name, m.beadsCommand)
 				}
 			}
-			_ = activeStartY
+			return m.selectCurrent()
 		}
 	}
 	return m, nil
 }
 
+// hostGroupStarts returns the index of each host's first session, empty
+// when every session is local.
+func (m sessionsModel) hostGroupStarts() []int {
+	var starts []int
+	remote := false
+	for i, line := range m.lines {
+		if line.Host != "" {
+			remote = true
+		}
+		if i == 0 || line.Host != m.lines[i-1].Host {
+			starts = append(starts, i)
+		}
+	}
+	if !remote {
+		return nil
+	}
+	return starts
+}
+
 // totalItems returns the total number of selectable items.
 func (m sessionsModel) totalItems() int {
-	return len(m.lines) + len(m.historyEntries)
+	return len(m.lines) + len(m.historyEntries) + len(m.remoteProjects)
+}
+
+// pageSize returns how many rows PgUp/PgDn move: the list rows that fit
+// below the title, subtitle, and section header.
+func (m sessionsModel) pageSize() int {
+	if rows := m.height - 5; rows > 1 {
+		return rows
+	}
+	return 1
 }
 
 // clampSelection ensures selectedIndex is within bounds.
@@ -545,11 +938,20 @@ func (m sessionsModel) selectCurrent() (tea.Model, tea.Cmd) {
 		m.attachSession = line.Name
 		m.selectedHost = line.Host
 		m.isHistorySelection = false
+	} else if project, ok := m.selectedRemoteProject(); ok {
+		// Remote project: connect and attach or create its session
+		m.attachSession = project.SessionName
+		m.remoteProject = &project
+		m.isHistorySelection = false
 	} else {
 		// History entry
 		histIdx := m.selectedIndex - len(m.lines)
 		if histIdx >= 0 && histIdx < len(m.historyEntries) {
 			entry := m.historyEntries[histIdx]
+			if m.missingDirs[entry.ID] {
+				m.lastError = fmt.Errorf("directory %s no longer exists (press X to remove missing entries)", entry.WorkingDirectory)
+				return m, nil
+			}
 			m.attachSession = entry.SessionName
 			m.reviveDir = entry.WorkingDirectory
 			m.isHistorySelection = true
@@ -564,19 +966,7 @@ func (m sessionsModel) View() string {
 		return "Loading..."
 	}
 
-	title := lipgloss.NewStyle().Bold(true).Render("Sessions")
-	xHint := "x remove"
-	if m.selectedIndex < len(m.lines) {
-		xHint = "x kill"
-	}
-	subtitleParts := "↑↓ select, digits jump, Enter attach, " + xHint
-	if !m.stalenessDisabled {
-		subtitleParts += ", S kill-stale"
-	}
-	subtitleParts += ", q quit"
-	subtitle := lipgloss.NewStyle().Foreground(dimColor).Render(subtitleParts)
-	numberWidth := len(fmt.Sprintf("%d", max(1, len(m.lines))))
-
+	title, subtitle := m.titleRows()
 	var sections []string
 
 	// Show kill confirmation if active
@@ -596,6 +986,12 @@ func (m sessionsModel) View() string {
 			}
 		}
 		sections = append(sections, warning)
+		if m.killImpact != nil {
+			sections = append(sections, killImpactLines(*m.killImpact)...)
+		} else if windows := m.killSessionWindows(); windows > 0 {
+			// Until its panes are listed, the session line knows its windows
+			sections = append(sections, killImpactLines(tmux.KillImpact{Windows: windows})...)
+		}
 		return lipgloss.JoinVertical(lipgloss.Left, sections...)
 	}
 
@@ -613,7 +1009,69 @@ func (m sessionsModel) View() string {
 		return lipgloss.JoinVertical(lipgloss.Left, sections...)
 	}
 
+	sections, rows := m.listLayout()
+	for _, row := range rows {
+		sections = append(sections, row.text)
+	}
+
+	// Add tip at the bottom, or the latest confirmation in its place
+	footer := RenderTipForContext(TipSessions)
+	if m.recentlyKilled != nil {
+		footer = m.killUndoFooter()
+	}
+	if m.notice != "" {
+		footer = lipgloss.NewStyle().Foreground(activeColor).Render(m.notice)
+	}
+	sections = append(sections, "", footer)
+
+	result := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return truncateToHeight(result, m.height)
+}
+
+// titleRows returns the title and the key hints under it.
+func (m sessionsModel) titleRows() (string, string) {
+	title := lipgloss.NewStyle().Bold(true).Render("Sessions")
+	if m.nextRun != "" {
+		title += "  " + lipgloss.NewStyle().Foreground(dimColor).Render(m.nextRun)
+	}
+	xHint := "x remove"
+	if m.selectedIndex < len(m.lines) {
+		xHint = "x kill"
+		if m.skipKillConfirm {
+			xHint = "x kill (no confirm)"
+		}
+	}
+	subtitleParts := "↑↓/PgUp/PgDn select, digits jump, "
+	if len(m.hostGroupStarts()) > 0 {
+		subtitleParts += "[ ] host, "
+	}
+	subtitleParts += "Enter attach, r read-only, " + xHint
+	if !m.stalenessDisabled {
+		subtitleParts += ", S kill-stale"
+		if m.symbolIndicators {
+			subtitleParts += " (! stale, ~ aging)"
+		}
+	}
+	subtitleParts += ", y copy attach cmd, n note, p pin, t times, o sort: " + string(m.sortMode) + ", q quit"
+	subtitle := lipgloss.NewStyle().Foreground(dimColor).Render(subtitleParts)
+	return title, subtitle
+}
+
+// listLayout returns the rows above the list (title, prompts, banners and
+// errors) and the list rows that fit below them, windowed around the
+// selection. View and the mouse handler share it so clicks land on the row
+// drawn there.
+func (m sessionsModel) listLayout() ([]string, []listRow) {
+	title, subtitle := m.titleRows()
+	numberWidth := len(fmt.Sprintf("%d", max(1, len(m.lines))))
+
+	var sections []string
 	sections = append(sections, title, subtitle, "")
+	if m.editingNote != nil {
+		prompt := lipgloss.NewStyle().Bold(true).Render("Note for " + m.editingNote.SessionName + ": ")
+		sections = append(sections, prompt+m.noteInput.View(),
+			lipgloss.NewStyle().Foreground(dimColor).Render("Enter save, Esc cancel"), "")
+	}
 
 	// Suggestion banner when many sessions and some are stale
 	if !m.stalenessDisabled && len(m.lines) >= m.suggestionThreshold {
@@ -638,6 +1096,7 @@ func (m sessionsModel) View() string {
 	// Active sessions section — iterate m.lines in order (already grouped
 	// by host via groupSessionsByHost) and insert a header when the host changes.
 	sectionHeader := lipgloss.NewStyle().Bold(true).Foreground(secondaryColor)
+	list := newListRows()
 
 	if len(m.lines) > 0 {
 		lastHost := "\x00" // sentinel so the first line always triggers a header
@@ -654,34 +1113,49 @@ func (m sessionsModel) View() string {
 				if line.Host != "" {
 					hostLabel = "Active @ " + line.Host
 				}
-				sections = append(sections, sectionHeader.Render(hostLabel))
+				list.addHeader(sectionHeader.Render(hostLabel))
 				lastHost = line.Host
 			} else if !hasRemote && i == 0 {
-				sections = append(sections, sectionHeader.Render("Active"))
+				list.addHeader(sectionHeader.Render("Active"))
+			}
+			row, beadsX := m.renderActiveSessionRow(i, line, numberWidth)
+			list.add(row, i)
+			if beadsX >= 0 {
+				list.markBeads(beadsX, lipgloss.Width(m.beadsLabel(line.Name)))
 			}
-			row := m.renderActiveSessionRow(i, line, numberWidth)
-			sections = append(sections, row)
 		}
 	} else if m.pendingExecutors > 0 {
-		sections = append(sections, sectionHeader.Render("Active"))
-		sections = append(sections, lipgloss.NewStyle().Foreground(dimColor).Render("  Loading..."))
+		list.addHeader(sectionHeader.Render("Active"))
+		list.add(m.loadingRow(), -1)
 	} else {
-		sections = append(sections, sectionHeader.Render("Active"))
-		sections = append(sections, lipgloss.NewStyle().Foreground(dimColor).Render("  No active sessions"))
+		list.addHeader(sectionHeader.Render("Active"))
+		list.add(lipgloss.NewStyle().Foreground(dimColor).Render("  No active sessions"), -1)
 	}
 
 	// Show loading indicator for remote hosts still connecting
 	if m.pendingExecutors > 0 && len(m.lines) > 0 {
-		sections = append(sections, lipgloss.NewStyle().Foreground(dimColor).Render("  Loading remote hosts..."))
+		list.add(m.loadingRow(), -1)
+	}
+	// Hosts that failed or timed out, so a missing host isn't mistaken for
+	// one with no sessions
+	for _, exec := range m.executors {
+		if err, failed := m.hostErrors[exec.HostLabel()]; failed {
+			row := fmt.Sprintf("  %s: %s", exec.HostLabel(), tmux.FetchErrorReason(err))
+			list.add(lipgloss.NewStyle().Foreground(errorColor).Render(row), -1)
+		}
 	}
 
 	// Recent history section
 	if len(m.historyEntries) > 0 {
-		sections = append(sections, "") // spacing
-		sections = append(sections, sectionHeader.Render("Recent"))
+		list.add("", -1) // spacing
+		missingCount := len(missingEntryIDs(m.historyEntries, m.missingDirs))
+		list.addHeader(sectionHeader.Render("Recent") + missingCleanupHint(missingCount))
 		for i, entry := range m.historyEntries {
 			globalIdx := len(m.lines) + i
 			ago := sessionsTimeAgo(entry.LastUsedAt)
+			if m.absoluteTimes {
+				ago = formatLastUsed(entry.LastUsedAt)
+			}
 
 			// Color the time-ago text by staleness
 			var metaColor lipgloss.Color
@@ -690,8 +1164,16 @@ func (m sessionsModel) View() string {
 			} else {
 				metaColor = stalenessColor(m.historyStalenessTier(entry.LastUsedAt))
 			}
-			meta := lipgloss.NewStyle().Foreground(metaColor).Render("(" + ago + ")")
+			metaText := "(" + ago + ")"
+			if m.symbolIndicators && !m.stalenessDisabled {
+				metaText = stalenessSymbol(m.historyStalenessTier(entry.LastUsedAt)) + " " + metaText
+			}
+			meta := lipgloss.NewStyle().Foreground(metaColor).Render(metaText)
 			dir := lipgloss.NewStyle().Foreground(dimColor).Render(entry.WorkingDirectory)
+			if m.missingDirs[entry.ID] {
+				dir += missingTag()
+			}
+			dir += m.noteText(history.NoteKey{SessionName: entry.SessionName, Host: entry.Host})
 			var row string
 			if globalIdx == m.selectedIndex {
 				formattedName := formatSessionName(entry.Name, selectedStyle)
@@ -700,15 +1182,24 @@ func (m sessionsModel) View() string {
 				formattedName := formatSessionName(entry.Name, lipgloss.NewStyle())
 				row = "  " + formattedName + "  " + meta + "  " + dir
 			}
-			sections = append(sections, row)
+			list.add(row, globalIdx)
 		}
 	}
 
-	// Add tip at the bottom
-	sections = append(sections, "", RenderTipForContext(TipSessions))
+	// Remote projects section
+	if len(m.remoteProjects) > 0 {
+		list.add("", -1)
+		list.addHeader(sectionHeader.Render("Remote Projects"))
+		offset := len(m.lines) + len(m.historyEntries)
+		for i, row := range m.renderRemoteProjectRows() {
+			list.add(row, offset+i)
+		}
+	}
 
-	result := lipgloss.JoinVertical(lipgloss.Left, sections...)
-	return truncateToHeight(result, m.height)
+	// Window the list into the rows left between the header and the
+	// footer's two lines, keeping the selection in view
+	listHeight := m.height - lipgloss.Height(lipgloss.JoinVertical(lipgloss.Left, sections...)) - 2
+	return sections, windowListRows(list.rows, m.selectedIndex, listHeight)
 }
 
 func sessionsTimeAgo(t time.Time) string {
@@ -725,6 +1216,61 @@ func sessionsTimeAgo(t time.Time) string {
 	}
 }
 
+// formatLastUsed formats when a recent session was last used as a local
+// timestamp, for when absolute times are on.
+func formatLastUsed(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// saveAbsoluteTimes saves whether recent sessions show timestamps, shared by
+// the sessions list and landing page.
+func saveAbsoluteTimes(on bool) error {
+	settings, err := config.LoadSettings()
+	if err == nil {
+		settings.AbsoluteTimes = on
+		err = settings.Save()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save time display: %w", err)
+	}
+	return nil
+}
+
+// formatLastAttached formats when a session was last attached, as a
+// timestamp when absolute times are on. It returns "" for sessions never
+// attached.
+func (m sessionsModel) formatLastAttached(lastAttached int64) string {
+	if lastAttached <= 0 {
+		return ""
+	}
+	t := time.Unix(lastAttached, 0)
+	if m.absoluteTimes {
+		return formatLastUsed(t)
+	}
+	return sessionsTimeAgo(t)
+}
+
+// formatUptime formats how long ago created was as a compact uptime
+// ("12m", "3h12m", "4d3h"), using the same units as sessionsTimeAgo.
+// It returns "" when the creation time is unknown.
+func formatUptime(created int64) string {
+	if created <= 0 {
+		return ""
+	}
+	d := time.Since(time.Unix(created, 0))
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours()/24), int(d.Hours())%24)
+	}
+}
+
 type historyDeletedMsg struct {
 	id  int64
 	err error
@@ -756,6 +1302,31 @@ func (m sessionsModel) deleteSelectedHistoryEntry() tea.Cmd {
 	}
 }
 
+// killSessionWindows returns the window count of the session awaiting kill
+// confirmation, as listed, or 0 if unknown.
+func (m sessionsModel) killSessionWindows() int {
+	for _, line := range m.lines {
+		if line.Name == m.killSessionName {
+			return line.Windows
+		}
+	}
+	return 0
+}
+
+// fetchKillImpact lists the panes in a session about to be killed, for the
+// confirmation to show.
+func (m sessionsModel) fetchKillImpact(line tmux.SessionLine) tea.Cmd {
+	exec, ok := m.executorMap[line.Host]
+	if !ok {
+		exec = tmux.NewLocalExecutor()
+	}
+	programs := m.agentPrograms
+	return func() tea.Msg {
+		impact, err := tmux.SessionKillImpactWithExecutor(line.Name, programs, exec)
+		return killImpactMsg{sessionName: line.Name, impact: impact, err: err}
+	}
+}
+
 func (m sessionsModel) killSession(name string) tea.Cmd {
 	return func() tea.Msg {
 		err := tmux.KillSession(name)
@@ -811,6 +1382,19 @@ func stalenessColor(tier stalenessTier) lipgloss.Color {
 	}
 }
 
+// stalenessSymbol returns a one-character marker for tier so staleness can be
+// read without color: "!" stale, "~" getting stale, " " fresh.
+func stalenessSymbol(tier stalenessTier) string {
+	switch tier {
+	case tierGettingStale:
+		return "~"
+	case tierStale:
+		return "!"
+	default:
+		return " "
+	}
+}
+
 // staleSessions returns the names of active sessions classified as stale.
 func (m sessionsModel) staleSessions() []string {
 	var names []string
@@ -922,21 +1506,55 @@ func (m sessionsModel) beadsLabel(sessionName string) string {
 	if !m.showBeads {
 		return ""
 	}
-	count, ok := m.beadsCounts[sessionName]
-	if !ok || count == nil {
+	tally, ok := m.beadsCounts[sessionName]
+	if !ok || tally == nil {
 		return ""
 	}
-	label := fmt.Sprintf("bd:%d", *count)
-	if *count > 0 {
-		return beadsCountStyle.Render(label)
+	return tally.label()
+}
+
+// loadingRow shows the spinner and, with more than one host, which hosts
+// haven't answered yet.
+func (m sessionsModel) loadingRow() string {
+	text := "Loading..."
+	if len(m.executors) > 1 {
+		var pending []string
+		for _, exec := range m.executors {
+			if m.loadedHosts[exec.HostLabel()] {
+				continue
+			}
+			label := exec.HostLabel()
+			if label == "" {
+				label = "local"
+			}
+			pending = append(pending, label)
+		}
+		text = "Waiting for " + strings.Join(pending, ", ")
 	}
-	return lipgloss.NewStyle().Foreground(dimColor).Render(label)
+	return "  " + m.spinner.View() + " " + lipgloss.NewStyle().Foreground(dimColor).Render(text)
 }
 
-func (m sessionsModel) renderActiveSessionRow(index int, line tmux.SessionLine, numberWidth int) string {
+// renderActiveSessionRow renders an active session's row and the column its
+// beads label starts at (-1 without one).
+func (m sessionsModel) renderActiveSessionRow(index int, line tmux.SessionLine, numberWidth int) (string, int) {
 	number := fmt.Sprintf("%*d.", numberWidth, index+1)
+	if m.symbolIndicators && !m.stalenessDisabled {
+		number = stalenessSymbol(m.sessionStalenessTier(line.Activity)) + number
+	}
 	memSummary := m.memorySummary(line.Name)
 	bdLabel := m.beadsLabel(line.Name)
+	note := m.noteText(history.NoteKey{SessionName: line.Name, Host: line.Host})
+	pin := ""
+	if m.pinned[pinKey(line.Host, line.Name)] {
+		pin = lipgloss.NewStyle().Foreground(primaryColor).Render(pinGlyph) + " "
+	}
+	uptime := ""
+	if up := formatUptime(line.Created); up != "" {
+		uptime = "  " + lipgloss.NewStyle().Foreground(dimColor).Render("up "+up)
+	}
+	if attached := m.formatLastAttached(line.LastAttached); attached != "" {
+		uptime += "  " + lipgloss.NewStyle().Foreground(dimColor).Render("attached "+attached)
+	}
 
 	// Determine number color based on staleness
 	tier := m.sessionStalenessTier(line.Activity)
@@ -950,26 +1568,34 @@ func (m sessionsModel) renderActiveSessionRow(index int, line tmux.SessionLine,
 	if index == m.selectedIndex {
 		row := selectedStyle.Render("> ") +
 			lipgloss.NewStyle().Foreground(numberColor).Bold(true).Render(number) +
-			" " +
-			formatSessionLine(line.Line, selectedStyle)
+			" " + pin +
+			formatSessionLine(line.Line, selectedStyle) +
+			uptime
+		beadsX := -1
 		if bdLabel != "" {
-			row += "  " + bdLabel
+			row += "  "
+			beadsX = lipgloss.Width(row)
+			row += bdLabel
 		}
 		if memSummary != "" {
 			row += "  " + lipgloss.NewStyle().Foreground(dimColor).Render(memSummary)
 		}
-		return row
+		return row + note, beadsX
 	}
 
 	row := "  " +
 		lipgloss.NewStyle().Foreground(numberColor).Render(number) +
-		" " +
-		formatSessionLine(line.Line, lipgloss.NewStyle())
+		" " + pin +
+		formatSessionLine(line.Line, lipgloss.NewStyle()) +
+		uptime
+	beadsX := -1
 	if bdLabel != "" {
-		row += "  " + bdLabel
+		row += "  "
+		beadsX = lipgloss.Width(row)
+		row += bdLabel
 	}
 	if memSummary != "" {
 		row += "  " + lipgloss.NewStyle().Foreground(dimColor).Render(memSummary)
 	}
-	return row
+	return row + note, beadsX
 }
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listRow is one line of the sessions list. item is the selectable index it
+// shows, or -1 for headers and spacing; header is the row of the section
+// header it falls under, or -1 before the first header. A session's beads
+// label spans columns [beadsStart, beadsEnd).
+type listRow struct {
+	text   string
+	item   int
+	header int
+
+	beadsStart, beadsEnd int
+}
+
+// listRows builds the sessions list a row at a time, remembering the
+// current section header so windowListRows can pin it.
+type listRows struct {
+	rows   []listRow
+	header int
+}
+
+func newListRows() *listRows {
+	return &listRows{header: -1}
+}
+
+// addHeader starts a section.
+func (l *listRows) addHeader(text string) {
+	l.header = len(l.rows)
+	l.rows = append(l.rows, listRow{text: text, item: -1, header: l.header})
+}
+
+// add appends a row showing item, or -1 for a row that can't be selected.
+func (l *listRows) add(text string, item int) {
+	l.rows = append(l.rows, listRow{text: text, item: item, header: l.header})
+}
+
+// markBeads records where the last row's beads label is drawn.
+func (l *listRows) markBeads(x, width int) {
+	last := &l.rows[len(l.rows)-1]
+	last.beadsStart, last.beadsEnd = x, x+width
+}
+
+// inBeads reports whether column x falls on the row's beads label.
+func (r listRow) inBeads(x int) bool {
+	return x >= r.beadsStart && x < r.beadsEnd
+}
+
+// windowListRows returns the rows that fit in height lines, centered on the
+// selected item. Rows cut off above or below are replaced by a "▲ N more" /
+// "▼ N more" line, and when the window starts partway through a section its
+// header takes the first row so the host stays visible.
+func windowListRows(rows []listRow, selected, height int) []listRow {
+	if len(rows) <= height || height < 3 {
+		return rows
+	}
+
+	selectedRow := 0
+	for i, r := range rows {
+		if r.item == selected {
+			selectedRow = i
+			break
+		}
+	}
+
+	// Leave a line for one indicator, and another if both ends are cut
+	view := height - 1
+	start := windowStart(selectedRow, view, len(rows))
+	if start > 0 && start+view < len(rows) {
+		view--
+		start = windowStart(selectedRow, view, len(rows))
+	}
+	end := start + view
+
+	var out []listRow
+	hiddenAbove := rows[:start]
+	visible := append([]listRow(nil), rows[start:end]...)
+	if header := rows[start].header; start > 0 && header >= 0 && header < start && selectedRow > start {
+		visible[0] = rows[header]
+		hiddenAbove = rows[:start+1]
+	}
+	if start > 0 {
+		out = append(out, moreIndicator("▲", hiddenAbove))
+	}
+	out = append(out, visible...)
+	if end < len(rows) {
+		out = append(out, moreIndicator("▼", rows[end:]))
+	}
+	return out
+}
+
+// windowStart returns the first row of a view-row window centered on row,
+// kept within total rows.
+func windowStart(row, view, total int) int {
+	return min(max(row-view/2, 0), total-view)
+}
+
+// moreIndicator returns the row standing in for hidden rows, counting the
+// selectable items among them.
+func moreIndicator(arrow string, hidden []listRow) listRow {
+	count := 0
+	for _, r := range hidden {
+		if r.item >= 0 {
+			count++
+		}
+	}
+	text := "  " + arrow + " more"
+	if count > 0 {
+		text = fmt.Sprintf("  %s %d more", arrow, count)
+	}
+	return listRow{text: lipgloss.NewStyle().Foreground(dimColor).Render(text), item: -1, header: -1}
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func testListRows() []listRow {
+	list := newListRows()
+	list.addHeader("Active @ devbox")
+	for i := 0; i < 10; i++ {
+		list.add(fmt.Sprintf("session %d", i), i)
+	}
+	return list.rows
+}
+
+func TestWindowListRowsFitsWithoutIndicators(t *testing.T) {
+	rows := testListRows()
+	if got := windowListRows(rows, 0, 20); len(got) != len(rows) {
+		t.Fatalf("expected all %d rows, got %d", len(rows), len(got))
+	}
+}
+
+func TestWindowListRowsCentersSelection(t *testing.T) {
+	rows := testListRows()
+
+	top := windowListRows(rows, 0, 6)
+	if len(top) != 6 || top[0].text != "Active @ devbox" || ansi.Strip(top[5].text) != "  ▼ 6 more" {
+		t.Fatalf("unexpected top window: %q", top)
+	}
+
+	// Mid-list the section header is pinned in place of the first row
+	mid := windowListRows(rows, 5, 6)
+	want := []string{"  ▲ 4 more", "Active @ devbox", "session 4", "session 5", "session 6", "  ▼ 3 more"}
+	if len(mid) != len(want) {
+		t.Fatalf("got %q, want %q", mid, want)
+	}
+	for i := range want {
+		if ansi.Strip(mid[i].text) != want[i] {
+			t.Fatalf("got %q, want %q", mid, want)
+		}
+	}
+
+	bottom := windowListRows(rows, 9, 6)
+	if len(bottom) != 6 || ansi.Strip(bottom[0].text) != "  ▲ 6 more" || bottom[5].text != "session 9" {
+		t.Fatalf("unexpected bottom window: %q", bottom)
+	}
+}
+
+func TestSessionsViewKeepsSelectionVisible(t *testing.T) {
+	m := sessionsModel{width: 100, height: 12}
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("agent-%02d", i)
+		m.lines = append(m.lines, tmux.SessionLine{Name: name, Line: name + ": 1 windows"})
+	}
+	m.selectedIndex = 15
+
+	view := ansi.Strip(m.View())
+	if lines := strings.Split(view, "\n"); len(lines) > m.height {
+		t.Fatalf("expected at most %d lines, got %d", m.height, len(lines))
+	}
+	if !strings.Contains(view, "> ") || !strings.Contains(view, "agent-15") {
+		t.Fatalf("expected the selected session in view, got:\n%s", view)
+	}
+	if !strings.Contains(view, "▲") || !strings.Contains(view, "▼") {
+		t.Fatalf("expected indicators for hidden sessions, got:\n%s", view)
+	}
+}
+
+// clickRow returns the screen position of the view line containing text.
+func clickRow(t *testing.T, m sessionsModel, text string) (int, int) {
+	t.Helper()
+	for y, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+		if x := strings.Index(line, text); x >= 0 {
+			return lipgloss.Width(line[:x]), y
+		}
+	}
+	t.Fatalf("%q not in view", text)
+	return 0, 0
+}
+
+func TestSessionsClickSelectsRowWhileScrolled(t *testing.T) {
+	m := sessionsModel{width: 100, height: 12}
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("agent-%02d", i)
+		m.lines = append(m.lines, tmux.SessionLine{Name: name, Line: name + ": 1 windows"})
+	}
+	m.selectedIndex = 15
+
+	x, y := clickRow(t, m, "agent-16")
+	updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if got := updated.(sessionsModel).attachSession; got != "agent-16" {
+		t.Fatalf("expected the clicked session, got %q", got)
+	}
+}
+
+func TestSessionsClickOnBeadsLabelOpensTracker(t *testing.T) {
+	m := sessionsModel{width: 100, height: 12, showBeads: true, beadsCommand: "bd list"}
+	m.lines = []tmux.SessionLine{{Name: "app", Line: "app: 1 windows"}}
+	m.beadsCounts = map[string]*beadsTally{"app": {open: 3}}
+
+	x, y := clickRow(t, m, "bd:3")
+	updated, cmd := m.Update(tea.MouseMsg{X: x + 1, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if updated.(sessionsModel).attachSession != "" || cmd == nil {
+		t.Fatal("expected a beads label click to open the tracker before attaching")
+	}
+
+	updated, _ = updated.Update(beadsOpenedMsg{sessionName: "app"})
+	if got := updated.(sessionsModel).attachSession; got != "app" {
+		t.Fatalf("expected to attach once the tracker opened, got %q", got)
+	}
+
+	// Elsewhere on the row attaches straight away
+	x, y = clickRow(t, m, "app:")
+	updated, _ = m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if got := updated.(sessionsModel).attachSession; got != "app" {
+		t.Fatalf("expected a row click to attach, got %q", got)
+	}
+}
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/porganisciak/agent-tmux/config"
 	"github.com/porganisciak/agent-tmux/tmux"
 )
@@ -145,3 +147,33 @@ func TestStalenessConfigInvalidDuration(t *testing.T) {
 		t.Errorf("invalid stale = %v, want 48h (default)", stale)
 	}
 }
+
+func TestActiveSessionRowSymbolIndicators(t *testing.T) {
+	m := sessionsModel{
+		freshThreshold:   24 * time.Hour,
+		staleThreshold:   48 * time.Hour,
+		symbolIndicators: true,
+		selectedIndex:    -1,
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		activity int64
+		want     string
+	}{
+		{"fresh", now.Unix(), " 1."},
+		{"getting stale", now.Add(-30 * time.Hour).Unix(), "~1."},
+		{"stale", now.Add(-72 * time.Hour).Unix(), "!1."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := tmux.SessionLine{Name: "agent-x", Line: "agent-x: 1 windows", Activity: tt.activity}
+			rendered, _ := m.renderActiveSessionRow(0, line, 1)
+			row := ansi.Strip(rendered)
+			if !strings.HasPrefix(row, "  "+tt.want) {
+				t.Fatalf("expected row to start with %q, got %q", "  "+tt.want, row)
+			}
+		})
+	}
+}
@@ -0,0 +1,938 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/config"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// settingKind is how a setting is edited.
+type settingKind int
+
+const (
+	settingToggle settingKind = iota // Space/Enter flips it
+	settingChoice                    // Space/Enter and ←/→ cycle through choices
+	settingText                      // Enter opens an input; validated on save
+	settingAction                    // Space/Enter runs it; nothing is stored
+)
+
+// settingField is one editable option in the settings screen.
+type settingField struct {
+	group       string
+	label       string
+	kind        settingKind
+	choices     []string // settingChoice values, in cycle order
+	placeholder string   // settingText hint shown when empty (the default)
+	get         func(s *config.Settings) string
+	set         func(s *config.Settings, value string) error
+	run         func(s *config.Settings) (string, error) // settingAction only; returns a status
+}
+
+// Getters read nested settings through these value copies so that rendering
+// never adds empty sections to settings.json.
+func stalenessOf(s *config.Settings) config.StalenessConfig {
+	if s.Staleness == nil {
+		return config.StalenessConfig{}
+	}
+	return *s.Staleness
+}
+
+func treeCacheOf(s *config.Settings) config.TreeCacheConfig {
+	if s.TreeCache == nil {
+		return config.TreeCacheConfig{}
+	}
+	return *s.TreeCache
+}
+
+func sshMultiplexOf(s *config.Settings) config.SSHMultiplexConfig {
+	if s.SSHMultiplex == nil {
+		return config.SSHMultiplexConfig{}
+	}
+	return *s.SSHMultiplex
+}
+
+func historyRetentionOf(s *config.Settings) config.HistoryRetentionConfig {
+	if s.HistoryRetention == nil {
+		return config.HistoryRetentionConfig{}
+	}
+	return *s.HistoryRetention
+}
+
+func keybindOf(s *config.Settings) config.KeybindConfig {
+	if s.Keybind == nil {
+		return config.KeybindConfig{}
+	}
+	return *s.Keybind
+}
+
+// staleness returns s.Staleness, creating it when unset.
+func staleness(s *config.Settings) *config.StalenessConfig {
+	if s.Staleness == nil {
+		s.Staleness = &config.StalenessConfig{}
+	}
+	return s.Staleness
+}
+
+// treeCache returns s.TreeCache, creating it when unset.
+func treeCache(s *config.Settings) *config.TreeCacheConfig {
+	if s.TreeCache == nil {
+		s.TreeCache = &config.TreeCacheConfig{}
+	}
+	return s.TreeCache
+}
+
+// sshMultiplex returns s.SSHMultiplex, creating it when unset.
+func sshMultiplex(s *config.Settings) *config.SSHMultiplexConfig {
+	if s.SSHMultiplex == nil {
+		s.SSHMultiplex = &config.SSHMultiplexConfig{}
+	}
+	return s.SSHMultiplex
+}
+
+// historyRetention returns s.HistoryRetention, creating it when unset.
+func historyRetention(s *config.Settings) *config.HistoryRetentionConfig {
+	if s.HistoryRetention == nil {
+		s.HistoryRetention = &config.HistoryRetentionConfig{}
+	}
+	return s.HistoryRetention
+}
+
+// keybind returns s.Keybind, creating it when unset.
+func keybind(s *config.Settings) *config.KeybindConfig {
+	if s.Keybind == nil {
+		s.Keybind = &config.KeybindConfig{}
+	}
+	return s.Keybind
+}
+
+// agentPrefOf returns the saved (or default) preferences for the agent
+// called name, without adding agents to settings.json.
+func agentPrefOf(s *config.Settings, name string) config.AgentPreference {
+	for _, p := range s.EffectiveAgents() {
+		if p.Name == name {
+			return p
+		}
+	}
+	return config.AgentPreference{Name: name}
+}
+
+// agentPref returns the agent called name in s.Agents, saving the defaults
+// first so that changing one agent keeps the others.
+func agentPref(s *config.Settings, name string) *config.AgentPreference {
+	if len(s.Agents) == 0 {
+		s.Agents = s.EffectiveAgents()
+	}
+	for i := range s.Agents {
+		if s.Agents[i].Name == name {
+			return &s.Agents[i]
+		}
+	}
+	s.Agents = append(s.Agents, agentPrefOf(s, name))
+	return &s.Agents[len(s.Agents)-1]
+}
+
+// agentFields returns the settings for one known agent: whether new
+// sessions start it, its auto-approve flag (when it has one), and extra
+// flags.
+func agentFields(agent config.AgentSpec) []settingField {
+	name := agent.Name
+	fields := []settingField{{
+		group: "Agents", label: "Start " + name + " in new sessions", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(agentPrefOf(s, name).Enabled) },
+		set: func(s *config.Settings, v string) error { agentPref(s, name).Enabled = v == "true"; return nil },
+	}}
+	if flag := agent.YoloFlag; flag != "" {
+		fields = append(fields, settingField{
+			group: "Agents", label: name + " auto-approve (" + flag + ")", kind: settingToggle,
+			get: func(s *config.Settings) string { return boolString(agentPrefOf(s, name).Yolo) },
+			set: func(s *config.Settings, v string) error { agentPref(s, name).Yolo = v == "true"; return nil },
+		})
+	}
+	return append(fields, settingField{
+		group: "Agents", label: name + " extra flags", kind: settingText, placeholder: "none (e.g. --model opus)",
+		get: func(s *config.Settings) string { return agentPrefOf(s, name).Flags },
+		set: func(s *config.Settings, v string) error { agentPref(s, name).Flags = strings.TrimSpace(v); return nil },
+	})
+}
+
+// boolString formats a toggle value.
+func boolString(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+// parseDurationSetting validates a duration setting. Empty means the default.
+func parseDurationSetting(value string, allowZero bool) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a duration (e.g. 30s, 5m, 24h)", value)
+	}
+	if d < 0 || (d == 0 && !allowZero) {
+		return fmt.Errorf("duration must be positive")
+	}
+	return nil
+}
+
+// parseIntSetting validates a whole-number setting of at least min. Empty
+// means the default (0).
+func parseIntSetting(value string, min int) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number", value)
+	}
+	if n < min {
+		return 0, fmt.Errorf("must be at least %d", min)
+	}
+	return n, nil
+}
+
+// intString formats a whole-number setting, leaving 0 (the default) empty.
+func intString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// settingFieldsFor lists the settings screen options in display order. The
+// settings for each known agent follow the startup ones.
+func settingFieldsFor(s *config.Settings) []settingField {
+	return slices.Concat(startupFields, agentSettingFields(s.KnownAgents()), otherFields)
+}
+
+// agentSettingFields returns the settings for each agent in agents.
+func agentSettingFields(agents []config.AgentSpec) []settingField {
+	var fields []settingField
+	for _, a := range agents {
+		fields = append(fields, agentFields(a)...)
+	}
+	return fields
+}
+
+var startupFields = []settingField{
+	{
+		group: "Startup", label: "Running atmux with no command", kind: settingChoice,
+		choices: []string{"landing", "resume", "sessions"},
+		get:     func(s *config.Settings) string { return s.DefaultAction },
+		set:     func(s *config.Settings, v string) error { s.DefaultAction = v; return nil },
+	},
+	{
+		group: "Startup", label: "Session name template", kind: settingText, placeholder: config.DefaultSessionNameTemplate,
+		get: func(s *config.Settings) string { return s.SessionNameTemplate },
+		set: func(s *config.Settings, v string) error {
+			if err := config.ValidateSessionNameTemplate(v); err != nil {
+				return err
+			}
+			s.SessionNameTemplate = v
+			return nil
+		},
+	},
+	{
+		group: "Startup", label: "Return to last focused pane on resume", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(s.RestoreFocus) },
+		set: func(s *config.Settings, v string) error { s.RestoreFocus = v == "true"; return nil },
+	},
+}
+
+var otherFields = []settingField{
+	{
+		group: "Sessions list", label: "Sort order", kind: settingChoice,
+		choices: []string{"activity", "name", "created", "windows", "attached"},
+		get: func(s *config.Settings) string {
+			if s.SessionsSort == "" {
+				return string(config.SessionSortActivity)
+			}
+			return string(s.SessionsSort)
+		},
+		set: func(s *config.Settings, v string) error { s.SessionsSort = config.SessionSort(v); return nil },
+	},
+	{
+		group: "Sessions list", label: "Show beads issue counts", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(!s.HideBeads) },
+		set: func(s *config.Settings, v string) error { s.HideBeads = v != "true"; return nil },
+	},
+	{
+		group: "Sessions list", label: "Beads label click runs", kind: settingText, placeholder: "bd list",
+		get: func(s *config.Settings) string { return s.BeadsCommand },
+		set: func(s *config.Settings, v string) error { s.BeadsCommand = v; return nil },
+	},
+	{
+		group: "Sessions list", label: "Kill without confirmation", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(s.SkipKillConfirm) },
+		set: func(s *config.Settings, v string) error { s.SkipKillConfirm = v == "true"; return nil },
+	},
+	{
+		group: "Staleness", label: "Show staleness indicators", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(!stalenessOf(s).Disabled) },
+		set: func(s *config.Settings, v string) error { staleness(s).Disabled = v != "true"; return nil },
+	},
+	{
+		group: "Staleness", label: "Fresh for", kind: settingText, placeholder: "24h",
+		get: func(s *config.Settings) string { return stalenessOf(s).FreshDuration },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, false); err != nil {
+				return err
+			}
+			staleness(s).FreshDuration = v
+			return nil
+		},
+	},
+	{
+		group: "Staleness", label: "Stale after", kind: settingText, placeholder: "48h",
+		get: func(s *config.Settings) string { return stalenessOf(s).StaleDuration },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, false); err != nil {
+				return err
+			}
+			staleness(s).StaleDuration = v
+			return nil
+		},
+	},
+	{
+		group: "Staleness", label: "Suggest kill-stale at (sessions)", kind: settingText, placeholder: "7",
+		get: func(s *config.Settings) string { return intString(stalenessOf(s).SuggestionThreshold) },
+		set: func(s *config.Settings, v string) error {
+			n, err := parseIntSetting(v, 1)
+			if err != nil {
+				return err
+			}
+			staleness(s).SuggestionThreshold = n
+			return nil
+		},
+	},
+	{
+		group: "Browse", label: "Refresh interval (0s = off)", kind: settingText, placeholder: "2s",
+		get: func(s *config.Settings) string { return s.RefreshInterval },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, true); err != nil {
+				return err
+			}
+			s.RefreshInterval = v
+			return nil
+		},
+	},
+	{
+		group: "Browse", label: "Preview refresh interval (0s = with tree)", kind: settingText, placeholder: "0s",
+		get: func(s *config.Settings) string { return s.PreviewRefreshInterval },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, true); err != nil {
+				return err
+			}
+			s.PreviewRefreshInterval = v
+			return nil
+		},
+	},
+	{
+		group: "Browse", label: "Mobile layout below width", kind: settingText, placeholder: "60",
+		get: func(s *config.Settings) string { return intString(s.MobileWidth) },
+		set: func(s *config.Settings, v string) error {
+			n, err := parseIntSetting(v, 20)
+			if err != nil {
+				return err
+			}
+			s.MobileWidth = n
+			return nil
+		},
+	},
+	{
+		group: "Browse", label: "Tree width (% of window)", kind: settingText, placeholder: "35",
+		get: func(s *config.Settings) string { return intString(s.TreeWidthPercent) },
+		set: func(s *config.Settings, v string) error {
+			n, err := parseIntSetting(v, 10)
+			if err != nil {
+				return err
+			}
+			if n > 90 {
+				return fmt.Errorf("must be at most 90")
+			}
+			s.TreeWidthPercent = n
+			return nil
+		},
+	},
+	{
+		group: "Browse", label: "Layout", kind: settingChoice,
+		choices: []string{"auto", "side", "stacked"},
+		get: func(s *config.Settings) string {
+			if s.BrowseLayout == "" {
+				return string(config.BrowseLayoutAuto)
+			}
+			return string(s.BrowseLayout)
+		},
+		set: func(s *config.Settings, v string) error { s.BrowseLayout = config.BrowseLayout(v); return nil },
+	},
+	{
+		group: "Browse", label: "Send to shell panes without asking", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(s.SkipShellConfirm) },
+		set: func(s *config.Settings, v string) error { s.SkipShellConfirm = v == "true"; return nil },
+	},
+	{
+		group: "Browse", label: "Clock in status bar", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(s.StatusClock) },
+		set: func(s *config.Settings, v string) error { s.StatusClock = v == "true"; return nil },
+	},
+	{
+		group: "Browse", label: "Session counts in status bar", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(s.StatusCounts) },
+		set: func(s *config.Settings, v string) error { s.StatusCounts = v == "true"; return nil },
+	},
+	{
+		group: "Browse", label: "Command input after sending", kind: settingChoice,
+		choices: []string{"keep", "clear", "clear-on-success"},
+		get: func(s *config.Settings) string {
+			if s.AfterSend == "" {
+				return string(config.AfterSendKeep)
+			}
+			return string(s.AfterSend)
+		},
+		set: func(s *config.Settings, v string) error { s.AfterSend = config.AfterSend(v); return nil },
+	},
+	{
+		group: "Browse", label: "Prefix sent commands with", kind: settingText,
+		get: func(s *config.Settings) string { return s.CommandPrefix },
+		set: func(s *config.Settings, v string) error { s.CommandPrefix = v; return nil },
+	},
+	{
+		group: "Browse", label: "Suffix sent commands with", kind: settingText,
+		get: func(s *config.Settings) string { return s.CommandSuffix },
+		set: func(s *config.Settings, v string) error { s.CommandSuffix = v; return nil },
+	},
+	{
+		group: "Browse", label: "Watched pane is idle after", kind: settingText, placeholder: "30s",
+		get: func(s *config.Settings) string { return s.WatchIdleAfter },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, false); err != nil {
+				return err
+			}
+			s.WatchIdleAfter = v
+			return nil
+		},
+	},
+	{
+		group: "Browse", label: "Desktop notification when idle", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(s.WatchNotify) },
+		set: func(s *config.Settings, v string) error { s.WatchNotify = v == "true"; return nil },
+	},
+	{
+		group: "Browse", label: "New windows start in", kind: settingChoice,
+		choices: []string{"session", "pane"},
+		get: func(s *config.Settings) string {
+			if s.NewWindowDir == "" {
+				return string(config.NewWindowDirSession)
+			}
+			return string(s.NewWindowDir)
+		},
+		set: func(s *config.Settings, v string) error { s.NewWindowDir = config.NewWindowDir(v); return nil },
+	},
+	{
+		group: "Remote hosts", label: "Attach strategy", kind: settingChoice,
+		choices: []string{"auto", "replace", "new-window"},
+		get: func(s *config.Settings) string {
+			if s.RemoteAttachStrategy == "" {
+				return string(config.AttachStrategyAuto)
+			}
+			return string(s.RemoteAttachStrategy)
+		},
+		set: func(s *config.Settings, v string) error {
+			s.RemoteAttachStrategy = config.AttachStrategy(v)
+			return nil
+		},
+	},
+	{
+		group: "Remote hosts", label: "Give up on a slow host after", kind: settingText, placeholder: "10s",
+		get: func(s *config.Settings) string { return s.RemoteTimeout },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, false); err != nil {
+				return err
+			}
+			s.RemoteTimeout = v
+			return nil
+		},
+	},
+	{
+		group: "Remote hosts", label: "Hosts fetched at once", kind: settingText, placeholder: "4",
+		get: func(s *config.Settings) string { return intString(s.RemoteConcurrency) },
+		set: func(s *config.Settings, v string) error {
+			n, err := parseIntSetting(v, 1)
+			if err != nil {
+				return err
+			}
+			s.RemoteConcurrency = n
+			return nil
+		},
+	},
+	{
+		group: "Remote hosts", label: "Share one SSH connection per host", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(!sshMultiplexOf(s).Disabled) },
+		set: func(s *config.Settings, v string) error { sshMultiplex(s).Disabled = v != "true"; return nil },
+	},
+	{
+		group: "Remote hosts", label: "Keep idle SSH connection for", kind: settingText, placeholder: "5m",
+		get: func(s *config.Settings) string { return sshMultiplexOf(s).Persist },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, false); err != nil {
+				return err
+			}
+			sshMultiplex(s).Persist = v
+			return nil
+		},
+	},
+	{
+		group: "Remote hosts", label: "Show cached trees while connecting", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(!treeCacheOf(s).Disabled) },
+		set: func(s *config.Settings, v string) error { treeCache(s).Disabled = v != "true"; return nil },
+	},
+	{
+		group: "Remote hosts", label: "Cached tree stale after", kind: settingText, placeholder: "5m",
+		get: func(s *config.Settings) string { return treeCacheOf(s).TTL },
+		set: func(s *config.Settings, v string) error {
+			if err := parseDurationSetting(v, false); err != nil {
+				return err
+			}
+			treeCache(s).TTL = v
+			return nil
+		},
+	},
+	{
+		group: "History", label: "Prune old history", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(!historyRetentionOf(s).Disabled) },
+		set: func(s *config.Settings, v string) error { historyRetention(s).Disabled = v != "true"; return nil },
+	},
+	{
+		group: "History", label: "Remove entries unused for", kind: settingText, placeholder: "never (e.g. 90d)",
+		get: func(s *config.Settings) string { return historyRetentionOf(s).MaxAge },
+		set: func(s *config.Settings, v string) error {
+			if v != "" && (&config.HistoryRetentionConfig{MaxAge: v}).ParsedMaxAge() == 0 {
+				return fmt.Errorf("%q is not a positive age (e.g. 90d or 720h)", v)
+			}
+			historyRetention(s).MaxAge = v
+			return nil
+		},
+	},
+	{
+		group: "History", label: "Keep at most (entries)", kind: settingText, placeholder: "100",
+		get: func(s *config.Settings) string { return intString(historyRetentionOf(s).MaxEntries) },
+		set: func(s *config.Settings, v string) error {
+			n, err := parseIntSetting(v, 1)
+			if err != nil {
+				return err
+			}
+			historyRetention(s).MaxEntries = n
+			return nil
+		},
+	},
+	{
+		group: "History", label: "Database location", kind: settingText, placeholder: "default (e.g. ~/sync/atmux.sqlite3)",
+		get: func(s *config.Settings) string { return s.HistoryDB },
+		set: func(s *config.Settings, v string) error { s.HistoryDB = v; return nil },
+	},
+	{
+		group: "tmux keybinding", label: "Key (after prefix)", kind: settingText, placeholder: "S",
+		get: func(s *config.Settings) string { return keybindOf(s).Key },
+		set: func(s *config.Settings, v string) error {
+			if v != "" {
+				if _, err := tmux.KeybindLine(v, "browse"); err != nil {
+					return err
+				}
+			}
+			keybind(s).Key = v
+			return nil
+		},
+	},
+	{
+		group: "tmux keybinding", label: "Opens", kind: settingChoice,
+		choices: tmux.KeybindTargets,
+		get:     func(s *config.Settings) string { return s.KeybindTarget() },
+		set:     func(s *config.Settings, v string) error { keybind(s).Target = v; return nil },
+	},
+	{
+		group: "tmux keybinding", label: "Add to ~/.tmux.conf", kind: settingAction,
+		run: installKeybinding,
+	},
+	{
+		group: "tmux keybinding", label: "Remove atmux keybindings from ~/.tmux.conf", kind: settingAction,
+		run: removeKeybindings,
+	},
+	{
+		group: "Appearance", label: "Theme", kind: settingChoice,
+		choices: []string{"dark", "light", "high-contrast"},
+		get: func(s *config.Settings) string {
+			if s.Theme == nil || s.Theme.Name == "" {
+				return DefaultThemeName
+			}
+			return s.Theme.Name
+		},
+		set: func(s *config.Settings, v string) error {
+			if s.Theme == nil {
+				s.Theme = &config.ThemeConfig{}
+			}
+			s.Theme.Name = v
+			return nil
+		},
+	},
+	{
+		group: "Appearance", label: "Accessible mode (symbols, not just color)", kind: settingToggle,
+		get: func(s *config.Settings) string { return boolString(s.AccessibleMode) },
+		set: func(s *config.Settings, v string) error { s.AccessibleMode = v == "true"; return nil },
+	},
+}
+
+// installKeybinding adds the configured keybinding to the managed block in
+// ~/.tmux.conf, refusing keys already bound outside it.
+func installKeybinding(s *config.Settings) (string, error) {
+	path, err := tmux.TmuxConfPath()
+	if err != nil {
+		return "", err
+	}
+	key := s.KeybindKey()
+	line, err := tmux.KeybindLine(key, s.KeybindTarget())
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("could not read %s: %w", path, err)
+	}
+	if found, existing := tmux.FindUnmanagedKeybinding(string(content), key); found {
+		if existing == line {
+			return "Already bound in " + path + ": " + line, nil
+		}
+		return "", fmt.Errorf("prefix + %s is already bound in %s (%s); choose another key or remove that line", key, path, existing)
+	}
+	if err := tmux.UpdateTmuxConf(path, func(c string) string { return tmux.AddManagedKeybinding(c, line) }); err != nil {
+		return "", err
+	}
+	return "Added " + line + " (run tmux source-file ~/.tmux.conf to load it)", nil
+}
+
+// removeKeybindings deletes the managed keybinding block from ~/.tmux.conf.
+func removeKeybindings(*config.Settings) (string, error) {
+	path, err := tmux.TmuxConfPath()
+	if err != nil {
+		return "", err
+	}
+	removed := false
+	err = tmux.UpdateTmuxConf(path, func(c string) string {
+		updated, found := tmux.RemoveManagedKeybindings(c)
+		removed = found
+		return updated
+	})
+	if err != nil {
+		return "", err
+	}
+	if !removed {
+		return "No atmux keybindings in " + path, nil
+	}
+	return "Removed atmux keybindings from " + path + " (reload tmux to unbind)", nil
+}
+
+// settingsActionMsg reports the result of running a settingAction.
+type settingsActionMsg struct {
+	status string
+	err    error
+}
+
+// runAction runs the selected action field in the background.
+func (m settingsModel) runAction() tea.Cmd {
+	field := m.fields[m.selected]
+	settings := cloneSettings(m.settings)
+	return func() tea.Msg {
+		status, err := field.run(settings)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", field.label, err)
+		}
+		return settingsActionMsg{status: status, err: err}
+	}
+}
+
+// validateSettings checks constraints between settings.
+func validateSettings(s *config.Settings) error {
+	if s.Staleness != nil {
+		fresh, stale := s.Staleness.ParsedStalenessThresholds()
+		if fresh >= stale {
+			return fmt.Errorf("stale after (%s) must be longer than fresh for (%s)", stale, fresh)
+		}
+	}
+	return nil
+}
+
+// cloneSettings deep-copies settings so a change can be validated before it
+// replaces the current values.
+func cloneSettings(s *config.Settings) *config.Settings {
+	clone := &config.Settings{}
+	if data, err := json.Marshal(s); err == nil {
+		if json.Unmarshal(data, clone) == nil {
+			return clone
+		}
+	}
+	*clone = *s
+	return clone
+}
+
+// settingsSavedMsg is returned after writing settings.json.
+type settingsSavedMsg struct {
+	err error
+}
+
+// saveSettings writes a snapshot of s to settings.json.
+func saveSettings(s *config.Settings) tea.Cmd {
+	return func() tea.Msg {
+		if err := s.Save(); err != nil {
+			return settingsSavedMsg{err: fmt.Errorf("failed to save settings: %w", err)}
+		}
+		return settingsSavedMsg{}
+	}
+}
+
+// SettingsOptions configures the settings TUI.
+type SettingsOptions struct {
+	AltScreen bool
+}
+
+// RunSettings runs the settings screen. Changes are saved as they are made.
+func RunSettings(opts SettingsOptions) error {
+	loadTheme()
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	programOptions := []tea.ProgramOption{tea.WithMouseCellMotion()}
+	if opts.AltScreen {
+		programOptions = append(programOptions, tea.WithAltScreen())
+	}
+	_, err = tea.NewProgram(newSettingsModel(settings), programOptions...).Run()
+	return err
+}
+
+// settingsModel is the Bubble Tea model for the settings screen.
+type settingsModel struct {
+	settings *config.Settings
+	fields   []settingField // Options shown, including one set per known agent
+	selected int
+	editing  bool // Text input open for the selected setting
+	input    textinput.Model
+	status   string // Result of the last change
+	err      error
+	width    int
+	height   int
+}
+
+func newSettingsModel(settings *config.Settings) settingsModel {
+	return settingsModel{settings: settings, fields: settingFieldsFor(settings)}
+}
+
+func (m settingsModel) Init() tea.Cmd {
+	return nil
+}
+
+// apply sets the selected field to value, validates the result, and saves.
+func (m settingsModel) apply(value string) (settingsModel, tea.Cmd) {
+	field := m.fields[m.selected]
+	updated := cloneSettings(m.settings)
+	if err := field.set(updated, strings.TrimSpace(value)); err != nil {
+		m.err = fmt.Errorf("%s: %w", field.label, err)
+		return m, nil
+	}
+	if err := validateSettings(updated); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.settings = updated
+	m.err = nil
+	m.status = ""
+	return m, saveSettings(cloneSettings(updated))
+}
+
+// cycle moves the selected toggle or choice field by delta.
+func (m settingsModel) cycle(delta int) (settingsModel, tea.Cmd) {
+	field := m.fields[m.selected]
+	current := field.get(m.settings)
+	switch field.kind {
+	case settingToggle:
+		return m.apply(boolString(current != "true"))
+	case settingChoice:
+		idx := 0
+		for i, choice := range field.choices {
+			if choice == current {
+				idx = i
+			}
+		}
+		idx = (idx + delta + len(field.choices)) % len(field.choices)
+		return m.apply(field.choices[idx])
+	}
+	return m, nil
+}
+
+func (m settingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case settingsSavedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.status = "Saved"
+		}
+		return m, nil
+	case settingsActionMsg:
+		m.err = msg.err
+		m.status = msg.status
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.handleEditKeys(msg)
+		}
+		field := m.fields[m.selected]
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+		case "down", "j":
+			if m.selected < len(m.fields)-1 {
+				m.selected++
+			}
+		case "left", "h":
+			return m.cycle(-1)
+		case "right", "l":
+			return m.cycle(1)
+		case "enter", " ":
+			if field.kind == settingAction {
+				m.err = nil
+				m.status = ""
+				return m, m.runAction()
+			}
+			if field.kind == settingText {
+				m.editing = true
+				m.err = nil
+				m.input = textinput.New()
+				m.input.Placeholder = field.placeholder
+				m.input.CharLimit = 32
+				m.input.Width = 24
+				m.input.SetValue(field.get(m.settings))
+				m.input.CursorEnd()
+				m.input.Focus()
+				return m, textinput.Blink
+			}
+			return m.cycle(1)
+		case "backspace", "delete":
+			// Reset a text setting to its default
+			if field.kind == settingText {
+				return m.apply("")
+			}
+		}
+	}
+	return m, nil
+}
+
+// handleEditKeys handles keys while a text setting is being edited.
+func (m settingsModel) handleEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		updated, cmd := m.apply(m.input.Value())
+		if updated.err == nil {
+			updated.editing = false
+		}
+		return updated, cmd
+	case "esc", "ctrl+c":
+		m.editing = false
+		m.err = nil
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m settingsModel) View() string {
+	dim := lipgloss.NewStyle().Foreground(dimColor)
+	sectionHeader := lipgloss.NewStyle().Bold(true).Foreground(secondaryColor)
+
+	header := []string{
+		lipgloss.NewStyle().Bold(true).Render("Settings"),
+		dim.Render("↑↓ select, Space/Enter change, ←→ cycle, Backspace reset to default, q quit"),
+	}
+
+	var sections []string
+	selectedLine := 0
+	group := ""
+	for i, field := range m.fields {
+		if field.group != group {
+			group = field.group
+			sections = append(sections, "", sectionHeader.Render(group))
+		}
+
+		var value string
+		if field.get != nil {
+			value = field.get(m.settings)
+		}
+		var row string
+		switch field.kind {
+		case settingToggle:
+			checkbox := "[ ]"
+			if value == "true" {
+				checkbox = "[x]"
+			}
+			row = checkbox + " " + field.label
+		case settingChoice:
+			row = field.label + ": " + value
+		case settingText:
+			if i == m.selected && m.editing {
+				row = field.label + ": " + m.input.View()
+			} else if value == "" {
+				row = field.label + ": " + dim.Render(field.placeholder+" (default)")
+			} else {
+				row = field.label + ": " + value
+			}
+		case settingAction:
+			row = "[" + field.label + "]"
+		}
+
+		if i == m.selected {
+			selectedLine = len(sections)
+			sections = append(sections, selectedStyle.Render("> ")+row)
+		} else {
+			sections = append(sections, "  "+row)
+		}
+	}
+
+	footer := []string{""}
+	if m.err != nil {
+		footer = append(footer, lipgloss.NewStyle().Foreground(errorColor).Render("Error: "+m.err.Error()))
+	} else if m.status != "" {
+		footer = append(footer, dim.Render(m.status))
+	}
+
+	// Scroll the list so the selected setting stays on screen
+	if m.height > 0 {
+		if visible := m.height - len(header) - len(footer); visible > 0 && len(sections) > visible {
+			offset := max(0, selectedLine-visible+1)
+			sections = sections[offset : offset+visible]
+		}
+	}
+
+	lines := append(append(header, sections...), footer...)
+	result := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	if m.height > 0 {
+		return truncateToHeight(result, m.height)
+	}
+	return result
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// settingIndex returns the index of the setting with label.
+func settingIndex(t *testing.T, label string) int {
+	t.Helper()
+	for i, field := range settingFieldsFor(config.DefaultSettings()) {
+		if field.label == label {
+			return i
+		}
+	}
+	t.Fatalf("no setting %q", label)
+	return -1
+}
+
+// typeSetting opens the selected text setting, replaces its value, and
+// presses Enter, running any save command.
+func typeSetting(m settingsModel, value string) settingsModel {
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(settingsModel)
+	m.input.SetValue(value)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(settingsModel)
+	if cmd != nil {
+		updated, _ = m.Update(cmd())
+		m = updated.(settingsModel)
+	}
+	return m
+}
+
+func TestSettingsScreenValidatesAndSaves(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := newSettingsModel(config.DefaultSettings())
+
+	// A toggle saves right away
+	m.selected = settingIndex(t, "Kill without confirmation")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	m = updated.(settingsModel)
+	if cmd == nil {
+		t.Fatal("expected the toggle to save")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(settingsModel)
+	if m.err != nil || m.status != "Saved" {
+		t.Fatalf("expected a successful save, got %v", m.err)
+	}
+
+	// Invalid numbers and durations are rejected and the input stays open
+	m.selected = settingIndex(t, "Suggest kill-stale at (sessions)")
+	m = typeSetting(m, "zero")
+	if m.err == nil || !m.editing {
+		t.Fatal("expected a non-number to be rejected")
+	}
+	m.editing = false
+	m.selected = settingIndex(t, "Fresh for")
+	m = typeSetting(m, "72h")
+	if m.err == nil {
+		t.Fatal("expected fresh for to have to stay below stale after (48h)")
+	}
+	m.editing = false
+	m = typeSetting(m, "12h")
+	if m.err != nil || m.editing {
+		t.Fatalf("expected 12h to be accepted, got %v", m.err)
+	}
+
+	saved, err := config.LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.SkipKillConfirm || saved.Staleness == nil || saved.Staleness.FreshDuration != "12h" {
+		t.Fatalf("expected the changes in settings.json, got %+v", saved)
+	}
+	if saved.TreeCache != nil || saved.HistoryRetention != nil {
+		t.Fatal("expected untouched sections to stay out of settings.json")
+	}
+	if saved.DefaultAction != "landing" {
+		t.Fatalf("expected the default action to be kept, got %q", saved.DefaultAction)
+	}
+}
+
+func TestSettingsKeybindingActions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	confPath := filepath.Join(home, ".tmux.conf")
+	if err := os.WriteFile(confPath, []byte("bind-key S choose-tree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(m settingsModel, label string) settingsModel {
+		m.selected = settingIndex(t, label)
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		updated, _ = updated.Update(cmd())
+		return updated.(settingsModel)
+	}
+
+	// The default key clashes with a hand-written binding
+	m := run(newSettingsModel(config.DefaultSettings()), "Add to ~/.tmux.conf")
+	if m.err == nil {
+		t.Fatal("expected prefix + S to be reported as taken")
+	}
+
+	m.selected = settingIndex(t, "Key (after prefix)")
+	m = typeSetting(m, "T")
+	m = run(m, "Add to ~/.tmux.conf")
+	if m.err != nil {
+		t.Fatalf("expected the binding to be added, got %v", m.err)
+	}
+	content, _ := os.ReadFile(confPath)
+	if !strings.Contains(string(content), `bind-key T run-shell "atmux browse"`) {
+		t.Fatalf("binding missing from tmux.conf:\n%s", content)
+	}
+
+	m = run(m, "Remove atmux keybindings from ~/.tmux.conf")
+	content, _ = os.ReadFile(confPath)
+	if m.err != nil || string(content) != "bind-key S choose-tree\n" {
+		t.Fatalf("expected only the hand-written binding to remain, got %v:\n%s", m.err, content)
+	}
+}
+
+func TestSettingsScreenEditsAgentDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := newSettingsModel(config.DefaultSettings())
+
+	m.selected = settingIndex(t, "Claude extra flags")
+	m = typeSetting(m, "--model opus")
+	if m.err != nil {
+		t.Fatalf("unexpected error: %v", m.err)
+	}
+	m.selected = settingIndex(t, "Codex auto-approve (--yolo)")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	updated.(settingsModel).Update(cmd())
+
+	saved, err := config.LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, agent := range saved.AgentCommands() {
+		got = append(got, agent.Command)
+	}
+	want := []string{"claude --dangerously-skip-permissions --model opus", "codex"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected agents %q, got %q", want, got)
+	}
+}
+
+func TestSettingsScreenListsCustomAgents(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	settings := config.DefaultSettings()
+	settings.CustomAgents = []config.AgentSpec{{Name: "Aider", Command: "aider", YoloFlag: "--yes-always"}}
+	m := newSettingsModel(settings)
+
+	for i, field := range m.fields {
+		if field.label == "Start Aider in new sessions" {
+			m.selected = i
+		}
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	updated.(settingsModel).Update(cmd())
+
+	if view := m.View(); !strings.Contains(view, "Aider auto-approve (--yes-always)") {
+		t.Fatalf("expected settings for the custom agent, got:\n%s", view)
+	}
+	saved, err := config.LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, agent := range saved.AgentCommands() {
+		got = append(got, agent.Command)
+	}
+	want := []string{"claude --dangerously-skip-permissions", "codex --yolo", "aider"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected agents %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// shellCommands are foreground commands that mean a pane is sitting at a
+// plain shell prompt rather than running an agent.
+var shellCommands = map[string]bool{
+	"sh":   true,
+	"bash": true,
+	"zsh":  true,
+	"fish": true,
+	"dash": true,
+	"ksh":  true,
+	"tcsh": true,
+	"csh":  true,
+	"nu":   true,
+}
+
+// isShellCommand reports whether a pane's foreground command is a bare shell.
+// Login shells are reported with a leading dash (e.g. "-zsh").
+func isShellCommand(command string) bool {
+	return shellCommands[strings.TrimPrefix(filepath.Base(command), "-")]
+}
+
+// shellSendRequest is a pending send to a shell pane awaiting confirmation.
+type shellSendRequest struct {
+	node    *tmux.TreeNode
+	command string
+	method  tmux.SendMethod
+}
+
+// requestSend sends command to a pane, asking first when the pane is running
+// a plain shell (unless disabled with skip_shell_confirm).
+func (m Model) requestSend(node *tmux.TreeNode, command string) (tea.Model, tea.Cmd) {
+	return m.requestSendWithMethod(node, command, m.sendMethod)
+}
+
+// requestSendWithMethod is requestSend using the given send method.
+func (m Model) requestSendWithMethod(node *tmux.TreeNode, command string, method tmux.SendMethod) (tea.Model, tea.Cmd) {
+	m.pushInputHistory(command)
+	if !m.options.SkipShellConfirm && isShellCommand(node.Command) {
+		m.pendingShellSend = &shellSendRequest{node: node, command: command, method: method}
+		return m, nil
+	}
+	return m, m.sendCommandForNodeWithMethod(node, command, method)
+}
+
+// handleShellSendConfirmKeys handles keys while confirming a send to a shell pane.
+func (m Model) handleShellSendConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		req := m.pendingShellSend
+		m.pendingShellSend = nil
+		return m, m.sendCommandForNodeWithMethod(req.node, req.command, req.method)
+	case "n", "N", "esc":
+		m.pendingShellSend = nil
+		m.clearOnSent = "" // Nothing was sent, so nothing to clear
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderShellSendConfirmOverlay renders the send-to-shell confirmation.
+func (m Model) renderShellSendConfirmOverlay(base string) string {
+	req := m.pendingShellSend
+	title := helpTitleStyle.Render("Send to Shell?")
+
+	command := commandSummary(req.command)
+	if len(command) > 30 {
+		command = command[:27] + "..."
+	}
+	message := lipgloss.NewStyle().
+		Foreground(textColor).
+		Bold(true).
+		Render(fmt.Sprintf("%s is running %s, not an agent. Send '%s' anyway?", req.node.Target, req.node.Command, command))
+
+	hint := lipgloss.NewStyle().
+		Foreground(dimColor).
+		Render("Press [y] to send, [n] or [Esc] to cancel")
+
+	content := strings.Join([]string{title, "", message, "", hint}, "\n")
+	box := helpOverlayStyle.Width(50).Render(content)
+
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
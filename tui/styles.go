@@ -5,259 +5,391 @@ import "github.com/charmbracelet/lipgloss"
 import "strings"
 
 var (
+	// Active color theme, set by applyTheme
+	theme Theme
+
 	// Colors
-	primaryColor   = lipgloss.Color("39")  // Cyan
-	secondaryColor = lipgloss.Color("170") // Magenta
-	activeColor    = lipgloss.Color("82")  // Green
-	dimColor       = lipgloss.Color("240") // Gray
-	errorColor     = lipgloss.Color("196") // Red
-	buttonColor    = lipgloss.Color("33")  // Blue
+	primaryColor     lipgloss.Color
+	secondaryColor   lipgloss.Color
+	activeColor      lipgloss.Color
+	dimColor         lipgloss.Color
+	errorColor       lipgloss.Color
+	buttonColor      lipgloss.Color
+	buttonTextColor  lipgloss.Color
+	textColor        lipgloss.Color
+	selectionBgColor lipgloss.Color
 
 	// Staleness colors
-	freshColor        = lipgloss.Color("82")  // green (same as activeColor)
-	gettingStaleColor = lipgloss.Color("220") // yellow/amber
-	staleColor        = lipgloss.Color("196") // red (same as errorColor)
+	freshColor        lipgloss.Color
+	gettingStaleColor lipgloss.Color
+	staleColor        lipgloss.Color
 
 	// Dimmed prefix style for agent-/atmux- session names
-	agentPrefixStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("243")) // Slightly dimmed
+	agentPrefixStyle lipgloss.Style
 
 	// Remote host styles
-	remoteHostColor = lipgloss.Color("214") // Orange
+	remoteHostColor      lipgloss.Color
+	remoteHostStyle      lipgloss.Style
+	remoteIndicatorStyle lipgloss.Style
+
+	// Border styles
+	borderStyle       lipgloss.Style
+	activeBorderStyle lipgloss.Style
+
+	// Tree styles
+	sessionStyle         lipgloss.Style
+	sessionAttachedStyle lipgloss.Style
+	windowStyle          lipgloss.Style
+	windowActiveStyle    lipgloss.Style
+	paneStyle            lipgloss.Style
+	paneActiveStyle      lipgloss.Style
+	selectedStyle        lipgloss.Style
+
+	// Button styles
+	sendButtonStyle      lipgloss.Style
+	sendButtonHoverStyle lipgloss.Style
+	escapeButtonStyle    lipgloss.Style
+	interruptButtonStyle lipgloss.Style
+	attachButtonStyle    lipgloss.Style
+	helpButtonStyle      lipgloss.Style
+
+	// Help overlay styles
+	helpOverlayStyle lipgloss.Style
+	helpTitleStyle   lipgloss.Style
+	helpSectionStyle lipgloss.Style
+	helpKeyStyle     lipgloss.Style
+	helpDescStyle    lipgloss.Style
+
+	// Input styles
+	inputStyle        lipgloss.Style
+	inputFocusedStyle lipgloss.Style
+
+	// Preview styles
+	previewStyle lipgloss.Style
+
+	// Status bar styles
+	statusBarStyle      lipgloss.Style
+	statusSelectedStyle lipgloss.Style
+
+	// Scheduler-specific styles
+	schedTitleStyle        lipgloss.Style
+	schedAddBtnStyle       lipgloss.Style
+	schedStatusActiveStyle lipgloss.Style
+	schedStatusDimStyle    lipgloss.Style
+	schedIDStyle           lipgloss.Style
+	schedTargetStyle       lipgloss.Style
+	schedHintStyle         lipgloss.Style
+	schedSeparatorStyle    lipgloss.Style
+	schedConfirmStyle      lipgloss.Style
+
+	// Wizard styles
+	wizSubtitleStyle        lipgloss.Style
+	wizBoxStyle             lipgloss.Style
+	wizInputStyle           lipgloss.Style
+	wizCronFieldFocusStyle  lipgloss.Style
+	wizCronHeaderFocusStyle lipgloss.Style
+	wizCronRangeStyle       lipgloss.Style
+	wizCronRangeFocusStyle  lipgloss.Style
+	wizPreviewOKStyle       lipgloss.Style
+	wizPreviewErrStyle      lipgloss.Style
+	wizRefStyle             lipgloss.Style
+	wizLabelStyle           lipgloss.Style
+	wizValueStyle           lipgloss.Style
+	wizSaveBtnStyle         lipgloss.Style
+	wizCancelBtnStyle       lipgloss.Style
+	wizSaveBtnActiveStyle   lipgloss.Style
+	wizCancelBtnActiveStyle lipgloss.Style
+	wizSaveBtnInactiveStyle lipgloss.Style
+	wizSeparatorStyle       lipgloss.Style
+
+	// Beads count style (amber for non-zero counts)
+	beadsCountStyle lipgloss.Style
+)
+
+var (
+	// Expand/collapse indicators
+	expandedIcon   = "[-]"
+	collapsedIcon  = "[+]"
+	paneIcon       = " > "
+	paneActiveIcon = "[*]"
+
+	// Layout constants
+	treeWidthPercent    = 35
+	previewWidthPercent = 65
+	minTreeWidth        = 30
+	minPreviewWidth     = 40
+	treeWidthStep       = 2 // Columns < and > move the divider
+	treeHeightStep      = 1 // Rows < and > move the stacked divider
+	stackedTreePercent  = 40
+	minStackedTree      = 3 // Tree rows when stacked above the preview
+	minStackedPreview   = 5
+	inputHeight         = 3
+	statusHeight        = 1
+
+	// Mobile layout constants
+	mobileWidthThreshold = 60 // Auto-switch to mobile if width < this
+	mobileButtonHeight   = 3  // Height for touch-friendly button bar
+
+	// Wizard styles
+	wizCronFieldStyle = lipgloss.NewStyle().Width(10).Align(lipgloss.Center)
+
+	wizCronHeaderStyle = lipgloss.NewStyle().Width(10).Align(lipgloss.Center)
+)
+
+// Styles start out in the default theme until an entry point loads the
+// configured one.
+func init() {
+	applyTheme(builtinThemes[DefaultThemeName])
+}
+
+// applyTheme makes t the active theme, rebuilding every style drawn in
+// its colors.
+func applyTheme(t Theme) {
+	theme = t
+
+	// Colors
+	primaryColor = theme.Primary
+	secondaryColor = theme.Secondary
+	activeColor = theme.Active
+	dimColor = theme.Dim
+	errorColor = theme.Error
+	buttonColor = theme.Button
+	buttonTextColor = theme.ButtonText
+	textColor = theme.Text
+	selectionBgColor = theme.SelectionBg
+
+	// Staleness colors
+	freshColor = theme.Fresh
+	gettingStaleColor = theme.GettingStale
+	staleColor = theme.Stale
+
+	agentPrefixStyle = lipgloss.NewStyle().Foreground(dimColor)
+
+	// Remote host styles
+	remoteHostColor = theme.RemoteHost
 	remoteHostStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(remoteHostColor)
+		Bold(true).
+		Foreground(remoteHostColor)
 
 	remoteIndicatorStyle = lipgloss.NewStyle().
-				Foreground(remoteHostColor)
+		Foreground(remoteHostColor)
 
 	// Border styles
 	borderStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(dimColor)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(dimColor)
 
 	activeBorderStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(primaryColor)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor)
 
 	// Tree styles
 	sessionStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor)
+		Bold(true).
+		Foreground(primaryColor)
 
 	sessionAttachedStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(activeColor)
+		Bold(true).
+		Foreground(activeColor)
 
 	windowStyle = lipgloss.NewStyle().
-			Foreground(secondaryColor)
+		Foreground(secondaryColor)
 
 	windowActiveStyle = lipgloss.NewStyle().
-				Foreground(activeColor)
+		Foreground(activeColor)
 
 	paneStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252"))
+		Foreground(textColor)
 
 	paneActiveStyle = lipgloss.NewStyle().
-			Foreground(activeColor)
+		Foreground(activeColor)
 
 	selectedStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("236")).
-			Bold(true)
+		Background(selectionBgColor).
+		Bold(true)
 
 	// Button styles
 	sendButtonStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("255")).
-			Background(buttonColor).
-			Padding(0, 1)
+		Foreground(buttonTextColor).
+		Background(buttonColor).
+		Padding(0, 1)
 
 	sendButtonHoverStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")).
-				Background(activeColor).
-				Padding(0, 1)
+		Foreground(buttonTextColor).
+		Background(activeColor).
+		Padding(0, 1)
 
 	escapeButtonStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")).
-				Background(errorColor).
-				Padding(0, 1)
+		Foreground(buttonTextColor).
+		Background(errorColor).
+		Padding(0, 1)
+
+	interruptButtonStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(gettingStaleColor).
+		Padding(0, 1)
 
 	attachButtonStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")).
-				Background(activeColor).
-				Padding(0, 1)
+		Foreground(buttonTextColor).
+		Background(activeColor).
+		Padding(0, 1)
 
 	helpButtonStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("255")).
-			Background(lipgloss.Color("99")). // Purple
-			Padding(0, 1)
+		Foreground(buttonTextColor).
+		Background(secondaryColor).
+		Padding(0, 1)
 
 	// Help overlay styles
 	helpOverlayStyle = lipgloss.NewStyle().
-				Border(lipgloss.DoubleBorder()).
-				BorderForeground(primaryColor).
-				Padding(1, 2)
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2)
 
 	helpTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor)
+		Bold(true).
+		Foreground(primaryColor)
 
 	helpSectionStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(secondaryColor)
+		Bold(true).
+		Foreground(secondaryColor)
 
 	helpKeyStyle = lipgloss.NewStyle().
-			Foreground(activeColor).
-			Bold(true)
+		Foreground(activeColor).
+		Bold(true)
 
 	helpDescStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252"))
+		Foreground(textColor)
 
 	// Input styles
 	inputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(dimColor).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(dimColor).
+		Padding(0, 1)
 
 	inputFocusedStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(primaryColor).
-				Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 1)
 
 	// Preview styles
 	previewStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("250"))
+		Foreground(textColor)
 
 	// Status bar styles
 	statusBarStyle = lipgloss.NewStyle().
-			Foreground(dimColor).
-			Padding(0, 1)
+		Foreground(dimColor).
+		Padding(0, 1)
 
 	statusSelectedStyle = lipgloss.NewStyle().
-				Foreground(primaryColor).
-				Bold(true)
-
-	// Expand/collapse indicators
-	expandedIcon   = "[-]"
-	collapsedIcon  = "[+]"
-	paneIcon       = " > "
-	paneActiveIcon = "[*]"
-
-	// Layout constants
-	treeWidthPercent    = 35
-	previewWidthPercent = 65
-	minTreeWidth        = 30
-	minPreviewWidth     = 40
-	inputHeight         = 3
-	statusHeight        = 1
-
-	// Mobile layout constants
-	mobileWidthThreshold = 60 // Auto-switch to mobile if width < this
-	mobileButtonHeight   = 3  // Height for touch-friendly button bar
+		Foreground(primaryColor).
+		Bold(true)
 
 	// Scheduler-specific styles
 	schedTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor)
+		Bold(true).
+		Foreground(primaryColor)
 
 	schedAddBtnStyle = lipgloss.NewStyle().
-				Background(activeColor).
-				Foreground(lipgloss.Color("255")).
-				Padding(0, 1)
+		Background(activeColor).
+		Foreground(buttonTextColor).
+		Padding(0, 1)
 
 	schedStatusActiveStyle = lipgloss.NewStyle().Foreground(activeColor)
-	schedStatusDimStyle    = lipgloss.NewStyle().Foreground(dimColor)
+	schedStatusDimStyle = lipgloss.NewStyle().Foreground(dimColor)
 
-	schedIDStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	schedIDStyle = lipgloss.NewStyle().Foreground(activeColor)
 
 	schedTargetStyle = lipgloss.NewStyle().Foreground(dimColor)
 
 	schedHintStyle = lipgloss.NewStyle().Foreground(dimColor)
 
-	schedSeparatorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	schedSeparatorStyle = lipgloss.NewStyle().Foreground(dimColor)
 
 	schedConfirmStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("196")).
-				Foreground(lipgloss.Color("255")).
-				Padding(0, 1)
+		Background(errorColor).
+		Foreground(buttonTextColor).
+		Padding(0, 1)
 
 	// Wizard styles
 	wizSubtitleStyle = lipgloss.NewStyle().Foreground(dimColor)
 
 	wizBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2)
 
 	wizInputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(0, 1).
-			Width(40)
-
-	wizCronFieldStyle = lipgloss.NewStyle().Width(10).Align(lipgloss.Center)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 1).
+		Width(40)
 
 	wizCronFieldFocusStyle = lipgloss.NewStyle().
-				Width(10).
-				Align(lipgloss.Center).
-				Bold(true).
-				Background(lipgloss.Color("236")).
-				Foreground(lipgloss.Color("255"))
-
-	wizCronHeaderStyle = lipgloss.NewStyle().Width(10).Align(lipgloss.Center)
+		Width(10).
+		Align(lipgloss.Center).
+		Bold(true).
+		Background(selectionBgColor).
+		Foreground(textColor)
 
 	wizCronHeaderFocusStyle = lipgloss.NewStyle().
-				Width(10).
-				Align(lipgloss.Center).
-				Bold(true).
-				Foreground(primaryColor)
+		Width(10).
+		Align(lipgloss.Center).
+		Bold(true).
+		Foreground(primaryColor)
 
 	wizCronRangeStyle = lipgloss.NewStyle().
-				Width(10).
-				Align(lipgloss.Center).
-				Foreground(dimColor)
+		Width(10).
+		Align(lipgloss.Center).
+		Foreground(dimColor)
 
 	wizCronRangeFocusStyle = lipgloss.NewStyle().
-				Width(10).
-				Align(lipgloss.Center).
-				Foreground(primaryColor)
+		Width(10).
+		Align(lipgloss.Center).
+		Foreground(primaryColor)
 
-	wizPreviewOKStyle  = lipgloss.NewStyle().Foreground(activeColor)
+	wizPreviewOKStyle = lipgloss.NewStyle().Foreground(activeColor)
 	wizPreviewErrStyle = lipgloss.NewStyle().Foreground(errorColor)
-	wizRefStyle        = lipgloss.NewStyle().Foreground(dimColor)
-	wizLabelStyle      = lipgloss.NewStyle().Foreground(dimColor)
-	wizValueStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
+	wizRefStyle = lipgloss.NewStyle().Foreground(dimColor)
+	wizLabelStyle = lipgloss.NewStyle().Foreground(dimColor)
+	wizValueStyle = lipgloss.NewStyle().Foreground(textColor)
 
 	wizSaveBtnStyle = lipgloss.NewStyle().
-			Background(activeColor).
-			Foreground(lipgloss.Color("255")).
-			Padding(0, 1)
+		Background(activeColor).
+		Foreground(buttonTextColor).
+		Padding(0, 1)
 
 	wizCancelBtnStyle = lipgloss.NewStyle().
-				Background(dimColor).
-				Foreground(lipgloss.Color("255")).
-				Padding(0, 1)
+		Background(dimColor).
+		Foreground(buttonTextColor).
+		Padding(0, 1)
 
 	wizSaveBtnActiveStyle = lipgloss.NewStyle().
-				Background(activeColor).
-				Foreground(lipgloss.Color("255")).
-				Padding(0, 1).
-				Bold(true)
+		Background(activeColor).
+		Foreground(buttonTextColor).
+		Padding(0, 1).
+		Bold(true)
 
 	wizCancelBtnActiveStyle = lipgloss.NewStyle().
-				Background(errorColor).
-				Foreground(lipgloss.Color("255")).
-				Padding(0, 1).
-				Bold(true)
+		Background(errorColor).
+		Foreground(buttonTextColor).
+		Padding(0, 1).
+		Bold(true)
 
 	wizSaveBtnInactiveStyle = lipgloss.NewStyle().
-				Background(dimColor).
-				Foreground(lipgloss.Color("255")).
-				Padding(0, 1)
+		Background(dimColor).
+		Foreground(buttonTextColor).
+		Padding(0, 1)
 
 	wizSeparatorStyle = lipgloss.NewStyle().Foreground(dimColor)
 
-	// Beads count style (yellow/orange for non-zero counts)
-	beadsCountStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
-)
+	beadsCountStyle = lipgloss.NewStyle().Foreground(gettingStaleColor)
+
+	buildMobileStyles()
+	buildMenuStyles()
+	buildExpandFooterStyles()
+	buildFormStyles()
+	buildTipStyles()
+}
 
 // Helper to get tree node style based on type and state
 func getNodeStyle(nodeType string, active, selected bool) lipgloss.Style {
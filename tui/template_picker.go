@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultTemplateLabel is the picker entry for the regular (non-template) layout.
+const defaultTemplateLabel = "Default layout"
+
+// templatePicker asks which session template to use before creating a session.
+type templatePicker struct {
+	names    []string // Template names; index 0 is the default layout ("")
+	selected int
+}
+
+// newTemplatePicker returns a picker for names, or nil when there are no
+// templates to choose from.
+func newTemplatePicker(names []string) *templatePicker {
+	if len(names) == 0 {
+		return nil
+	}
+	return &templatePicker{names: append([]string{""}, names...)}
+}
+
+// handleKey updates the picker for key. It reports done when a template was
+// chosen (see choice) and cancelled when the picker should close.
+func (p *templatePicker) handleKey(key string) (done, cancelled bool) {
+	switch key {
+	case "up", "k", "shift+tab":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j", "tab":
+		if p.selected < len(p.names)-1 {
+			p.selected++
+		}
+	case "enter", " ":
+		return true, false
+	case "esc", "q", "ctrl+c":
+		return false, true
+	}
+	return false, false
+}
+
+// choice returns the selected template name ("" for the default layout).
+func (p *templatePicker) choice() string {
+	return p.names[p.selected]
+}
+
+// render draws the picker centered over base.
+func (p *templatePicker) render(base string, width, height int) string {
+	rows := []string{helpTitleStyle.Render("Choose a Template"), ""}
+	for i, name := range p.names {
+		label := name
+		if name == "" {
+			label = defaultTemplateLabel
+		}
+		if i == p.selected {
+			rows = append(rows, selectedStyle.Render("> "+label))
+		} else {
+			rows = append(rows, "  "+label)
+		}
+	}
+	rows = append(rows, "", lipgloss.NewStyle().Foreground(dimColor).Render("[Enter] create  [↑/↓] select  [Esc] cancel"))
+
+	box := helpOverlayStyle.Width(44).Render(strings.Join(rows, "\n"))
+	x := (width - lipgloss.Width(box)) / 2
+	y := (height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+// Theme maps named color roles to lipgloss colors.
+type Theme struct {
+	Primary      lipgloss.Color
+	Secondary    lipgloss.Color
+	Active       lipgloss.Color
+	Dim          lipgloss.Color
+	Error        lipgloss.Color
+	Button       lipgloss.Color
+	Fresh        lipgloss.Color
+	GettingStale lipgloss.Color
+	Stale        lipgloss.Color
+	RemoteHost   lipgloss.Color
+	Text         lipgloss.Color
+	SelectionBg  lipgloss.Color
+	ButtonText   lipgloss.Color // Text on button and banner backgrounds
+}
+
+// DefaultThemeName is the theme used when none (or an unknown one) is configured.
+const DefaultThemeName = "dark"
+
+// builtinThemes are the themes selectable by name in settings.
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Primary:      "39",  // Cyan
+		Secondary:    "170", // Magenta
+		Active:       "82",  // Green
+		Dim:          "240", // Gray
+		Error:        "196", // Red
+		Button:       "33",  // Blue
+		Fresh:        "82",  // Green
+		GettingStale: "220", // Yellow/amber
+		Stale:        "196", // Red
+		RemoteHost:   "214", // Orange
+		Text:         "252",
+		SelectionBg:  "236",
+		ButtonText:   "255",
+	},
+	"light": {
+		Primary:      "25",  // Dark blue
+		Secondary:    "127", // Dark magenta
+		Active:       "28",  // Dark green
+		Dim:          "244", // Mid gray
+		Error:        "160", // Dark red
+		Button:       "25",
+		Fresh:        "28",
+		GettingStale: "136", // Dark yellow
+		Stale:        "160",
+		RemoteHost:   "166", // Dark orange
+		Text:         "235",
+		SelectionBg:  "254",
+		ButtonText:   "255",
+	},
+	"high-contrast": {
+		Primary:      "51",  // Bright cyan
+		Secondary:    "213", // Bright pink
+		Active:       "46",  // Bright green
+		Dim:          "250", // Light gray (readable on black)
+		Error:        "196",
+		Button:       "21", // Bright blue
+		Fresh:        "46",
+		GettingStale: "226", // Bright yellow
+		Stale:        "196",
+		RemoteHost:   "208",
+		Text:         "231", // White
+		SelectionBg:  "19",  // Dark blue
+		ButtonText:   "231",
+	},
+}
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether s is an ANSI color index (0-255) or a hex color.
+func validColor(s string) bool {
+	if hexColorPattern.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// resolveTheme builds the theme for cfg: the named built-in theme (default
+// "dark") with any valid per-role overrides applied. Unknown theme names, role
+// names, and color values are ignored.
+func resolveTheme(cfg *config.ThemeConfig) Theme {
+	theme := builtinThemes[DefaultThemeName]
+	if cfg == nil {
+		return theme
+	}
+	if t, ok := builtinThemes[cfg.Name]; ok {
+		theme = t
+	}
+
+	roles := map[string]*lipgloss.Color{
+		"primary":       &theme.Primary,
+		"secondary":     &theme.Secondary,
+		"active":        &theme.Active,
+		"dim":           &theme.Dim,
+		"error":         &theme.Error,
+		"button":        &theme.Button,
+		"fresh":         &theme.Fresh,
+		"getting_stale": &theme.GettingStale,
+		"stale":         &theme.Stale,
+		"remote_host":   &theme.RemoteHost,
+		"text":          &theme.Text,
+		"selection_bg":  &theme.SelectionBg,
+		"button_text":   &theme.ButtonText,
+	}
+	for role, value := range cfg.Colors {
+		if dst, ok := roles[role]; ok && validColor(value) {
+			*dst = lipgloss.Color(value)
+		}
+	}
+	return theme
+}
+
+// loadTheme makes the theme from user settings the active one. Each Run
+// entry point calls it before building its model, so settings are read when
+// a screen opens rather than whenever the package is imported.
+func loadTheme() {
+	var cfg *config.ThemeConfig
+	if settings, err := config.LoadSettings(); err == nil {
+		cfg = settings.Theme
+	}
+	applyTheme(resolveTheme(cfg))
+}
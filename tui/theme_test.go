@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/config"
+)
+
+func TestResolveThemeDefaultsToDark(t *testing.T) {
+	if got := resolveTheme(nil); got != builtinThemes["dark"] {
+		t.Fatalf("expected dark theme, got %+v", got)
+	}
+	if got := resolveTheme(&config.ThemeConfig{Name: "solarized"}); got != builtinThemes["dark"] {
+		t.Fatalf("expected unknown theme to fall back to dark, got %+v", got)
+	}
+}
+
+func TestResolveThemeOverrides(t *testing.T) {
+	got := resolveTheme(&config.ThemeConfig{
+		Name: "light",
+		Colors: map[string]string{
+			"primary": "#00afff",
+			"stale":   "not-a-color",
+			"dim":     "300",
+			"bogus":   "1",
+		},
+	})
+
+	want := builtinThemes["light"]
+	want.Primary = lipgloss.Color("#00afff")
+	if got != want {
+		t.Fatalf("expected light theme with primary override only\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestLoadThemeRestylesFromSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { applyTheme(builtinThemes[DefaultThemeName]) })
+
+	settings := config.DefaultSettings()
+	settings.Theme = &config.ThemeConfig{Name: "light"}
+	if err := settings.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loadTheme()
+	light := builtinThemes["light"]
+	if primaryColor != light.Primary {
+		t.Fatalf("expected light primary color, got %q", primaryColor)
+	}
+	if got := sessionStyle.GetForeground(); got != light.Primary {
+		t.Fatalf("expected session style rebuilt in light colors, got %v", got)
+	}
+	if got := menuBorderStyle.GetBorderTopForeground(); got != light.Primary {
+		t.Fatalf("expected menu style rebuilt in light colors, got %v", got)
+	}
+
+	// Styles that used to hardcode colors follow the theme too
+	if got := wizValueStyle.GetForeground(); got != light.Text {
+		t.Fatalf("expected wizard values in the light text color, got %v", got)
+	}
+	if got := sendButtonStyle.GetForeground(); got != light.ButtonText {
+		t.Fatalf("expected button text from the theme, got %v", got)
+	}
+	if got := tipStyle.GetForeground(); got != light.Dim {
+		t.Fatalf("expected tips in the light dim color, got %v", got)
+	}
+}
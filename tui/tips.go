@@ -29,7 +29,7 @@ type Tip struct {
 var tips = []Tip{
 	{Text: "Double-click to attach to a session", Contexts: []TipContext{TipSessions, TipLanding}},
 	{Text: "Press ? for full keyboard shortcuts", Contexts: nil},
-	{Text: "Use / to focus the command input", Contexts: []TipContext{TipBrowse}},
+	{Text: "Use / to filter the tree, Tab to reach the command input", Contexts: []TipContext{TipBrowse}},
 	{Text: "Press Enter to attach to selected session", Contexts: []TipContext{TipSessions, TipLanding}},
 	{Text: "Tab cycles between sections", Contexts: nil},
 	{Text: "Press r to refresh the session list", Contexts: nil},
@@ -38,6 +38,7 @@ var tips = []Tip{
 	{Text: "Use --remote to include remote hosts in sessions", Contexts: []TipContext{TipSessions}},
 	{Text: "Space expands or collapses tree nodes", Contexts: []TipContext{TipBrowse}},
 	{Text: "Press M to toggle mouse support", Contexts: []TipContext{TipBrowse}},
+	{Text: "Press Ctrl+P to find and run any browse action", Contexts: []TipContext{TipBrowse}},
 	{Text: "Use `atmux browse --remote=devbox` to include remote panes", Contexts: []TipContext{TipBrowse}},
 	{Text: "Run `atmux onboard` for a quick setup guide", Contexts: nil},
 	{Text: "Use Esc to exit input mode or quit", Contexts: nil},
@@ -50,6 +51,7 @@ var tips = []Tip{
 	{Text: "Press x or Delete to remove a history entry", Contexts: []TipContext{TipRecents}},
 	{Text: "Remote recents are marked with @host", Contexts: []TipContext{TipRecents}},
 	{Text: "Up/Down arrows recall previous commands in input", Contexts: []TipContext{TipBrowse}},
+	{Text: "Press f to search pane contents across every host", Contexts: []TipContext{TipBrowse}},
 	{Text: "Use `atmux sessions -p` for a popup session picker", Contexts: nil},
 	{Text: "Run `atmux keybind --command sessions` for quick popup access", Contexts: nil},
 	{Text: "Run `atmux recents` to quickly reopen recent projects", Contexts: nil},
@@ -64,13 +66,22 @@ var tips = []Tip{
 	{Text: "Ctrl+C twice to quit from any view", Contexts: nil},
 }
 
-// tipStyle defines the subtle appearance for tips
-var tipStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("238"))
+var (
+	// tipStyle defines the subtle appearance for tips
+	tipStyle lipgloss.Style
 
-// tipLabelStyle is slightly brighter than the tip text for the "Tip:" prefix
-var tipLabelStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("243"))
+	// tipLabelStyle is slightly brighter than the tip text for the "Tip:" prefix
+	tipLabelStyle lipgloss.Style
+)
+
+// buildTipStyles builds the tip styles from the theme colors.
+func buildTipStyles() {
+	tipStyle = lipgloss.NewStyle().
+		Foreground(dimColor).
+		Faint(true)
+	tipLabelStyle = lipgloss.NewStyle().
+		Foreground(dimColor)
+}
 
 // GetRandomTip returns a random tip string (from all tips)
 func GetRandomTip() string {
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// newTreeFilterInput creates the (unfocused) input for the tree filter.
+func newTreeFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "filter tree"
+	ti.CharLimit = 64
+	ti.Width = 24
+	return ti
+}
+
+// treeFilterQuery returns the normalized filter query, or "" when the tree
+// is unfiltered.
+func (m *Model) treeFilterQuery() string {
+	return strings.ToLower(strings.TrimSpace(m.treeFilter.Value()))
+}
+
+// filterTreeNodes keeps the sessions, windows, and panes whose name or target
+// contains query, along with their ancestors so the hierarchy still reads
+// correctly. It also returns how many nodes matched (ancestors excluded).
+func filterTreeNodes(nodes []*tmux.TreeNode, query string) ([]*tmux.TreeNode, int) {
+	return filterNodes(nodes, func(node *tmux.TreeNode) bool {
+		return treeNodeMatches(node, query)
+	})
+}
+
+// filterNodes keeps the nodes match accepts and their ancestors, returning
+// how many matched.
+func filterNodes(nodes []*tmux.TreeNode, match func(*tmux.TreeNode) bool) ([]*tmux.TreeNode, int) {
+	var filtered, ancestors []*tmux.TreeNode
+	shown := make(map[*tmux.TreeNode]bool)
+	matches := 0
+	for _, node := range nodes {
+		for len(ancestors) > 0 && ancestors[len(ancestors)-1].Level >= node.Level {
+			ancestors = ancestors[:len(ancestors)-1]
+		}
+		if match(node) {
+			for _, a := range ancestors {
+				if !shown[a] {
+					shown[a] = true
+					filtered = append(filtered, a)
+				}
+			}
+			shown[node] = true
+			filtered = append(filtered, node)
+			matches++
+		}
+		ancestors = append(ancestors, node)
+	}
+	return filtered, matches
+}
+
+// treeNodeMatches reports whether node matches a lowercased filter query.
+// Host headers and placeholder rows (no target) only appear as ancestors.
+func treeNodeMatches(node *tmux.TreeNode, query string) bool {
+	if node.Type == "host" || node.Target == "" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(node.Name), query) ||
+		strings.Contains(strings.ToLower(node.Target), query)
+}
+
+// openTreeFilter focuses the tree filter input.
+func (m *Model) openTreeFilter() tea.Cmd {
+	m.focused = FocusTree
+	m.focusRecent = false
+	m.commandInput.Blur()
+	m.filteringTree = true
+	m.treeFilter.Focus()
+	return textinput.Blink
+}
+
+// clearTreeFilter removes the filter, restoring the tree's own expansion
+// state and keeping the selected node selected where possible.
+func (m *Model) clearTreeFilter() {
+	m.filteringTree = false
+	m.treeFilter.Blur()
+	m.treeFilter.SetValue("")
+	m.treeFilterMatches = 0
+	m.refilterTree(false)
+}
+
+// refilterTree rebuilds the tree for the current filter. With firstMatch set
+// the selection moves to the first matching node, otherwise it follows the
+// previously selected node, or its closest visible ancestor when collapsed.
+func (m *Model) refilterTree(firstMatch bool) {
+	prev := m.selectedNode()
+	m.rebuildFlatNodes()
+
+	index := -1
+	if query := m.treeFilterQuery(); firstMatch && query != "" {
+		for i, node := range m.flatNodes {
+			if treeNodeMatches(node, query) {
+				index = i
+				break
+			}
+		}
+	} else if prev != nil {
+		index = m.closestNodeIndex(prev)
+	}
+	if index == -1 {
+		index = m.selectedIndex
+	}
+	m.jumpSelection(index)
+	m.calculateButtonZones()
+}
+
+// closestNodeIndex returns the index of node in the tree, falling back to its
+// window and then its session, or -1 when none of them are shown.
+func (m *Model) closestNodeIndex(node *tmux.TreeNode) int {
+	targets := []string{node.Target}
+	if idx := strings.LastIndex(node.Target, "."); idx != -1 && node.Type == "pane" {
+		targets = append(targets, node.Target[:idx])
+	}
+	targets = append(targets, sessionFromTarget(node.Target))
+	for _, target := range targets {
+		for i, candidate := range m.flatNodes {
+			if candidate.Type != "host" && candidate.Target == target && candidate.Host == node.Host {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// handleTreeFilterKeys handles keys while the tree filter input has focus.
+func (m Model) handleTreeFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.clearTreeFilter()
+		return m, m.updatePreviewForSelection()
+	case "enter":
+		// Keep the filter applied and return to tree navigation
+		m.filteringTree = false
+		m.treeFilter.Blur()
+		if m.treeFilterQuery() == "" {
+			m.clearTreeFilter()
+		}
+		return m, m.updatePreviewForSelection()
+	case "up", "down":
+		delta := 1
+		if msg.String() == "up" {
+			delta = -1
+		}
+		m.jumpSelection(m.selectedIndex + delta)
+		m.calculateButtonZones()
+		return m, m.updatePreviewForSelection()
+	}
+
+	before := m.treeFilter.Value()
+	var cmd tea.Cmd
+	m.treeFilter, cmd = m.treeFilter.Update(msg)
+	if m.treeFilter.Value() != before {
+		m.refilterTree(true)
+		return m, tea.Batch(cmd, m.updatePreviewForSelection())
+	}
+	return m, cmd
+}
+
+// treeFilterStatus renders the filter input and match count for the status
+// bar, or "" when no filter is active.
+func (m Model) treeFilterStatus() string {
+	if !m.filteringTree && m.treeFilterQuery() == "" {
+		return ""
+	}
+	status := m.treeFilter.View()
+	if m.treeFilterQuery() != "" {
+		noun := "matches"
+		if m.treeFilterMatches == 1 {
+			noun = "match"
+		}
+		status += lipgloss.NewStyle().Foreground(dimColor).Render(fmt.Sprintf(" %d %s", m.treeFilterMatches, noun))
+	}
+	return status
+}
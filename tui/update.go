@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/config"
 	"github.com/porganisciak/agent-tmux/tmux"
 )
 
@@ -33,11 +36,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		// Auto-detect mobile mode based on terminal width (unless forced via --mobile)
 		if !m.mobileForcedMode {
-			m.mobileMode = shouldUseMobileLayout(m.width, false)
+			m.mobileMode = shouldUseMobileLayout(m.width, m.options.MobileWidth, false)
 		}
 		m.calculateLayout()
 		m.calculateButtonZones()
 		m.commandInput.Width = m.width - 20
+		m.commandArea.SetWidth(m.width - 20)
 		return m, nil
 
 	case TreeRefreshedMsg:
@@ -52,46 +56,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.filterRecentSessions()
 
 			// Fetch preview for selected node
-			if node := m.selectedNode(); node != nil && node.Type == "pane" {
-				cmds = append(cmds, m.fetchPreviewForNode(node))
-			}
+			cmds = append(cmds, m.treePreviewCmd())
 		}
 		// Schedule next refresh
-		if m.options.RefreshInterval > 0 {
-			cmds = append(cmds, tickCmd(m.options.RefreshInterval))
-		}
+		cmds = append(cmds, m.armRefreshTick())
 		return m, tea.Batch(cmds...)
 
 	case MultiTreeRefreshedMsg:
-		m.hostTrees = msg.HostTrees
-		// Build a merged tree for filterRecentSessions compatibility
-		merged := &tmux.Tree{}
-		m.hostErrors = map[string]error{}
-		for _, ht := range msg.HostTrees {
-			if ht.Err != nil {
-				label := ht.Host
-				if label == "" {
-					label = "local"
-				}
-				m.hostErrors[label] = ht.Err
-				continue
+		if msg.Cached {
+			// Cached trees are only a placeholder until the first live fetch
+			if m.liveTree || len(msg.HostTrees) == 0 {
+				return m, nil
 			}
-			if ht.Tree != nil {
-				merged.Sessions = append(merged.Sessions, ht.Tree.Sessions...)
+			// Fill in hosts that haven't answered yet, keeping any that have
+			trees := m.displayHostTrees()
+			for _, ht := range msg.HostTrees {
+				trees = mergeHostTree(trees, ht)
 			}
+			m.applyHostTrees(trees)
+			return m, nil
 		}
-		m.tree = merged
-		m.rebuildFlatNodes()
-		m.calculateButtonZones()
+		m.liveTree = true
+		m.applyHostTrees(msg.HostTrees)
 		m.lastError = nil
-		m.filterRecentSessions()
 
-		if node := m.selectedNode(); node != nil && node.Type == "pane" {
-			cmds = append(cmds, m.fetchPreviewForNode(node))
-		}
-		if m.options.RefreshInterval > 0 {
-			cmds = append(cmds, tickCmd(m.options.RefreshInterval))
-		}
+		cmds = append(cmds, m.treePreviewCmd())
+		cmds = append(cmds, m.armRefreshTick())
 		return m, tea.Batch(cmds...)
 
 	case RecentSessionsMsg:
@@ -131,8 +121,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case HostTreeRefreshedMsg:
+		delete(m.treeFetching, msg.HostTree.Host)
+		cmds = append(cmds, m.trackHostFailure(msg.HostTree))
+		m.applyHostTrees(mergeHostTree(m.displayHostTrees(), msg.HostTree))
+		if len(m.treeFetching) > 0 {
+			// Show this host now; the round finishes when the rest answer
+			return m, tea.Batch(cmds...)
+		}
+		m.liveTree = true
+		m.lastError = nil
+
+		if !m.options.DisableTreeCache && m.treeCacheChanged() {
+			cmds = append(cmds, saveTreeCacheCmd(m.hostTrees))
+		}
+		cmds = append(cmds, m.treePreviewCmd())
+		cmds = append(cmds, m.armRefreshTick())
+		return m, tea.Batch(cmds...)
+
+	case hostRetryMsg:
+		return m, m.retryHost(msg)
+
 	case PreviewUpdatedMsg:
-		if msg.Err == nil && msg.Target == m.previewTarget {
+		m.previewFetching = false
+		// Hold the content still while a drag selection is over it
+		if msg.Err == nil && msg.Target == m.previewTarget && m.previewSelect == nil {
+			host := ""
+			if node := m.selectedNode(); node != nil {
+				host = node.Host
+			}
+			m.recordPreviewActivity(host, msg.Target, msg.Content)
 			m.previewContent = msg.Content
 			m.previewPort.SetContent(msg.Content)
 			m.previewPort.GotoBottom()
@@ -140,10 +158,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case CommandSentMsg:
+		clearOnSent := m.clearOnSent
+		m.clearOnSent = ""
 		if msg.Err != nil {
 			m.lastError = msg.Err
 		} else {
-			m.lastSent = msg.Command + " -> " + msg.Target
+			if clearOnSent != "" && m.commandInput.Value() == clearOnSent {
+				m.clearCommandInput()
+			}
+			if clearOnSent != "" && strings.TrimRight(m.commandArea.Value(), "\n") == clearOnSent {
+				m.commandArea.Reset()
+			}
+			m.lastSent = commandSummary(msg.Command) + " -> " + msg.Target
+			host := ""
+			if node := m.nodeForTarget(msg.Target); node != nil {
+				host = node.Host
+			}
+			m.recordSent(host, msg.Panes, msg.Command)
 			// Refresh preview after sending (route through executor if applicable)
 			if node := m.nodeForTarget(msg.Target); node != nil {
 				cmds = append(cmds, m.fetchPreviewForNode(node))
@@ -153,16 +184,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 
+	case nextRunMsg:
+		m.nextRun = msg.label
+		m.scheduledJobs = msg.jobs
+		return m, nil
+
 	case TickMsg:
+		m.tickArmed = false
+		m.clock = time.Now()
 		// Auto-refresh tree and recent sessions
 		cmds = append(cmds, m.fetchTreeCmd())
 		cmds = append(cmds, fetchRecentSessions)
+		cmds = append(cmds, loadNextRun)
 		// Also refresh preview if we have a selected pane
-		if node := m.selectedNode(); node != nil && node.Type == "pane" {
+		cmds = append(cmds, m.treePreviewCmd())
+		cmds = append(cmds, m.captureWatchedCmd())
+		return m, tea.Batch(cmds...)
+
+	case PreviewTickMsg:
+		// Re-capture the previewed pane, skipping while the last capture
+		// is still running
+		if node := m.selectedNode(); !m.previewFetching && node != nil && node.Type == "pane" && node.Target == m.previewTarget {
+			m.previewFetching = true
 			cmds = append(cmds, m.fetchPreviewForNode(node))
 		}
+		cmds = append(cmds, previewTickCmd(m.previewInterval()))
 		return m, tea.Batch(cmds...)
 
+	case watchCapturedMsg:
+		return m, m.handleWatchCaptured(msg)
+
+	case PaneSearchResultsMsg:
+		// Ignore stale results if the overlay was closed or the query changed
+		if m.search != nil && m.search.query == msg.Query {
+			m.search.searching = false
+			m.search.results = msg.Matches
+			m.search.selected = 0
+		}
+		return m, nil
+
+	case ClipboardCopiedMsg:
+		if msg.Err != nil {
+			m.lastError = msg.Err
+		} else {
+			m.lastError = nil
+			m.lastNotice = fmt.Sprintf("Copied %s (%s)", msg.What, msg.Method)
+		}
+		return m, nil
+
 	case KillCompletedMsg:
 		if msg.Err != nil {
 			m.lastError = msg.Err
@@ -171,13 +240,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(m.fetchTreeCmd(), fetchRecentSessions)
 		}
 		return m, nil
+
+	case BulkKillCompletedMsg:
+		// Refresh even on failure: some of the batch may have been killed
+		if msg.Err != nil {
+			m.lastError = msg.Err
+		}
+		return m, tea.Batch(m.fetchTreeCmd(), fetchRecentSessions)
 	}
 
 	// Update focused component
 	switch m.focused {
 	case FocusInput:
 		var cmd tea.Cmd
-		m.commandInput, cmd = m.commandInput.Update(msg)
+		if m.multiline {
+			m.commandArea, cmd = m.commandArea.Update(msg)
+		} else {
+			m.commandInput, cmd = m.commandInput.Update(msg)
+		}
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
@@ -192,6 +272,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// applyHostTrees stores per-host trees and rebuilds the merged tree and nodes.
+func (m *Model) applyHostTrees(hostTrees []tmux.HostTree) {
+	m.hostTrees = hostTrees
+	// Build a merged tree for filterRecentSessions compatibility
+	merged := &tmux.Tree{}
+	m.hostErrors = map[string]error{}
+	m.hostLatency = map[string]time.Duration{}
+	for _, ht := range hostTrees {
+		label := ht.Host
+		if label == "" {
+			label = "local"
+		}
+		if ht.CachedAt.IsZero() && ht.Latency > 0 {
+			m.hostLatency[label] = ht.Latency
+		}
+		if ht.Err != nil {
+			m.hostErrors[label] = ht.Err
+			continue
+		}
+		if ht.Tree != nil {
+			merged.Sessions = append(merged.Sessions, ht.Tree.Sessions...)
+		}
+	}
+	m.tree = merged
+	m.rebuildFlatNodes()
+	m.calculateButtonZones()
+	m.filterRecentSessions()
+}
+
 // handleKeyMsg handles keyboard input
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -236,6 +345,61 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil // Ignore other keys while confirmation is shown
 	}
 
+	// Handle bulk kill confirmation if active
+	if m.pendingBulkKill != nil {
+		return m.handleBulkKillConfirmKeys(msg)
+	}
+
+	// Handle "session already exists" prompt if active
+	if m.confirmNewSession {
+		return m.handleNewSessionConfirmKeys(msg)
+	}
+
+	// Handle send-to-all-panes confirmation if active
+	if m.pendingSendAll != nil {
+		return m.handleSendAllConfirmKeys(msg)
+	}
+
+	// Handle send-to-shell confirmation if active
+	if m.pendingShellSend != nil {
+		return m.handleShellSendConfirmKeys(msg)
+	}
+
+	// Handle the send-file prompt if active
+	if m.sendFile != nil {
+		return m.handleSendFileKeys(msg)
+	}
+
+	// Handle template choice for a new session if active
+	if m.templatePicker != nil {
+		return m.handleTemplatePickerKeys(msg)
+	}
+
+	// Handle the move-window session picker if active
+	if m.moveWindow != nil {
+		return m.handleMoveWindowKeys(msg)
+	}
+
+	// Handle pane search overlay if active
+	if m.search != nil {
+		return m.handleSearchKeys(msg)
+	}
+
+	// Handle command palette if active
+	if m.palette != nil {
+		return m.handlePaletteKeys(msg)
+	}
+
+	// Handle the command history picker if active
+	if m.historyPicker != nil {
+		return m.handleHistoryPickerKeys(msg)
+	}
+
+	// Handle tree filter input if active
+	if m.filteringTree {
+		return m.handleTreeFilterKeys(msg)
+	}
+
 	// Close help overlay first if open
 	if m.showHelp {
 		switch msg.String() {
@@ -246,11 +410,31 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil // Ignore other keys while help is open
 	}
 
+	// The multi-line editor takes most keys, including ? and Esc
+	if m.focused == FocusInput && m.multiline {
+		if model, cmd, handled := m.handleCommandEditorKeys(msg); handled {
+			return model, cmd
+		}
+	}
+
+	if m.focused != FocusInput {
+		if to, ok := m.screenJump.consume(msg.String(), ScreenBrowse); ok {
+			if to == "" {
+				return m, nil
+			}
+			m.switchTo = m.screenSwitch(to)
+			return m, tea.Quit
+		}
+	}
+
 	// Global keys
 	switch msg.String() {
 	case "?":
 		m.showHelp = true
 		return m, nil
+	case "ctrl+p":
+		m.palette = newCommandPalette(m.paletteItems())
+		return m, textinput.Blink
 	case "ctrl+c", "q":
 		if msg.String() == "q" && m.focused != FocusInput {
 			return m, tea.Quit
@@ -289,10 +473,32 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Second Esc (input already empty): switch to tree panel
 				m.focused = FocusTree
 				m.commandInput.Blur()
+				m.setPreviewZoomed(false)
 			}
 			m.ctrlCPrimed = false
 			return m, nil
 		}
+		if m.previewZoomed {
+			// Esc leaves zoom before quitting
+			m.setPreviewZoomed(false)
+			return m, nil
+		}
+		if m.swapFrom != nil {
+			// Esc cancels a swap in progress before quitting
+			m.swapFrom = nil
+			m.lastNotice = ""
+			return m, nil
+		}
+		if len(m.killMarks) > 0 {
+			// Esc drops bulk kill marks before quitting
+			m.killMarks = nil
+			return m, nil
+		}
+		if m.treeFilterQuery() != "" {
+			// Esc clears an applied filter before quitting
+			m.clearTreeFilter()
+			return m, m.updatePreviewForSelection()
+		}
 		return m, tea.Quit
 	case "tab":
 		m.cycleFocus(1)
@@ -301,14 +507,13 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cycleFocus(-1)
 		return m, nil
 	case "/":
-		// Only focus input if not already focused (so "/" can be typed)
+		// Only filter if not in the input (so "/" can be typed)
 		if m.focused != FocusInput {
-			m.focused = FocusInput
-			m.commandInput.Focus()
-			return m, nil
+			return m, m.openTreeFilter()
 		}
 	case "r":
 		if m.focused != FocusInput {
+			m.resetHostRetries()
 			return m, tea.Batch(m.fetchTreeCmd(), fetchRecentSessions)
 		}
 	case "m":
@@ -319,11 +524,7 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "M":
 		if m.focused != FocusInput {
-			m.mouseEnabled = !m.mouseEnabled
-			if m.mouseEnabled {
-				return m, tea.EnableMouseCellMotion
-			}
-			return m, tea.DisableMouse
+			return m, m.toggleMouse()
 		}
 	}
 
@@ -340,6 +541,15 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// toggleMouse flips mouse capture and returns the command that applies it
+func (m *Model) toggleMouse() tea.Cmd {
+	m.mouseEnabled = !m.mouseEnabled
+	if m.mouseEnabled {
+		return tea.EnableMouseCellMotion
+	}
+	return tea.DisableMouse
+}
+
 // handleTreeKeys handles keys when tree is focused
 func (m Model) handleTreeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// If focus is on the recent section, handle recent-specific keys
@@ -347,6 +557,13 @@ func (m Model) handleTreeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleRecentKeys(msg)
 	}
 
+	targets := m.jumpTargets()
+	if idx, ok := m.lineJump.consumeKey(msg, len(targets)); ok {
+		m.jumpSelection(targets[idx])
+		m.calculateButtonZones()
+		return m, m.updatePreviewForSelection()
+	}
+
 	switch msg.String() {
 	case "up", "k":
 		m.moveSelection(-1)
@@ -358,42 +575,188 @@ func (m Model) handleTreeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m, m.updatePreviewForSelection()
+	case "pgup", "pgdown", "home", "end":
+		switch msg.String() {
+		case "pgup":
+			m.jumpSelection(m.selectedIndex - m.treeViewHeight())
+		case "pgdown":
+			m.jumpSelection(m.selectedIndex + m.treeViewHeight())
+		case "home":
+			m.jumpSelection(0)
+		case "end":
+			m.jumpSelection(len(m.flatNodes) - 1)
+		}
+		m.calculateButtonZones()
+		return m, m.updatePreviewForSelection()
+	case "[", "]":
+		// Jump to the previous/next host header
+		dir := 1
+		if msg.String() == "[" {
+			dir = -1
+		}
+		if index, ok := adjacentGroupStart(m.hostHeaderIndices(), m.selectedIndex, dir); ok {
+			m.jumpSelection(index)
+			m.calculateButtonZones()
+			return m, m.updatePreviewForSelection()
+		}
+		return m, nil
 	case "enter", " ":
+		if node := m.selectedNode(); m.swapFrom != nil && node != nil && node.Type == "pane" {
+			// Enter on a pane picks it as the other half of a swap
+			return m.swapPaneWith(node)
+		}
 		m.toggleExpand()
 		m.calculateButtonZones()
 		return m, nil
+	case "P":
+		// Paste a file's contents into the selected pane
+		return m.openSendFile()
+	case "w":
+		// Watch the selected pane and get told when it goes idle
+		m.toggleWatch()
+		return m, nil
+	case "A":
+		// Show only the panes running a configured agent
+		m.toggleAgentsView()
+		return m, m.updatePreviewForSelection()
+	case "-", "+", "=":
+		// Collapse or expand the whole tree
+		m.setAllExpanded(msg.String() != "-")
+		m.calculateButtonZones()
+		return m, m.updatePreviewForSelection()
 	case "a":
 		// Attach to selected session/window/pane
 		if node := m.selectedNode(); node != nil {
 			if session := sessionFromNode(node); session != "" {
-				m.attachSession = session
-				m.reviveDir = ""
-				return m, tea.Quit
+				return m.quitToAttach(session, node.Target)
 			}
 		}
 	case "s":
 		// Send command to selected pane
 		if node := m.selectedNode(); node != nil && node.Type == "pane" {
-			cmd := m.commandInput.Value()
-			if cmd != "" {
-				m.pushInputHistory(cmd)
-				return m, m.sendCommandForNode(node, cmd)
+			return m.sendCommandInput(node)
+		}
+	case "S":
+		// Send command to every pane in the selected (or selected pane's) window
+		if node := m.selectedNode(); node != nil {
+			if window := windowTargetOf(node); window != "" {
+				return m.requestSendAll(node.Host, window, m.commandInput.Value())
 			}
 		}
+	case "v":
+		// Mark the selected session/window/pane for a bulk kill
+		if node := m.selectedNode(); node != nil {
+			m.toggleMark(node)
+			m.moveSelection(1)
+			return m, m.updatePreviewForSelection()
+		}
+	case "V":
+		// Mark the inactive windows (or panes) beside the selected one
+		m.markInactiveSiblings(m.selectedNode())
+		return m, nil
 	case "x", "d":
-		// Kill selected session/window/pane (with confirmation)
+		// Kill the marked targets if any, else the selected session/window/pane
+		// (with confirmation unless disabled)
+		if len(m.killMarks) > 0 {
+			return m.requestBulkKill()
+		}
 		if node := m.selectedNode(); node != nil && node.Type != "host" {
-			m.confirmKill = true
-			m.killNodeType = node.Type
-			m.killNodeTarget = node.Target
-			m.killNodeName = node.Name
-			m.killNodeHost = node.Host
-			return m, nil
+			return m.requestKill(node.Type, node.Target, node.Name, node.Host, node.Attached)
+		}
+	case "z":
+		// Toggle zoom on the selected pane, or a window's active pane
+		if node := m.selectedNode(); node != nil && (node.Type == "pane" || node.Type == "window") {
+			return m, tea.Sequence(toggleZoomPane(node.Target, m.executorOrLocal(node.Host)), m.fetchTreeCmd())
 		}
+	case "^":
+		// Interrupt the selected pane's foreground process
+		return m, m.sendInterruptForNode(m.selectedNode())
+	case "`":
+		// Mark or unmark the selected pane, like tmux's prefix+m
+		return m.toggleMarkedPane()
+	case "'":
+		// Jump to the marked pane, wherever it is in the tree
+		return m.jumpToMarkedPane()
 	case "c":
 		// Show context menu for selected item (alternative to right-click)
 		m.showContextMenuForSelected()
 		return m, nil
+	case "n":
+		// New session for the current directory
+		return m.startNewSession()
+	case "y":
+		// Copy the selected target to the clipboard
+		if node := m.selectedNode(); node != nil && node.Type != "host" {
+			return m, copyTargetCmd(node.Target)
+		}
+	case "Y":
+		// Copy the selected pane's content to the clipboard
+		if node := m.selectedNode(); node != nil && node.Type != "host" {
+			return m, m.copyContentCmd(node.Host, node.Target)
+		}
+	case "f":
+		// Search pane contents across all hosts
+		m.search = newPaneSearch()
+		return m, textinput.Blink
+	case "i":
+		// Show/hide pane dimensions
+		m.showPaneSize = !m.showPaneSize
+		return m, nil
+	case "<", ">":
+		// Nudge the tree/preview divider
+		dir := 1
+		if msg.String() == "<" {
+			dir = -1
+		}
+		if m.previewZoomed {
+			return m, nil
+		}
+		if m.stacked {
+			m.setStackedTreeHeight(m.stackedTreeHeight + dir*treeHeightStep)
+			m.calculateButtonZones()
+			return m, nil
+		}
+		width := m.treeWidth
+		m.setTreeWidth(width + dir*treeWidthStep)
+		if m.treeWidth != width {
+			m.saveTreeWidth()
+			m.calculateButtonZones()
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// handleTemplatePickerKeys handles keys while choosing a template for the
+// new session; choosing one creates the session on quit.
+func (m Model) handleTemplatePickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	done, cancelled := m.templatePicker.handleKey(msg.String())
+	if cancelled {
+		m.templatePicker = nil
+		return m, nil
+	}
+	if done {
+		m.sessionTemplate = m.templatePicker.choice()
+		m.templatePicker = nil
+		m.attachSession = m.options.SessionName
+		m.reviveDir = m.options.WorkingDir
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// handleNewSessionConfirmKeys handles keys while asking whether to attach to
+// the already-existing session for the current directory.
+func (m Model) handleNewSessionConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.confirmNewSession = false
+		m.attachSession = m.options.SessionName
+		m.reviveDir = ""
+		return m, tea.Quit
+	case "n", "N", "esc":
+		m.confirmNewSession = false
+		return m, nil
 	}
 	return m, nil
 }
@@ -474,13 +837,13 @@ func (m Model) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		// Send to selected pane
 		if node := m.selectedNode(); node != nil && node.Type == "pane" {
-			cmd := m.commandInput.Value()
-			if cmd != "" {
-				m.pushInputHistory(cmd)
-				return m, m.sendCommandForNode(node, cmd)
-			}
+			return m.sendCommandInput(node)
 		}
 		return m, nil
+	case "ctrl+o":
+		return m.openCommandEditor()
+	case "ctrl+r":
+		return m.openHistoryPicker()
 	}
 
 	// Pass to text input
@@ -502,6 +865,29 @@ func (m Model) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// sendCommandInput sends the command input to a pane, clearing the input
+// now or once the send succeeds when after_send asks for it.
+func (m Model) sendCommandInput(node *tmux.TreeNode) (tea.Model, tea.Cmd) {
+	command := m.commandInput.Value()
+	if command == "" {
+		return m, nil
+	}
+	switch m.options.AfterSend {
+	case config.AfterSendClear:
+		m.clearCommandInput()
+	case config.AfterSendClearOnSuccess:
+		m.clearOnSent = command
+	}
+	return m.requestSend(node, command)
+}
+
+// clearCommandInput empties the command input without saving it to history.
+func (m *Model) clearCommandInput() {
+	m.commandInput.SetValue("")
+	m.commandInput.CursorEnd()
+	m.lastInputVal = ""
+}
+
 func isDeletionKey(msg tea.KeyMsg) bool {
 	switch msg.Type {
 	case tea.KeyBackspace, tea.KeyDelete:
@@ -516,6 +902,11 @@ func isDeletionKey(msg tea.KeyMsg) bool {
 
 // handlePreviewKeys handles keys when preview is focused
 func (m Model) handlePreviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "z" {
+		m.setPreviewZoomed(!m.previewZoomed)
+		return m, nil
+	}
+
 	var cmd tea.Cmd
 	m.previewPort, cmd = m.previewPort.Update(msg)
 	return m, cmd
@@ -550,7 +941,7 @@ func (m Model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		if msg.Button == tea.MouseButtonLeft {
 			if m.isOnDivider(msg.X, msg.Y) {
 				m.resizing = true
-				m.resizeTreeWidth(msg.X)
+				m.resizeDivider(msg.X, msg.Y)
 				return m, nil
 			}
 			return m.handleLeftClick(msg.X, msg.Y)
@@ -560,7 +951,11 @@ func (m Model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		}
 	case tea.MouseActionMotion:
 		if m.resizing {
-			m.resizeTreeWidth(msg.X)
+			m.resizeDivider(msg.X, msg.Y)
+			return m, nil
+		}
+		if m.previewSelect != nil {
+			m.extendPreviewSelection(msg.X, msg.Y)
 			return m, nil
 		}
 		// Track hover for button highlighting
@@ -569,8 +964,14 @@ func (m Model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	case tea.MouseActionRelease:
 		if m.resizing {
 			m.resizing = false
+			if !m.stacked {
+				m.saveTreeWidth()
+			}
 			return m, nil
 		}
+		if m.previewSelect != nil {
+			return m, m.finishPreviewSelection()
+		}
 	}
 
 	// Pass scroll to appropriate component
@@ -594,27 +995,25 @@ func (m Model) handleLeftClick(x, y int) (tea.Model, tea.Cmd) {
 	if zone, ok := m.findButtonAt(x, y); ok {
 		switch zone.action {
 		case buttonActionSend:
-			cmd := m.commandInput.Value()
-			if cmd != "" {
-				m.pushInputHistory(cmd)
-				if node := m.nodeForTarget(zone.target); node != nil {
-					return m, m.sendCommandForNode(node, cmd)
-				}
-				return m, sendCommand(zone.target, cmd, m.sendMethod)
+			node := m.nodeForTarget(zone.target)
+			if node == nil {
+				node = &tmux.TreeNode{Type: "pane", Target: zone.target}
 			}
-			return m, nil
+			return m.sendCommandInput(node)
 		case buttonActionEscape:
 			if node := m.nodeForTarget(zone.target); node != nil {
 				return m, m.sendEscapeForNode(node)
 			}
 			return m, sendEscape(zone.target)
+		case buttonActionInterrupt:
+			if node := m.nodeForTarget(zone.target); node != nil {
+				return m, m.sendInterruptForNode(node)
+			}
+			return m, sendInterrupt(zone.target)
 		case buttonActionAttach:
-			// Extract session from target and attach
-			session := sessionFromTarget(zone.target)
-			if session != "" {
-				m.attachSession = session
-				m.reviveDir = ""
-				return m, tea.Quit
+			// Attach to the button's pane
+			if session := sessionFromTarget(zone.target); session != "" {
+				return m.quitToAttach(session, zone.target)
 			}
 			return m, nil
 		case buttonActionHelp:
@@ -624,43 +1023,35 @@ func (m Model) handleLeftClick(x, y int) (tea.Model, tea.Cmd) {
 			return m, m.fetchTreeCmd()
 		case buttonActionKillHint:
 			if node := m.selectedNode(); node != nil && node.Type != "host" {
-				m.confirmKill = true
-				m.killNodeType = node.Type
-				m.killNodeTarget = node.Target
-				m.killNodeName = node.Name
-				m.killNodeHost = node.Host
+				return m.requestKill(node.Type, node.Target, node.Name, node.Host, node.Attached)
 			}
 			return m, nil
-		case buttonActionFocusInput:
-			m.focused = FocusInput
-			m.commandInput.Focus()
-			return m, nil
+		case buttonActionFilter:
+			return m, m.openTreeFilter()
 		}
 	}
 
 	// Check regions for focus change
-	// Input area is at the top (rows 1-3)
-	if y <= inputHeight {
-		m.focused = FocusInput
-		m.commandInput.Focus()
+	// Input area is at the top (rows 1-3, more while the editor is open)
+	if y <= m.inputAreaHeight() {
+		m.focusCommandInput()
 		return m, nil
 	}
 
-	// Tree is on the left
-	if x < m.treeWidth+2 {
+	// Tree is on the left or on top (hidden while the preview is zoomed)
+	if m.inTreeArea(x, y) {
 		m.focused = FocusTree
 		m.commandInput.Blur()
 
 		// Calculate which tree item was clicked
-		// inputHeight (3) + tree top border (1) + tree content padding (1) = 5
-		treeStartY := inputHeight + 2
-		clickedIdx := y - treeStartY
-		if clickedIdx >= 0 && clickedIdx < len(m.flatNodes) {
+		row := y - m.treeStartY()
+		clickedIdx := row + m.treeScroll
+		if row >= 0 && row < m.visibleTreeNodeCount() {
 			m.focusRecent = false
 			node := m.flatNodes[clickedIdx]
 			m.selectedIndex = clickedIdx
 			if node.Type == "session" || node.Type == "window" || node.Type == "host" {
-				indent := node.Level * 2
+				indent := m.jumpGutterWidth() + node.Level*2
 				icon := getNodeIcon(node.Type, node.Expanded, node.Active)
 				iconStartX := indent
 				iconEndX := iconStartX + lipgloss.Width(icon)
@@ -674,9 +1065,7 @@ func (m Model) handleLeftClick(x, y int) (tea.Model, tea.Cmd) {
 			if clickedIdx == m.lastClickIdx &&
 				time.Since(m.lastClickAt) <= doubleClickThreshold {
 				if session := sessionFromNode(node); session != "" {
-					m.attachSession = session
-					m.reviveDir = ""
-					return m, tea.Quit
+					return m.quitToAttach(session, node.Target)
 				}
 			}
 			m.lastClickIdx = clickedIdx
@@ -687,8 +1076,8 @@ func (m Model) handleLeftClick(x, y int) (tea.Model, tea.Cmd) {
 		// Check if clicking in the recent section area
 		// Recent section starts at: tree nodes + 1 (empty line) + 1 (header)
 		if len(m.recentSessions) > 0 {
-			recentStartLine := len(m.flatNodes) + 2 // blank line + header
-			recentIdx := clickedIdx - recentStartLine
+			recentStartLine := m.visibleTreeNodeCount() + 2 // blank line + header
+			recentIdx := row - recentStartLine
 			if recentIdx >= 0 && recentIdx < len(m.recentSessions) {
 				m.focusRecent = true
 				m.recentSelectedIndex = recentIdx
@@ -707,9 +1096,10 @@ func (m Model) handleLeftClick(x, y int) (tea.Model, tea.Cmd) {
 			}
 		}
 	} else {
-		// Preview is on the right
+		// Preview is on the right; a drag from here selects text to copy
 		m.focused = FocusPreview
 		m.commandInput.Blur()
+		m.startPreviewSelection(x, y)
 	}
 
 	return m, nil
@@ -718,6 +1108,7 @@ func (m Model) handleLeftClick(x, y int) (tea.Model, tea.Cmd) {
 // cycleFocus cycles through focusable components
 func (m *Model) cycleFocus(delta int) {
 	m.commandInput.Blur()
+	m.commandArea.Blur()
 	m.focusRecent = false // Reset recent focus when cycling panels
 
 	focusOrder := []FocusedComponent{FocusTree, FocusInput, FocusPreview}
@@ -733,7 +1124,10 @@ func (m *Model) cycleFocus(delta int) {
 	m.focused = focusOrder[current]
 
 	if m.focused == FocusInput {
-		m.commandInput.Focus()
+		m.focusCommandInput()
+	}
+	if m.focused != FocusPreview {
+		m.setPreviewZoomed(false)
 	}
 }
 
@@ -761,6 +1155,19 @@ func (m *Model) pushInputHistory(value string) {
 	m.historyDraft = ""
 }
 
+// quitToAttach quits browse to attach to session, landing on target when
+// it is one of the session's windows or panes. A session target keeps the
+// session's current window.
+func (m Model) quitToAttach(session, target string) (tea.Model, tea.Cmd) {
+	m.attachSession = session
+	m.attachTarget = ""
+	if strings.HasPrefix(target, session+":") {
+		m.attachTarget = target
+	}
+	m.reviveDir = ""
+	return m, tea.Quit
+}
+
 func sessionFromNode(node *tmux.TreeNode) string {
 	if node == nil {
 		return ""
@@ -787,22 +1194,51 @@ func sessionFromTarget(target string) string {
 	return target
 }
 
+// inTreeArea reports whether (x, y) is in the tree panel.
+func (m *Model) inTreeArea(x, y int) bool {
+	switch {
+	case m.previewZoomed:
+		return false
+	case m.stacked:
+		return y < m.inputAreaHeight()+m.stackedTreeHeight+2
+	}
+	return x < m.treeWidth+2
+}
+
 func (m *Model) isOnDivider(x, y int) bool {
-	if y <= inputHeight || y >= m.height-statusHeight {
+	if m.previewZoomed || y <= m.inputAreaHeight() || y >= m.height-statusHeight {
 		return false
 	}
+	if m.stacked {
+		// The tree's bottom border or the preview's top border
+		dividerY := m.inputAreaHeight() + m.stackedTreeHeight + 1
+		return y >= dividerY && y <= dividerY+1
+	}
 	dividerX := m.treeWidth - 1
 	return x >= dividerX-1 && x <= dividerX+1
 }
 
-func (m *Model) resizeTreeWidth(x int) {
+// resizeDivider moves the divider to follow a drag at (x, y): across for
+// side-by-side panels, up and down when stacked.
+func (m *Model) resizeDivider(x, y int) {
+	if m.stacked {
+		m.setStackedTreeHeight(y - m.inputAreaHeight() - 1)
+		m.calculateButtonZones()
+		return
+	}
+	m.setTreeWidth(x + 1)
+}
+
+// setTreeWidth moves the divider so the tree is newTreeWidth wide, clamped
+// so neither panel drops below its minimum, and remembers the width as a
+// share of the window for calculateLayout.
+func (m *Model) setTreeWidth(newTreeWidth int) {
 	availableWidth := m.width - 4
 	maxTreeWidth := availableWidth - minPreviewWidth
 	if maxTreeWidth < minTreeWidth {
 		return
 	}
 
-	newTreeWidth := x + 1
 	if newTreeWidth < minTreeWidth {
 		newTreeWidth = minTreeWidth
 	}
@@ -815,27 +1251,36 @@ func (m *Model) resizeTreeWidth(x int) {
 
 	m.treeWidth = newTreeWidth
 	m.previewWidth = availableWidth - m.treeWidth
-	previewHeight := m.height - inputHeight - statusHeight - 4
-	if previewHeight < 5 {
-		previewHeight = 5
+	m.options.TreeWidthPercent = (m.treeWidth*100 + availableWidth/2) / availableWidth
+	m.sizePreviewPort()
+}
+
+// saveTreeWidth stores the tree width share in settings so it survives
+// restarts.
+func (m *Model) saveTreeWidth() {
+	settings, err := config.LoadSettings()
+	if err == nil {
+		settings.TreeWidthPercent = m.options.TreeWidthPercent
+		err = settings.Save()
+	}
+	if err != nil {
+		m.lastError = fmt.Errorf("failed to save tree width: %w", err)
 	}
-	m.previewPort.Width = m.previewWidth - 2
-	m.previewPort.Height = previewHeight
 }
 
 // handleRightClick handles right mouse clicks to show context menus
 func (m Model) handleRightClick(x, y int) (tea.Model, tea.Cmd) {
 	// Only show context menu when clicking in tree area
-	if x >= m.treeWidth+2 {
+	if !m.inTreeArea(x, y) {
 		return m, nil
 	}
 
 	// Calculate which tree item was clicked
-	treeStartY := inputHeight + 2
-	clickedIdx := y - treeStartY
-	if clickedIdx < 0 || clickedIdx >= len(m.flatNodes) {
+	row := y - m.treeStartY()
+	if row < 0 || row >= m.visibleTreeNodeCount() {
 		return m, nil
 	}
+	clickedIdx := row + m.treeScroll
 
 	node := m.flatNodes[clickedIdx]
 	m.selectedIndex = clickedIdx
@@ -845,7 +1290,7 @@ func (m Model) handleRightClick(x, y int) (tea.Model, tea.Cmd) {
 	menuX := x
 	menuY := y
 
-	menu := NewContextMenu(node.Type, node.Target, node.Name, menuX, menuY)
+	menu := NewContextMenu(node, menuX, menuY)
 
 	// Adjust menu position to stay within screen bounds
 	menuWidth := menu.Width + 4
@@ -878,12 +1323,11 @@ func (m *Model) showContextMenuForSelected() {
 		return
 	}
 
-	// Position menu near the selected item in the tree
-	treeStartY := inputHeight + 2
-	menuY := treeStartY + m.selectedIndex
-	menuX := node.Level*2 + 5 // Indent based on level
+	// Position menu at the selected row as it is drawn, scrolled
+	menuY := m.treeStartY() + m.selectedIndex - m.treeScroll
+	menuX := m.jumpGutterWidth() + node.Level*2 + 5 // Indent based on level
 
-	menu := NewContextMenu(node.Type, node.Target, node.Name, menuX, menuY)
+	menu := NewContextMenu(node, menuX, menuY)
 
 	// Adjust menu position to stay within screen bounds
 	menuWidth := menu.Width + 4
@@ -922,26 +1366,27 @@ func (m Model) executeMenuAction(action string) (tea.Model, tea.Cmd) {
 
 	switch action {
 	case MenuActionAttach:
-		// Attach to session
-		session := sessionFromTarget(target)
-		if session != "" {
-			m.attachSession = session
-			m.reviveDir = ""
-			return m, tea.Quit
+		// Attach to the item's session, landing on the item
+		if session := sessionFromTarget(target); session != "" {
+			return m.quitToAttach(session, target)
+		}
+
+	case MenuActionAttachRO:
+		// Attach without sending keys; scrolling and copy mode still work
+		if session := sessionFromTarget(target); session != "" {
+			m.attachRO = true
+			return m.quitToAttach(session, target)
 		}
 
 	case MenuActionAttachPopup:
 		// Attach in popup mode - for now just attach normally
-		session := sessionFromTarget(target)
-		if session != "" {
-			m.attachSession = session
-			m.reviveDir = ""
-			return m, tea.Quit
+		if session := sessionFromTarget(target); session != "" {
+			return m.quitToAttach(session, target)
 		}
 
 	case MenuActionNewWindow:
 		// Create new window in session
-		return m, createNewWindow(target)
+		return m, tea.Sequence(createNewWindow(target, m.options.NewInPaneDir), m.fetchTreeCmd())
 
 	case MenuActionRename:
 		// TODO: Implement rename dialog
@@ -949,75 +1394,150 @@ func (m Model) executeMenuAction(action string) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case MenuActionKillSession, MenuActionKillWindow, MenuActionKillPane:
-		// Show kill confirmation
+		// Show kill confirmation (unless disabled)
 		node := m.selectedNode()
 		if node != nil {
-			m.confirmKill = true
-			m.killNodeType = nodeType
-			m.killNodeTarget = target
-			m.killNodeName = node.Name
-			m.killNodeHost = node.Host
+			return m.requestKill(nodeType, target, node.Name, node.Host, node.Attached)
 		}
 		return m, nil
 
 	case MenuActionSelectWindow:
 		// Switch to window
-		return m, switchToTarget(target)
+		return m, switchToTarget(target, m.options.RestoreFocus)
 
 	case MenuActionNewPaneH:
 		// Create horizontal split
-		return m, createNewPane(target, false)
+		return m, tea.Sequence(createNewPane(target, false, m.options.NewInPaneDir), m.fetchTreeCmd())
 
 	case MenuActionNewPaneV:
 		// Create vertical split
-		return m, createNewPane(target, true)
+		return m, tea.Sequence(createNewPane(target, true, m.options.NewInPaneDir), m.fetchTreeCmd())
 
 	case MenuActionSelectPane:
 		// Switch to pane
-		return m, switchToTarget(target)
+		return m, switchToTarget(target, m.options.RestoreFocus)
 
 	case MenuActionZoomPane:
-		// Toggle zoom on pane
-		return m, toggleZoomPane(target)
+		// Toggle zoom on the pane (or a window's active pane)
+		host := ""
+		if node := m.selectedNode(); node != nil {
+			host = node.Host
+		}
+		return m, tea.Sequence(toggleZoomPane(target, m.executorOrLocal(host)), m.fetchTreeCmd())
 
 	case MenuActionSendKeys:
 		// Focus the input and set target
 		m.focused = FocusInput
 		m.commandInput.Focus()
 		return m, nil
+
+	case MenuActionSendFile:
+		return m.openSendFile()
+
+	case MenuActionInterrupt:
+		return m, m.sendInterruptForNode(m.selectedNode())
+
+	case MenuActionSendAllPanes:
+		host := ""
+		if node := m.selectedNode(); node != nil {
+			host = node.Host
+		}
+		return m.requestSendAll(host, target, m.commandInput.Value())
+
+	case MenuActionSyncPanes:
+		// Toggle synchronize-panes, then refresh so the tree shows the new state
+		var exec tmux.TmuxExecutor = tmux.NewLocalExecutor()
+		if node := m.selectedNode(); node != nil && node.Host != "" {
+			if e := m.executorForHost(node.Host); e != nil {
+				exec = e
+			}
+		}
+		return m, tea.Sequence(toggleSynchronizePanes(target, exec), m.fetchTreeCmd())
+
+	case MenuActionSwapPane:
+		if node := m.selectedNode(); node != nil && node.Type == "pane" {
+			return m.swapPaneWith(node)
+		}
+		return m, nil
+
+	case MenuActionBreakPane:
+		host := ""
+		if node := m.selectedNode(); node != nil {
+			host = node.Host
+		}
+		return m, tea.Sequence(breakPaneCmd(target, m.executorOrLocal(host)), m.fetchTreeCmd())
+
+	case MenuActionMoveWindow:
+		host := ""
+		if node := m.selectedNode(); node != nil {
+			host = node.Host
+		}
+		return m.openMoveWindow(target, host)
+
+	case MenuActionCopyTarget:
+		return m, copyTargetCmd(target)
+
+	case MenuActionCopyContent:
+		host := ""
+		if node := m.selectedNode(); node != nil {
+			host = node.Host
+		}
+		return m, m.copyContentCmd(host, target)
 	}
 
 	return m, nil
 }
 
-// createNewWindow creates a new window in the specified session
-func createNewWindow(sessionTarget string) tea.Cmd {
+// createNewWindow creates a new window in the specified session. With
+// inPaneDir it starts in the directory of the session's active pane.
+func createNewWindow(sessionTarget string, inPaneDir bool) tea.Cmd {
 	return func() tea.Msg {
-		err := tmux.CreateNewWindow(sessionTarget)
-		return TreeRefreshedMsg{Err: err}
+		dir := ""
+		if inPaneDir {
+			dir = tmux.GetPaneCurrentPath(sessionTarget)
+		}
+		err := tmux.CreateNewWindowInDir(sessionTarget, dir)
+		return CommandSentMsg{Target: sessionTarget, Command: "new-window", Err: err}
 	}
 }
 
-// createNewPane creates a new pane in the specified window
-func createNewPane(windowTarget string, vertical bool) tea.Cmd {
+// createNewPane creates a new pane in the specified window. With inPaneDir
+// it starts in the directory of the window's active pane.
+func createNewPane(windowTarget string, vertical bool, inPaneDir bool) tea.Cmd {
 	return func() tea.Msg {
-		err := tmux.CreateNewPane(windowTarget, vertical)
-		return TreeRefreshedMsg{Err: err}
+		dir := ""
+		if inPaneDir {
+			dir = tmux.GetPaneCurrentPath(windowTarget)
+		}
+		err := tmux.CreateNewPaneInDir(windowTarget, vertical, dir)
+		return CommandSentMsg{Target: windowTarget, Command: "split-window", Err: err}
 	}
 }
 
-// switchToTarget switches the client to the specified target
-func switchToTarget(target string) tea.Cmd {
+// switchToTarget switches the client to the specified target, remembering
+// it as the session's last focus when remember is set.
+func switchToTarget(target string, remember bool) tea.Cmd {
 	return func() tea.Msg {
 		err := tmux.SwitchToTarget(target)
+		if err == nil && remember {
+			RememberFocus(target)
+		}
 		return CommandSentMsg{Target: target, Command: "switch", Err: err}
 	}
 }
 
-// toggleZoomPane toggles zoom on the specified pane
-func toggleZoomPane(target string) tea.Cmd {
+// toggleSynchronizePanes toggles synchronize-panes on a window via exec
+func toggleSynchronizePanes(target string, exec tmux.TmuxExecutor) tea.Cmd {
+	return func() tea.Msg {
+		err := tmux.ToggleSynchronizePanesWithExecutor(target, exec)
+		return CommandSentMsg{Target: target, Command: "synchronize-panes", Err: err}
+	}
+}
+
+// toggleZoomPane toggles zoom on the specified pane via exec
+func toggleZoomPane(target string, exec tmux.TmuxExecutor) tea.Cmd {
 	return func() tea.Msg {
-		err := tmux.ToggleZoom(target)
+		err := tmux.ToggleZoomWithExecutor(target, exec)
 		return CommandSentMsg{Target: target, Command: "zoom", Err: err}
 	}
 }
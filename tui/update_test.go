@@ -1,10 +1,17 @@
 package tui
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/config"
 	"github.com/porganisciak/agent-tmux/history"
 	"github.com/porganisciak/agent-tmux/tmux"
 )
@@ -47,9 +54,20 @@ func TestAttachKeySetsSession(t *testing.T) {
 	if updatedModel.attachSession != "sess" {
 		t.Fatalf("expected attach session sess, got %q", updatedModel.attachSession)
 	}
+	if updatedModel.attachTarget != "sess:0.0" {
+		t.Fatalf("expected to land on the selected pane, got %q", updatedModel.attachTarget)
+	}
+
+	// A session node attaches to the session's current window
+	m.selectedIndex = 0
+	updated, _ = m.handleTreeKeys(key)
+	if got := updated.(Model); got.attachSession != "sess" || got.attachTarget != "" {
+		t.Fatalf("expected a plain session attach, got %q at %q", got.attachSession, got.attachTarget)
+	}
 }
 
 func TestMouseResizeUpdatesTreeWidth(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 	m := NewModel(Options{})
 	m.width = 120
 	m.height = 40
@@ -92,6 +110,153 @@ func TestMouseResizeUpdatesTreeWidth(t *testing.T) {
 	if releasedModel.resizing {
 		t.Fatalf("expected resizing to stop on release")
 	}
+
+	// The width is saved as a share of the window and restored on restart
+	settings, err := config.LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (expectedWidth*100 + 58) / 116; settings.TreeWidthPercent != want {
+		t.Fatalf("expected %d%% saved, got %d", want, settings.TreeWidthPercent)
+	}
+	restored := NewModel(Options{TreeWidthPercent: settings.TreeWidthPercent})
+	restored.width = 120
+	restored.height = 40
+	restored.calculateLayout()
+	if restored.treeWidth < expectedWidth-1 || restored.treeWidth > expectedWidth+1 {
+		t.Fatalf("expected the restored tree near %d columns, got %d", expectedWidth, restored.treeWidth)
+	}
+}
+
+func TestTreeWidthKeysNudgeAndClamp(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 40
+	m.calculateLayout()
+	start := m.treeWidth
+
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'>'}})
+	m = updated.(Model)
+	if m.treeWidth != start+treeWidthStep || m.previewWidth != 116-m.treeWidth {
+		t.Fatalf("expected > to widen the tree by %d, got %d", treeWidthStep, m.treeWidth)
+	}
+
+	for i := 0; i < 50; i++ {
+		updated, _ = m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+		m = updated.(Model)
+	}
+	if m.treeWidth != minTreeWidth {
+		t.Fatalf("expected the tree clamped to %d, got %d", minTreeWidth, m.treeWidth)
+	}
+
+	// A saved width wider than the window allows still leaves the preview room
+	wide := NewModel(Options{TreeWidthPercent: 90})
+	wide.width = 120
+	wide.height = 40
+	wide.calculateLayout()
+	if wide.previewWidth < minPreviewWidth || wide.treeWidth+wide.previewWidth != 116 {
+		t.Fatalf("expected the preview kept at %d+, got tree %d preview %d", minPreviewWidth, wide.treeWidth, wide.previewWidth)
+	}
+}
+
+func TestPreviewZoomTogglesFullWidth(t *testing.T) {
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 40
+	m.calculateLayout()
+	m.resizeDivider(50, 10)
+	treeWidth := m.treeWidth
+	splitWidth := m.previewWidth
+	m.focused = FocusPreview
+
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}}
+	updated, _ := m.handleKeyMsg(key)
+	zoomed := updated.(Model)
+	if !zoomed.previewZoomed {
+		t.Fatalf("expected z to zoom the preview")
+	}
+	if zoomed.previewWidth != m.width-2 {
+		t.Fatalf("expected zoomed preview width %d, got %d", m.width-2, zoomed.previewWidth)
+	}
+	if got := ansi.StringWidth(strings.Split(zoomed.renderMainContent(), "\n")[0]); got != m.width {
+		t.Fatalf("expected zoomed content to span %d columns, got %d", m.width, got)
+	}
+	if zoomed.isOnDivider(treeWidth-1, inputHeight+2) {
+		t.Fatalf("expected no divider while zoomed")
+	}
+
+	updated, _ = zoomed.handleKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	restored := updated.(Model)
+	if restored.previewZoomed {
+		t.Fatalf("expected leaving the preview to restore the split layout")
+	}
+	if restored.treeWidth != treeWidth || restored.previewWidth != splitWidth {
+		t.Fatalf("expected split widths %d/%d, got %d/%d",
+			treeWidth, splitWidth, restored.treeWidth, restored.previewWidth)
+	}
+}
+
+func TestStackedLayoutChoice(t *testing.T) {
+	tests := []struct {
+		layout        config.BrowseLayout
+		width, height int
+		want          bool
+	}{
+		{"", 120, 40, false},
+		{"", 80, 60, true},
+		{config.BrowseLayoutSide, 80, 60, false},
+		{config.BrowseLayoutStacked, 120, 40, true},
+		{config.BrowseLayoutStacked, 120, 12, false}, // Too short to stack
+	}
+	for _, tt := range tests {
+		m := NewModel(Options{Layout: tt.layout})
+		m.width, m.height = tt.width, tt.height
+		m.calculateLayout()
+		if m.stacked != tt.want {
+			t.Errorf("layout %q at %dx%d: stacked = %v, want %v", tt.layout, tt.width, tt.height, m.stacked, tt.want)
+		}
+	}
+}
+
+func TestStackedLayoutRendersAndResizes(t *testing.T) {
+	m := NewModel(Options{Layout: config.BrowseLayoutStacked})
+	m.width = 80
+	m.height = 60
+	m.calculateLayout()
+
+	lines := strings.Split(m.renderMainContent(), "\n")
+	if want := m.height - inputHeight - statusHeight - 2; len(lines) != want {
+		t.Fatalf("expected the stacked panels to fill %d rows, got %d", want, len(lines))
+	}
+	if got := ansi.StringWidth(lines[0]); got != m.width {
+		t.Fatalf("expected the tree to span %d columns, got %d", m.width, got)
+	}
+
+	// Dragging the horizontal divider moves the preview down
+	treeHeight := m.stackedTreeHeight
+	_, originY := m.previewContentOrigin()
+	dividerY := inputHeight + treeHeight + 1
+	if !m.isOnDivider(40, dividerY) || m.isOnDivider(40, dividerY+3) {
+		t.Fatalf("expected the divider at row %d", dividerY)
+	}
+	updated, _ := m.handleMouseMsg(mouse(tea.MouseActionPress, 40, dividerY))
+	updated, _ = updated.(Model).handleMouseMsg(mouse(tea.MouseActionMotion, 40, dividerY+3))
+	updated, _ = updated.(Model).handleMouseMsg(mouse(tea.MouseActionRelease, 40, dividerY+3))
+	m = updated.(Model)
+	if m.stackedTreeHeight != treeHeight+3 {
+		t.Fatalf("expected tree height %d, got %d", treeHeight+3, m.stackedTreeHeight)
+	}
+	if _, y := m.previewContentOrigin(); y != originY+3 {
+		t.Fatalf("expected the preview to start at row %d, got %d", originY+3, y)
+	}
+
+	// < and > move the divider a row at a time
+	m.focused = FocusTree
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	if got := updated.(Model).stackedTreeHeight; got != treeHeight+2 {
+		t.Fatalf("expected < to shrink the tree to %d rows, got %d", treeHeight+2, got)
+	}
 }
 
 func TestRecentEnterSetsAttachSessionAndReviveDir(t *testing.T) {
@@ -404,6 +569,7 @@ func TestMouseClickIconTogglesExpand(t *testing.T) {
 	m := NewModel(Options{})
 	m.width = 120
 	m.height = 40
+	m.calculateLayout()
 	m.tree = &tmux.Tree{
 		Sessions: []tmux.TmuxSession{
 			{
@@ -432,9 +598,10 @@ func TestMouseClickIconTogglesExpand(t *testing.T) {
 		t.Fatalf("expected 3 nodes when expanded, got %d", len(m.flatNodes))
 	}
 
-	// Tree content starts at inputHeight + 2 (input bar + tree border + padding)
+	// Tree content starts at inputHeight + 2 (input bar + tree border + padding);
+	// the icon follows the jump-number gutter
 	y := inputHeight + 2
-	updated, _ := m.handleLeftClick(0, y)
+	updated, _ := m.handleLeftClick(m.jumpGutterWidth(), y)
 	m = updated.(Model)
 
 	if len(m.flatNodes) != 1 {
@@ -485,7 +652,7 @@ func TestMouseClickIconTogglesHostExpand(t *testing.T) {
 
 	// Click the first host's `[-]` icon.
 	y := inputHeight + 2
-	updated, _ := m.handleLeftClick(2, y)
+	updated, _ := m.handleLeftClick(m.jumpGutterWidth(), y)
 	m = updated.(Model)
 
 	if len(m.flatNodes) >= initialCount {
@@ -506,3 +673,1104 @@ func TestMouseClickIconTogglesHostExpand(t *testing.T) {
 		t.Fatal("expected devbox host to remain visible after collapsing local host")
 	}
 }
+
+func TestRevealPaneExpandsCollapsedHost(t *testing.T) {
+	m := NewModel(Options{})
+	m.hostTrees = []tmux.HostTree{
+		{
+			Host: "",
+			Tree: &tmux.Tree{
+				Sessions: []tmux.TmuxSession{
+					{Name: "s1", Windows: []tmux.Window{{Index: 0, Name: "w1",
+						Panes: []tmux.Pane{{Index: 0, Target: "s1:0.0"}}}}},
+				},
+			},
+		},
+		{
+			Host: "devbox",
+			Tree: &tmux.Tree{
+				Sessions: []tmux.TmuxSession{
+					{Name: "s2", Windows: []tmux.Window{{Index: 1, Name: "w2",
+						Panes: []tmux.Pane{{Index: 0, Target: "s2:1.0"}, {Index: 1, Target: "s2:1.1"}}}}},
+				},
+			},
+		},
+	}
+	m.tree = &tmux.Tree{}
+	m.expanded[nodeKey("host", "host:devbox")] = false
+	m.expanded[nodeKey("window", "devbox/s2:1")] = false
+	m.rebuildFlatNodes()
+
+	m.revealPane("devbox", "s2:1.1")
+
+	node := m.selectedNode()
+	if node == nil || node.Type != "pane" || node.Target != "s2:1.1" || node.Host != "devbox" {
+		t.Fatalf("expected devbox pane s2:1.1 selected, got %+v", node)
+	}
+	if m.focused != FocusTree {
+		t.Fatalf("expected tree focus after reveal, got %v", m.focused)
+	}
+}
+
+func TestSearchResultsIgnoredForStaleQuery(t *testing.T) {
+	m := NewModel(Options{})
+	m.search = newPaneSearch()
+	m.search.query = "current"
+	m.search.searching = true
+
+	updated, _ := m.Update(PaneSearchResultsMsg{Query: "old", Matches: []tmux.PaneMatch{{Target: "a:0.0"}}})
+	um := updated.(Model)
+	if !um.search.searching || len(um.search.results) != 0 {
+		t.Fatal("expected stale search results to be ignored")
+	}
+
+	updated, _ = um.Update(PaneSearchResultsMsg{Query: "current", Matches: []tmux.PaneMatch{{Target: "a:0.0"}}})
+	um = updated.(Model)
+	if um.search.searching || len(um.search.results) != 1 {
+		t.Fatal("expected current search results to be applied")
+	}
+}
+
+func TestCachedTreeIgnoredAfterLiveFetch(t *testing.T) {
+	m := NewModel(Options{})
+	live := []tmux.HostTree{{Host: "devbox", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "live"}}}}}
+	cached := []tmux.HostTree{{Host: "devbox", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "old"}}}, CachedAt: time.Now()}}
+
+	updated, _ := m.Update(MultiTreeRefreshedMsg{HostTrees: live})
+	updated, _ = updated.(Model).Update(MultiTreeRefreshedMsg{HostTrees: cached, Cached: true})
+	um := updated.(Model)
+
+	if um.tree == nil || len(um.tree.Sessions) != 1 || um.tree.Sessions[0].Name != "live" {
+		t.Fatalf("expected live tree to win over cache, got %+v", um.tree)
+	}
+	if !um.hostCachedAt("devbox").IsZero() {
+		t.Fatal("expected live host tree to have no cache timestamp")
+	}
+}
+
+func TestTreeCacheRewrittenOnlyWhenATreeChanges(t *testing.T) {
+	m := NewModel(Options{})
+	round := func(name string) []tmux.HostTree {
+		return []tmux.HostTree{
+			{Host: "", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: name}}}},
+			{Host: "devbox", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "old"}}}, CachedAt: time.Now()},
+			{Host: "down", Err: errors.New("connection refused")},
+		}
+	}
+
+	m.hostTrees = round("api")
+	if !m.treeCacheChanged() {
+		t.Fatal("expected the first live round to be written")
+	}
+	m.hostTrees = round("api")
+	if m.treeCacheChanged() {
+		t.Fatal("expected an unchanged round not to be written")
+	}
+	m.hostTrees = round("web")
+	if !m.treeCacheChanged() {
+		t.Fatal("expected a changed tree to be written")
+	}
+	if _, ok := m.cachedTrees["devbox"]; ok {
+		t.Fatal("expected cached and failed hosts not to count as written")
+	}
+}
+
+func TestPendingHostsShownBeforeFirstFetch(t *testing.T) {
+	remote := tmux.NewRemoteExecutor("devbox", 0, "", "")
+	m := NewModel(Options{Executors: []tmux.TmuxExecutor{tmux.NewLocalExecutor(), remote}})
+
+	// local host, connecting…, devbox host, connecting…
+	if len(m.flatNodes) != 4 {
+		t.Fatalf("expected 4 pending nodes, got %d", len(m.flatNodes))
+	}
+	if m.flatNodes[2].Type != "host" || m.flatNodes[2].Name != "devbox" {
+		t.Fatalf("expected devbox host node, got %s:%s", m.flatNodes[2].Type, m.flatNodes[2].Name)
+	}
+	if m.flatNodes[3].Name != "connecting…" {
+		t.Fatalf("expected connecting node, got %q", m.flatNodes[3].Name)
+	}
+
+	updated, _ := m.Update(MultiTreeRefreshedMsg{HostTrees: []tmux.HostTree{
+		{Host: "", Tree: &tmux.Tree{}, Latency: 5 * time.Millisecond},
+		{Host: "devbox", Err: errors.New("connection refused")},
+	}})
+	um := updated.(Model)
+	if um.hostLatency["local"] != 5*time.Millisecond {
+		t.Fatalf("expected local latency recorded, got %v", um.hostLatency["local"])
+	}
+	if um.hostErrors["devbox"] == nil {
+		t.Fatal("expected devbox error recorded")
+	}
+	for _, n := range um.flatNodes {
+		if n.Name == "connecting…" {
+			t.Fatal("expected no pending nodes after live fetch")
+		}
+	}
+}
+
+func TestHostTreesRenderAsEachHostAnswers(t *testing.T) {
+	remote := tmux.NewRemoteExecutor("devbox", 0, "", "")
+	m := NewModel(Options{
+		Executors:        []tmux.TmuxExecutor{tmux.NewLocalExecutor(), remote},
+		RefreshInterval:  time.Second,
+		DisableTreeCache: true,
+	})
+	m.treeFetching[""] = true
+	m.treeFetching["devbox"] = true
+
+	local := tmux.HostTree{Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "work"}}}}
+	updated, cmd := m.Update(HostTreeRefreshedMsg{HostTree: local})
+	um := updated.(Model)
+	if cmd != nil {
+		t.Fatal("expected no refresh tick until every host has answered")
+	}
+	var names []string
+	for _, n := range um.flatNodes {
+		names = append(names, n.Name)
+	}
+	if len(names) != 4 || names[1] != "work" || names[3] != "connecting…" {
+		t.Fatalf("expected local sessions shown while devbox connects, got %q", names)
+	}
+
+	// Another refresh while devbox is still waiting out its timeout leaves
+	// it alone
+	um.fetchTreeCmd()
+	if !um.treeFetching[""] || !um.treeFetching["devbox"] {
+		t.Fatalf("expected both hosts in flight, got %v", um.treeFetching)
+	}
+	delete(um.treeFetching, "")
+
+	timedOut := tmux.HostTree{Host: "devbox", Err: fmt.Errorf("devbox: %w", context.DeadlineExceeded)}
+	updated, cmd = um.Update(HostTreeRefreshedMsg{HostTree: timedOut})
+	um = updated.(Model)
+	if cmd == nil || !um.liveTree {
+		t.Fatal("expected the round to finish once devbox answered")
+	}
+	if last := um.flatNodes[len(um.flatNodes)-1]; last.Name != "unreachable: timed out (retrying 1/3)" {
+		t.Fatalf("expected devbox shown as timed out, got %q", last.Name)
+	}
+	if um.tree == nil || len(um.tree.Sessions) != 1 {
+		t.Fatalf("expected local sessions kept, got %+v", um.tree)
+	}
+}
+
+func TestSessionsListShowsTimedOutHost(t *testing.T) {
+	remote := tmux.NewRemoteExecutor("devbox", 0, "", "")
+	m := sessionsModel{width: 100, height: 30, executors: []tmux.TmuxExecutor{tmux.NewLocalExecutor(), remote}, pendingExecutors: 2}
+
+	updated, _ := m.Update(executorSessionsMsg{lines: []tmux.SessionLine{{Name: "work", Line: "work: 1 windows"}}})
+	updated, _ = updated.(sessionsModel).Update(executorSessionsMsg{host: "devbox", err: fmt.Errorf("devbox: %w", context.DeadlineExceeded)})
+	view := ansi.Strip(updated.(sessionsModel).View())
+	if !strings.Contains(view, "work") || !strings.Contains(view, "devbox: timed out") {
+		t.Fatalf("expected local sessions and the timed out host, got:\n%s", view)
+	}
+}
+
+func TestSessionsListNamesPendingHosts(t *testing.T) {
+	executors := []tmux.TmuxExecutor{
+		tmux.NewLocalExecutor(),
+		tmux.NewRemoteExecutor("devbox", 0, "", ""),
+		tmux.NewRemoteExecutor("build-server", 0, "", ""),
+	}
+	m := sessionsModel{width: 100, height: 30, executors: executors, pendingExecutors: 3, spinner: spinner.New()}
+
+	updated, _ := m.Update(executorSessionsMsg{lines: []tmux.SessionLine{{Name: "work", Line: "work: 1 windows"}}})
+	view := ansi.Strip(updated.(sessionsModel).View())
+	if !strings.Contains(view, "Waiting for devbox, build-server") {
+		t.Fatalf("expected the pending hosts by name, got:\n%s", view)
+	}
+
+	updated, _ = updated.(sessionsModel).Update(executorSessionsMsg{host: "devbox"})
+	updated, _ = updated.(sessionsModel).Update(executorSessionsMsg{host: "build-server"})
+	if view := ansi.Strip(updated.(sessionsModel).View()); strings.Contains(view, "Waiting for") {
+		t.Fatalf("expected no loading row once every host answered, got:\n%s", view)
+	}
+	// With nothing pending the spinner stops ticking
+	if _, cmd := updated.(sessionsModel).Update(updated.(sessionsModel).spinner.Tick()); cmd != nil {
+		t.Fatal("expected the spinner to stop")
+	}
+}
+
+func TestNewSessionKeyCreatesForWorkingDir(t *testing.T) {
+	m := NewModel(Options{SessionName: "agent-proj", WorkingDir: "/tmp/proj"})
+	m.tree = &tmux.Tree{}
+
+	updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	um := updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+	if um.attachSession != "agent-proj" || um.reviveDir != "/tmp/proj" {
+		t.Fatalf("expected new session for /tmp/proj, got session=%q dir=%q", um.attachSession, um.reviveDir)
+	}
+}
+
+func TestNewSessionKeyAsksForTemplate(t *testing.T) {
+	m := NewModel(Options{SessionName: "agent-proj", WorkingDir: "/tmp/proj", Templates: []string{"frontend", "backend"}})
+	m.tree = &tmux.Tree{}
+
+	updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	um := updated.(Model)
+	if cmd != nil || um.templatePicker == nil {
+		t.Fatal("expected template picker before creating the session")
+	}
+
+	updated, _ = um.handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.(Model).handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd = updated.(Model).handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	um = updated.(Model)
+	if cmd == nil || um.templatePicker != nil {
+		t.Fatal("expected picker to close and quit")
+	}
+	if um.sessionTemplate != "backend" || um.attachSession != "agent-proj" || um.reviveDir != "/tmp/proj" {
+		t.Fatalf("expected backend session for /tmp/proj, got template=%q session=%q dir=%q",
+			um.sessionTemplate, um.attachSession, um.reviveDir)
+	}
+}
+
+func TestNewSessionKeyOffersAttachWhenExists(t *testing.T) {
+	m := NewModel(Options{SessionName: "agent-proj", WorkingDir: "/tmp/proj"})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "agent-proj"}}}
+
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	um := updated.(Model)
+	if !um.confirmNewSession {
+		t.Fatal("expected attach prompt for existing session")
+	}
+	if um.attachSession != "" {
+		t.Fatalf("expected no attach before confirmation, got %q", um.attachSession)
+	}
+
+	updated, _ = um.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	um = updated.(Model)
+	if um.attachSession != "agent-proj" || um.reviveDir != "" {
+		t.Fatalf("expected attach to existing session, got session=%q dir=%q", um.attachSession, um.reviveDir)
+	}
+}
+
+func TestMobileTabCyclesButtonFocus(t *testing.T) {
+	m := NewModel(Options{MobileMode: true})
+
+	updated, _ := m.handleMobileKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	um := updated.(Model)
+	if um.mobileFocus != MobileButtonKill {
+		t.Fatalf("expected focus on Kill, got %d", um.mobileFocus)
+	}
+
+	updated, _ = um.handleMobileKeyMsg(tea.KeyMsg{Type: tea.KeyShiftTab})
+	updated, _ = updated.(Model).handleMobileKeyMsg(tea.KeyMsg{Type: tea.KeyShiftTab})
+	um = updated.(Model)
+	if um.mobileFocus != MobileButtonNew {
+		t.Fatalf("expected focus to wrap to New, got %d", um.mobileFocus)
+	}
+}
+
+func TestMobileButtonTapHitsRenderedButton(t *testing.T) {
+	m := NewModel(Options{MobileMode: true})
+	m.width = 40
+	m.height = 20
+
+	bar := m.renderMobileButtonBar()
+	for _, want := range []MobileButton{MobileButtonAttach, MobileButtonKill, MobileButtonNew} {
+		col := strings.Index(ansi.Strip(strings.Split(bar, "\n")[1]), mobileButtonLabels[want])
+		if col < 0 {
+			t.Fatalf("label %q not found in button bar", mobileButtonLabels[want])
+		}
+		got, ok := m.mobileButtonAt(col)
+		if !ok || got != want {
+			t.Fatalf("tap at column %d: expected button %d, got %d (ok=%v)", col, want, got, ok)
+		}
+	}
+	if _, ok := m.mobileButtonAt(0); ok {
+		t.Fatal("expected no button at the left edge")
+	}
+}
+
+func TestMobileExpandSessionAttachesWindow(t *testing.T) {
+	m := NewModel(Options{MobileMode: true})
+	m.tree = &tmux.Tree{
+		Sessions: []tmux.TmuxSession{
+			{Name: "alpha", Windows: []tmux.Window{{Index: 0, Name: "editor"}, {Index: 2, Name: "logs"}}},
+			{Name: "beta", Windows: []tmux.Window{{Index: 0, Name: "main"}}},
+		},
+	}
+
+	updated, _ := m.handleMobileKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	um := updated.(Model)
+	if rows := um.mobileRows(); len(rows) != 4 {
+		t.Fatalf("expected 4 rows after expanding alpha, got %d", len(rows))
+	}
+
+	um.moveMobileSelection(2)
+	updated, cmd := um.handleMobileKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	um = updated.(Model)
+	if cmd == nil || um.attachSession != "alpha" || um.attachTarget != "alpha:2" {
+		t.Fatalf("expected attach to alpha:2, got %q at %q", um.attachSession, um.attachTarget)
+	}
+}
+
+func TestMobileCollapseFromWindowSelectsSession(t *testing.T) {
+	m := NewModel(Options{MobileMode: true})
+	m.tree = &tmux.Tree{
+		Sessions: []tmux.TmuxSession{
+			{Name: "alpha", Windows: []tmux.Window{{Index: 0, Name: "editor"}}},
+			{Name: "beta", Windows: []tmux.Window{{Index: 0, Name: "main"}}},
+		},
+	}
+	m.mobileExpanded["beta"] = true
+	m.selectedIndex = 2 // beta:0
+
+	updated, _ := m.handleMobileKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	um := updated.(Model)
+	if um.selectedIndex != 1 {
+		t.Fatalf("expected selection on beta session row, got %d", um.selectedIndex)
+	}
+	if len(um.mobileRows()) != 2 {
+		t.Fatalf("expected beta collapsed, got %d rows", len(um.mobileRows()))
+	}
+}
+
+func TestSkipKillConfirmKillsImmediately(t *testing.T) {
+	m := NewModel(Options{SkipKillConfirm: true})
+	m.tree = &tmux.Tree{
+		Sessions: []tmux.TmuxSession{
+			{Name: "idle", Windows: []tmux.Window{{Index: 0, Name: "main"}}},
+			{Name: "current", Attached: true, Windows: []tmux.Window{{Index: 0, Name: "main"}}},
+		},
+	}
+	m.rebuildFlatNodes()
+
+	m.selectedIndex = nodeIndex(t, m, "session", "idle")
+	updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if updated.(Model).confirmKill || cmd == nil {
+		t.Fatal("expected immediate kill without confirmation")
+	}
+
+	m.selectedIndex = nodeIndex(t, m, "session", "current")
+	updated, cmd = m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !updated.(Model).confirmKill || cmd != nil {
+		t.Fatal("expected attached session to still require confirmation")
+	}
+}
+
+func TestKillConfirmShowsWhatItDestroys(t *testing.T) {
+	m := NewModel(Options{})
+	m.width, m.height = 100, 30
+	sess := windowWithPanes("work", 3)
+	sess.Windows[0].Panes[1].Command = "claude"
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{sess}}
+	m.rebuildFlatNodes()
+	base := strings.Repeat(strings.Repeat(" ", m.width)+"\n", m.height)
+
+	m.selectedIndex = nodeIndex(t, m, "session", "work")
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	view := updated.(Model).renderKillConfirmOverlay(base)
+	for _, want := range []string{"Destroys 1 window, 3 panes", "1 running agent(s):", "claude in work:0.1"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the confirmation, got:\n%s", want, view)
+		}
+	}
+
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.2")
+	updated, _ = m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if view := updated.(Model).renderKillConfirmOverlay(base); !strings.Contains(view, "Destroys 1 pane") || strings.Contains(view, "agent") {
+		t.Fatalf("expected a single idle pane, got:\n%s", view)
+	}
+}
+
+func TestSessionsKillConfirmShowsPanesOnceListed(t *testing.T) {
+	m := sessionsModel{width: 80, height: 20, lines: []tmux.SessionLine{{Name: "work", Windows: 2}}}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(sessionsModel)
+	if !m.confirmKill || cmd == nil || !strings.Contains(m.View(), "Destroys 2 windows") {
+		t.Fatalf("expected the window count while panes load, got:\n%s", m.View())
+	}
+
+	updated, _ = m.Update(killImpactMsg{sessionName: "work", impact: tmux.KillImpact{Windows: 2, Panes: 4, Agents: []string{"codex in work:1.0"}}})
+	view := updated.(sessionsModel).View()
+	if !strings.Contains(view, "Destroys 2 windows, 4 panes") || !strings.Contains(view, "codex in work:1.0") {
+		t.Fatalf("expected the listed panes and agent, got:\n%s", view)
+	}
+}
+
+func TestPreviewTimerCapturesWithoutOverlapping(t *testing.T) {
+	m := NewModel(Options{RefreshInterval: 5 * time.Second, PreviewRefreshInterval: time.Second})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 2)}}
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.1")
+	if m.treePreviewCmd() != nil {
+		t.Fatal("expected tree refreshes to leave the preview to its own timer")
+	}
+	m.updatePreviewForSelection()
+
+	updated, cmd := m.Update(PreviewTickMsg{})
+	m = updated.(Model)
+	if !m.previewFetching || cmd == nil {
+		t.Fatal("expected the tick to capture the previewed pane")
+	}
+	updated, cmd = m.Update(PreviewTickMsg{})
+	if m = updated.(Model); !m.previewFetching || cmd == nil {
+		t.Fatal("expected the next tick to skip the capture but keep ticking")
+	}
+	updated, _ = m.Update(PreviewUpdatedMsg{Target: "work:0.1", Content: "done"})
+	if updated.(Model).previewFetching {
+		t.Fatal("expected the finished capture to let the next tick through")
+	}
+
+	m.options.PreviewRefreshInterval = 0
+	if m.treePreviewCmd() == nil {
+		t.Fatal("expected the preview to refresh with the tree by default")
+	}
+}
+
+func TestRefreshTicksNeverStackOrRunWhenOff(t *testing.T) {
+	m := NewModel(Options{RefreshInterval: time.Second, PreviewRefreshInterval: time.Second})
+	tree := &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 1)}}
+
+	updated, _ := m.Update(TreeRefreshedMsg{Tree: tree})
+	m = updated.(Model)
+	if !m.tickArmed {
+		t.Fatal("expected a refresh to arm the next tick")
+	}
+	// A manual refresh while a tick is pending must not start a second chain
+	if m.armRefreshTick() != nil {
+		t.Fatal("expected no second tick while one is pending")
+	}
+	updated, _ = m.Update(TickMsg{})
+	if updated.(Model).tickArmed {
+		t.Fatal("expected the tick to disarm until the refresh lands")
+	}
+
+	off := NewModel(Options{PreviewRefreshInterval: time.Second})
+	updated, _ = off.Update(TreeRefreshedMsg{Tree: tree})
+	off = updated.(Model)
+	if off.tickArmed || off.armRefreshTick() != nil || off.previewInterval() != 0 {
+		t.Fatal("expected no ticks of any kind with auto-refresh off")
+	}
+	if !strings.Contains(off.renderStatusBar(), "Manual refresh: r") {
+		t.Fatal("expected the status bar to show manual refresh mode")
+	}
+}
+
+func nodeIndex(t *testing.T, m Model, nodeType, target string) int {
+	t.Helper()
+	for i, node := range m.flatNodes {
+		if node.Type == nodeType && node.Target == target {
+			return i
+		}
+	}
+	t.Fatalf("node %s %q not found", nodeType, target)
+	return -1
+}
+
+func TestSessionsReadOnlyKeySelectsActiveSession(t *testing.T) {
+	m := sessionsModel{
+		lines: []tmux.SessionLine{{Name: "agent-one"}, {Name: "agent-two"}},
+	}
+	m.selectedIndex = 1
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	um := updated.(sessionsModel)
+	if cmd == nil || um.attachSession != "agent-two" || !um.readOnly {
+		t.Fatalf("expected read-only attach to agent-two, got session=%q readOnly=%v", um.attachSession, um.readOnly)
+	}
+}
+
+func TestSessionsSortKeyCyclesAndPersists(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := sessionsModel{
+		sortMode: config.SessionSortActivity,
+		lines: []tmux.SessionLine{
+			{Name: "bravo", Activity: 30, Created: 1, Windows: 1, LastAttached: 50},
+			{Name: "delta", Host: "devbox", Activity: 40, Created: 4, Windows: 2},
+			{Name: "alpha", Activity: 20, Created: 3, Windows: 5},
+			{Name: "charlie", Host: "devbox", Activity: 10, Created: 2, Windows: 3, LastAttached: 60},
+		},
+	}
+	m.lines = groupSessionsByHost(m.lines)
+	m.selectedIndex = 1 // alpha
+
+	names := func(m sessionsModel) string {
+		var out []string
+		for _, line := range m.lines {
+			out = append(out, line.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	// Local sessions stay grouped ahead of each remote host's sessions
+	for _, want := range []struct {
+		mode  config.SessionSort
+		order string
+	}{
+		{config.SessionSortName, "alpha,bravo,charlie,delta"},
+		{config.SessionSortCreated, "alpha,bravo,delta,charlie"},
+		{config.SessionSortWindows, "alpha,bravo,charlie,delta"},
+		{config.SessionSortAttached, "bravo,alpha,charlie,delta"}, // Never-attached last
+		{config.SessionSortActivity, "bravo,alpha,delta,charlie"},
+	} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+		m = updated.(sessionsModel)
+		if m.sortMode != want.mode || names(m) != want.order {
+			t.Fatalf("expected %s order %s, got %s order %s", want.mode, want.order, m.sortMode, names(m))
+		}
+		if m.lines[m.selectedIndex].Name != "alpha" {
+			t.Fatalf("expected selection to follow alpha, got %s", m.lines[m.selectedIndex].Name)
+		}
+	}
+
+	settings, err := config.LoadSettings()
+	if err != nil || settings.SessionsSort != config.SessionSortActivity {
+		t.Fatalf("expected sort order to be saved, got %+v (err %v)", settings, err)
+	}
+}
+
+func TestSessionsPinKeepsSessionFirstInItsGroup(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := sessionsModel{
+		sortMode: config.SessionSortActivity,
+		lines: []tmux.SessionLine{
+			{Name: "bravo", Activity: 30},
+			{Name: "alpha", Activity: 20},
+			{Name: "delta", Host: "devbox", Activity: 40},
+			{Name: "charlie", Host: "devbox", Activity: 10},
+		},
+	}
+	names := func(m sessionsModel) string {
+		var out []string
+		for _, line := range m.lines {
+			out = append(out, line.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	press := func(m sessionsModel, index int) sessionsModel {
+		m.selectedIndex = index
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+		return updated.(sessionsModel)
+	}
+
+	m = press(m, 1) // alpha
+	m = press(m, 3) // charlie
+	if names(m) != "alpha,bravo,charlie,delta" || m.lines[m.selectedIndex].Name != "charlie" {
+		t.Fatalf("expected pinned sessions first in each host group, got %s", names(m))
+	}
+	if row, _ := m.renderActiveSessionRow(0, m.lines[0], 1); !strings.Contains(row, pinGlyph) {
+		t.Fatal("expected a pin glyph on pinned sessions")
+	}
+	settings, err := config.LoadSettings()
+	if err != nil || strings.Join(settings.PinnedSessions, ",") != "alpha,devbox/charlie" {
+		t.Fatalf("expected pins to be saved, got %v (err %v)", settings.PinnedSessions, err)
+	}
+
+	// Pins survive a reload, and unpinning restores activity order
+	m.pinned = pinnedSet(settings.PinnedSessions)
+	m = press(m, 0)
+	if names(m) != "bravo,alpha,charlie,delta" {
+		t.Fatalf("expected alpha back in activity order, got %s", names(m))
+	}
+}
+
+func TestFuzzyScoreRanksSubstringFirst(t *testing.T) {
+	if _, ok := fuzzyScore("kss", "refresh tree"); ok {
+		t.Fatal("expected no match for out-of-order characters")
+	}
+	sub, ok := fuzzyScore("kill", "kill session")
+	if !ok {
+		t.Fatal("expected substring match")
+	}
+	seq, ok := fuzzyScore("ksn", "kill session")
+	if !ok {
+		t.Fatal("expected subsequence match")
+	}
+	if sub >= seq {
+		t.Fatalf("expected substring score %d to rank before subsequence score %d", sub, seq)
+	}
+}
+
+func TestCommandPaletteRunsNodeAction(t *testing.T) {
+	m := NewModel(Options{})
+	m.tree = &tmux.Tree{
+		Sessions: []tmux.TmuxSession{{Name: "alpha", Windows: []tmux.Window{{Index: 0, Name: "main"}}}},
+	}
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "session", "alpha")
+
+	updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlP})
+	um := updated.(Model)
+	if um.palette == nil {
+		t.Fatal("expected palette to open")
+	}
+	for _, r := range "kill" {
+		updated, _ = um.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		um = updated.(Model)
+	}
+	if len(um.palette.filtered) == 0 || um.palette.filtered[0].Action != MenuActionKillSession {
+		t.Fatalf("expected kill session as top match, got %+v", um.palette.filtered)
+	}
+
+	updated, _ = um.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	um = updated.(Model)
+	if um.palette != nil || !um.confirmKill || um.killNodeTarget != "alpha" {
+		t.Fatalf("expected kill confirmation for alpha, got palette=%v confirm=%v target=%q", um.palette != nil, um.confirmKill, um.killNodeTarget)
+	}
+}
+
+func TestTreePagingScrollsSelectionIntoView(t *testing.T) {
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 20
+	m.calculateLayout()
+	var sessions []tmux.TmuxSession
+	for i := 0; i < 30; i++ {
+		sessions = append(sessions, tmux.TmuxSession{Name: fmt.Sprintf("sess-%02d", i)})
+	}
+	m.tree = &tmux.Tree{Sessions: sessions}
+	m.rebuildFlatNodes()
+	page := m.treeViewHeight()
+
+	key := func(k tea.KeyType) {
+		updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: k})
+		m = updated.(Model)
+	}
+
+	key(tea.KeyPgDown)
+	if m.selectedIndex != page {
+		t.Fatalf("expected PgDn to move %d rows, got %d", page, m.selectedIndex)
+	}
+	if m.treeScroll == 0 || !strings.Contains(ansi.Strip(m.renderTree()), "sess-"+fmt.Sprintf("%02d", page)) {
+		t.Fatal("expected the tree to scroll the selection into view")
+	}
+
+	key(tea.KeyEnd)
+	if m.selectedIndex != 29 || m.treeScroll != 30-page {
+		t.Fatalf("expected End to select the last node, got index %d scroll %d", m.selectedIndex, m.treeScroll)
+	}
+	// Clicking the first visible row selects the scrolled node, not node 0
+	updated, _ := m.handleLeftClick(2, inputHeight+2)
+	m = updated.(Model)
+	if m.selectedIndex != m.treeScroll {
+		t.Fatalf("expected click to select node %d, got %d", m.treeScroll, m.selectedIndex)
+	}
+
+	key(tea.KeyHome)
+	if m.selectedIndex != 0 || m.treeScroll != 0 {
+		t.Fatalf("expected Home to select the first node, got index %d scroll %d", m.selectedIndex, m.treeScroll)
+	}
+	key(tea.KeyPgUp)
+	if m.selectedIndex != 0 {
+		t.Fatalf("expected PgUp at the top to stay put, got %d", m.selectedIndex)
+	}
+}
+
+func TestSessionsPagingSpansActiveAndHistory(t *testing.T) {
+	m := sessionsModel{height: 10}
+	for i := 0; i < 4; i++ {
+		m.lines = append(m.lines, tmux.SessionLine{Name: fmt.Sprintf("active-%d", i)})
+	}
+	for i := 0; i < 8; i++ {
+		m.historyEntries = append(m.historyEntries, history.Entry{ID: int64(i), Name: fmt.Sprintf("recent-%d", i)})
+	}
+
+	press := func(k tea.KeyType) {
+		updated, _ := m.Update(tea.KeyMsg{Type: k})
+		m = updated.(sessionsModel)
+	}
+
+	press(tea.KeyPgDown)
+	if m.selectedIndex != m.pageSize() {
+		t.Fatalf("expected PgDn to move %d rows, got %d", m.pageSize(), m.selectedIndex)
+	}
+	press(tea.KeyEnd)
+	if m.selectedIndex != 11 {
+		t.Fatalf("expected End to select the last recent entry, got %d", m.selectedIndex)
+	}
+	press(tea.KeyPgDown)
+	if m.selectedIndex != 11 {
+		t.Fatalf("expected PgDn at the end to stay put, got %d", m.selectedIndex)
+	}
+	press(tea.KeyHome)
+	if m.selectedIndex != 0 {
+		t.Fatalf("expected Home to select the first session, got %d", m.selectedIndex)
+	}
+}
+
+func TestAdjacentGroupStartWraps(t *testing.T) {
+	starts := []int{0, 4, 9}
+	tests := []struct{ current, dir, want int }{
+		{0, 1, 4}, {5, 1, 9}, {9, 1, 0}, {12, 1, 0},
+		{5, -1, 4}, {4, -1, 0}, {0, -1, 9},
+	}
+	for _, tt := range tests {
+		if got, ok := adjacentGroupStart(starts, tt.current, tt.dir); !ok || got != tt.want {
+			t.Errorf("adjacentGroupStart(%d, %d) = %d, want %d", tt.current, tt.dir, got, tt.want)
+		}
+	}
+	if _, ok := adjacentGroupStart(nil, 0, 1); ok {
+		t.Error("expected no jump without groups")
+	}
+}
+
+func TestTreeBracketsJumpBetweenHosts(t *testing.T) {
+	m := NewModel(Options{})
+	m.hostTrees = []tmux.HostTree{
+		{Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "a"}, {Name: "b"}}}},
+		{Host: "devbox", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{{Name: "c"}}}},
+	}
+	m.tree = &tmux.Tree{}
+	m.rebuildFlatNodes()
+	m.selectedIndex = 1 // session a
+
+	press := func(key string) {
+		updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+	press("]")
+	if node := m.selectedNode(); node == nil || node.Type != "host" || node.Host != "devbox" {
+		t.Fatalf("expected ] to select the devbox header, got %+v", node)
+	}
+	press("]")
+	if m.selectedIndex != 0 {
+		t.Fatalf("expected ] to wrap to the first host, got %d", m.selectedIndex)
+	}
+	press("[")
+	if node := m.selectedNode(); node == nil || node.Host != "devbox" {
+		t.Fatalf("expected [ to wrap to the last host, got %+v", node)
+	}
+}
+
+func TestSessionsBracketsJumpBetweenHosts(t *testing.T) {
+	m := sessionsModel{height: 20, lines: []tmux.SessionLine{
+		{Name: "a"}, {Name: "b"}, {Name: "c", Host: "devbox"}, {Name: "d", Host: "build"},
+	}}
+
+	press := func(key string) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(sessionsModel)
+	}
+	press("]")
+	press("]")
+	if m.selectedIndex != 3 {
+		t.Fatalf("expected ] twice to reach build's first session, got %d", m.selectedIndex)
+	}
+	press("]")
+	if m.selectedIndex != 0 {
+		t.Fatalf("expected ] to wrap to the first host, got %d", m.selectedIndex)
+	}
+	press("[")
+	if m.selectedIndex != 3 {
+		t.Fatalf("expected [ to wrap to the last host, got %d", m.selectedIndex)
+	}
+}
+
+func TestCollapseAllKeepsSelectionVisible(t *testing.T) {
+	m := NewModel(Options{})
+	sess := func(name string) tmux.TmuxSession {
+		return tmux.TmuxSession{Name: name, Windows: []tmux.Window{{Index: 0, Name: "zsh",
+			Panes: []tmux.Pane{{Index: 0, Target: name + ":0.0", Title: "zsh"}}}}}
+	}
+	m.hostTrees = []tmux.HostTree{
+		{Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{sess("a")}}},
+		{Host: "devbox", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{sess("b")}}},
+	}
+	m.tree = &tmux.Tree{}
+	m.rebuildFlatNodes()
+	m.selectedIndex = len(m.flatNodes) - 1 // devbox pane b:0.0
+
+	press := func(key string) {
+		updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+	press("-")
+	if len(m.flatNodes) != 2 {
+		t.Fatalf("expected only the host headers, got %d nodes", len(m.flatNodes))
+	}
+	if node := m.selectedNode(); node.Type != "host" || node.Host != "devbox" {
+		t.Fatalf("expected the selection to move up to devbox, got %+v", node)
+	}
+
+	press("+")
+	if len(m.flatNodes) != 8 {
+		t.Fatalf("expected every node after expand all, got %d", len(m.flatNodes))
+	}
+	if node := m.selectedNode(); node.Type != "host" || node.Host != "devbox" {
+		t.Fatalf("expected devbox to stay selected, got %+v", node)
+	}
+}
+
+func TestCollapseAllSingleHostLeavesSessions(t *testing.T) {
+	m := NewModel(Options{})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{
+		{Name: "a", Windows: []tmux.Window{{Index: 0, Panes: []tmux.Pane{{Target: "a:0.0"}}}}},
+		{Name: "b", Windows: []tmux.Window{{Index: 0, Panes: []tmux.Pane{{Target: "b:0.0"}}}}},
+	}}
+	m.rebuildFlatNodes()
+	m.selectedIndex = len(m.flatNodes) - 1 // pane b:0.0
+
+	m.setAllExpanded(false)
+	if len(m.flatNodes) != 2 || m.selectedNode().Target != "b" {
+		t.Fatalf("expected sessions only with b selected, got %d nodes, selected %+v", len(m.flatNodes), m.selectedNode())
+	}
+
+	// Expanding a session after collapse all shows its windows collapsed
+	m.toggleExpand()
+	if len(m.flatNodes) != 3 {
+		t.Fatalf("expected b's window without its panes, got %d nodes", len(m.flatNodes))
+	}
+}
+
+func TestTreeDigitJumpNumbersVisibleNodes(t *testing.T) {
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 20
+	m.calculateLayout()
+	var sessions []tmux.TmuxSession
+	for i := 0; i < 30; i++ {
+		sessions = append(sessions, tmux.TmuxSession{Name: fmt.Sprintf("sess-%02d", i)})
+	}
+	m.tree = &tmux.Tree{Sessions: sessions}
+	m.rebuildFlatNodes()
+	digit := func(r rune) {
+		updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+
+	digit('3')
+	if m.selectedIndex != 2 {
+		t.Fatalf("expected 3 to select the third node, got %d", m.selectedIndex)
+	}
+	digit('1')
+	digit('2')
+	if m.selectedIndex != 11 {
+		t.Fatalf("expected 12 to select the twelfth node, got %d", m.selectedIndex)
+	}
+
+	// Numbers follow the scrolled view rather than the whole tree
+	m.lineJump = lineJumpState{}
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyEnd})
+	m = updated.(Model)
+	digit('1')
+	if m.selectedIndex != m.treeScroll {
+		t.Fatalf("expected 1 to select the first visible node %d, got %d", m.treeScroll, m.selectedIndex)
+	}
+	if !strings.Contains(ansi.Strip(m.renderTree()), " 1 ") {
+		t.Fatal("expected jump numbers next to visible nodes")
+	}
+}
+
+func TestAgentsViewShowsOnlyAgentPanes(t *testing.T) {
+	work := windowWithPanes("work", 3)
+	work.Windows[0].Panes[0].Command = "zsh"
+	work.Windows[0].Panes[1].Command = "claude"
+	work.Windows[0].Panes[2].Command = "npm"
+	other := windowWithPanes("other", 1)
+	other.Windows[0].Panes[0].Command = "vim"
+
+	m := NewModel(Options{Agents: []config.AgentConfig{{Command: "claude --resume"}}})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{work, other}}
+	m.expanded[nodeKey("session", "work")] = false
+	m.rebuildFlatNodes()
+	m.selectedIndex = 0
+
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = updated.(Model)
+	// The collapsed session opens to show its agent, and other disappears
+	if len(m.flatNodes) != 3 || m.agentCount != 1 {
+		t.Fatalf("expected work, its window and the claude pane, got %d nodes (%d agents)", len(m.flatNodes), m.agentCount)
+	}
+	if node := m.selectedNode(); node.Target != "work:0.1" {
+		t.Fatalf("expected the agent pane selected, got %+v", node)
+	}
+	if status := ansi.Strip(m.agentsViewStatus()); status != "Agents 1 agent" {
+		t.Fatalf("unexpected status %q", status)
+	}
+
+	updated, _ = m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = updated.(Model)
+	if len(m.flatNodes) != 4 || m.selectedNode().Target != "work" {
+		t.Fatalf("expected the tree back with collapsed work selected, got %d nodes", len(m.flatNodes))
+	}
+}
+
+func TestTreeFilterKeepsAncestorsAndRestoresExpansion(t *testing.T) {
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 40
+	m.calculateLayout()
+	api := windowWithPanes("api", 2)
+	api.Windows[0].Panes[1].Title = "logs"
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{api, windowWithPanes("web", 1)}}
+	m.expanded[nodeKey("session", "api")] = false
+	m.rebuildFlatNodes()
+	before := len(m.flatNodes)
+
+	typeKeys := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			updated, _ := m.handleKeyMsg(k)
+			m = updated.(Model)
+		}
+	}
+	typeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	for _, r := range "logs" {
+		typeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	// The collapsed session is searched; its window stays as an ancestor
+	var targets []string
+	for _, node := range m.flatNodes {
+		targets = append(targets, node.Target)
+	}
+	if strings.Join(targets, ",") != "api,api:0,api:0.1" {
+		t.Fatalf("unexpected filtered nodes %v", targets)
+	}
+	if m.treeFilterMatches != 1 || m.selectedNode().Target != "api:0.1" {
+		t.Fatalf("expected the single match selected, got %d matches on %q", m.treeFilterMatches, m.selectedNode().Target)
+	}
+	if !strings.Contains(ansi.Strip(m.renderStatusBar()), "1 match") {
+		t.Fatal("expected the match count in the status bar")
+	}
+
+	// Enter keeps the filter for navigation; Esc clears it
+	typeKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filteringTree || len(m.flatNodes) != 3 {
+		t.Fatal("expected Enter to keep the filter applied")
+	}
+	typeKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.flatNodes) != before || m.expanded[nodeKey("session", "api")] {
+		t.Fatalf("expected the original expansion state after clearing, got %d nodes", len(m.flatNodes))
+	}
+	if node := m.selectedNode(); node == nil || node.Target != "api" {
+		t.Fatal("expected selection to stay on the match's visible ancestor")
+	}
+}
+
+func TestAfterSendClearsCommandInput(t *testing.T) {
+	for _, tc := range []struct {
+		afterSend  config.AfterSend
+		sendErr    error
+		afterPress string // Input right after pressing s
+		afterSent  string // Input once the send result arrives
+	}{
+		{afterSend: "", afterPress: "make test", afterSent: "make test"},
+		{afterSend: config.AfterSendClear, afterPress: "", afterSent: ""},
+		{afterSend: config.AfterSendClearOnSuccess, afterPress: "make test", afterSent: ""},
+		{afterSend: config.AfterSendClearOnSuccess, sendErr: errors.New("no pane"), afterPress: "make test", afterSent: "make test"},
+	} {
+		exec := &recordingExecutor{host: "devbox"}
+		m := NewModel(Options{AfterSend: tc.afterSend})
+		m.sendMethod = tmux.SendMethodEnterAppended // No delay
+		m.executors = []tmux.TmuxExecutor{exec}
+		m.hostTrees = []tmux.HostTree{{
+			Host:     "devbox",
+			Tree:     &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 1)}},
+			Executor: exec,
+		}}
+		m.liveTree = true
+		m.rebuildFlatNodes()
+		m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+		m.commandInput.SetValue("make test")
+
+		updated, cmd := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+		m = updated.(Model)
+		if cmd == nil || m.commandInput.Value() != tc.afterPress {
+			t.Fatalf("%q: input after s = %q, want %q", tc.afterSend, m.commandInput.Value(), tc.afterPress)
+		}
+		msg := cmd().(CommandSentMsg)
+		msg.Err = tc.sendErr
+		updated, _ = m.Update(msg)
+		if got := updated.(Model).commandInput.Value(); got != tc.afterSent {
+			t.Fatalf("%q (err %v): input after send = %q, want %q", tc.afterSend, tc.sendErr, got, tc.afterSent)
+		}
+	}
+}
+
+func TestCancelledShellSendKeepsInput(t *testing.T) {
+	session := windowWithPanes("work", 1)
+	session.Windows[0].Panes[0].Command = "zsh"
+	m := NewModel(Options{AfterSend: config.AfterSendClearOnSuccess})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{session}}
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+	m.commandInput.SetValue("make test")
+
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	updated, _ = updated.(Model).handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if m.clearOnSent != "" {
+		t.Fatalf("expected the cancelled send not to be cleared later, got %q", m.clearOnSent)
+	}
+
+	// A later send of the same text to another pane leaves it alone
+	updated, _ = m.Update(CommandSentMsg{Target: "work:0.1", Command: "make test"})
+	if got := updated.(Model).commandInput.Value(); got != "make test" {
+		t.Fatalf("expected the input kept, got %q", got)
+	}
+}
+
+func TestStatusSummaryCountsAndClock(t *testing.T) {
+	m := NewModel(Options{StatusClock: true, StatusCounts: true})
+	m.width, m.height = 160, 40
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("api", 2), windowWithPanes("web", 1)}}
+	m.clock = time.Date(2026, 3, 1, 14, 5, 0, 0, time.Local)
+	m.rebuildFlatNodes()
+
+	status := strings.Split(ansi.Strip(m.renderStatusBar()), "\n")[0]
+	if !strings.HasSuffix(strings.TrimRight(status, " "), "2s 2w 3p | 14:05") {
+		t.Fatalf("expected counts and clock at the right, got %q", status)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(status), "[r]efresh") {
+		t.Fatalf("expected the hints to stay first, got %q", status)
+	}
+
+	// Each host gets its own counts
+	m.executors = []tmux.TmuxExecutor{&recordingExecutor{host: "devbox"}}
+	m.hostTrees = []tmux.HostTree{
+		{Host: "local", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("api", 2)}}},
+		{Host: "devbox", Tree: &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("web", 1)}}},
+	}
+	if got := ansi.Strip(m.statusSummary()); got != "local 1s 1w 2p · devbox 1s 1w 1p | 14:05" {
+		t.Fatalf("unexpected per-host summary %q", got)
+	}
+
+	m.options = Options{}
+	if m.statusSummary() != "" {
+		t.Fatal("expected no summary when both are off")
+	}
+}
+
+func TestContextMenuOpensAtScrolledRow(t *testing.T) {
+	for _, layout := range []config.BrowseLayout{config.BrowseLayoutSide, config.BrowseLayoutStacked} {
+		m := NewModel(Options{Layout: layout})
+		m.width = 100
+		m.height = 60
+		m.calculateLayout()
+		var sessions []tmux.TmuxSession
+		for i := 0; i < 80; i++ {
+			sessions = append(sessions, tmux.TmuxSession{Name: fmt.Sprintf("sess-%02d", i)})
+		}
+		m.tree = &tmux.Tree{Sessions: sessions}
+		m.rebuildFlatNodes()
+		m.selectedIndex = 70
+		m.scrollTreeToSelection()
+		m.selectedIndex = m.treeScroll + 1
+		if m.treeScroll == 0 {
+			t.Fatalf("%s: expected the tree to scroll", layout)
+		}
+
+		m.showContextMenuForSelected()
+		if want := m.treeStartY() + 1; m.contextMenu == nil || m.contextMenu.Position.Y != want {
+			t.Fatalf("%s: expected the menu at row %d beside the selection, got %+v", layout, want, m.contextMenu)
+		}
+	}
+}
+
+func TestRevivesRecordHistoryUnlessDisabled(t *testing.T) {
+	if !recordsRevive(Options{}, nil) {
+		t.Fatal("expected revives to be recorded by default")
+	}
+	if recordsRevive(Options{NoHistory: true}, nil) {
+		t.Fatal("expected --no-history to skip recording a revive")
+	}
+	if recordsRevive(Options{}, &config.Config{NoHistory: true}) {
+		t.Fatal("expected no_history in the project config to skip recording a revive")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/tmux"
 )
 
 // View renders the TUI
@@ -41,11 +42,61 @@ func (m Model) View() string {
 		return m.renderKillConfirmOverlay(base)
 	}
 
+	// Show bulk kill confirmation overlay if active
+	if m.pendingBulkKill != nil {
+		return m.renderBulkKillConfirmOverlay(base)
+	}
+
+	// Show "session already exists" prompt if active
+	if m.confirmNewSession {
+		return m.renderNewSessionConfirmOverlay(base)
+	}
+
+	// Show send-to-all-panes confirmation if active
+	if m.pendingSendAll != nil {
+		return m.renderSendAllConfirmOverlay(base)
+	}
+
+	// Show send-to-shell confirmation if active
+	if m.pendingShellSend != nil {
+		return m.renderShellSendConfirmOverlay(base)
+	}
+
+	// Show the send-file prompt if active
+	if m.sendFile != nil {
+		return m.renderSendFileOverlay(base)
+	}
+
+	// Show template choice for a new session if active
+	if m.templatePicker != nil {
+		return m.templatePicker.render(base, m.width, m.height)
+	}
+
+	// Show the move-window session picker if active
+	if m.moveWindow != nil {
+		return m.renderMoveWindowOverlay(base)
+	}
+
 	// Show context menu overlay if active
 	if m.contextMenu != nil && m.contextMenu.Visible {
 		return m.renderContextMenuOverlay(base)
 	}
 
+	// Show pane search overlay if active
+	if m.search != nil {
+		return m.renderSearchOverlay(base)
+	}
+
+	// Show command palette overlay if active
+	if m.palette != nil {
+		return m.renderPaletteOverlay(base)
+	}
+
+	// Show the command history picker if active
+	if m.historyPicker != nil {
+		return m.renderHistoryPickerOverlay(base)
+	}
+
 	return base
 }
 
@@ -57,6 +108,9 @@ func (m *Model) renderInputBar() string {
 	}
 
 	label := lipgloss.NewStyle().Bold(true).Render("Command: ")
+	if m.multiline {
+		return style.Width(m.width - 4).Render(m.renderCommandEditor(label))
+	}
 	input := m.commandInput.View()
 
 	// Help button
@@ -66,11 +120,18 @@ func (m *Model) renderInputBar() string {
 	return style.Width(m.width - 4).Render(content)
 }
 
-// renderMainContent renders the tree and preview side by side
+// renderMainContent renders the tree and preview side by side (or stacked),
+// or only the preview while it is zoomed
 func (m Model) renderMainContent() string {
+	if m.previewZoomed {
+		return m.renderPreview()
+	}
 	tree := m.renderTree()
 	preview := m.renderPreview()
 
+	if m.stacked {
+		return lipgloss.JoinVertical(lipgloss.Left, tree, preview)
+	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, tree, preview)
 }
 
@@ -78,19 +139,28 @@ func (m Model) renderMainContent() string {
 func (m *Model) renderTree() string {
 	var lines []string
 
-	treeHeight := m.height - inputHeight - statusHeight - 4
-	if treeHeight < 1 {
-		treeHeight = 1
+	treeHeight := m.treeViewHeight()
+
+	// Number the on-screen nodes for digit jumps, padded to a common width
+	jumpNumbers := make(map[int]int)
+	for n, idx := range m.jumpTargets() {
+		jumpNumbers[idx] = n + 1
 	}
+	gutter := m.jumpGutterWidth()
+	numberStyle := lipgloss.NewStyle().Foreground(dimColor)
 
 	treeNodeLines := 0
-	for i, node := range m.flatNodes {
-		if i >= treeHeight {
+	for i := m.treeScroll; i < len(m.flatNodes); i++ {
+		node := m.flatNodes[i]
+		if i-m.treeScroll >= treeHeight {
 			break
 		}
 
 		selected := i == m.selectedIndex && !m.focusRecent
-		indent := strings.Repeat("  ", node.Level)
+		indent := strings.Repeat(" ", gutter) + strings.Repeat("  ", node.Level)
+		if n, ok := jumpNumbers[i]; ok {
+			indent = numberStyle.Render(fmt.Sprintf("%*d ", gutter-1, n)) + strings.Repeat("  ", node.Level)
+		}
 
 		// Host header nodes get special rendering
 		if node.Type == "host" {
@@ -106,6 +176,22 @@ func (m *Model) renderTree() string {
 					line = indent + icon + " " + remoteIndicatorStyle.Render("@ ") + selectedStyle.Inherit(remoteHostStyle).Render(node.Name)
 				}
 			}
+			// Mark trees still shown from the on-disk cache
+			if cachedAt := m.hostCachedAt(node.Host); !cachedAt.IsZero() {
+				if time.Since(cachedAt) > m.treeCacheTTL() {
+					line += lipgloss.NewStyle().Foreground(gettingStaleColor).Render(" (stale, " + browseTimeAgo(cachedAt) + ")")
+				} else {
+					line += lipgloss.NewStyle().Foreground(dimColor).Render(" (cached)")
+				}
+			} else if _, failed := m.hostErrors[node.Name]; failed {
+				if m.hostRetries[node.Host] > 0 {
+					line += lipgloss.NewStyle().Foreground(gettingStaleColor).Render(" retrying")
+				} else {
+					line += lipgloss.NewStyle().Foreground(errorColor).Render(" down")
+				}
+			} else if latency, ok := m.hostLatency[node.Name]; ok {
+				line += lipgloss.NewStyle().Foreground(dimColor).Render(" " + tmux.FormatLatency(latency))
+			}
 			lines = append(lines, line)
 			treeNodeLines++
 			continue
@@ -125,10 +211,13 @@ func (m *Model) renderTree() string {
 		if node.Type == "pane" {
 			sendButton := sendButtonStyle.Render("SEND")
 			escButton := escapeButtonStyle.Render("ESC")
-			buttonsWidth = lipgloss.Width(sendButton) + len(buttonGap) + lipgloss.Width(escButton)
+			intButton := interruptButtonStyle.Render("^C")
+			buttonsWidth = lipgloss.Width(sendButton) + len(buttonGap) + lipgloss.Width(escButton) + len(buttonGap) + lipgloss.Width(intButton)
 		}
 
-		maxNameLen := m.treeWidth - (node.Level * 2) - 4 - buttonsWidth // indent + icon + spacing + buttons
+		// Pane size, the sync glyph, and session uptime sit between the name and the buttons, so they come out of the name's space
+		metaText := countBadge(node) + m.paneSizeText(node) + syncGlyph(node) + zoomGlyph(node) + uptimeText(node) + m.watchGlyph(node) + m.markGlyph(node) + m.swapGlyph(node) + m.paneMarkGlyph(node)
+		maxNameLen := m.treeWidth - lipgloss.Width(indent) - 4 - buttonsWidth - lipgloss.Width(metaText) // indent + icon + spacing + meta + buttons
 		if len(name) > maxNameLen && maxNameLen > 3 {
 			name = name[:maxNameLen-3] + "..."
 		}
@@ -139,13 +228,14 @@ func (m *Model) renderTree() string {
 		} else {
 			styledName = style.Render(name)
 		}
-		line := indent + icon + " " + styledName
+		line := indent + icon + " " + styledName + metaText
 
-		// Add buttons for pane nodes only (SEND and ESC)
+		// Add buttons for pane nodes only (SEND, ESC, and ^C)
 		if node.Type == "pane" {
 			sendButton := sendButtonStyle.Render("SEND")
 			escButton := escapeButtonStyle.Render("ESC")
-			buttonsWidth := lipgloss.Width(sendButton) + len(buttonGap) + lipgloss.Width(escButton)
+			intButton := interruptButtonStyle.Render("^C")
+			buttonsWidth := lipgloss.Width(sendButton) + len(buttonGap) + lipgloss.Width(escButton) + len(buttonGap) + lipgloss.Width(intButton)
 
 			// Pad line to push buttons to the right
 			lineLen := lipgloss.Width(line)
@@ -153,7 +243,7 @@ func (m *Model) renderTree() string {
 			if padding < 1 {
 				padding = 1
 			}
-			line = line + strings.Repeat(" ", padding) + sendButton + buttonGap + escButton
+			line = line + strings.Repeat(" ", padding) + sendButton + buttonGap + escButton + buttonGap + intButton
 		}
 		// Sessions and windows no longer show ATT button - use tips strip instead
 
@@ -241,17 +331,74 @@ func (m *Model) renderTree() string {
 		Render(content)
 }
 
+// paneSizeText returns the dimmed " 80x24" suffix for pane rows, or "" when
+// pane sizes are hidden or unknown.
+func (m *Model) paneSizeText(node *tmux.TreeNode) string {
+	if !m.showPaneSize || node.Type != "pane" || node.Width <= 0 || node.Height <= 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimColor).Render(fmt.Sprintf(" %dx%d", node.Width, node.Height))
+}
+
+// syncGlyph marks windows with synchronize-panes on.
+func syncGlyph(node *tmux.TreeNode) string {
+	if node.Type != "window" || !node.Synchronized {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(activeColor).Render(" ⇉")
+}
+
+// zoomGlyph marks zoomed windows and the pane zoomed in them.
+func zoomGlyph(node *tmux.TreeNode) string {
+	if !node.Zoomed {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(activeColor).Render(" ⤢")
+}
+
+// countBadge returns the dimmed " (3w/7p)" suffix for collapsed sessions,
+// or " (4p)" for collapsed windows, so their size shows without expanding.
+func countBadge(node *tmux.TreeNode) string {
+	if node.Expanded {
+		return ""
+	}
+	var badge string
+	switch node.Type {
+	case "session":
+		badge = fmt.Sprintf(" (%dw/%dp)", node.Windows, node.Panes)
+	case "window":
+		badge = fmt.Sprintf(" (%dp)", node.Panes)
+	default:
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimColor).Render(badge)
+}
+
+// uptimeText returns the dimmed " up 3h12m" suffix for session rows, or ""
+// when the creation time is unknown.
+func uptimeText(node *tmux.TreeNode) string {
+	if node.Type != "session" {
+		return ""
+	}
+	up := formatUptime(node.Created)
+	if up == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimColor).Render(" up " + up)
+}
+
 // renderPreview renders the pane preview panel
 func (m Model) renderPreview() string {
-	previewHeight := m.height - inputHeight - statusHeight - 4
-	if previewHeight < 1 {
-		previewHeight = 1
-	}
+	previewHeight := m.previewViewHeight()
 
 	var content string
 	if node := m.selectedNode(); node != nil {
 		if node.Type == "pane" {
-			if m.previewContent != "" {
+			if m.previewContent != "" && m.previewSelect != nil {
+				port := m.previewPort
+				port.SetContent(m.previewSelect.highlight(m.previewContent))
+				content = port.View()
+			} else if m.previewContent != "" {
 				content = m.previewPort.View()
 			} else {
 				content = lipgloss.NewStyle().
@@ -281,7 +428,20 @@ func (m Model) renderPreview() string {
 		header = lipgloss.NewStyle().
 			Bold(true).
 			Foreground(primaryColor).
-			Render(targetStr) + "\n"
+			Render(targetStr)
+		// The sent commands get up to half of the room, the sparkline the rest
+		room := m.previewWidth - lipgloss.Width(header) - 4
+		sent := m.renderSentLog(node.Host, node.Target, room/2)
+		if sent != "" {
+			room -= lipgloss.Width(sent) + 2
+		}
+		if spark := m.activitySparkline(room); spark != "" {
+			header += "  " + lipgloss.NewStyle().Foreground(activeColor).Render(spark)
+		}
+		if sent != "" {
+			header += "  " + sent
+		}
+		header += "\n"
 	}
 
 	// Apply border style
@@ -308,7 +468,7 @@ func (m Model) renderStatusBar() string {
 			{"[r]", "efresh"},
 			{"[a]", "ttach"},
 			{"[x]", "kill"},
-			{"[/]", "input"},
+			{"[/]", "filter"},
 			{"[?]", "help"},
 		}
 		var hintParts []string
@@ -324,10 +484,22 @@ func (m Model) renderStatusBar() string {
 		parts = append(parts, lipgloss.NewStyle().Foreground(dimColor).Render("[Enter]send [Esc]exit"))
 	}
 
+	// Tree filter and its match count
+	if filter := m.treeFilterStatus(); filter != "" {
+		parts = append(parts, filter)
+	}
+	if agents := m.agentsViewStatus(); agents != "" {
+		parts = append(parts, agents)
+	}
+	if m.options.RefreshInterval <= 0 {
+		// Auto-refresh is off; say so, since the tree won't change by itself
+		parts = append(parts, lipgloss.NewStyle().Foreground(gettingStaleColor).Render("Manual refresh: r"))
+	}
+
 	// Debug mode: show send method
 	if m.options.DebugMode {
 		methodStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214")). // Orange
+			Foreground(gettingStaleColor).
 			Bold(true)
 		parts = append(parts, methodStyle.Render(fmt.Sprintf("Method: %s", m.sendMethod.String())))
 	}
@@ -356,17 +528,33 @@ func (m Model) renderStatusBar() string {
 		parts = append(parts, statusSelectedStyle.Render(node.Target))
 	}
 
+	// Next scheduled job
+	if m.nextRun != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(dimColor).Render(m.nextRun))
+	}
+
 	// Last sent command
 	if m.lastSent != "" {
 		parts = append(parts, lipgloss.NewStyle().Foreground(activeColor).Render("Sent: "+m.lastSent))
 	}
 
+	// Informational notice (e.g. clipboard copy)
+	if m.lastNotice != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(activeColor).Render(m.lastNotice))
+	}
+
 	// Error display
 	if m.lastError != nil {
 		parts = append(parts, lipgloss.NewStyle().Foreground(errorColor).Render("Error: "+m.lastError.Error()))
 	}
 
 	status := strings.Join(parts, " | ")
+	// The summary sits at the far right, clear of the clickable hints
+	if summary := m.statusSummary(); summary != "" {
+		if gap := m.width - 4 - lipgloss.Width(status) - lipgloss.Width(summary); gap >= 2 {
+			status += strings.Repeat(" ", gap) + summary
+		}
+	}
 	statusLine := statusBarStyle.Width(m.width - 2).Render(status)
 
 	// Add tip below status bar (only when not in input mode)
@@ -381,34 +569,67 @@ func (m Model) renderStatusBar() string {
 	return statusLine
 }
 
+// statusSummary returns the optional counts and clock shown at the right of
+// the status bar, e.g. "3s 7w 12p | 14:05". With several hosts, each host
+// gets its own counts.
+func (m Model) statusSummary() string {
+	var parts []string
+	if m.options.StatusCounts {
+		if len(m.executors) > 0 {
+			var hosts []string
+			for _, ht := range m.hostTrees {
+				if ht.Tree != nil {
+					hosts = append(hosts, ht.Host+" "+treeCounts(ht.Tree))
+				}
+			}
+			if len(hosts) > 0 {
+				parts = append(parts, strings.Join(hosts, " · "))
+			}
+		} else if m.tree != nil {
+			parts = append(parts, treeCounts(m.tree))
+		}
+	}
+	if m.options.StatusClock {
+		parts = append(parts, m.clock.Format("15:04"))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimColor).Render(strings.Join(parts, " | "))
+}
+
+// treeCounts summarizes a tree as session, window and pane counts.
+func treeCounts(tree *tmux.Tree) string {
+	windows, panes := 0, 0
+	for _, sess := range tree.Sessions {
+		windows += len(sess.Windows)
+		for _, win := range sess.Windows {
+			panes += len(win.Panes)
+		}
+	}
+	return fmt.Sprintf("%ds %dw %dp", len(tree.Sessions), windows, panes)
+}
+
 // renderHelpOverlay renders the help overlay on top of the base view
 func (m Model) renderHelpOverlay(base string) string {
 	// Build help content
 	title := helpTitleStyle.Render("atmux browse - Help")
 
-	keyboardSection := helpSectionStyle.Render("Keyboard Shortcuts")
-	keyboard := []struct{ key, desc string }{
-		{"↑/↓ or j/k", "Navigate tree"},
-		{"Enter/Space", "Expand/collapse node"},
-		{"a", "Attach to selected session"},
-		{"s", "Send command to selected pane"},
-		{"x or d", "Kill selected session/window/pane"},
-		{"c", "Show context menu"},
-		{"/", "Focus command input"},
-		{"r", "Refresh tree"},
-		{"M", "Toggle mouse support"},
-		{"Tab", "Cycle focus (Tree → Input → Preview)"},
-		{"Esc", "Clear input / Quit"},
-		{"q", "Quit"},
-		{"?", "Toggle this help"},
-	}
-
-	var keyboardLines []string
-	for _, k := range keyboard {
-		key := helpKeyStyle.Width(16).Render(k.key)
-		desc := helpDescStyle.Render(k.desc)
-		keyboardLines = append(keyboardLines, key+desc)
+	// Keys for the focused panel first, then keys available everywhere
+	renderKeys := func(keys []keyHelp) string {
+		var lines []string
+		for _, k := range keys {
+			key := helpKeyStyle.Width(16).Render(k.keys)
+			desc := helpDescStyle.Render(k.desc)
+			lines = append(lines, key+desc)
+		}
+		return strings.Join(lines, "\n")
 	}
+	scope := m.focusScope()
+	focusSection := helpSectionStyle.Render(focusScopeTitle(scope) + " Keys")
+	focusKeys := renderKeys(m.activeKeys(scope))
+	globalSection := helpSectionStyle.Render("\nGlobal Keys")
+	globalKeys := renderKeys(m.activeKeys(scopeGlobal))
 
 	mouseSection := helpSectionStyle.Render("\nMouse Actions")
 	mouse := []struct{ action, desc string }{
@@ -418,6 +639,7 @@ func (m Model) renderHelpOverlay(base string) string {
 		{"Double-click", "Attach to session"},
 		{"Click SEND", "Send command to pane"},
 		{"Click ESC", "Send Escape to pane"},
+		{"Click ^C", "Interrupt pane (C-c)"},
 		{"Drag divider", "Resize panels"},
 		{"Scroll", "Scroll preview pane"},
 	}
@@ -433,6 +655,7 @@ func (m Model) renderHelpOverlay(base string) string {
 	buttons := []struct{ btn, desc string }{
 		{"SEND", "Send command input to this pane"},
 		{"ESC", "Send Escape key to this pane"},
+		{"^C", "Send C-c to interrupt this pane"},
 	}
 
 	var buttonLines []string
@@ -443,18 +666,31 @@ func (m Model) renderHelpOverlay(base string) string {
 	}
 
 	footer := helpDescStyle.Render("\nPress ? or Esc to close")
+	if m.options.SkipKillConfirm {
+		footer = lipgloss.NewStyle().Foreground(gettingStaleColor).Render("\nKill confirmations are off (attached sessions still confirm)") + footer
+	}
 
-	helpContent := strings.Join([]string{
+	sections := []string{
 		title,
 		"",
-		keyboardSection,
-		strings.Join(keyboardLines, "\n"),
-		mouseSection,
-		strings.Join(mouseLines, "\n"),
-		buttonsSection,
-		strings.Join(buttonLines, "\n"),
-		footer,
-	}, "\n")
+		focusSection,
+		focusKeys,
+		globalSection,
+		globalKeys,
+	}
+	// Mouse actions only apply while mouse capture is on
+	if m.mouseEnabled {
+		sections = append(sections,
+			mouseSection,
+			strings.Join(mouseLines, "\n"),
+			buttonsSection,
+			strings.Join(buttonLines, "\n"),
+		)
+	} else {
+		sections = append(sections, helpDescStyle.Render("\nMouse capture is off (press M to enable)"))
+	}
+	sections = append(sections, footer)
+	helpContent := strings.Join(sections, "\n")
 
 	// Calculate overlay dimensions
 	helpBox := helpOverlayStyle.Render(helpContent)
@@ -521,6 +757,23 @@ func placeOverlay(x, y int, overlay, background string) string {
 	return strings.Join(bgLines, "\n")
 }
 
+// killImpactLines describes what a kill destroys for its confirmation: the
+// window and pane counts, then a warning naming any panes running agents.
+func killImpactLines(impact tmux.KillImpact) []string {
+	if impact.Windows == 0 && impact.Panes == 0 {
+		return nil // Nothing loaded for the target
+	}
+	lines := []string{lipgloss.NewStyle().Foreground(dimColor).Render("Destroys " + impact.String())}
+	if len(impact.Agents) > 0 {
+		warn := lipgloss.NewStyle().Foreground(errorColor)
+		lines = append(lines, warn.Render(fmt.Sprintf("%d running agent(s):", len(impact.Agents))))
+		for _, agent := range impact.Agents {
+			lines = append(lines, warn.Render("  - "+agent))
+		}
+	}
+	return lines
+}
+
 // renderKillConfirmOverlay renders the kill confirmation overlay
 func (m Model) renderKillConfirmOverlay(base string) string {
 	// Build confirmation content
@@ -534,7 +787,7 @@ func (m Model) renderKillConfirmOverlay(base string) string {
 
 	message := fmt.Sprintf("Kill %s '%s'?", typeLabel, nameDisplay)
 	messageStyled := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("15")).
+		Foreground(textColor).
 		Bold(true).
 		Render(message)
 
@@ -542,13 +795,8 @@ func (m Model) renderKillConfirmOverlay(base string) string {
 		Foreground(dimColor).
 		Render("Press [y] to confirm, [n] or [Esc] to cancel")
 
-	confirmContent := strings.Join([]string{
-		title,
-		"",
-		messageStyled,
-		"",
-		hint,
-	}, "\n")
+	lines := append([]string{title, "", messageStyled}, killImpactLines(m.killImpact)...)
+	confirmContent := strings.Join(append(lines, "", hint), "\n")
 
 	// Apply overlay style
 	confirmBox := helpOverlayStyle.
@@ -571,6 +819,34 @@ func (m Model) renderKillConfirmOverlay(base string) string {
 	return placeOverlay(x, y, confirmBox, base)
 }
 
+// renderNewSessionConfirmOverlay asks whether to attach to an existing
+// session instead of creating a new one.
+func (m Model) renderNewSessionConfirmOverlay(base string) string {
+	title := helpTitleStyle.Render("Session Exists")
+
+	message := lipgloss.NewStyle().
+		Foreground(textColor).
+		Bold(true).
+		Render(fmt.Sprintf("'%s' is already running. Attach to it?", m.options.SessionName))
+
+	hint := lipgloss.NewStyle().
+		Foreground(dimColor).
+		Render("Press [y] to attach, [n] or [Esc] to cancel")
+
+	content := strings.Join([]string{title, "", message, "", hint}, "\n")
+	box := helpOverlayStyle.Width(50).Render(content)
+
+	x := (m.width - lipgloss.Width(box)) / 2
+	y := (m.height - lipgloss.Height(box)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return placeOverlay(x, y, box, base)
+}
+
 // browseTimeAgo formats a time as a relative string for the browse view.
 func browseTimeAgo(t time.Time) string {
 	d := time.Since(t)
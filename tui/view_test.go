@@ -1,8 +1,12 @@
 package tui
 
 import (
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/x/ansi"
+	"github.com/porganisciak/agent-tmux/config"
 	"github.com/porganisciak/agent-tmux/tmux"
 )
 
@@ -38,28 +42,196 @@ func TestRenderTreeAddsEscapeButton(t *testing.T) {
 	m.rebuildFlatNodes()
 	m.calculateButtonZones()
 
-	// Expected: 1 help (top) + 1 ATT (session) + 1 ATT (window) + 1 SEND + 1 ESC + 1 ATT (pane) +
-	//           5 status bar hints (refresh, attach, killhint, focusinput, help) = 11 zones
-	if len(m.buttonZones) != 11 {
+	// Expected: 1 help (top) + 1 ATT (session) + 1 ATT (window) + 1 SEND + 1 ESC + 1 ^C + 1 ATT (pane) +
+	//           5 status bar hints (refresh, attach, killhint, focusinput, help) = 12 zones
+	if len(m.buttonZones) != 12 {
 		types := make([]string, 0, len(m.flatNodes))
 		for _, node := range m.flatNodes {
 			types = append(types, node.Type)
 		}
-		t.Fatalf("expected 11 button zones, got %d (nodes=%v)", len(m.buttonZones), types)
+		t.Fatalf("expected 12 button zones, got %d (nodes=%v)", len(m.buttonZones), types)
 	}
 
 	actions := map[string]int{}
 	for _, zone := range m.buttonZones {
 		actions[zone.action]++
-		if zone.action == buttonActionSend || zone.action == buttonActionEscape {
+		if zone.action == buttonActionSend || zone.action == buttonActionEscape || zone.action == buttonActionInterrupt {
 			if zone.target != "sess:0.0" {
 				t.Fatalf("expected target sess:0.0 for %s, got %q", zone.action, zone.target)
 			}
 		}
 	}
 
-	if actions[buttonActionSend] != 1 || actions[buttonActionEscape] != 1 || actions[buttonActionAttach] != 4 || actions[buttonActionHelp] != 2 ||
-		actions[buttonActionRefresh] != 1 || actions[buttonActionKillHint] != 1 || actions[buttonActionFocusInput] != 1 {
-		t.Fatalf("expected send=1, escape=1, attach=4, help=2, refresh=1, killhint=1, focusinput=1, got %+v", actions)
+	if actions[buttonActionSend] != 1 || actions[buttonActionEscape] != 1 || actions[buttonActionInterrupt] != 1 || actions[buttonActionAttach] != 4 || actions[buttonActionHelp] != 2 ||
+		actions[buttonActionRefresh] != 1 || actions[buttonActionKillHint] != 1 || actions[buttonActionFilter] != 1 {
+		t.Fatalf("expected send=1, escape=1, interrupt=1, attach=4, help=2, refresh=1, killhint=1, filter=1, got %+v", actions)
+	}
+}
+
+func TestRenderTreeShowsPaneSizeWhenToggled(t *testing.T) {
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 40
+	m.calculateLayout()
+	m.tree = &tmux.Tree{
+		Sessions: []tmux.TmuxSession{{
+			Name: "sess",
+			Windows: []tmux.Window{{
+				Index: 0,
+				Name:  "win",
+				Panes: []tmux.Pane{{
+					Index:  0,
+					Title:  strings.Repeat("long-pane-title-", 4),
+					Target: "sess:0.0",
+					Width:  80,
+					Height: 24,
+				}},
+			}},
+		}},
+	}
+	m.rebuildFlatNodes()
+
+	paneRow := func() string {
+		for _, line := range strings.Split(ansi.Strip(m.renderTree()), "\n") {
+			if strings.Contains(line, "SEND") {
+				return line
+			}
+		}
+		t.Fatal("pane row not rendered")
+		return ""
+	}
+
+	if strings.Contains(paneRow(), "80x24") {
+		t.Fatal("pane size should be hidden by default")
+	}
+
+	m.showPaneSize = true
+	row := paneRow()
+	if !strings.Contains(row, "80x24") {
+		t.Fatalf("expected pane size in row, got %q", row)
+	}
+	// The size must not push the buttons past the tree border
+	if !strings.HasSuffix(row, "ESC   ^C│") {
+		t.Fatalf("expected buttons to stay right-aligned, got %q", row)
+	}
+	if w := ansi.StringWidth(row); w != m.treeWidth+2 {
+		t.Fatalf("expected row width %d, got %d", m.treeWidth+2, w)
+	}
+}
+
+func TestRenderTreeShowsSessionUptime(t *testing.T) {
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 40
+	m.calculateLayout()
+	created := time.Now().Add(-(3*time.Hour + 12*time.Minute + 30*time.Second)).Unix()
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{
+		{Name: "long-lived", Created: created},
+		{Name: "unknown"},
+	}}
+	m.rebuildFlatNodes()
+
+	tree := ansi.Strip(m.renderTree())
+	if !strings.Contains(tree, "long-lived up 3h12m") {
+		t.Fatalf("expected uptime on the session row:\n%s", tree)
+	}
+	if strings.Contains(tree, "unknown up") {
+		t.Fatalf("expected no uptime without a creation time:\n%s", tree)
+	}
+}
+
+func TestRenderTreeShowsCountsOnCollapsedNodes(t *testing.T) {
+	m := NewModel(Options{})
+	m.width = 120
+	m.height = 40
+	m.calculateLayout()
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{
+		{Name: "big", Windows: []tmux.Window{
+			{Index: 0, Name: "editor", Panes: []tmux.Pane{{Index: 0}, {Index: 1}}},
+			{Index: 1, Name: "logs", Panes: []tmux.Pane{{Index: 0}}},
+		}},
+		{Name: "open", Windows: []tmux.Window{
+			{Index: 0, Name: "shell", Panes: []tmux.Pane{{Index: 0}, {Index: 1}, {Index: 2}}},
+		}},
+	}}
+	m.expanded[nodeKey("session", "big")] = false
+	m.expanded[nodeKey("window", "open:0")] = false
+	m.rebuildFlatNodes()
+
+	tree := ansi.Strip(m.renderTree())
+	for _, want := range []string{"big (2w/3p)", "shell (3p)"} {
+		if !strings.Contains(tree, want) {
+			t.Fatalf("expected %q on a collapsed node:\n%s", want, tree)
+		}
+	}
+	if strings.Contains(tree, "open (") {
+		t.Fatalf("expected no badge on an expanded session:\n%s", tree)
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{5*time.Minute + 10*time.Second, "5m"},
+		{3*time.Hour + 12*time.Minute + 10*time.Second, "3h12m"},
+		{4*24*time.Hour + 3*time.Hour + time.Minute, "4d3h"},
+	}
+	for _, tt := range tests {
+		if got := formatUptime(now.Add(-tt.age).Unix()); got != tt.want {
+			t.Errorf("formatUptime(%v ago) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+	if got := formatUptime(0); got != "" {
+		t.Errorf("expected empty uptime for unknown creation time, got %q", got)
+	}
+}
+
+func TestHelpKeysReflectModeAndFocus(t *testing.T) {
+	hasKey := func(keys []keyHelp, key string) bool {
+		for _, k := range keys {
+			if k.keys == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	m := NewModel(Options{})
+	if hasKey(m.activeKeys(scopeGlobal), "m") {
+		t.Fatal("send-method key should only be listed in debug mode")
+	}
+	debug := NewModel(Options{DebugMode: true})
+	if !hasKey(debug.activeKeys(scopeGlobal), "m") {
+		t.Fatal("expected send-method key in debug mode")
+	}
+
+	for _, k := range m.activeKeys(scopeTree) {
+		if strings.Contains(k.desc, "host") {
+			t.Fatalf("unexpected host binding without executors: %q", k.desc)
+		}
+	}
+
+	m.focused = FocusInput
+	if m.focusScope() != scopeInput {
+		t.Fatalf("expected input scope, got %d", m.focusScope())
+	}
+	if hasKey(m.activeKeys(scopeGlobal), "r") {
+		t.Fatal("refresh key is typed into the input, so it should not be listed")
+	}
+}
+
+func TestNextRunLabel(t *testing.T) {
+	jobs := []config.ScheduledJob{
+		{Name: "backup", CronExpr: "0 3 * * *", Enabled: true},
+		{Command: "/compact", CronExpr: "* * * * *", Enabled: false},
+	}
+	if got := nextRunLabel(jobs); !strings.HasPrefix(got, "next: backup ") {
+		t.Fatalf("expected the enabled job, got %q", got)
+	}
+	if got := nextRunLabel(jobs[1:]); got != "" {
+		t.Fatalf("expected no indicator without enabled jobs, got %q", got)
 	}
 }
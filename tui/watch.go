@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+// defaultWatchIdleAfter is how long a watched pane's output must stay still
+// before it counts as idle.
+const defaultWatchIdleAfter = 30 * time.Second
+
+// paneWatch follows a pane's output between refreshes to tell when it stops
+// changing.
+type paneWatch struct {
+	host      string
+	target    string
+	last      string    // previous capture
+	primed    bool      // whether last holds a capture to compare against
+	changedAt time.Time // when the output last changed
+	busy      bool      // output changed since the pane last went idle
+}
+
+func watchKey(host, target string) string {
+	return host + "\x00" + target
+}
+
+// label names the pane in notices.
+func (w *paneWatch) label() string {
+	if w.host != "" {
+		return w.target + " @ " + w.host
+	}
+	return w.target
+}
+
+// observe records a capture taken at now and reports whether the pane has
+// just gone idle: its output changed while watched, then stayed the same for
+// idleAfter. It reports each stretch of work once.
+func (w *paneWatch) observe(content string, now time.Time, idleAfter time.Duration) bool {
+	if !w.primed || content != w.last {
+		w.busy = w.primed
+		w.last = content
+		w.primed = true
+		w.changedAt = now
+		return false
+	}
+	if w.busy && now.Sub(w.changedAt) >= idleAfter {
+		w.busy = false
+		return true
+	}
+	return false
+}
+
+// watchCapturedMsg carries a watched pane's content for the idle check.
+type watchCapturedMsg struct {
+	key     string
+	content string
+	at      time.Time
+	err     error
+}
+
+// toggleWatch starts or stops watching the selected pane.
+func (m *Model) toggleWatch() {
+	node := m.selectedNode()
+	if node == nil || node.Type != "pane" || node.Target == "" {
+		return
+	}
+	key := watchKey(node.Host, node.Target)
+	if w, ok := m.watches[key]; ok {
+		delete(m.watches, key)
+		m.lastNotice = "Stopped watching " + w.label()
+		return
+	}
+	if m.watches == nil {
+		m.watches = make(map[string]*paneWatch)
+	}
+	w := &paneWatch{host: node.Host, target: node.Target}
+	m.watches[key] = w
+	m.lastNotice = "Watching " + w.label() + " until it goes idle"
+}
+
+// captureWatchedCmd captures every watched pane for the idle check.
+func (m *Model) captureWatchedCmd() tea.Cmd {
+	var cmds []tea.Cmd
+	for key, w := range m.watches {
+		key, target := key, w.target
+		executor := m.executorForHost(w.host)
+		if executor == nil {
+			executor = tmux.NewLocalExecutor()
+		}
+		cmds = append(cmds, func() tea.Msg {
+			content, err := tmux.CapturePaneTextWithExecutor(target, executor)
+			return watchCapturedMsg{key: key, content: content, at: time.Now(), err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleWatchCaptured runs the idle check for a watched pane, ringing the
+// bell (and optionally sending a desktop notification) when it goes idle.
+func (m *Model) handleWatchCaptured(msg watchCapturedMsg) tea.Cmd {
+	w, ok := m.watches[msg.key]
+	if !ok {
+		return nil
+	}
+	if msg.err != nil {
+		// The pane closed, which also means its work is done
+		delete(m.watches, msg.key)
+		m.lastNotice = "Stopped watching " + w.label() + " (pane closed)"
+		return nil
+	}
+	idleAfter := m.options.WatchIdleAfter
+	if idleAfter <= 0 {
+		idleAfter = defaultWatchIdleAfter
+	}
+	if !w.observe(msg.content, msg.at, idleAfter) {
+		return nil
+	}
+	message := w.label() + " went idle"
+	m.lastNotice = message
+	return notifyIdle(message, m.options.WatchNotify)
+}
+
+// notifyIdle rings the terminal bell and, when desktop is set, sends a
+// system notification through terminal-notifier or notify-send.
+func notifyIdle(message string, desktop bool) tea.Cmd {
+	return func() tea.Msg {
+		fmt.Fprint(os.Stderr, "\a")
+		if desktop {
+			sendDesktopNotification("atmux", message)
+		}
+		return nil
+	}
+}
+
+func sendDesktopNotification(title, message string) {
+	if path, err := exec.LookPath("terminal-notifier"); err == nil {
+		exec.Command(path, "-title", title, "-message", message).Run()
+		return
+	}
+	if path, err := exec.LookPath("notify-send"); err == nil {
+		exec.Command(path, title, message).Run()
+	}
+}
+
+// watchGlyph marks watched panes in the tree.
+func (m *Model) watchGlyph(node *tmux.TreeNode) string {
+	if node.Type != "pane" {
+		return ""
+	}
+	if _, ok := m.watches[watchKey(node.Host, node.Target)]; !ok {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(activeColor).Render(" ◎")
+}
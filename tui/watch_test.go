@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/porganisciak/agent-tmux/tmux"
+)
+
+func TestPaneWatchReportsIdleOncePerBurst(t *testing.T) {
+	w := &paneWatch{target: "work:0.1"}
+	start := time.Now()
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	// A pane that never changes was already idle when watching began
+	if w.observe("ready", at(0), 10*time.Second) || w.observe("ready", at(20), 10*time.Second) {
+		t.Fatal("expected no notice for a pane that never worked")
+	}
+	w.observe("thinking", at(21), 10*time.Second)
+	w.observe("thinking.", at(25), 10*time.Second)
+	if w.observe("thinking.", at(30), 10*time.Second) {
+		t.Fatal("expected the pane to still count as busy")
+	}
+	if !w.observe("thinking.", at(35), 10*time.Second) {
+		t.Fatal("expected the pane to go idle 10s after its last change")
+	}
+	if w.observe("thinking.", at(50), 10*time.Second) {
+		t.Fatal("expected a single notice per stretch of work")
+	}
+}
+
+func TestWatchKeyTogglesAndNotifies(t *testing.T) {
+	m := NewModel(Options{WatchIdleAfter: time.Second})
+	m.tree = &tmux.Tree{Sessions: []tmux.TmuxSession{windowWithPanes("work", 1)}}
+	m.rebuildFlatNodes()
+	m.selectedIndex = nodeIndex(t, m, "pane", "work:0.0")
+
+	updated, _ := m.handleTreeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	m = updated.(Model)
+	if len(m.watches) != 1 || m.watchGlyph(m.selectedNode()) == "" {
+		t.Fatal("expected w to watch the selected pane")
+	}
+
+	key := watchKey("", "work:0.0")
+	start := time.Now()
+	for i, content := range []string{"a", "b"} {
+		updated, _ = m.Update(watchCapturedMsg{key: key, content: content, at: start.Add(time.Duration(i) * time.Second)})
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(watchCapturedMsg{key: key, content: "b", at: start.Add(3 * time.Second)})
+	m = updated.(Model)
+	if cmd == nil || m.lastNotice != "work:0.0 went idle" {
+		t.Fatalf("expected an idle notice, got %q", m.lastNotice)
+	}
+
+	// A closed pane stops the watch
+	updated, _ = m.Update(watchCapturedMsg{key: key, err: errors.New("can't find pane")})
+	if m = updated.(Model); len(m.watches) != 0 {
+		t.Fatal("expected the watch to end when the pane closes")
+	}
+}